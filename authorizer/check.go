@@ -73,7 +73,7 @@ func (s *CheckService) FindChecks(ctx context.Context, filter influxdb.CheckFilt
 	return rules, len(rules), nil
 }
 
-// FindCheck will return the check.
+// FindCheck will return the check if the authorizer on context has read access to its organization.
 func (s *CheckService) FindCheck(ctx context.Context, filter influxdb.CheckFilter) (influxdb.Check, error) {
 	chk, err := s.s.FindCheck(ctx, filter)
 	if err != nil {
@@ -142,3 +142,17 @@ func (s *CheckService) DeleteCheck(ctx context.Context, id influxdb.ID) error {
 
 	return s.s.DeleteCheck(ctx, id)
 }
+
+// CloneCheck checks to see if the authorizer on context has write access to the organization of the check provided.
+func (s *CheckService) CloneCheck(ctx context.Context, id influxdb.ID, newName string) (influxdb.Check, error) {
+	chk, err := s.FindCheckByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteOrg(ctx, chk.GetOrgID()); err != nil {
+		return nil, err
+	}
+
+	return s.s.CloneCheck(ctx, id, newName)
+}
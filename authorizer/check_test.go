@@ -121,12 +121,119 @@ func TestCheckService_FindCheckByID(t *testing.T) {
 	}
 }
 
+func TestCheckService_FindCheck(t *testing.T) {
+	type fields struct {
+		CheckService influxdb.CheckService
+	}
+	type args struct {
+		permission influxdb.Permission
+		filter     influxdb.CheckFilter
+	}
+	type wants struct {
+		err error
+	}
+
+	name := "check1"
+	orgID := influxdb.ID(10)
+
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "authorized to access check by name",
+			fields: fields{
+				CheckService: &mock.CheckService{
+					FindCheckFn: func(ctx context.Context, filter influxdb.CheckFilter) (influxdb.Check, error) {
+						return &check.Deadman{
+							Base: check.Base{
+								ID:    1,
+								Name:  *filter.Name,
+								OrgID: 10,
+							},
+						}, nil
+					},
+				},
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type: influxdb.OrgsResourceType,
+						ID:   influxdbtesting.IDPtr(10),
+					},
+				},
+				filter: influxdb.CheckFilter{
+					Name:  &name,
+					OrgID: &orgID,
+				},
+			},
+			wants: wants{
+				err: nil,
+			},
+		},
+		{
+			name: "unauthorized to access check by name in another org",
+			fields: fields{
+				CheckService: &mock.CheckService{
+					FindCheckFn: func(ctx context.Context, filter influxdb.CheckFilter) (influxdb.Check, error) {
+						return &check.Deadman{
+							Base: check.Base{
+								ID:    1,
+								Name:  *filter.Name,
+								OrgID: 10,
+							},
+						}, nil
+					},
+				},
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type: influxdb.OrgsResourceType,
+						ID:   influxdbtesting.IDPtr(2),
+					},
+				},
+				filter: influxdb.CheckFilter{
+					Name:  &name,
+					OrgID: &orgID,
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "read:orgs/000000000000000a is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewCheckService(tt.fields.CheckService, mock.NewUserResourceMappingService(), mock.NewOrganizationService())
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.args.permission}})
+
+			chk, err := s.FindCheck(ctx, tt.args.filter)
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+			if err != nil && chk != nil {
+				t.Errorf("expected no check to be returned on error, got %v", chk)
+			}
+		})
+	}
+}
+
 func TestCheckService_FindChecks(t *testing.T) {
 	type fields struct {
 		CheckService influxdb.CheckService
 	}
 	type args struct {
 		permission influxdb.Permission
+		filter     influxdb.CheckFilter
 	}
 	type wants struct {
 		err    error
@@ -252,6 +359,67 @@ func TestCheckService_FindChecks(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "ids outside of the authorized org are dropped",
+			fields: fields{
+				CheckService: &mock.CheckService{
+					FindChecksFn: func(ctx context.Context, filter influxdb.CheckFilter, opt ...influxdb.FindOptions) ([]influxdb.Check, int, error) {
+						checks := []influxdb.Check{
+							&check.Deadman{
+								Base: check.Base{
+									ID:    1,
+									OrgID: 10,
+								},
+							},
+							&check.Deadman{
+								Base: check.Base{
+									ID:    2,
+									OrgID: 10,
+								},
+							},
+							&check.Threshold{
+								Base: check.Base{
+									ID:    3,
+									OrgID: 11,
+								},
+							},
+						}
+
+						var found []influxdb.Check
+						for _, c := range checks {
+							for _, id := range filter.IDs {
+								if c.GetID() == id {
+									found = append(found, c)
+								}
+							}
+						}
+						return found, len(found), nil
+					},
+				},
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type:  influxdb.OrgsResourceType,
+						OrgID: influxdbtesting.IDPtr(10),
+					},
+				},
+				filter: influxdb.CheckFilter{
+					IDs: []influxdb.ID{1, 3},
+				},
+			},
+			wants: wants{
+				checks: []influxdb.Check{
+					&check.Deadman{
+						Base: check.Base{
+							ID:    1,
+							OrgID: 10,
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -261,7 +429,7 @@ func TestCheckService_FindChecks(t *testing.T) {
 			ctx := context.Background()
 			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.args.permission}})
 
-			ts, _, err := s.FindChecks(ctx, influxdb.CheckFilter{})
+			ts, _, err := s.FindChecks(ctx, tt.args.filter)
 			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
 
 			if diff := cmp.Diff(ts, tt.wants.checks, checkCmpOptions...); diff != "" {
@@ -618,6 +786,130 @@ func TestCheckService_DeleteCheck(t *testing.T) {
 	}
 }
 
+func TestCheckService_CloneCheck(t *testing.T) {
+	type fields struct {
+		CheckService influxdb.CheckService
+	}
+	type args struct {
+		id          influxdb.ID
+		name        string
+		permissions []influxdb.Permission
+	}
+	type wants struct {
+		err error
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "authorized to clone check",
+			fields: fields{
+				CheckService: &mock.CheckService{
+					FindCheckByIDFn: func(ctx context.Context, id influxdb.ID) (influxdb.Check, error) {
+						return &check.Deadman{
+							Base: check.Base{
+								ID:    1,
+								OrgID: 10,
+							},
+						}, nil
+					},
+					CloneCheckFn: func(ctx context.Context, id influxdb.ID, newName string) (influxdb.Check, error) {
+						return &check.Deadman{
+							Base: check.Base{
+								ID:    2,
+								OrgID: 10,
+								Name:  newName,
+							},
+						}, nil
+					},
+				},
+			},
+			args: args{
+				id:   1,
+				name: "clone",
+				permissions: []influxdb.Permission{
+					{
+						Action: "write",
+						Resource: influxdb.Resource{
+							Type: influxdb.OrgsResourceType,
+							ID:   influxdbtesting.IDPtr(10),
+						},
+					},
+					{
+						Action: "read",
+						Resource: influxdb.Resource{
+							Type: influxdb.OrgsResourceType,
+							ID:   influxdbtesting.IDPtr(10),
+						},
+					},
+				},
+			},
+			wants: wants{
+				err: nil,
+			},
+		},
+		{
+			name: "unauthorized to clone check",
+			fields: fields{
+				CheckService: &mock.CheckService{
+					FindCheckByIDFn: func(ctx context.Context, id influxdb.ID) (influxdb.Check, error) {
+						return &check.Deadman{
+							Base: check.Base{
+								ID:    1,
+								OrgID: 10,
+							},
+						}, nil
+					},
+					CloneCheckFn: func(ctx context.Context, id influxdb.ID, newName string) (influxdb.Check, error) {
+						return &check.Deadman{
+							Base: check.Base{
+								ID:    2,
+								OrgID: 10,
+								Name:  newName,
+							},
+						}, nil
+					},
+				},
+			},
+			args: args{
+				id:   1,
+				name: "clone",
+				permissions: []influxdb.Permission{
+					{
+						Action: "read",
+						Resource: influxdb.Resource{
+							Type: influxdb.OrgsResourceType,
+							ID:   influxdbtesting.IDPtr(10),
+						},
+					},
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "write:orgs/000000000000000a is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewCheckService(tt.fields.CheckService, mock.NewUserResourceMappingService(), mock.NewOrganizationService())
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{tt.args.permissions})
+
+			_, err := s.CloneCheck(ctx, tt.args.id, tt.args.name)
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+		})
+	}
+}
+
 func TestCheckService_CreateCheck(t *testing.T) {
 	type fields struct {
 		CheckService influxdb.CheckService
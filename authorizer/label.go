@@ -154,6 +154,48 @@ func (s *LabelService) FindResourceLabels(ctx context.Context, filter influxdb.L
 	return labels, nil
 }
 
+// FindResourceLabelsForResources retrieves the labels of the resources the authorizer on context has read access to.
+// Resources it cannot read are left out, and each list is filtered down to only the labels that are authorized.
+func (s *LabelService) FindResourceLabelsForResources(ctx context.Context, rt influxdb.ResourceType, ids []influxdb.ID) (map[influxdb.ID][]*influxdb.Label, error) {
+	authorized := make([]influxdb.ID, 0, len(ids))
+	for _, id := range ids {
+		err := authorizeLabelMappingAction(ctx, influxdb.ReadAction, id, rt)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		authorized = append(authorized, id)
+	}
+
+	m, err := s.s.FindResourceLabelsForResources(ctx, rt, authorized)
+	if err != nil {
+		return nil, err
+	}
+
+	for id, ls := range m {
+		labels := ls[:0]
+		for _, l := range ls {
+			err := authorizeReadLabel(ctx, l.OrgID, l.ID)
+			if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				return nil, err
+			}
+
+			if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+				continue
+			}
+
+			labels = append(labels, l)
+		}
+		m[id] = labels
+	}
+
+	return m, nil
+}
+
 // CreateLabel checks to see if the authorizer on context has read access to the new label's org.
 func (s *LabelService) CreateLabel(ctx context.Context, l *influxdb.Label) error {
 	if err := authorizeReadOrg(ctx, l.OrgID); err != nil {
@@ -796,6 +796,95 @@ func TestLabelService_FindResourceLabels(t *testing.T) {
 	}
 }
 
+func TestLabelService_FindResourceLabelsForResources(t *testing.T) {
+	type fields struct {
+		LabelService influxdb.LabelService
+	}
+	type args struct {
+		ids         []influxdb.ID
+		permissions []influxdb.Permission
+	}
+	type wants struct {
+		err    error
+		labels map[influxdb.ID][]*influxdb.Label
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "only the labels of authorized resources are returned",
+			fields: fields{
+				LabelService: &mock.LabelService{
+					FindResourceLabelsForResourcesFn: func(ctx context.Context, rt influxdb.ResourceType, ids []influxdb.ID) (map[influxdb.ID][]*influxdb.Label, error) {
+						m := map[influxdb.ID][]*influxdb.Label{}
+						for _, id := range ids {
+							m[id] = []*influxdb.Label{
+								{
+									ID:    1,
+									OrgID: influxdbtesting.MustIDBase16(orgOneID),
+								},
+								{
+									ID:    2,
+									OrgID: influxdbtesting.MustIDBase16(orgOneID),
+								},
+							}
+						}
+						return m, nil
+					},
+				},
+			},
+			args: args{
+				ids: []influxdb.ID{10, 11},
+				permissions: []influxdb.Permission{
+					{
+						Action: "read",
+						Resource: influxdb.Resource{
+							Type: influxdb.LabelsResourceType,
+							ID:   influxdbtesting.IDPtr(2),
+						},
+					},
+					{
+						Action: "read",
+						Resource: influxdb.Resource{
+							Type: influxdb.BucketsResourceType,
+							ID:   influxdbtesting.IDPtr(10),
+						},
+					},
+				},
+			},
+			wants: wants{
+				labels: map[influxdb.ID][]*influxdb.Label{
+					10: {
+						{
+							ID:    2,
+							OrgID: influxdbtesting.MustIDBase16(orgOneID),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewLabelService(tt.fields.LabelService)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{tt.args.permissions})
+
+			labels, err := s.FindResourceLabelsForResources(ctx, influxdb.BucketsResourceType, tt.args.ids)
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(labels, tt.wants.labels, labelCmpOptions...); diff != "" {
+				t.Errorf("labels are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
 func TestLabelService_CreateLabelMapping(t *testing.T) {
 	type fields struct {
 		LabelService influxdb.LabelService
@@ -6,6 +6,8 @@ import (
 	"sort"
 	"testing"
 
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb/notification"
 	"github.com/influxdata/influxdb/notification/rule"
 
 	"github.com/google/go-cmp/cmp"
@@ -106,6 +108,69 @@ func TestNotificationRuleStore_FindNotificationRuleByID(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "authorized to access http rule id",
+			fields: fields{
+				NotificationRuleStore: &mock.NotificationRuleStore{
+					FindNotificationRuleByIDF: func(ctx context.Context, id influxdb.ID) (influxdb.NotificationRule, error) {
+						return &rule.HTTP{
+							Base: rule.Base{
+								ID:    id,
+								OrgID: 10,
+							},
+							URL:        "http://localhost:8888/alerts",
+							AuthSecret: influxdb.SecretField{Key: "http-auth"},
+						}, nil
+					},
+				},
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type: influxdb.OrgsResourceType,
+						ID:   influxdbtesting.IDPtr(10),
+					},
+				},
+				id: 1,
+			},
+			wants: wants{
+				err: nil,
+			},
+		},
+		{
+			name: "unauthorized to access http rule id",
+			fields: fields{
+				NotificationRuleStore: &mock.NotificationRuleStore{
+					FindNotificationRuleByIDF: func(ctx context.Context, id influxdb.ID) (influxdb.NotificationRule, error) {
+						return &rule.HTTP{
+							Base: rule.Base{
+								ID:    id,
+								OrgID: 10,
+							},
+							URL:        "http://localhost:8888/alerts",
+							AuthSecret: influxdb.SecretField{Key: "http-auth"},
+						}, nil
+					},
+				},
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type: influxdb.OrgsResourceType,
+						ID:   influxdbtesting.IDPtr(2),
+					},
+				},
+				id: 1,
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "read:orgs/000000000000000a is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -163,7 +228,13 @@ func TestNotificationRuleStore_FindNotificationRules(t *testing.T) {
 									OrgID: 11,
 								},
 							},
-						}, 3, nil
+							&rule.MSTeams{
+								Base: rule.Base{
+									ID:    4,
+									OrgID: 10,
+								},
+							},
+						}, 4, nil
 					},
 				},
 			},
@@ -195,6 +266,12 @@ func TestNotificationRuleStore_FindNotificationRules(t *testing.T) {
 							OrgID: 11,
 						},
 					},
+					&rule.MSTeams{
+						Base: rule.Base{
+							ID:    4,
+							OrgID: 10,
+						},
+					},
 				},
 			},
 		},
@@ -222,7 +299,13 @@ func TestNotificationRuleStore_FindNotificationRules(t *testing.T) {
 									OrgID: 11,
 								},
 							},
-						}, 3, nil
+							&rule.MSTeams{
+								Base: rule.Base{
+									ID:    4,
+									OrgID: 10,
+								},
+							},
+						}, 4, nil
 					},
 				},
 			},
@@ -249,6 +332,12 @@ func TestNotificationRuleStore_FindNotificationRules(t *testing.T) {
 							OrgID: 10,
 						},
 					},
+					&rule.MSTeams{
+						Base: rule.Base{
+							ID:    4,
+							OrgID: 10,
+						},
+					},
 				},
 			},
 		},
@@ -334,6 +423,51 @@ func TestNotificationRuleStore_UpdateNotificationRule(t *testing.T) {
 				err: nil,
 			},
 		},
+		{
+			name: "authorized to update discord notificationRule",
+			fields: fields{
+				NotificationRuleStore: &mock.NotificationRuleStore{
+					FindNotificationRuleByIDF: func(ctc context.Context, id influxdb.ID) (influxdb.NotificationRule, error) {
+						return &rule.Discord{
+							Base: rule.Base{
+								ID:    1,
+								OrgID: 10,
+							},
+						}, nil
+					},
+					UpdateNotificationRuleF: func(ctx context.Context, id influxdb.ID, upd influxdb.NotificationRule, userID influxdb.ID) (influxdb.NotificationRule, error) {
+						return &rule.Discord{
+							Base: rule.Base{
+								ID:    1,
+								OrgID: 10,
+							},
+						}, nil
+					},
+				},
+			},
+			args: args{
+				id: 1,
+				permissions: []influxdb.Permission{
+					{
+						Action: "write",
+						Resource: influxdb.Resource{
+							Type: influxdb.OrgsResourceType,
+							ID:   influxdbtesting.IDPtr(10),
+						},
+					},
+					{
+						Action: "read",
+						Resource: influxdb.Resource{
+							Type: influxdb.OrgsResourceType,
+							ID:   influxdbtesting.IDPtr(10),
+						},
+					},
+				},
+			},
+			wants: wants{
+				err: nil,
+			},
+		},
 		{
 			name: "unauthorized to update notificationRule",
 			fields: fields{
@@ -390,6 +524,121 @@ func TestNotificationRuleStore_UpdateNotificationRule(t *testing.T) {
 	}
 }
 
+func TestNotificationRuleStore_UpdateNotificationRule_preservesCooldown(t *testing.T) {
+	dur, err := parser.ParseDuration("10m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cooldown := (*notification.Duration)(dur)
+
+	store := &mock.NotificationRuleStore{
+		FindNotificationRuleByIDF: func(ctc context.Context, id influxdb.ID) (influxdb.NotificationRule, error) {
+			return &rule.Slack{
+				Base: rule.Base{
+					ID:    1,
+					OrgID: 10,
+				},
+			}, nil
+		},
+		UpdateNotificationRuleF: func(ctx context.Context, id influxdb.ID, upd influxdb.NotificationRule, userID influxdb.ID) (influxdb.NotificationRule, error) {
+			return upd, nil
+		},
+	}
+	s := authorizer.NewNotificationRuleStore(store, mock.NewUserResourceMappingService(), mock.NewOrganizationService())
+
+	ctx := context.Background()
+	ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{
+		{
+			Action: "write",
+			Resource: influxdb.Resource{
+				Type: influxdb.OrgsResourceType,
+				ID:   influxdbtesting.IDPtr(10),
+			},
+		},
+		{
+			Action: "read",
+			Resource: influxdb.Resource{
+				Type: influxdb.OrgsResourceType,
+				ID:   influxdbtesting.IDPtr(10),
+			},
+		},
+	}})
+
+	upd := &rule.Slack{
+		Base: rule.Base{
+			ID:            1,
+			OrgID:         10,
+			CooldownEvery: cooldown,
+		},
+	}
+	nr, err := s.UpdateNotificationRule(ctx, 1, upd, influxdb.ID(1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := nr.(*rule.Slack).CooldownEvery
+	if diff := cmp.Diff(got, cooldown); diff != "" {
+		t.Errorf("cooldownEvery was not preserved -got/+want\n%s", diff)
+	}
+}
+
+func TestNotificationRuleStore_UpdateNotificationRule_preservesActiveHours(t *testing.T) {
+	activeHours := &rule.ActiveHours{
+		Days:     []string{"Monday", "Tuesday"},
+		Start:    "09:00",
+		End:      "17:00",
+		Timezone: "America/New_York",
+	}
+
+	store := &mock.NotificationRuleStore{
+		FindNotificationRuleByIDF: func(ctc context.Context, id influxdb.ID) (influxdb.NotificationRule, error) {
+			return &rule.Slack{
+				Base: rule.Base{
+					ID:    1,
+					OrgID: 10,
+				},
+			}, nil
+		},
+		UpdateNotificationRuleF: func(ctx context.Context, id influxdb.ID, upd influxdb.NotificationRule, userID influxdb.ID) (influxdb.NotificationRule, error) {
+			return upd, nil
+		},
+	}
+	s := authorizer.NewNotificationRuleStore(store, mock.NewUserResourceMappingService(), mock.NewOrganizationService())
+
+	ctx := context.Background()
+	ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{
+		{
+			Action: "write",
+			Resource: influxdb.Resource{
+				Type: influxdb.OrgsResourceType,
+				ID:   influxdbtesting.IDPtr(10),
+			},
+		},
+		{
+			Action: "read",
+			Resource: influxdb.Resource{
+				Type: influxdb.OrgsResourceType,
+				ID:   influxdbtesting.IDPtr(10),
+			},
+		},
+	}})
+
+	upd := &rule.Slack{
+		Base: rule.Base{
+			ID:          1,
+			OrgID:       10,
+			ActiveHours: activeHours,
+		},
+	}
+	nr, err := s.UpdateNotificationRule(ctx, 1, upd, influxdb.ID(1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := nr.(*rule.Slack).ActiveHours
+	if diff := cmp.Diff(got, activeHours); diff != "" {
+		t.Errorf("activeHours was not preserved -got/+want\n%s", diff)
+	}
+}
+
 func TestNotificationRuleStore_PatchNotificationRule(t *testing.T) {
 	type fields struct {
 		NotificationRuleStore influxdb.NotificationRuleStore
@@ -567,6 +816,46 @@ func TestNotificationRuleStore_DeleteNotificationRule(t *testing.T) {
 				err: nil,
 			},
 		},
+		{
+			name: "authorized to delete discord notificationRule",
+			fields: fields{
+				NotificationRuleStore: &mock.NotificationRuleStore{
+					FindNotificationRuleByIDF: func(ctc context.Context, id influxdb.ID) (influxdb.NotificationRule, error) {
+						return &rule.Discord{
+							Base: rule.Base{
+								ID:    1,
+								OrgID: 10,
+							},
+						}, nil
+					},
+					DeleteNotificationRuleF: func(ctx context.Context, id influxdb.ID) error {
+						return nil
+					},
+				},
+			},
+			args: args{
+				id: 1,
+				permissions: []influxdb.Permission{
+					{
+						Action: "write",
+						Resource: influxdb.Resource{
+							Type: influxdb.OrgsResourceType,
+							ID:   influxdbtesting.IDPtr(10),
+						},
+					},
+					{
+						Action: "read",
+						Resource: influxdb.Resource{
+							Type: influxdb.OrgsResourceType,
+							ID:   influxdbtesting.IDPtr(1),
+						},
+					},
+				},
+			},
+			wants: wants{
+				err: nil,
+			},
+		},
 		{
 			name: "unauthorized to delete notificationRule",
 			fields: fields{
@@ -694,11 +983,14 @@ func TestNotificationRuleStore_CreateNotificationRule(t *testing.T) {
 			ctx := context.Background()
 			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.args.permission}})
 
-			err := s.CreateNotificationRule(ctx, &rule.Slack{
-				Base: rule.Base{
-					OrgID: tt.args.orgID},
-			}, influxdb.ID(1))
-			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+			nrs := []influxdb.NotificationRule{
+				&rule.Slack{Base: rule.Base{OrgID: tt.args.orgID}},
+				&rule.Discord{Base: rule.Base{OrgID: tt.args.orgID}},
+			}
+			for _, nr := range nrs {
+				err := s.CreateNotificationRule(ctx, nr, influxdb.ID(1))
+				influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+			}
 		})
 	}
 }
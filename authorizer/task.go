@@ -113,6 +113,51 @@ func (ts *taskServiceValidator) FindTasks(ctx context.Context, filter influxdb.T
 	return tasks, len(tasks), nil
 }
 
+func (ts *taskServiceValidator) CountTasks(ctx context.Context, filter influxdb.TaskFilter) (int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	auth, err := platcontext.GetAuthorizer(ctx)
+	if err != nil {
+		ts.logger.Info("Failed to retrieve authorizer from context", zap.String("method", "CountTasks"))
+		return 0, err
+	}
+
+	// If the user may read every task of the organization, all of the matching tasks are counted.
+	if filter.OrganizationID != nil {
+		perm, err := influxdb.NewPermission(influxdb.ReadAction, influxdb.TasksResourceType, *filter.OrganizationID)
+		if err == nil && auth.Allowed(*perm) {
+			return ts.TaskService.CountTasks(ctx, filter)
+		}
+	}
+
+	// Otherwise, count the tasks the user is allowed to see a page at a time.
+	filter.After, filter.Before = nil, nil
+	filter.Limit = influxdb.TaskMaxPageSize
+	var n int
+	for {
+		unauthenticatedTasks, _, err := ts.TaskService.FindTasks(ctx, filter)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, t := range unauthenticatedTasks {
+			perm, err := influxdb.NewPermissionAtID(t.ID, influxdb.ReadAction, influxdb.TasksResourceType, t.OrganizationID)
+			if err != nil {
+				continue
+			}
+			if auth.Allowed(*perm) {
+				n++
+			}
+		}
+
+		if len(unauthenticatedTasks) < filter.Limit {
+			return n, nil
+		}
+		filter.After = &unauthenticatedTasks[len(unauthenticatedTasks)-1].ID
+	}
+}
+
 func (ts *taskServiceValidator) CreateTask(ctx context.Context, t influxdb.TaskCreate) (*influxdb.Task, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
@@ -282,7 +327,7 @@ func (ts *taskServiceValidator) CancelRun(ctx context.Context, taskID, runID inf
 	return ts.TaskService.CancelRun(ctx, taskID, runID)
 }
 
-func (ts *taskServiceValidator) RetryRun(ctx context.Context, taskID, runID influxdb.ID) (*influxdb.Run, error) {
+func (ts *taskServiceValidator) CancelTaskRuns(ctx context.Context, taskID influxdb.ID) ([]influxdb.ID, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
@@ -292,25 +337,21 @@ func (ts *taskServiceValidator) RetryRun(ctx context.Context, taskID, runID infl
 		return nil, err
 	}
 
-	if task.Status != string(backend.TaskActive) {
-		return nil, ErrInactiveTask
-	}
-
 	p, err := influxdb.NewPermissionAtID(taskID, influxdb.WriteAction, influxdb.TasksResourceType, task.OrganizationID)
 	if err != nil {
 		return nil, err
 	}
 
 	if err := ts.validatePermission(ctx, *p,
-		zap.String("method", "RetryRun"), zap.Stringer("task_id", taskID), zap.Stringer("run_id", runID),
+		zap.String("method", "CancelTaskRuns"), zap.Stringer("task_id", taskID),
 	); err != nil {
 		return nil, err
 	}
 
-	return ts.TaskService.RetryRun(ctx, taskID, runID)
+	return ts.TaskService.CancelTaskRuns(ctx, taskID)
 }
 
-func (ts *taskServiceValidator) ForceRun(ctx context.Context, taskID influxdb.ID, scheduledFor int64) (*influxdb.Run, error) {
+func (ts *taskServiceValidator) RetryRun(ctx context.Context, taskID, runID influxdb.ID, scheduledFor *int64) (*influxdb.Run, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
@@ -330,14 +371,59 @@ func (ts *taskServiceValidator) ForceRun(ctx context.Context, taskID influxdb.ID
 	}
 
 	if err := ts.validatePermission(ctx, *p,
-		zap.String("method", "ForceRun"), zap.Stringer("task_id", taskID),
+		zap.String("method", "RetryRun"), zap.Stringer("task_id", taskID), zap.Stringer("run_id", runID),
 	); err != nil {
 		return nil, err
 	}
 
+	return ts.TaskService.RetryRun(ctx, taskID, runID, scheduledFor)
+}
+
+func (ts *taskServiceValidator) ForceRun(ctx context.Context, taskID influxdb.ID, scheduledFor int64) (*influxdb.Run, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := ts.validateForceRun(ctx, taskID, "ForceRun"); err != nil {
+		return nil, err
+	}
+
 	return ts.TaskService.ForceRun(ctx, taskID, scheduledFor)
 }
 
+func (ts *taskServiceValidator) ForceRunWithNote(ctx context.Context, taskID influxdb.ID, scheduledFor int64, note string) (*influxdb.Run, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := ts.validateForceRun(ctx, taskID, "ForceRunWithNote"); err != nil {
+		return nil, err
+	}
+
+	return ts.TaskService.ForceRunWithNote(ctx, taskID, scheduledFor, note)
+}
+
+// validateForceRun returns an error if the task is not active or the caller
+// is not allowed to write it.
+func (ts *taskServiceValidator) validateForceRun(ctx context.Context, taskID influxdb.ID, method string) error {
+	// Unauthenticated task lookup, to identify the task's organization.
+	task, err := ts.TaskService.FindTaskByID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	if task.Status != string(backend.TaskActive) {
+		return ErrInactiveTask
+	}
+
+	p, err := influxdb.NewPermissionAtID(taskID, influxdb.WriteAction, influxdb.TasksResourceType, task.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	return ts.validatePermission(ctx, *p,
+		zap.String("method", method), zap.Stringer("task_id", taskID),
+	)
+}
+
 func (ts *taskServiceValidator) validatePermission(ctx context.Context, perm influxdb.Permission, loggerFields ...zap.Field) error {
 	auth, err := platcontext.GetAuthorizer(ctx)
 	if err != nil {
@@ -105,7 +105,7 @@ from(bucket:"holder") |> range(start:-5m) |> to(bucket:"holder", org:"thing")`,
 		CancelRunFn: func(context.Context, influxdb.ID, influxdb.ID) error {
 			return nil
 		},
-		RetryRunFn: func(context.Context, influxdb.ID, influxdb.ID) (*influxdb.Run, error) {
+		RetryRunFn: func(context.Context, influxdb.ID, influxdb.ID, *int64) (*influxdb.Run, error) {
 			return &run, nil
 		},
 		ForceRunFn: func(context.Context, influxdb.ID, int64) (*influxdb.Run, error) {
@@ -632,7 +632,7 @@ from(bucket:"cows") |> range(start:-5m) |> to(bucket:"other_bucket", org:"other_
 			name: "RetryRun with bad auth",
 			auth: &influxdb.Authorization{Status: "active", Permissions: wrongOrgReadAllTaskPermissions},
 			check: func(ctx context.Context, svc influxdb.TaskService) error {
-				_, err := svc.RetryRun(ctx, taskID, 10)
+				_, err := svc.RetryRun(ctx, taskID, 10, nil)
 				if err == nil {
 					return errors.New("returned no error with a invalid auth")
 				}
@@ -643,7 +643,7 @@ from(bucket:"cows") |> range(start:-5m) |> to(bucket:"other_bucket", org:"other_
 			name: "RetryRun with org auth",
 			auth: &influxdb.Authorization{Status: "active", Permissions: orgWriteAllTaskPermissions},
 			check: func(ctx context.Context, svc influxdb.TaskService) error {
-				_, err := svc.RetryRun(ctx, taskID, 10)
+				_, err := svc.RetryRun(ctx, taskID, 10, nil)
 				return err
 			},
 		},
@@ -651,7 +651,7 @@ from(bucket:"cows") |> range(start:-5m) |> to(bucket:"other_bucket", org:"other_
 			name: "RetryRun with task auth",
 			auth: &influxdb.Authorization{Status: "active", Permissions: orgWriteTaskPermissions},
 			check: func(ctx context.Context, svc influxdb.TaskService) error {
-				_, err := svc.RetryRun(ctx, taskID, 10)
+				_, err := svc.RetryRun(ctx, taskID, 10, nil)
 				return err
 			},
 		},
@@ -696,3 +696,72 @@ from(bucket:"cows") |> range(start:-5m) |> to(bucket:"other_bucket", org:"other_
 		})
 	}
 }
+
+func TestCountTasks(t *testing.T) {
+	const total = influxdb.TaskMaxPageSize + 2
+	orgID := influxdb.ID(1)
+
+	var counted bool
+	ts := authorizer.NewTaskService(zaptest.NewLogger(t), &mock.TaskService{
+		FindTasksFn: func(_ context.Context, f influxdb.TaskFilter) ([]*influxdb.Task, int, error) {
+			start := 1
+			if f.After != nil {
+				start = int(*f.After) + 1
+			}
+			var tasks []*influxdb.Task
+			for i := start; i <= total && len(tasks) < f.Limit; i++ {
+				tasks = append(tasks, &influxdb.Task{ID: influxdb.ID(i), OrganizationID: orgID})
+			}
+			return tasks, len(tasks), nil
+		},
+		CountTasksFn: func(context.Context, influxdb.TaskFilter) (int, error) {
+			counted = true
+			return total, nil
+		},
+	}, inmem.NewService())
+
+	firstID, lastID := influxdb.ID(2), influxdb.ID(total)
+	for _, tt := range []struct {
+		name        string
+		permissions []influxdb.Permission
+		exp         int
+		expCounted  bool
+	}{
+		{
+			name: "read all tasks in org",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.TasksResourceType, OrgID: &orgID}},
+			},
+			exp:        total,
+			expCounted: true,
+		},
+		{
+			name: "read specific tasks",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.TasksResourceType, OrgID: &orgID, ID: &firstID}},
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.TasksResourceType, OrgID: &orgID, ID: &lastID}},
+			},
+			exp: 2,
+		},
+		{
+			name: "no permissions",
+			exp:  0,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			counted = false
+			ctx := pctx.SetAuthorizer(context.Background(), &influxdb.Authorization{Status: influxdb.Active, Permissions: tt.permissions})
+
+			n, err := ts.CountTasks(ctx, influxdb.TaskFilter{OrganizationID: &orgID, Limit: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != tt.exp {
+				t.Errorf("expected %d tasks, got %d", tt.exp, n)
+			}
+			if counted != tt.expCounted {
+				t.Errorf("expected the task service to count the tasks to be %t", tt.expCounted)
+			}
+		})
+	}
+}
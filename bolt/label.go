@@ -182,6 +182,20 @@ func (c *Client) FindResourceLabels(ctx context.Context, filter influxdb.LabelMa
 	return ls, nil
 }
 
+// FindResourceLabelsForResources returns the labels of each of the resources.
+func (c *Client) FindResourceLabelsForResources(ctx context.Context, rt influxdb.ResourceType, ids []influxdb.ID) (map[influxdb.ID][]*influxdb.Label, error) {
+	m := make(map[influxdb.ID][]*influxdb.Label, len(ids))
+	for _, id := range ids {
+		ls, err := c.FindResourceLabels(ctx, influxdb.LabelMappingFilter{ResourceID: id, ResourceType: rt})
+		if err != nil {
+			return nil, err
+		}
+		m[id] = ls
+	}
+
+	return m, nil
+}
+
 // CreateLabelMapping creates a new mapping between a resource and a label.
 func (c *Client) CreateLabelMapping(ctx context.Context, m *influxdb.LabelMapping) error {
 	_, err := c.FindLabelByID(ctx, m.LabelID)
@@ -34,6 +34,7 @@ var (
 	OpCreateCheck   = "CreateCheck"
 	OpUpdateCheck   = "UpdateCheck"
 	OpDeleteCheck   = "DeleteCheck"
+	OpCloneCheck    = "CloneCheck"
 )
 
 // CheckService represents a service for managing checks.
@@ -47,7 +48,8 @@ type CheckService interface {
 	// FindCheckByID returns a single check by ID.
 	FindCheckByID(ctx context.Context, id ID) (Check, error)
 
-	// FindCheck returns the first check that matches filter.
+	// FindCheck returns the first check that matches filter, e.g. by Name within an OrgID.
+	// Returns ENotFound if no check matches.
 	FindCheck(ctx context.Context, filter CheckFilter) (Check, error)
 
 	// FindChecks returns a list of checks that match filter and the total count of matching checkns.
@@ -67,6 +69,10 @@ type CheckService interface {
 
 	// DeleteCheck will delete the check by id.
 	DeleteCheck(ctx context.Context, id ID) error
+
+	// CloneCheck creates a copy of the check with a new ID and the provided name.
+	// The clone is created inactive.
+	CloneCheck(ctx context.Context, id ID, newName string) (Check, error)
 }
 
 // CheckUpdate are properties than can be updated on a check
@@ -107,6 +113,10 @@ type CheckFilter struct {
 	Name  *string
 	OrgID *ID
 	Org   *string
+	// IDs restricts the checks found to those with one of the ids, if not empty.
+	IDs []ID
+	// Type restricts the checks found to those of the type, i.e. "deadman".
+	Type *string
 }
 
 // QueryParams Converts CheckFilter fields to url query params.
@@ -129,5 +139,9 @@ func (f CheckFilter) QueryParams() map[string][]string {
 		qp["org"] = []string{*f.Org}
 	}
 
+	if f.Type != nil {
+		qp["type"] = []string{*f.Type}
+	}
+
 	return qp
 }
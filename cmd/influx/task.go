@@ -580,7 +580,7 @@ func runRetryF(cmd *cobra.Command, args []string) error {
 	}
 
 	ctx := context.TODO()
-	newRun, err := s.RetryRun(ctx, taskID, runID)
+	newRun, err := s.RetryRun(ctx, taskID, runID, nil)
 	if err != nil {
 		return err
 	}
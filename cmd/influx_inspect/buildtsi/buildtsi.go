@@ -2,11 +2,15 @@
 package buildtsi
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"hash/crc32"
 	"io"
 	"io/ioutil"
+	"math"
 	"os"
 	"os/user"
 	"path/filepath"
@@ -14,7 +18,9 @@ import (
 	"strconv"
 	"strings"
 	"sync/atomic"
+	"time"
 
+	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/logger"
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/pkg/fs"
@@ -29,6 +35,8 @@ import (
 
 const defaultBatchSize = 10000
 
+const defaultLogEvery = 100000
+
 // Command represents the program execution for "influx_inspect buildtsi".
 type Command struct {
 	Stderr  io.Writer
@@ -40,24 +48,42 @@ type Command struct {
 	databaseFilter  string
 	retentionFilter string
 	shardFilter     string
+	shardMin        uint64
+	shardMax        uint64
 	maxLogFileSize  int64
 	maxCacheSize    uint64
 	batchSize       int
+	logEvery        int
+	skipErrors      bool
+	verify          bool
+	orgFilter       string
+	bucketFilter    string
+	namePrefix      []byte // Prefix of the series keys to index, set from orgFilter and bucketFilter.
+	seriesDir       string // Series file directory, relative to the database directory unless absolute.
+
+	// indexShard rebuilds the index of a shard. It can be replaced by tests.
+	indexShard func(ctx context.Context, sfile *tsdb.SeriesFile, s shard, log *zap.Logger) error
 }
 
 // NewCommand returns a new instance of Command.
 func NewCommand() *Command {
-	return &Command{
+	cmd := &Command{
 		Stderr:      os.Stderr,
 		Stdout:      os.Stdout,
 		Logger:      zap.NewNop(),
 		batchSize:   defaultBatchSize,
+		logEvery:    defaultLogEvery,
+		shardMax:    math.MaxUint64,
 		concurrency: runtime.GOMAXPROCS(0),
+		seriesDir:   storage.DefaultSeriesFileDirectoryName,
 	}
+	cmd.indexShard = cmd.rebuildShard
+	return cmd
 }
 
-// Run executes the command.
-func (cmd *Command) Run(args ...string) error {
+// Run executes the command. Canceling ctx stops the rebuild and removes the
+// partial index of the shards being indexed.
+func (cmd *Command) Run(ctx context.Context, args ...string) error {
 	fs := flag.NewFlagSet("buildtsi", flag.ExitOnError)
 	dataDir := fs.String("datadir", "", "data directory")
 	walDir := fs.String("waldir", "", "WAL directory")
@@ -65,9 +91,17 @@ func (cmd *Command) Run(args ...string) error {
 	fs.StringVar(&cmd.databaseFilter, "database", "", "optional: database name")
 	fs.StringVar(&cmd.retentionFilter, "retention", "", "optional: retention policy")
 	fs.StringVar(&cmd.shardFilter, "shard", "", "optional: shard id")
+	fs.Uint64Var(&cmd.shardMin, "shard-min", 0, "optional: minimum shard id, inclusive")
+	fs.Uint64Var(&cmd.shardMax, "shard-max", math.MaxUint64, "optional: maximum shard id, inclusive")
+	fs.StringVar(&cmd.orgFilter, "org", "", "optional: only add the series of this organization id to the existing index, keeping the series of others")
+	fs.StringVar(&cmd.bucketFilter, "bucket", "", "optional: only index the series of this bucket id. Requires -org")
 	fs.Int64Var(&cmd.maxLogFileSize, "max-log-file-size", tsi1.DefaultMaxIndexLogFileSize, "optional: maximum log file size")
 	fs.Uint64Var(&cmd.maxCacheSize, "max-cache-size", uint64(tsm1.DefaultCacheMaxMemorySize), "optional: maximum cache size")
 	fs.IntVar(&cmd.batchSize, "batch-size", defaultBatchSize, "optional: set the size of the batches we write to the index. Setting this can have adverse affects on performance and heap requirements")
+	fs.IntVar(&cmd.logEvery, "log-every", defaultLogEvery, "optional: log progress every time this many series have been indexed in a shard. Set to 0 to disable")
+	fs.StringVar(&cmd.seriesDir, "series-dir", storage.DefaultSeriesFileDirectoryName, "optional: series file directory, relative to the database directory unless absolute")
+	fs.BoolVar(&cmd.skipErrors, "skip-errors", false, "optional: skip tsm files that cannot be indexed instead of aborting, and report them once the shard is rebuilt")
+	fs.BoolVar(&cmd.verify, "verify", false, "optional: verify the block checksums of each tsm file before indexing it. Files that fail are skipped with -skip-errors")
 	fs.BoolVar(&cmd.Verbose, "v", false, "verbose")
 	fs.SetOutput(cmd.Stdout)
 	if err := fs.Parse(args); err != nil {
@@ -76,12 +110,24 @@ func (cmd *Command) Run(args ...string) error {
 		fs.Usage()
 		return nil
 	}
+
+	if cmd.shardFilter != "" && (cmd.shardMin != 0 || cmd.shardMax != math.MaxUint64) {
+		return errors.New("-shard cannot be used with -shard-min or -shard-max")
+	} else if cmd.shardMin > cmd.shardMax {
+		return fmt.Errorf("-shard-min %d is larger than -shard-max %d", cmd.shardMin, cmd.shardMax)
+	}
+
+	prefix, err := namePrefix(cmd.orgFilter, cmd.bucketFilter)
+	if err != nil {
+		return err
+	}
+	cmd.namePrefix = prefix
 	cmd.Logger = logger.New(cmd.Stderr)
 
-	return cmd.run(*dataDir, *walDir)
+	return cmd.run(ctx, *dataDir, *walDir)
 }
 
-func (cmd *Command) run(dataDir, walDir string) error {
+func (cmd *Command) run(ctx context.Context, dataDir, walDir string) error {
 	// Verify the user actually wants to run as root.
 	if isRoot() {
 		fmt.Println("You are currently running as root. This will build your")
@@ -108,7 +154,7 @@ func (cmd *Command) run(dataDir, walDir string) error {
 			continue
 		}
 
-		if err := cmd.processDatabase(name, filepath.Join(dataDir, name), filepath.Join(walDir, name)); err != nil {
+		if err := cmd.processDatabase(ctx, name, filepath.Join(dataDir, name), filepath.Join(walDir, name)); err != nil {
 			return err
 		}
 	}
@@ -116,12 +162,17 @@ func (cmd *Command) run(dataDir, walDir string) error {
 	return nil
 }
 
-func (cmd *Command) processDatabase(dbName, dataDir, walDir string) error {
+func (cmd *Command) processDatabase(ctx context.Context, dbName, dataDir, walDir string) error {
 	cmd.Logger.Info("Rebuilding database", zap.String("name", dbName))
 
-	sfile := tsdb.NewSeriesFile(filepath.Join(dataDir, storage.DefaultSeriesFileDirectoryName))
+	seriesPath := filepath.Clean(cmd.seriesDir)
+	if !filepath.IsAbs(seriesPath) {
+		seriesPath = filepath.Join(dataDir, seriesPath)
+	}
+
+	sfile := tsdb.NewSeriesFile(seriesPath)
 	sfile.Logger = cmd.Logger
-	if err := sfile.Open(context.Background()); err != nil {
+	if err := sfile.Open(ctx); err != nil {
 		return err
 	}
 	defer sfile.Close()
@@ -131,39 +182,97 @@ func (cmd *Command) processDatabase(dbName, dataDir, walDir string) error {
 		return err
 	}
 
+	// Gather the shards of every retention policy so that they are all indexed
+	// by the same pool of workers.
+	var shards []shard
 	for _, fi := range fis {
 		rpName := fi.Name()
 		if !fi.IsDir() {
 			continue
-		} else if rpName == storage.DefaultSeriesFileDirectoryName {
+		} else if rpName == storage.DefaultSeriesFileDirectoryName || filepath.Join(dataDir, rpName) == seriesPath {
 			continue
 		} else if cmd.retentionFilter != "" && rpName != cmd.retentionFilter {
 			continue
 		}
 
-		if err := cmd.processRetentionPolicy(sfile, dbName, rpName, filepath.Join(dataDir, rpName), filepath.Join(walDir, rpName)); err != nil {
+		rpShards, err := cmd.collectShards(dbName, rpName, filepath.Join(dataDir, rpName), filepath.Join(walDir, rpName))
+		if err != nil {
 			return err
 		}
+		shards = append(shards, rpShards...)
 	}
 
+	errC := make(chan error, len(shards))
+	var maxi uint32 // index of maximum shard being worked on.
+	for k := 0; k < cmd.concurrency; k++ {
+		go func() {
+			for {
+				i := int(atomic.AddUint32(&maxi, 1) - 1) // Get next partition to work on.
+				if i >= len(shards) {
+					return // No more work.
+				}
+
+				log := cmd.Logger.With(logger.Database(dbName), logger.RetentionPolicy(shards[i].RP), logger.Shard(shards[i].ID))
+				errC <- cmd.indexShard(ctx, sfile, shards[i], log)
+			}
+		}()
+	}
+
+	// Check for error
+	for i := 0; i < cap(errC); i++ {
+		if err := <-errC; err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (cmd *Command) processRetentionPolicy(sfile *tsdb.SeriesFile, dbName, rpName, dataDir, walDir string) error {
-	cmd.Logger.Info("Rebuilding retention policy", logger.Database(dbName), logger.RetentionPolicy(rpName))
+// namePrefix returns the prefix of the series keys belonging to the org and
+// bucket with the provided ids. A nil prefix is returned if no org is given.
+func namePrefix(org, bucket string) ([]byte, error) {
+	if org == "" {
+		if bucket != "" {
+			return nil, errors.New("-bucket requires -org")
+		}
+		return nil, nil
+	}
 
-	fis, err := ioutil.ReadDir(dataDir)
+	orgID, err := platform.IDFromString(org)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("invalid org id %q: %v", org, err)
+	}
+	if bucket == "" {
+		encoded := tsdb.EncodeOrgName(*orgID)
+		return models.EscapeMeasurement(encoded[:]), nil
 	}
 
-	type shard struct {
-		ID   uint64
-		Path string
+	bucketID, err := platform.IDFromString(bucket)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bucket id %q: %v", bucket, err)
 	}
+	encoded := tsdb.EncodeName(*orgID, *bucketID)
+	return models.EscapeMeasurement(encoded[:]), nil
+}
 
-	var shards []shard
+// shard describes a shard of a retention policy to be indexed.
+type shard struct {
+	ID     uint64
+	Path   string
+	RP     string
+	RPDir  string // Data directory of the retention policy.
+	WALDir string // WAL directory of the retention policy.
+}
+
+// collectShards returns the shards of the retention policy that match the filters.
+func (cmd *Command) collectShards(dbName, rpName, dataDir, walDir string) ([]shard, error) {
+	cmd.Logger.Info("Rebuilding retention policy", logger.Database(dbName), logger.RetentionPolicy(rpName))
 
+	fis, err := ioutil.ReadDir(dataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var shards []shard
 	for _, fi := range fis {
 		if !fi.IsDir() {
 			continue
@@ -174,88 +283,274 @@ func (cmd *Command) processRetentionPolicy(sfile *tsdb.SeriesFile, dbName, rpNam
 		shardID, err := strconv.ParseUint(fi.Name(), 10, 64)
 		if err != nil {
 			continue
+		} else if shardID < cmd.shardMin || shardID > cmd.shardMax {
+			continue
 		}
 
-		shards = append(shards, shard{shardID, fi.Name()})
+		shards = append(shards, shard{
+			ID:     shardID,
+			Path:   fi.Name(),
+			RP:     rpName,
+			RPDir:  dataDir,
+			WALDir: walDir,
+		})
 	}
+	return shards, nil
+}
 
-	errC := make(chan error, len(shards))
-	var maxi uint32 // index of maximum shard being worked on.
-	for k := 0; k < cmd.concurrency; k++ {
-		go func() {
-			for {
-				i := int(atomic.AddUint32(&maxi, 1) - 1) // Get next partition to work on.
-				if i >= len(shards) {
-					return // No more work.
-				}
+// rebuildShard rebuilds the index of a single shard.
+func (cmd *Command) rebuildShard(ctx context.Context, sfile *tsdb.SeriesFile, s shard, log *zap.Logger) error {
+	return IndexShard(ctx, sfile, filepath.Join(s.RPDir, "index"), filepath.Join(s.RPDir, s.Path), filepath.Join(s.WALDir, s.Path), cmd.namePrefix, cmd.maxLogFileSize, cmd.maxCacheSize, cmd.batchSize, cmd.logEvery, cmd.skipErrors, cmd.verify, log, cmd.Verbose)
+}
 
-				id, name := shards[i].ID, shards[i].Path
-				log := cmd.Logger.With(logger.Database(dbName), logger.RetentionPolicy(rpName), logger.Shard(id))
-				errC <- IndexShard(sfile, filepath.Join(dataDir, "index"), filepath.Join(dataDir, name), filepath.Join(walDir, name), cmd.maxLogFileSize, cmd.maxCacheSize, cmd.batchSize, log, cmd.Verbose)
-			}
-		}()
-	}
+// IndexProgress counts the series indexed in a shard, periodically logging
+// how many have been indexed so far.
+type IndexProgress struct {
+	log      *zap.Logger
+	logEvery int
+	start    time.Time
+	n        int
+}
 
-	// Check for error
-	for i := 0; i < cap(errC); i++ {
-		if err := <-errC; err != nil {
-			return err
-		}
+// NewIndexProgress returns an IndexProgress that logs to log every time
+// another logEvery series have been indexed. A logEvery of 0 disables logging.
+func NewIndexProgress(log *zap.Logger, logEvery int) *IndexProgress {
+	return &IndexProgress{log: log, logEvery: logEvery, start: time.Now()}
+}
+
+// Add records that another n series have been indexed.
+func (p *IndexProgress) Add(n int) {
+	before := p.n
+	p.n += n
+	if p.logEvery > 0 && p.n/p.logEvery > before/p.logEvery {
+		p.log.Info("Indexed series", zap.Int("series", p.n), zap.Duration("elapsed", time.Since(p.start)))
 	}
-	return nil
 }
 
-func IndexShard(sfile *tsdb.SeriesFile, indexPath, dataDir, walDir string, maxLogFileSize int64, maxCacheSize uint64, batchSize int, log *zap.Logger, verboseLogging bool) error {
+// N returns the number of series indexed.
+func (p *IndexProgress) N() int { return p.n }
+
+// SkippedFilesError is returned by IndexShard when TSM files were skipped
+// because they could not be indexed.
+type SkippedFilesError struct {
+	Paths []string
+}
+
+func (e *SkippedFilesError) Error() string {
+	return fmt.Sprintf("skipped %d tsm files that could not be indexed: %s", len(e.Paths), strings.Join(e.Paths, ", "))
+}
+
+// unreadableFileError is returned by IndexTSMFile when a TSM file cannot be opened.
+type unreadableFileError struct {
+	err error
+}
+
+func (e unreadableFileError) Error() string { return e.err.Error() }
+
+// IndexShard rebuilds the TSI index of a shard from its TSM and WAL files. TSM files
+// that cannot be opened are skipped. If skipErrors is set, TSM files that fail part
+// way through being indexed are skipped too, and a *SkippedFilesError listing all of
+// the skipped files is returned once the rest of the shard has been indexed. If verify
+// is set, the block checksums of each TSM file are verified before it is indexed. If
+// ctx is canceled, the error of ctx is returned and the partial index is removed. If
+// a previous run failed or died part way through, the TSM files it already processed
+// are not indexed again, and those it skipped are still reported.
+//
+// If prefix is not empty, only the series with keys starting with it are indexed, and
+// they are added to the existing index at indexPath rather than to a new one, so that
+// the series of other orgs and buckets remain indexed. An error is returned if there
+// is no existing index.
+func IndexShard(ctx context.Context, sfile *tsdb.SeriesFile, indexPath, dataDir, walDir string, prefix []byte, maxLogFileSize int64, maxCacheSize uint64, batchSize, logEvery int, skipErrors, verify bool, log *zap.Logger, verboseLogging bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	log.Info("Rebuilding shard")
 
 	// Check if shard already has a TSI index.
 	log.Info("Checking index path", zap.String("path", indexPath))
-	if _, err := os.Stat(indexPath); !os.IsNotExist(err) {
+	_, err := os.Stat(indexPath)
+	exists := !os.IsNotExist(err)
+	if len(prefix) > 0 {
+		// An index built from the series of a single org or bucket would drop
+		// those of every other, so they can only be added to an existing index.
+		if !exists {
+			return fmt.Errorf("no tsi1 index to add the series of the org or bucket to, rebuild the whole index first: %s", indexPath)
+		}
+		return mergeShard(ctx, sfile, indexPath, dataDir, walDir, prefix, maxLogFileSize, batchSize, logEvery, skipErrors, verify, log, verboseLogging)
+	}
+	if exists {
 		log.Info("tsi1 index already exists, skipping", zap.String("path", indexPath))
 		return nil
 	}
 
 	log.Info("Opening shard")
 
-	// Remove temporary index files if this is being re-run.
+	// Remove temporary index files if this is being re-run, unless the previous
+	// run left a progress marker to resume from.
 	tmpPath := filepath.Join(dataDir, ".index")
-	log.Info("Cleaning up partial index from previous run, if any")
-	if err := os.RemoveAll(tmpPath); err != nil {
+	progressPath := filepath.Join(tmpPath, progressFileName)
+	resume, err := readProgress(progressPath)
+	if err != nil {
 		return err
 	}
+	if resume.last != "" {
+		log.Info("Resuming partial index from previous run", zap.String("after", resume.last))
+	} else {
+		log.Info("Cleaning up partial index from previous run, if any")
+		if err := os.RemoveAll(tmpPath); err != nil {
+			return err
+		}
+	}
 
 	// Open TSI index in temporary path.
+	tsiIndex := newRebuildIndex(sfile, tmpPath, maxLogFileSize, batchSize, log, tsi1.DisableFsync())
+
+	log.Info("Opening tsi index in temporary location", zap.String("path", tmpPath))
+	if err := tsiIndex.Open(ctx); err != nil {
+		return err
+	}
+	defer tsiIndex.Close()
+
+	// Remove the partial index, along with its progress marker, if the rebuild
+	// is canceled. Only a rebuild that fails or dies is resumed.
+	defer func() {
+		if ctx.Err() == nil {
+			return
+		}
+		tsiIndex.Close()
+		log.Info("Rebuild canceled, removing partial index", zap.String("path", tmpPath))
+		os.RemoveAll(tmpPath)
+	}()
+
+	skipped, err := indexShardFiles(ctx, tsiIndex, dataDir, walDir, nil, batchSize, logEvery, skipErrors, verify, resume, progressPath, log, verboseLogging)
+	if err != nil {
+		return err
+	}
+
+	// Attempt to compact the index & wait for all compactions to complete.
+	log.Info("compacting index")
+	tsiIndex.Compact()
+	tsiIndex.Wait()
+
+	// Close TSI index.
+	log.Info("Closing tsi index")
+	if err := tsiIndex.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Remove(progressPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	// Rename TSI to standard path.
+	log.Info("Moving tsi to permanent location")
+	if err := fs.RenameFile(tmpPath, indexPath); err != nil {
+		return err
+	}
+
+	if skipErrors && len(skipped) > 0 {
+		return &SkippedFilesError{Paths: skipped}
+	}
+	return nil
+}
+
+// mergeShard adds the series of the shard with keys starting with prefix to the
+// existing index at indexPath. Series already in the index are left as they are.
+func mergeShard(ctx context.Context, sfile *tsdb.SeriesFile, indexPath, dataDir, walDir string, prefix []byte, maxLogFileSize int64, batchSize, logEvery int, skipErrors, verify bool, log *zap.Logger, verboseLogging bool) error {
+	tsiIndex := newRebuildIndex(sfile, indexPath, maxLogFileSize, batchSize, log)
+
+	log.Info("Opening existing tsi index to add series to", zap.String("path", indexPath))
+	if err := tsiIndex.Open(ctx); err != nil {
+		return err
+	}
+	defer tsiIndex.Close()
+
+	skipped, err := indexShardFiles(ctx, tsiIndex, dataDir, walDir, prefix, batchSize, logEvery, skipErrors, verify, shardProgress{}, "", log, verboseLogging)
+	if err != nil {
+		return err
+	}
+
+	log.Info("compacting index")
+	tsiIndex.Compact()
+	tsiIndex.Wait()
+
+	log.Info("Closing tsi index")
+	if err := tsiIndex.Close(); err != nil {
+		return err
+	}
+
+	if skipErrors && len(skipped) > 0 {
+		return &SkippedFilesError{Paths: skipped}
+	}
+	return nil
+}
+
+// newRebuildIndex returns a TSI index at path configured for bulk loading series.
+func newRebuildIndex(sfile *tsdb.SeriesFile, path string, maxLogFileSize int64, batchSize int, log *zap.Logger, options ...tsi1.IndexOption) *tsi1.Index {
 	c := tsi1.NewConfig()
 	c.MaxIndexLogFileSize = toml.Size(maxLogFileSize)
 
-	tsiIndex := tsi1.NewIndex(sfile, c,
-		tsi1.WithPath(tmpPath),
-		tsi1.DisableFsync(),
+	options = append([]tsi1.IndexOption{
+		tsi1.WithPath(path),
 		// Each new series entry in a log file is ~12 bytes so this should
 		// roughly equate to one flush to the file for every batch.
-		tsi1.WithLogFileBufferSize(12*batchSize),
+		tsi1.WithLogFileBufferSize(12 * batchSize),
 		tsi1.DisableMetrics(), // Disable metrics when rebuilding an index
-	)
+	}, options...)
+	tsiIndex := tsi1.NewIndex(sfile, c, options...)
 	tsiIndex.WithLogger(log)
+	return tsiIndex
+}
 
-	log.Info("Opening tsi index in temporary location", zap.String("path", tmpPath))
-	if err := tsiIndex.Open(context.Background()); err != nil {
-		return err
-	}
-	defer tsiIndex.Close()
-
+// indexShardFiles adds the series of the TSM and WAL files of a shard with keys
+// starting with prefix to index, and returns the TSM files that were skipped. TSM
+// files named up to the last one recorded by resume are not indexed again, and the
+// files it records as skipped are returned as well. If progressPath is not empty,
+// the progress marker there is updated after each TSM file is processed.
+func indexShardFiles(ctx context.Context, tsiIndex *tsi1.Index, dataDir, walDir string, prefix []byte, batchSize, logEvery int, skipErrors, verify bool, resume shardProgress, progressPath string, log *zap.Logger, verboseLogging bool) ([]string, error) {
 	// Write out tsm1 files.
 	// Find shard files.
 	tsmPaths, err := collectTSMFiles(dataDir)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	progress := NewIndexProgress(log, logEvery)
+
+	skipped := resume.skipped
+
 	log.Info("Iterating over tsm files")
 	for _, path := range tsmPaths {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if filepath.Base(path) <= resume.last {
+			log.Info("Already indexed by previous run, skipping", zap.String("path", path))
+			continue
+		}
+
 		log.Info("Processing tsm file", zap.String("path", path))
-		if err := IndexTSMFile(tsiIndex, path, batchSize, log, verboseLogging); err != nil {
-			return err
+		err := IndexTSMFile(ctx, tsiIndex, path, prefix, batchSize, verify, progress, log, verboseLogging)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		} else if _, ok := err.(unreadableFileError); ok {
+			log.Warn("Unable to read, skipping", zap.String("path", path), zap.Error(err))
+			skipped = append(skipped, path)
+		} else if err != nil {
+			if !skipErrors {
+				return nil, err
+			}
+			log.Warn("Unable to index, skipping", zap.String("path", path), zap.Error(err))
+			skipped = append(skipped, path)
+		}
+
+		if progressPath != "" {
+			p := shardProgress{last: filepath.Base(path), skipped: skipped}
+			if err := writeProgress(tsiIndex, progressPath, p); err != nil {
+				return nil, err
+			}
 		}
 	}
 
@@ -263,16 +558,16 @@ func IndexShard(sfile *tsdb.SeriesFile, indexPath, dataDir, walDir string, maxLo
 	walPaths, err := collectWALFiles(walDir)
 	if err != nil {
 		if !os.IsNotExist(err) {
-			return err
+			return nil, err
 		}
 
 	} else {
 		log.Info("Building cache from wal files")
 		cache := tsm1.NewCache(uint64(tsm1.DefaultCacheMaxMemorySize))
-		loader := tsm1.NewCacheLoader(walPaths)
+		loader := tsm1.NewCacheLoader(walPaths, tsm1.WithCacheLoaderKeyPrefix(prefix))
 		loader.WithLogger(log)
-		if err := loader.Load(cache); err != nil {
-			return err
+		if _, err := loader.Load(cache); err != nil {
+			return nil, err
 		}
 
 		log.Info("Iterating over cache")
@@ -283,7 +578,11 @@ func IndexShard(sfile *tsdb.SeriesFile, indexPath, dataDir, walDir string, maxLo
 			Types: make([]models.FieldType, 0, batchSize),
 		}
 
-		for _, key := range cache.Keys() {
+		cache.ForEachKey(func(key []byte) bool {
+			if !bytes.HasPrefix(key, prefix) {
+				return true
+			}
+
 			seriesKey, _ := tsm1.SeriesAndFieldFromCompositeKey(key)
 			name, tags := models.ParseKeyBytes(seriesKey)
 			typ, _ := cache.Type(key)
@@ -296,42 +595,116 @@ func IndexShard(sfile *tsdb.SeriesFile, indexPath, dataDir, walDir string, maxLo
 			collection.Names = append(collection.Names, name)
 			collection.Tags = append(collection.Tags, tags)
 			collection.Types = append(collection.Types, typ)
+			progress.Add(1)
 
 			// Flush batch?
 			if collection.Length() == batchSize {
-				if err := tsiIndex.CreateSeriesListIfNotExists(collection); err != nil {
-					return fmt.Errorf("problem creating series: (%s)", err)
+				if err = ctx.Err(); err != nil {
+					return false
+				}
+				if err = tsiIndex.CreateSeriesListIfNotExists(collection); err != nil {
+					err = fmt.Errorf("problem creating series: (%s)", err)
+					return false
 				}
 				collection.Truncate(0)
 			}
+			return true
+		})
+		if err != nil {
+			return nil, err
 		}
 
 		// Flush any remaining series in the batches
 		if collection.Length() > 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
 			if err := tsiIndex.CreateSeriesListIfNotExists(collection); err != nil {
-				return fmt.Errorf("problem creating series: (%s)", err)
+				return nil, fmt.Errorf("problem creating series: (%s)", err)
 			}
 			collection = nil
 		}
 	}
 
-	// Attempt to compact the index & wait for all compactions to complete.
-	log.Info("compacting index")
-	tsiIndex.Compact()
-	tsiIndex.Wait()
+	log.Info("Indexed shard", zap.Int("series", progress.N()), zap.Duration("elapsed", time.Since(progress.start)))
 
-	// Close TSI index.
-	log.Info("Closing tsi index")
-	if err := tsiIndex.Close(); err != nil {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return skipped, nil
+}
+
+// progressFileName is the name of the file in a partial index that records the
+// last TSM file processed, so that an interrupted rebuild can resume after it.
+const progressFileName = ".progress"
+
+// shardProgress is the content of a progress marker. The first line of the
+// marker is the name of the last TSM file processed, and each following line
+// is the path of a TSM file that was skipped.
+type shardProgress struct {
+	last    string
+	skipped []string
+}
+
+// readProgress returns the progress recorded by the marker at path, or an empty
+// progress if there is no marker.
+func readProgress(path string) (shardProgress, error) {
+	buf, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return shardProgress{}, nil
+	} else if err != nil {
+		return shardProgress{}, err
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(buf)), "\n")
+	p := shardProgress{last: lines[0]}
+	for _, line := range lines[1:] {
+		if line != "" {
+			p.skipped = append(p.skipped, line)
+		}
+	}
+	return p, nil
+}
+
+// writeProgress syncs index to disk and then records p in the progress marker
+// at progressPath.
+func writeProgress(index *tsi1.Index, progressPath string, p shardProgress) error {
+	if err := index.Sync(); err != nil {
 		return err
 	}
 
-	// Rename TSI to standard path.
-	log.Info("Moving tsi to permanent location")
-	return fs.RenameFile(tmpPath, indexPath)
+	var buf bytes.Buffer
+	buf.WriteString(p.last + "\n")
+	for _, path := range p.skipped {
+		buf.WriteString(path + "\n")
+	}
+
+	tmpPath := progressPath + ".tmp"
+	f, err := fs.CreateFileWithReplacement(tmpPath)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return err
+	} else if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	} else if err := f.Close(); err != nil {
+		return err
+	}
+
+	if err := fs.RenameFileWithReplacement(tmpPath, progressPath); err != nil {
+		return err
+	}
+	return fs.SyncDir(filepath.Dir(progressPath))
 }
 
-func IndexTSMFile(index *tsi1.Index, path string, batchSize int, log *zap.Logger, verboseLogging bool) error {
+// IndexTSMFile adds the series in the TSM file at path to index. If prefix is not
+// empty, only the series with keys starting with it are added. If verify is set, the
+// checksum of every block is verified before any series are added. An error is
+// returned if the file cannot be read or fails verification, or if ctx is canceled.
+func IndexTSMFile(ctx context.Context, index *tsi1.Index, path string, prefix []byte, batchSize int, verify bool, progress *IndexProgress, log *zap.Logger, verboseLogging bool) error {
 	f, err := os.Open(path)
 	if err != nil {
 		return err
@@ -340,11 +713,17 @@ func IndexTSMFile(index *tsi1.Index, path string, batchSize int, log *zap.Logger
 
 	r, err := tsm1.NewTSMReader(f)
 	if err != nil {
-		log.Warn("Unable to read, skipping", zap.String("path", path), zap.Error(err))
-		return nil
+		return unreadableFileError{err: err}
 	}
 	defer r.Close()
 
+	if verify {
+		log.Info("Verifying tsm file", zap.String("path", path))
+		if err := verifyTSMFile(r); err != nil {
+			return err
+		}
+	}
+
 	collection := &tsdb.SeriesCollection{
 		Keys:  make([][]byte, 0, batchSize),
 		Names: make([][]byte, 0, batchSize),
@@ -352,9 +731,12 @@ func IndexTSMFile(index *tsi1.Index, path string, batchSize int, log *zap.Logger
 		Types: make([]models.FieldType, 0, batchSize),
 	}
 	var ti int
-	iter := r.Iterator(nil)
+	iter := r.Iterator(prefix)
 	for iter.Next() {
 		key := iter.Key()
+		if !bytes.HasPrefix(key, prefix) {
+			break
+		}
 		seriesKey, _ := tsm1.SeriesAndFieldFromCompositeKey(key)
 		var name []byte
 		name, collection.Tags[ti] = models.ParseKeyBytesWithTags(seriesKey, collection.Tags[ti])
@@ -368,9 +750,13 @@ func IndexTSMFile(index *tsi1.Index, path string, batchSize int, log *zap.Logger
 		collection.Names = append(collection.Names, name)
 		collection.Types = append(collection.Types, modelsFieldType(typ))
 		ti++
+		progress.Add(1)
 
 		// Flush batch?
 		if len(collection.Keys) == batchSize {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 			collection.Truncate(ti)
 			if err := index.CreateSeriesListIfNotExists(collection); err != nil {
 				return fmt.Errorf("problem creating series: (%s)", err)
@@ -386,6 +772,9 @@ func IndexTSMFile(index *tsi1.Index, path string, batchSize int, log *zap.Logger
 
 	// Flush any remaining series in the batches
 	if len(collection.Keys) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		collection.Truncate(ti)
 		if err := index.CreateSeriesListIfNotExists(collection); err != nil {
 			return fmt.Errorf("problem creating series: (%s)", err)
@@ -394,6 +783,25 @@ func IndexTSMFile(index *tsi1.Index, path string, batchSize int, log *zap.Logger
 	return nil
 }
 
+// verifyTSMFile returns an error if the checksum of any block in the TSM file
+// does not match its data.
+func verifyTSMFile(r *tsm1.TSMReader) error {
+	iter := r.BlockIterator()
+	for iter.Next() {
+		key, _, _, _, checksum, buf, err := iter.Read()
+		if err != nil {
+			return fmt.Errorf("problem verifying tsm file: (%s)", err)
+		}
+		if exp := crc32.ChecksumIEEE(buf); checksum != exp {
+			return fmt.Errorf("invalid checksum for block of key %q: got %d, exp %d", key, checksum, exp)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return fmt.Errorf("problem verifying tsm file: (%s)", err)
+	}
+	return nil
+}
+
 func collectTSMFiles(path string) ([]string, error) {
 	fis, err := ioutil.ReadDir(path)
 	if err != nil {
@@ -0,0 +1,181 @@
+package buildtsi
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
+)
+
+func TestCommand_CollectShards_ShardRange(t *testing.T) {
+	dir, err := ioutil.TempDir("", "buildtsi-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for i := 1; i <= 5; i++ {
+		if err := os.MkdirAll(filepath.Join(dir, strconv.Itoa(i)), 0777); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := NewCommand()
+	cmd.shardMin, cmd.shardMax = 2, 4
+
+	shards, err := cmd.collectShards("db", "rp", dir, filepath.Join(dir, "wal"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []uint64
+	for _, s := range shards {
+		got = append(got, s.ID)
+	}
+	if exp := []uint64{2, 3, 4}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected shards collected: got %v, exp %v", got, exp)
+	}
+}
+
+func TestCommand_ProcessDatabase_Concurrency(t *testing.T) {
+	dir, err := ioutil.TempDir("", "buildtsi-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Two retention policies with three shards each.
+	for _, rp := range []string{"rp0", "rp1"} {
+		for i := 1; i <= 3; i++ {
+			if err := os.MkdirAll(filepath.Join(dir, rp, strconv.Itoa(i)), 0777); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	var (
+		mu               sync.Mutex
+		running, maxSeen int
+		seen             []string
+	)
+	cmd := NewCommand()
+	cmd.concurrency = 2
+	cmd.indexShard = func(ctx context.Context, sfile *tsdb.SeriesFile, s shard, log *zap.Logger) error {
+		mu.Lock()
+		running++
+		if running > maxSeen {
+			maxSeen = running
+		}
+		seen = append(seen, filepath.Join(s.RP, s.Path))
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return nil
+	}
+
+	if err := cmd.processDatabase(context.Background(), "db", dir, filepath.Join(dir, "wal")); err != nil {
+		t.Fatal(err)
+	}
+
+	if maxSeen > cmd.concurrency {
+		t.Fatalf("too many shards indexed concurrently: got %d, max %d", maxSeen, cmd.concurrency)
+	}
+
+	sort.Strings(seen)
+	exp := []string{"rp0/1", "rp0/2", "rp0/3", "rp1/1", "rp1/2", "rp1/3"}
+	if !reflect.DeepEqual(seen, exp) {
+		t.Fatalf("unexpected shards indexed: got %v, exp %v", seen, exp)
+	}
+}
+
+func TestCommand_ProcessDatabase_SeriesDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "buildtsi-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.MkdirAll(filepath.Join(dir, "rp0", "1"), 0777); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, seriesDir := range []string{filepath.Join(dir, "..", filepath.Base(dir)+"-series"), "custom-series"} {
+		var seen []string
+		cmd := NewCommand()
+		cmd.concurrency = 1
+		cmd.seriesDir = seriesDir
+		cmd.indexShard = func(ctx context.Context, sfile *tsdb.SeriesFile, s shard, log *zap.Logger) error {
+			exp := filepath.Clean(seriesDir)
+			if !filepath.IsAbs(exp) {
+				exp = filepath.Join(dir, exp)
+			}
+			if got := sfile.Path(); got != exp {
+				t.Errorf("unexpected series file path: got %s, exp %s", got, exp)
+			}
+			seen = append(seen, filepath.Join(s.RP, s.Path))
+			return nil
+		}
+
+		if err := cmd.processDatabase(context.Background(), "db", dir, filepath.Join(dir, "wal")); err != nil {
+			t.Fatal(err)
+		}
+		if exp := []string{"rp0/1"}; !reflect.DeepEqual(seen, exp) {
+			t.Fatalf("unexpected shards indexed: got %v, exp %v", seen, exp)
+		}
+		if filepath.IsAbs(seriesDir) {
+			os.RemoveAll(seriesDir)
+		}
+	}
+}
+
+func TestCommand_Run_ShardRangeWithShard(t *testing.T) {
+	cmd := NewCommand()
+	cmd.Stdout = ioutil.Discard
+	err := cmd.Run(context.Background(), "-datadir", "data", "-waldir", "wal", "-shard", "1", "-shard-min", "2")
+	if err == nil {
+		t.Fatal("expected error: got nil")
+	}
+}
+
+func TestNamePrefix(t *testing.T) {
+	orgID, bucketID := platform.ID(0x10), platform.ID(0x20)
+	name := tsdb.EncodeName(orgID, bucketID)
+	org := tsdb.EncodeOrgName(orgID)
+
+	for _, tt := range []struct {
+		org, bucket string
+		exp         []byte
+		expErr      bool
+	}{
+		{},
+		{org: orgID.String(), exp: org[:]},
+		{org: orgID.String(), bucket: bucketID.String(), exp: models.EscapeMeasurement(name[:])},
+		{bucket: bucketID.String(), expErr: true},
+		{org: "not-an-id", expErr: true},
+		{org: orgID.String(), bucket: "not-an-id", expErr: true},
+	} {
+		got, err := namePrefix(tt.org, tt.bucket)
+		if (err != nil) != tt.expErr {
+			t.Fatalf("org=%q bucket=%q: unexpected error: %v", tt.org, tt.bucket, err)
+		}
+		if !bytes.Equal(got, tt.exp) {
+			t.Fatalf("org=%q bucket=%q: got prefix %x, exp %x", tt.org, tt.bucket, got, tt.exp)
+		}
+	}
+}
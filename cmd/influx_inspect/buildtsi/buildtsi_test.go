@@ -0,0 +1,534 @@
+package buildtsi_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influx_inspect/buildtsi"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/tsi1"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestIndexTSMFile_LogEvery(t *testing.T) {
+	dir, err := ioutil.TempDir("", "buildtsi-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Write a TSM file holding five series.
+	path := filepath.Join(dir, "000000001-000000001.tsm")
+	MustWriteTSMFile(t, path, "cpu", 5)
+
+	sfile := tsdb.NewSeriesFile(filepath.Join(dir, "_series"))
+	if err := sfile.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer sfile.Close()
+
+	index := tsi1.NewIndex(sfile, tsi1.NewConfig(), tsi1.WithPath(filepath.Join(dir, "index")))
+	if err := index.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer index.Close()
+
+	core, logs := observer.New(zap.InfoLevel)
+	progress := buildtsi.NewIndexProgress(zap.New(core), 2)
+	if err := buildtsi.IndexTSMFile(context.Background(), index, path, nil, 3, false, progress, zap.NewNop(), false); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, exp := progress.N(), 5; got != exp {
+		t.Fatalf("unexpected number of series indexed: got %d, exp %d", got, exp)
+	}
+
+	entries := logs.FilterMessage("Indexed series").All()
+	if got, exp := len(entries), 2; got != exp {
+		t.Fatalf("unexpected number of progress logs: got %d, exp %d", got, exp)
+	}
+	for i, entry := range entries {
+		if got, exp := entry.ContextMap()["series"], int64(2*(i+1)); got != exp {
+			t.Fatalf("unexpected series count in log %d: got %v, exp %v", i, got, exp)
+		}
+	}
+}
+
+func TestIndexShard_SkipErrors(t *testing.T) {
+	for _, skipErrors := range []bool{false, true} {
+		t.Run(fmt.Sprintf("skipErrors=%v", skipErrors), func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "buildtsi-")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			// Write one good TSM file and one that has been truncated.
+			dataDir := filepath.Join(dir, "1")
+			if err := os.Mkdir(dataDir, 0777); err != nil {
+				t.Fatal(err)
+			}
+			MustWriteTSMFile(t, filepath.Join(dataDir, "000000001-000000001.tsm"), "cpu", 3)
+			truncated := filepath.Join(dataDir, "000000002-000000001.tsm")
+			MustWriteTSMFile(t, truncated, "mem", 3)
+			if err := os.Truncate(truncated, 10); err != nil {
+				t.Fatal(err)
+			}
+
+			sfile := tsdb.NewSeriesFile(filepath.Join(dir, "_series"))
+			if err := sfile.Open(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			defer sfile.Close()
+
+			indexPath := filepath.Join(dir, "index")
+			err = buildtsi.IndexShard(context.Background(), sfile, indexPath, dataDir, "", nil, tsi1.DefaultMaxIndexLogFileSize, uint64(tsm1.DefaultCacheMaxMemorySize), 1000, 0, skipErrors, false, zap.NewNop(), false)
+			if !skipErrors {
+				if err != nil {
+					t.Fatal(err)
+				}
+			} else if serr, ok := err.(*buildtsi.SkippedFilesError); !ok {
+				t.Fatalf("expected skipped files error, got %v", err)
+			} else if !reflect.DeepEqual(serr.Paths, []string{truncated}) {
+				t.Fatalf("unexpected skipped files: %v", serr.Paths)
+			}
+
+			// The series of the good file should still have been indexed.
+			if got, exp := sfile.SeriesCount(), uint64(3); got != exp {
+				t.Fatalf("unexpected series count: got %d, exp %d", got, exp)
+			}
+			if _, err := os.Stat(indexPath); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestIndexShard_Verify(t *testing.T) {
+	for _, verify := range []bool{false, true} {
+		t.Run(fmt.Sprintf("verify=%v", verify), func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "buildtsi-")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			// Write one good TSM file and one with a byte flipped in its first block.
+			dataDir := filepath.Join(dir, "1")
+			if err := os.Mkdir(dataDir, 0777); err != nil {
+				t.Fatal(err)
+			}
+			MustWriteTSMFile(t, filepath.Join(dataDir, "000000001-000000001.tsm"), "cpu", 3)
+			corrupt := filepath.Join(dataDir, "000000002-000000001.tsm")
+			MustWriteTSMFile(t, corrupt, "mem", 3)
+			buf, err := ioutil.ReadFile(corrupt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			buf[12] ^= 0xff // Past the 5 byte header and 4 byte block checksum.
+			if err := ioutil.WriteFile(corrupt, buf, 0666); err != nil {
+				t.Fatal(err)
+			}
+
+			sfile := tsdb.NewSeriesFile(filepath.Join(dir, "_series"))
+			if err := sfile.Open(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			defer sfile.Close()
+
+			err = buildtsi.IndexShard(context.Background(), sfile, filepath.Join(dir, "index"), dataDir, "", nil, tsi1.DefaultMaxIndexLogFileSize, uint64(tsm1.DefaultCacheMaxMemorySize), 1000, 0, true, verify, zap.NewNop(), false)
+			if !verify {
+				// The corrupt block is never read, so the file is indexed.
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got, exp := sfile.SeriesCount(), uint64(6); got != exp {
+					t.Fatalf("unexpected series count: got %d, exp %d", got, exp)
+				}
+				return
+			}
+
+			if serr, ok := err.(*buildtsi.SkippedFilesError); !ok {
+				t.Fatalf("expected skipped files error, got %v", err)
+			} else if !reflect.DeepEqual(serr.Paths, []string{corrupt}) {
+				t.Fatalf("unexpected skipped files: %v", serr.Paths)
+			}
+			if got, exp := sfile.SeriesCount(), uint64(3); got != exp {
+				t.Fatalf("unexpected series count: got %d, exp %d", got, exp)
+			}
+		})
+	}
+}
+
+func TestIndexShard_Cancel(t *testing.T) {
+	dir, err := ioutil.TempDir("", "buildtsi-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	dataDir := filepath.Join(dir, "1")
+	if err := os.Mkdir(dataDir, 0777); err != nil {
+		t.Fatal(err)
+	}
+	MustWriteTSMFile(t, filepath.Join(dataDir, "000000001-000000001.tsm"), "cpu", 10)
+	MustWriteTSMFile(t, filepath.Join(dataDir, "000000002-000000001.tsm"), "mem", 10)
+
+	sfile := tsdb.NewSeriesFile(filepath.Join(dir, "_series"))
+	if err := sfile.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer sfile.Close()
+
+	// Cancel the rebuild once the first batch of series has been indexed.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	core, _ := observer.New(zap.InfoLevel)
+	log := zap.New(core, zap.Hooks(func(e zapcore.Entry) error {
+		if e.Message == "Indexed series" {
+			cancel()
+		}
+		return nil
+	}))
+
+	indexPath := filepath.Join(dir, "index")
+	err = buildtsi.IndexShard(ctx, sfile, indexPath, dataDir, "", nil, tsi1.DefaultMaxIndexLogFileSize, uint64(tsm1.DefaultCacheMaxMemorySize), 2, 2, false, false, log, false)
+	if err != context.Canceled {
+		t.Fatalf("expected context canceled error, got %v", err)
+	}
+
+	// Neither the partial nor the permanent index should be left behind.
+	if _, err := os.Stat(filepath.Join(dataDir, ".index")); !os.IsNotExist(err) {
+		t.Fatalf("expected partial index to be removed, got %v", err)
+	}
+	if _, err := os.Stat(indexPath); !os.IsNotExist(err) {
+		t.Fatalf("expected no index, got %v", err)
+	}
+}
+
+func TestIndexShard_CancelAfterFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "buildtsi-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	dataDir := filepath.Join(dir, "1")
+	if err := os.Mkdir(dataDir, 0777); err != nil {
+		t.Fatal(err)
+	}
+	first := filepath.Join(dataDir, "000000001-000000001.tsm")
+	MustWriteTSMFile(t, first, "cpu", 3)
+	second := filepath.Join(dataDir, "000000002-000000001.tsm")
+	MustWriteTSMFile(t, second, "mem", 3)
+
+	sfile := tsdb.NewSeriesFile(filepath.Join(dir, "_series"))
+	if err := sfile.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer sfile.Close()
+
+	// Cancel the rebuild when it starts processing the second file.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var started int
+	core, _ := observer.New(zap.InfoLevel)
+	log := zap.New(core, zap.Hooks(func(e zapcore.Entry) error {
+		if e.Message == "Processing tsm file" {
+			if started++; started == 2 {
+				cancel()
+			}
+		}
+		return nil
+	}))
+
+	indexPath := filepath.Join(dir, "index")
+	err = buildtsi.IndexShard(ctx, sfile, indexPath, dataDir, "", nil, tsi1.DefaultMaxIndexLogFileSize, uint64(tsm1.DefaultCacheMaxMemorySize), 1000, 0, false, false, log, false)
+	if err != context.Canceled {
+		t.Fatalf("expected context canceled error, got %v", err)
+	}
+
+	// The partial index is removed along with its progress marker, even though
+	// the first file was processed.
+	if _, err := os.Stat(filepath.Join(dataDir, ".index")); !os.IsNotExist(err) {
+		t.Fatalf("expected partial index to be removed, got %v", err)
+	}
+
+	// The next run starts from scratch.
+	core, logs := observer.New(zap.InfoLevel)
+	if err := buildtsi.IndexShard(context.Background(), sfile, indexPath, dataDir, "", nil, tsi1.DefaultMaxIndexLogFileSize, uint64(tsm1.DefaultCacheMaxMemorySize), 1000, 0, false, false, zap.New(core), false); err != nil {
+		t.Fatal(err)
+	}
+
+	var processed []interface{}
+	for _, entry := range logs.FilterMessage("Processing tsm file").All() {
+		processed = append(processed, entry.ContextMap()["path"])
+	}
+	if exp := []interface{}{first, second}; !reflect.DeepEqual(processed, exp) {
+		t.Fatalf("unexpected tsm files processed: got %v, exp %v", processed, exp)
+	}
+}
+
+func TestIndexShard_ResumeSkipped(t *testing.T) {
+	dir, err := ioutil.TempDir("", "buildtsi-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Write one truncated TSM file that is skipped and one good one.
+	dataDir := filepath.Join(dir, "1")
+	if err := os.Mkdir(dataDir, 0777); err != nil {
+		t.Fatal(err)
+	}
+	truncated := filepath.Join(dataDir, "000000001-000000001.tsm")
+	MustWriteTSMFile(t, truncated, "cpu", 3)
+	if err := os.Truncate(truncated, 10); err != nil {
+		t.Fatal(err)
+	}
+	MustWriteTSMFile(t, filepath.Join(dataDir, "000000002-000000001.tsm"), "mem", 3)
+
+	sfile := tsdb.NewSeriesFile(filepath.Join(dir, "_series"))
+	if err := sfile.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer sfile.Close()
+
+	// The first run fails once all TSM files are processed, as the WAL
+	// directory cannot be read.
+	walDir := filepath.Join(dir, "wal")
+	if err := ioutil.WriteFile(walDir, nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	indexPath := filepath.Join(dir, "index")
+	err = buildtsi.IndexShard(context.Background(), sfile, indexPath, dataDir, walDir, nil, tsi1.DefaultMaxIndexLogFileSize, uint64(tsm1.DefaultCacheMaxMemorySize), 1000, 0, true, false, zap.NewNop(), false)
+	if _, ok := err.(*buildtsi.SkippedFilesError); ok || err == nil {
+		t.Fatalf("expected error reading the wal, got %v", err)
+	}
+
+	// The resumed run processes no TSM file, but still reports the skipped one.
+	if err := os.Remove(walDir); err != nil {
+		t.Fatal(err)
+	}
+	core, logs := observer.New(zap.InfoLevel)
+	err = buildtsi.IndexShard(context.Background(), sfile, indexPath, dataDir, walDir, nil, tsi1.DefaultMaxIndexLogFileSize, uint64(tsm1.DefaultCacheMaxMemorySize), 1000, 0, true, false, zap.New(core), false)
+	if serr, ok := err.(*buildtsi.SkippedFilesError); !ok {
+		t.Fatalf("expected skipped files error, got %v", err)
+	} else if !reflect.DeepEqual(serr.Paths, []string{truncated}) {
+		t.Fatalf("unexpected skipped files: %v", serr.Paths)
+	}
+	if n := len(logs.FilterMessage("Processing tsm file").All()); n != 0 {
+		t.Fatalf("unexpected tsm files processed: %d", n)
+	}
+	if got, exp := sfile.SeriesCount(), uint64(3); got != exp {
+		t.Fatalf("unexpected series count: got %d, exp %d", got, exp)
+	}
+}
+
+func TestIndexShard_Resume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "buildtsi-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Write two TSM files, the second with a byte flipped in its first block so
+	// that the first run fails verifying it.
+	dataDir := filepath.Join(dir, "1")
+	if err := os.Mkdir(dataDir, 0777); err != nil {
+		t.Fatal(err)
+	}
+	first := filepath.Join(dataDir, "000000001-000000001.tsm")
+	MustWriteTSMFile(t, first, "cpu", 3)
+	second := filepath.Join(dataDir, "000000002-000000001.tsm")
+	MustWriteTSMFile(t, second, "mem", 3)
+	buf, err := ioutil.ReadFile(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf[12] ^= 0xff
+	if err := ioutil.WriteFile(second, buf, 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	sfile := tsdb.NewSeriesFile(filepath.Join(dir, "_series"))
+	if err := sfile.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer sfile.Close()
+
+	indexPath := filepath.Join(dir, "index")
+	if err := buildtsi.IndexShard(context.Background(), sfile, indexPath, dataDir, "", nil, tsi1.DefaultMaxIndexLogFileSize, uint64(tsm1.DefaultCacheMaxMemorySize), 1000, 0, false, true, zap.NewNop(), false); err == nil {
+		t.Fatal("expected error verifying the second file")
+	}
+	if _, err := os.Stat(indexPath); !os.IsNotExist(err) {
+		t.Fatalf("expected no index, got %v", err)
+	}
+
+	// Repair the second file and resume. The stats file written alongside it
+	// has to be removed first, as the TSM writer will not replace it.
+	if err := os.Remove(tsm1.StatsFilename(second)); err != nil {
+		t.Fatal(err)
+	}
+	MustWriteTSMFile(t, second, "mem", 3)
+	core, logs := observer.New(zap.InfoLevel)
+	if err := buildtsi.IndexShard(context.Background(), sfile, indexPath, dataDir, "", nil, tsi1.DefaultMaxIndexLogFileSize, uint64(tsm1.DefaultCacheMaxMemorySize), 1000, 0, false, true, zap.New(core), false); err != nil {
+		t.Fatal(err)
+	}
+
+	var processed []interface{}
+	for _, entry := range logs.FilterMessage("Processing tsm file").All() {
+		processed = append(processed, entry.ContextMap()["path"])
+	}
+	if exp := []interface{}{second}; !reflect.DeepEqual(processed, exp) {
+		t.Fatalf("unexpected tsm files processed: got %v, exp %v", processed, exp)
+	}
+
+	// The series of both files should be in the permanent index.
+	if got, exp := sfile.SeriesCount(), uint64(6); got != exp {
+		t.Fatalf("unexpected series count: got %d, exp %d", got, exp)
+	}
+	if _, err := os.Stat(filepath.Join(indexPath, ".progress")); !os.IsNotExist(err) {
+		t.Fatalf("expected progress marker to be removed, got %v", err)
+	}
+
+	index := tsi1.NewIndex(sfile, tsi1.NewConfig(), tsi1.WithPath(indexPath))
+	if err := index.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer index.Close()
+	if got, exp := index.SeriesN(), int64(6); got != exp {
+		t.Fatalf("unexpected indexed series: got %d, exp %d", got, exp)
+	}
+}
+
+func TestIndexShard_Prefix(t *testing.T) {
+	dir, err := ioutil.TempDir("", "buildtsi-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	dataDir := filepath.Join(dir, "1")
+	if err := os.Mkdir(dataDir, 0777); err != nil {
+		t.Fatal(err)
+	}
+	orgID, bucketA, bucketB, bucketC := influxdb.ID(0x10), influxdb.ID(0x20), influxdb.ID(0x30), influxdb.ID(0x40)
+	nameA := tsdb.EncodeName(orgID, bucketA)
+	nameB := tsdb.EncodeName(orgID, bucketB)
+	nameC := tsdb.EncodeName(orgID, bucketC)
+
+	sfile := tsdb.NewSeriesFile(filepath.Join(dir, "_series"))
+	if err := sfile.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer sfile.Close()
+
+	// Build the index while the shard only holds the first bucket.
+	indexPath := filepath.Join(dir, "index")
+	MustWriteTSMFile(t, filepath.Join(dataDir, "000000001-000000001.tsm"), string(models.EscapeMeasurement(nameA[:])), 3)
+	if err := buildtsi.IndexShard(context.Background(), sfile, indexPath, dataDir, "", nil, tsi1.DefaultMaxIndexLogFileSize, uint64(tsm1.DefaultCacheMaxMemorySize), 1000, 0, false, false, zap.NewNop(), false); err != nil {
+		t.Fatal(err)
+	}
+
+	// Add the series of the second bucket only.
+	MustWriteTSMFile(t, filepath.Join(dataDir, "000000002-000000001.tsm"), string(models.EscapeMeasurement(nameB[:])), 2)
+	MustWriteTSMFile(t, filepath.Join(dataDir, "000000003-000000001.tsm"), string(models.EscapeMeasurement(nameC[:])), 4)
+	prefix := models.EscapeMeasurement(nameB[:])
+	if err := buildtsi.IndexShard(context.Background(), sfile, indexPath, dataDir, "", prefix, tsi1.DefaultMaxIndexLogFileSize, uint64(tsm1.DefaultCacheMaxMemorySize), 1000, 0, false, false, zap.NewNop(), false); err != nil {
+		t.Fatal(err)
+	}
+
+	index := tsi1.NewIndex(sfile, tsi1.NewConfig(), tsi1.WithPath(indexPath))
+	if err := index.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer index.Close()
+
+	// The series of the first bucket should still be indexed, alongside those
+	// of the second. The third bucket was not selected.
+	for _, tt := range []struct {
+		name []byte
+		exp  bool
+	}{
+		{nameA[:], true},
+		{nameB[:], true},
+		{nameC[:], false},
+	} {
+		if got, err := index.MeasurementExists(tt.name); err != nil {
+			t.Fatal(err)
+		} else if got != tt.exp {
+			t.Fatalf("unexpected indexed measurement %x: got %v, exp %v", tt.name, got, tt.exp)
+		}
+	}
+	if got, exp := index.SeriesN(), int64(5); got != exp {
+		t.Fatalf("unexpected indexed series: got %d, exp %d", got, exp)
+	}
+}
+
+func TestIndexShard_PrefixWithoutIndex(t *testing.T) {
+	dir, err := ioutil.TempDir("", "buildtsi-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	dataDir := filepath.Join(dir, "1")
+	if err := os.Mkdir(dataDir, 0777); err != nil {
+		t.Fatal(err)
+	}
+	name := tsdb.EncodeName(influxdb.ID(0x10), influxdb.ID(0x20))
+	MustWriteTSMFile(t, filepath.Join(dataDir, "000000001-000000001.tsm"), string(models.EscapeMeasurement(name[:])), 3)
+
+	sfile := tsdb.NewSeriesFile(filepath.Join(dir, "_series"))
+	if err := sfile.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer sfile.Close()
+
+	// An index holding only the series of one bucket must not be built.
+	indexPath := filepath.Join(dir, "index")
+	prefix := models.EscapeMeasurement(name[:])
+	if err := buildtsi.IndexShard(context.Background(), sfile, indexPath, dataDir, "", prefix, tsi1.DefaultMaxIndexLogFileSize, uint64(tsm1.DefaultCacheMaxMemorySize), 1000, 0, false, false, zap.NewNop(), false); err == nil {
+		t.Fatal("expected error without an existing index")
+	}
+	if _, err := os.Stat(indexPath); !os.IsNotExist(err) {
+		t.Fatalf("expected no index, got %v", err)
+	}
+}
+
+// MustWriteTSMFile writes a TSM file to path holding n series of the measurement name.
+func MustWriteTSMFile(t *testing.T, path, name string, n int) {
+	t.Helper()
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := tsm1.NewTSMWriter(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < n; i++ {
+		key := []byte(fmt.Sprintf("%s,host=server%d#!~#value", name, i))
+		if err := w.Write(key, []tsm1.Value{tsm1.NewValue(1, 1.0)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.WriteIndex(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -188,6 +188,30 @@ func buildLauncherCommand(l *Launcher, cmd *cobra.Command) {
 			Default: false,
 			Desc:    "disables automatically extending session ttl on request",
 		},
+		{
+			DestP:   &l.taskMaxLogsPerRun,
+			Flag:    "task-max-logs-per-run",
+			Default: 0,
+			Desc:    "maximum number of log entries kept for each task run, 0 keeps every entry",
+		},
+		{
+			DestP:   &l.taskValidateBuckets,
+			Flag:    "task-validate-buckets",
+			Default: false,
+			Desc:    "reject tasks that write to buckets that do not exist when they are created or updated",
+		},
+		{
+			DestP:   &l.taskForceRunMaxFuture,
+			Flag:    "task-force-run-max-future",
+			Default: time.Duration(0),
+			Desc:    "maximum time in the future a manual task run can be scheduled for, 0 allows any time",
+		},
+		{
+			DestP:   &l.taskOrgRequestsPerSecond,
+			Flag:    "task-org-requests-per-second",
+			Default: 0,
+			Desc:    "maximum rate of task API requests per organization, 0 disables the limit",
+		},
 	}
 
 	cli.BindOptions(cmd, opts)
@@ -201,11 +225,15 @@ type Launcher struct {
 	cancel  func()
 	running bool
 
-	storeType            string
-	assetsPath           string
-	testing              bool
-	sessionLength        int // in minutes
-	sessionRenewDisabled bool
+	storeType                string
+	assetsPath               string
+	testing                  bool
+	sessionLength            int // in minutes
+	sessionRenewDisabled     bool
+	taskMaxLogsPerRun        int
+	taskValidateBuckets      bool
+	taskForceRunMaxFuture    time.Duration
+	taskOrgRequestsPerSecond int
 
 	logLevel          string
 	tracingType       string
@@ -401,6 +429,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 
 	serviceConfig := kv.ServiceConfig{
 		SessionLength: time.Duration(m.sessionLength) * time.Minute,
+		MaxLogsPerRun: m.taskMaxLogsPerRun,
 	}
 
 	var flusher http.Flusher
@@ -536,7 +565,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	}
 
 	var storageQueryService = readservice.NewProxyQueryService(m.queryController)
-	var taskSvc platform.TaskService
+	var taskSvc, taskHealthSvc platform.TaskService
 	{
 
 		// create the task stack:
@@ -562,6 +591,8 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		taskSvc = middleware.New(combinedTaskService, coordinator)
 		taskSvc = authorizer.NewTaskService(m.logger.With(zap.String("service", "task-authz-validator")), taskSvc, bucketSvc)
 		m.taskControlService = combinedTaskService
+		// The task health check is served without authentication, so it reads below the authorizer.
+		taskHealthSvc = combinedTaskService
 	}
 
 	var checkSvc platform.CheckService
@@ -623,14 +654,17 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	}
 
 	m.apibackend = &http.APIBackend{
-		AssetsPath:           m.assetsPath,
-		HTTPErrorHandler:     http.ErrorHandler(0),
-		Logger:               m.logger,
-		SessionRenewDisabled: m.sessionRenewDisabled,
-		NewBucketService:     source.NewBucketService,
-		NewQueryService:      source.NewQueryService,
-		PointsWriter:         pointsWriter,
-		AuthorizationService: authSvc,
+		AssetsPath:               m.assetsPath,
+		HTTPErrorHandler:         http.ErrorHandler(0),
+		Logger:                   m.logger,
+		SessionRenewDisabled:     m.sessionRenewDisabled,
+		TaskValidateBuckets:      m.taskValidateBuckets,
+		TaskForceRunMaxFuture:    m.taskForceRunMaxFuture,
+		TaskOrgRequestsPerSecond: m.taskOrgRequestsPerSecond,
+		NewBucketService:         source.NewBucketService,
+		NewQueryService:          source.NewQueryService,
+		PointsWriter:             pointsWriter,
+		AuthorizationService:     authSvc,
 		// Wrap the BucketService in a storage backed one that will ensure deleted buckets are removed from the storage engine.
 		BucketService:                   storage.NewBucketService(bucketSvc, m.engine),
 		SessionService:                  sessionSvc,
@@ -650,6 +684,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		InfluxQLService:                 nil, // No InfluxQL support
 		FluxService:                     storageQueryService,
 		TaskService:                     taskSvc,
+		TaskHealthService:               taskHealthSvc,
 		TelegrafService:                 telegrafSvc,
 		NotificationRuleStore:           notificationRuleSvc,
 		NotificationEndpointService:     notificationEndpointSvc,
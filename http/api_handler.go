@@ -3,6 +3,7 @@ package http
 import (
 	http "net/http"
 	"strings"
+	"time"
 
 	influxdb "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/authorizer"
@@ -48,7 +49,10 @@ type APIBackend struct {
 	AssetsPath string // if empty then assets are served from bindata.
 	Logger     *zap.Logger
 	influxdb.HTTPErrorHandler
-	SessionRenewDisabled bool
+	SessionRenewDisabled     bool
+	TaskValidateBuckets      bool
+	TaskForceRunMaxFuture    time.Duration
+	TaskOrgRequestsPerSecond int
 
 	NewBucketService func(*influxdb.Source) (influxdb.BucketService, error)
 	NewQueryService  func(*influxdb.Source) (query.ProxyQueryService, error)
@@ -76,6 +80,7 @@ type APIBackend struct {
 	InfluxQLService                 query.ProxyQueryService
 	FluxService                     query.ProxyQueryService
 	TaskService                     influxdb.TaskService
+	TaskHealthService               influxdb.TaskService
 	CheckService                    influxdb.CheckService
 	TelegrafService                 influxdb.TelegrafConfigStore
 	ScraperTargetStoreService       influxdb.ScraperTargetStoreService
@@ -167,6 +172,8 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 	notificationRuleBackend := NewNotificationRuleBackend(b)
 	notificationRuleBackend.NotificationRuleStore = authorizer.NewNotificationRuleStore(b.NotificationRuleStore,
 		b.UserResourceMappingService, b.OrganizationService)
+	notificationRuleBackend.NotificationEndpointService = authorizer.NewNotificationEndpointService(b.NotificationEndpointService,
+		b.UserResourceMappingService, b.OrganizationService, b.SecretService)
 	h.NotificationRuleHandler = NewNotificationRuleHandler(notificationRuleBackend)
 
 	notificationEndpointBackend := NewNotificationEndpointBackend(b)
@@ -25,6 +25,7 @@ type CheckBackend struct {
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
 	OrganizationService        influxdb.OrganizationService
+	TaskService                influxdb.TaskService
 }
 
 // NewCheckBackend returns a new instance of CheckBackend.
@@ -38,6 +39,7 @@ func NewCheckBackend(b *APIBackend) *CheckBackend {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		OrganizationService:        b.OrganizationService,
+		TaskService:                b.TaskService,
 	}
 }
 
@@ -52,12 +54,14 @@ type CheckHandler struct {
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
 	OrganizationService        influxdb.OrganizationService
+	TaskService                influxdb.TaskService
 }
 
 const (
 	checksPath            = "/api/v2/checks"
 	checksIDPath          = "/api/v2/checks/:id"
 	checksIDQueryPath     = "/api/v2/checks/:id/query"
+	checksIDClonePath     = "/api/v2/checks/:id/clone"
 	checksIDMembersPath   = "/api/v2/checks/:id/members"
 	checksIDMembersIDPath = "/api/v2/checks/:id/members/:userID"
 	checksIDOwnersPath    = "/api/v2/checks/:id/owners"
@@ -78,6 +82,7 @@ func NewCheckHandler(b *CheckBackend) *CheckHandler {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		OrganizationService:        b.OrganizationService,
+		TaskService:                b.TaskService,
 	}
 	h.HandlerFunc("POST", checksPath, h.handlePostCheck)
 	h.HandlerFunc("GET", checksPath, h.handleGetChecks)
@@ -86,6 +91,7 @@ func NewCheckHandler(b *CheckBackend) *CheckHandler {
 	h.HandlerFunc("DELETE", checksIDPath, h.handleDeleteCheck)
 	h.HandlerFunc("PUT", checksIDPath, h.handlePutCheck)
 	h.HandlerFunc("PATCH", checksIDPath, h.handlePatchCheck)
+	h.HandlerFunc("POST", checksIDClonePath, h.handlePostCheckClone)
 
 	memberBackend := MemberBackend{
 		HTTPErrorHandler:           b.HTTPErrorHandler,
@@ -133,8 +139,9 @@ type checkLinks struct {
 
 type checkResponse struct {
 	influxdb.Check
-	Labels []influxdb.Label `json:"labels"`
-	Links  checkLinks       `json:"links"`
+	Labels          []influxdb.Label `json:"labels"`
+	Links           checkLinks       `json:"links"`
+	LatestCompleted string           `json:"latestCompleted,omitempty"`
 }
 
 func (resp checkResponse) MarshalJSON() ([]byte, error) {
@@ -144,11 +151,13 @@ func (resp checkResponse) MarshalJSON() ([]byte, error) {
 	}
 
 	b2, err := json.Marshal(struct {
-		Labels []influxdb.Label `json:"labels"`
-		Links  checkLinks       `json:"links"`
+		Labels          []influxdb.Label `json:"labels"`
+		Links           checkLinks       `json:"links"`
+		LatestCompleted string           `json:"latestCompleted,omitempty"`
 	}{
-		Links:  resp.Links,
-		Labels: resp.Labels,
+		Links:           resp.Links,
+		Labels:          resp.Labels,
+		LatestCompleted: resp.LatestCompleted,
 	})
 	if err != nil {
 		return nil, err
@@ -289,12 +298,31 @@ func (h *CheckHandler) handleGetCheck(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := encodeResponse(ctx, w, http.StatusOK, newCheckResponse(chk, labels)); err != nil {
+	// The task must be looked up before the response clears the task ID.
+	latestCompleted := h.latestCompleted(ctx, chk)
+
+	resp := newCheckResponse(chk, labels)
+	resp.LatestCompleted = latestCompleted
+	if err := encodeResponse(ctx, w, http.StatusOK, resp); err != nil {
 		logEncodingError(h.Logger, r, err)
 		return
 	}
 }
 
+// latestCompleted returns the time of the latest completed run of the task
+// backing the check. A failed lookup is logged rather than failing the request.
+func (h *CheckHandler) latestCompleted(ctx context.Context, chk influxdb.Check) string {
+	if h.TaskService == nil || !chk.GetTaskID().Valid() {
+		return ""
+	}
+	t, err := h.TaskService.FindTaskByID(ctx, chk.GetTaskID())
+	if err != nil {
+		h.Logger.Info("failed to find check task", zap.String("check", chk.GetID().String()), zap.Error(err))
+		return ""
+	}
+	return t.LatestCompleted
+}
+
 func decodeCheckFilter(ctx context.Context, r *http.Request) (*influxdb.CheckFilter, *influxdb.FindOptions, error) {
 	f := &influxdb.CheckFilter{}
 
@@ -317,6 +345,15 @@ func decodeCheckFilter(ctx context.Context, r *http.Request) (*influxdb.CheckFil
 	} else if orgNameStr := q.Get("org"); orgNameStr != "" {
 		*f.Org = orgNameStr
 	}
+	if typ := q.Get("type"); typ != "" {
+		if !check.IsValidType(typ) {
+			return f, opts, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("check type %q is invalid, must be deadman or threshold", typ),
+			}
+		}
+		f.Type = &typ
+	}
 	return f, opts, err
 }
 
@@ -503,6 +540,63 @@ func (h *CheckHandler) handlePatchCheck(w http.ResponseWriter, r *http.Request)
 	}
 }
 
+type postCheckCloneRequest struct {
+	ID   influxdb.ID
+	Name string
+}
+
+func decodePostCheckCloneRequest(ctx context.Context, r *http.Request) (*postCheckCloneRequest, error) {
+	i, err := decodeGetCheckRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	var body struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  err.Error(),
+		}
+	}
+	if body.Name == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "clone name can't be empty",
+		}
+	}
+
+	return &postCheckCloneRequest{
+		ID:   i,
+		Name: body.Name,
+	}, nil
+}
+
+// handlePostCheckClone is the HTTP handler for the POST /api/v2/checks/:id/clone route.
+func (h *CheckHandler) handlePostCheckClone(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("check clone request", zap.String("r", fmt.Sprint(r)))
+	req, err := decodePostCheckCloneRequest(ctx, r)
+	if err != nil {
+		h.Logger.Debug("failed to decode request", zap.Error(err))
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	chk, err := h.CheckService.CloneCheck(ctx, req.ID, req.Name)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("check cloned", zap.String("check", fmt.Sprint(chk)))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newCheckResponse(chk, []*influxdb.Label{})); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
 func (h *CheckHandler) handleDeleteCheck(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	h.Logger.Debug("check delete request", zap.String("r", fmt.Sprint(r)))
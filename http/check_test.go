@@ -313,6 +313,22 @@ func mustDuration(d string) *notification.Duration {
 	return (*notification.Duration)(dur)
 }
 
+func Test_decodeCheckFilter_type(t *testing.T) {
+	r := httptest.NewRequest("GET", "http://any.url?type=deadman", nil)
+	f, _, err := decodeCheckFilter(context.Background(), r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Type == nil || *f.Type != "deadman" {
+		t.Fatalf("unexpected type filter: %v", f.Type)
+	}
+
+	r = httptest.NewRequest("GET", "http://any.url?type=heartbeat", nil)
+	if _, _, err := decodeCheckFilter(context.Background(), r); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected an invalid error, got %v", err)
+	}
+}
+
 func TestService_handleGetCheckQuery(t *testing.T) {
 	type fields struct {
 		CheckService influxdb.CheckService
@@ -412,6 +428,284 @@ func TestService_handleGetCheckQuery(t *testing.T) {
 				body:        `{"flux":"package main\nimport \"influxdata/influxdb/monitor\"\nimport \"influxdata/influxdb/v1\"\n\ndata = from(bucket: \"foo\")\n\t|\u003e range(start: -1h)\n\t|\u003e aggregateWindow(every: 1h, fn: mean)\n\noption task = {name: \"hello\", every: 1h}\n\ncheck = {\n\t_check_id: \"020f755c3c082000\",\n\t_check_name: \"hello\",\n\t_check_type: \"threshold\",\n\ttags: {aaa: \"vaaa\", bbb: \"vbbb\"},\n}\nok = (r) =\u003e\n\t(r.usage_user \u003e 10.0)\ninfo = (r) =\u003e\n\t(r.usage_user \u003c 40.0)\nwarn = (r) =\u003e\n\t(r.usage_user \u003c 40.0 and r.usage_user \u003e 10.0)\ncrit = (r) =\u003e\n\t(r.usage_user \u003c 40.0 and r.usage_user \u003e 10.0)\nmessageFn = (r) =\u003e\n\t(\"whoa! {check.yeah}\")\n\ndata\n\t|\u003e v1.fieldsAsCols()\n\t|\u003e monitor.check(\n\t\tdata: check,\n\t\tmessageFn: messageFn,\n\t\tok: ok,\n\t\tinfo: info,\n\t\twarn: warn,\n\t\tcrit: crit,\n\t)"}`,
 			},
 		},
+		{
+			name: "get a greater or equal and lesser or equal check query by id",
+			fields: fields{
+				&mock.CheckService{
+					FindCheckByIDFn: func(ctx context.Context, id influxdb.ID) (influxdb.Check, error) {
+						if id == influxTesting.MustIDBase16("020f755c3c082000") {
+							return &check.Threshold{
+								Base: check.Base{
+									ID:     influxTesting.MustIDBase16("020f755c3c082000"),
+									OrgID:  influxTesting.MustIDBase16("020f755c3c082000"),
+									Name:   "hello",
+									Status: influxdb.Active,
+									TaskID: 3,
+									Tags: []notification.Tag{
+										{Key: "aaa", Value: "vaaa"},
+									},
+									Every:                 mustDuration("1h"),
+									StatusMessageTemplate: "whoa!",
+									Query: influxdb.DashboardQuery{
+										Text: `from(bucket: "foo") |> range(start: -1d, stop: now()) |> aggregateWindow(every: 1m, fn: mean) |> yield()`,
+										BuilderConfig: influxdb.BuilderConfig{
+											Tags: []struct {
+												Key    string   `json:"key"`
+												Values []string `json:"values"`
+											}{
+												{
+													Key:    "_field",
+													Values: []string{"usage_user"},
+												},
+											},
+										},
+									},
+								},
+								Thresholds: []check.ThresholdConfig{
+									check.GreaterEqual{
+										ThresholdConfigBase: check.ThresholdConfigBase{
+											Level: notification.Warn,
+										},
+										Value: l,
+									},
+									check.LesserEqual{
+										ThresholdConfigBase: check.ThresholdConfigBase{
+											Level: notification.Critical,
+										},
+										Value: u,
+									},
+								},
+							}, nil
+						}
+						return nil, fmt.Errorf("not found")
+					},
+				},
+			},
+			args: args{
+				id: "020f755c3c082000",
+			},
+			wants: wants{
+				statusCode:  http.StatusOK,
+				contentType: "application/json; charset=utf-8",
+				body:        `{"flux":"package main\nimport \"influxdata/influxdb/monitor\"\nimport \"influxdata/influxdb/v1\"\n\ndata = from(bucket: \"foo\")\n\t|> range(start: -1h)\n\t|> aggregateWindow(every: 1h, fn: mean)\n\noption task = {name: \"hello\", every: 1h}\n\ncheck = {\n\t_check_id: \"020f755c3c082000\",\n\t_check_name: \"hello\",\n\t_check_type: \"threshold\",\n\ttags: {aaa: \"vaaa\"},\n}\nwarn = (r) =>\n\t(r.usage_user >= 10.0)\ncrit = (r) =>\n\t(r.usage_user <= 40.0)\nmessageFn = (r) =>\n\t(\"whoa!\")\n\ndata\n\t|> v1.fieldsAsCols()\n\t|> monitor.check(\n\t\tdata: check,\n\t\tmessageFn: messageFn,\n\t\twarn: warn,\n\t\tcrit: crit,\n\t)"}`,
+			},
+		},
+		{
+			name: "get a check query with a suppression window by id",
+			fields: fields{
+				&mock.CheckService{
+					FindCheckByIDFn: func(ctx context.Context, id influxdb.ID) (influxdb.Check, error) {
+						if id == influxTesting.MustIDBase16("020f755c3c082000") {
+							return &check.Threshold{
+								Base: check.Base{
+									ID:     influxTesting.MustIDBase16("020f755c3c082000"),
+									OrgID:  influxTesting.MustIDBase16("020f755c3c082000"),
+									Name:   "hello",
+									Status: influxdb.Active,
+									TaskID: 3,
+									Tags: []notification.Tag{
+										{Key: "aaa", Value: "vaaa"},
+									},
+									Every:                 mustDuration("1h"),
+									SuppressFor:           mustDuration("10m"),
+									StatusMessageTemplate: "whoa!",
+									Query: influxdb.DashboardQuery{
+										Text: `from(bucket: "foo") |> range(start: -1d, stop: now()) |> aggregateWindow(every: 1m, fn: mean) |> yield()`,
+										BuilderConfig: influxdb.BuilderConfig{
+											Tags: []struct {
+												Key    string   `json:"key"`
+												Values []string `json:"values"`
+											}{
+												{
+													Key:    "_field",
+													Values: []string{"usage_user"},
+												},
+											},
+										},
+									},
+								},
+								Thresholds: []check.ThresholdConfig{
+									check.GreaterEqual{
+										ThresholdConfigBase: check.ThresholdConfigBase{
+											Level: notification.Warn,
+										},
+										Value: l,
+									},
+									check.LesserEqual{
+										ThresholdConfigBase: check.ThresholdConfigBase{
+											Level: notification.Critical,
+										},
+										Value: u,
+									},
+								},
+							}, nil
+						}
+						return nil, fmt.Errorf("not found")
+					},
+				},
+			},
+			args: args{
+				id: "020f755c3c082000",
+			},
+			wants: wants{
+				statusCode:  http.StatusOK,
+				contentType: "application/json; charset=utf-8",
+				body:        `{"flux":"package main\nimport \"influxdata/influxdb/monitor\"\nimport \"influxdata/influxdb/v1\"\nimport \"experimental\"\n\ndata = from(bucket: \"foo\")\n\t|> range(start: -1h)\n\t|> aggregateWindow(every: 1h, fn: mean)\n\noption task = {name: \"hello\", every: 1h}\noption monitor.write = (tables=<-) =>\n\t(union(tables: [monitor.from(start: -10m, fn: (r) =>\n\t\t(r._check_id == \"020f755c3c082000\" and r._level == \"crit\"))\n\t\t|> drop(columns: [\"_start\", \"_stop\"])\n\t\t|> map(fn: (r) =>\n\t\t\t({r with _crit_count: 1})), tables\n\t\t|> drop(columns: [\"_start\", \"_stop\"])\n\t\t|> map(fn: (r) =>\n\t\t\t({r with _crit_count: 0}))])\n\t\t|> experimental.group(mode: \"extend\", columns: [])\n\t\t|> sort(columns: [\"_time\"])\n\t\t|> cumulativeSum(columns: [\"_crit_count\"])\n\t\t|> filter(fn: (r) =>\n\t\t\t(r._level != \"crit\" or r._crit_count == 0))\n\t\t|> drop(columns: [\"_crit_count\"])\n\t\t|> experimental.to(bucket: monitor.bucket))\n\ncheck = {\n\t_check_id: \"020f755c3c082000\",\n\t_check_name: \"hello\",\n\t_check_type: \"threshold\",\n\ttags: {aaa: \"vaaa\"},\n}\nwarn = (r) =>\n\t(r.usage_user >= 10.0)\ncrit = (r) =>\n\t(r.usage_user <= 40.0)\nmessageFn = (r) =>\n\t(\"whoa!\")\n\ndata\n\t|> v1.fieldsAsCols()\n\t|> monitor.check(\n\t\tdata: check,\n\t\tmessageFn: messageFn,\n\t\twarn: warn,\n\t\tcrit: crit,\n\t)"}`,
+			},
+		},
+		{
+			name: "get a percent change check query by id",
+			fields: fields{
+				&mock.CheckService{
+					FindCheckByIDFn: func(ctx context.Context, id influxdb.ID) (influxdb.Check, error) {
+						if id == influxTesting.MustIDBase16("020f755c3c082000") {
+							return &check.Threshold{
+								Base: check.Base{
+									ID:     influxTesting.MustIDBase16("020f755c3c082000"),
+									OrgID:  influxTesting.MustIDBase16("020f755c3c082000"),
+									Name:   "hello",
+									Status: influxdb.Active,
+									TaskID: 3,
+									Tags: []notification.Tag{
+										{Key: "aaa", Value: "vaaa"},
+										{Key: "bbb", Value: "vbbb"},
+									},
+									Every:                 mustDuration("1h"),
+									StatusMessageTemplate: "whoa! {check.yeah}",
+									Query: influxdb.DashboardQuery{
+										Text: `from(bucket: "foo") |> range(start: -1d, stop: now()) |> aggregateWindow(every: 1m, fn: mean) |> yield()`,
+										BuilderConfig: influxdb.BuilderConfig{
+											Tags: []struct {
+												Key    string   `json:"key"`
+												Values []string `json:"values"`
+											}{
+												{
+													Key:    "_field",
+													Values: []string{"usage_user"},
+												},
+											},
+										},
+									},
+								},
+								Thresholds: []check.ThresholdConfig{
+									check.Greater{
+										ThresholdConfigBase: check.ThresholdConfigBase{
+											Level: notification.Ok,
+										},
+										Value: l,
+									},
+									check.PercentChange{
+										ThresholdConfigBase: check.ThresholdConfigBase{
+											Level: notification.Critical,
+										},
+										Min: l,
+										Max: u,
+									},
+								},
+							}, nil
+						}
+						return nil, fmt.Errorf("not found")
+					},
+				},
+			},
+			args: args{
+				id: "020f755c3c082000",
+			},
+			wants: wants{
+				statusCode:  http.StatusOK,
+				contentType: "application/json; charset=utf-8",
+				body:        `{"flux":"package main\nimport \"influxdata/influxdb/monitor\"\nimport \"influxdata/influxdb/v1\"\nimport \"experimental\"\n\ndata = from(bucket: \"foo\")\n\t|> range(start: -2h)\n\t|> aggregateWindow(every: 1h, fn: mean)\n\noption task = {name: \"hello\", every: 1h}\n\ncheck = {\n\t_check_id: \"020f755c3c082000\",\n\t_check_name: \"hello\",\n\t_check_type: \"threshold\",\n\ttags: {aaa: \"vaaa\", bbb: \"vbbb\"},\n}\nok = (r) =>\n\t(r.usage_user > 10.0)\ncrit = (r) =>\n\t(exists r._prior_delta and r.usage_user - r._prior_delta != 0.0 and (r._prior_delta / (r.usage_user - r._prior_delta) * 100.0 < 40.0 and r._prior_delta / (r.usage_user - r._prior_delta) * 100.0 > 10.0))\nmessageFn = (r) =>\n\t(\"whoa! {check.yeah}\")\n\ndata\n\t|> v1.fieldsAsCols()\n\t|> duplicate(column: \"usage_user\", as: \"_prior_delta\")\n\t|> difference(columns: [\"_prior_delta\"])\n\t|> filter(fn: (r) =>\n\t\t(r._time > experimental.subDuration(from: now(), d: 1h)))\n\t|> monitor.check(\n\t\tdata: check,\n\t\tmessageFn: messageFn,\n\t\tok: ok,\n\t\tcrit: crit,\n\t)"}`,
+			},
+		},
+		{
+			name: "get a multi-field deadman check query by id",
+			fields: fields{
+				&mock.CheckService{
+					FindCheckByIDFn: func(ctx context.Context, id influxdb.ID) (influxdb.Check, error) {
+						if id == influxTesting.MustIDBase16("020f755c3c082000") {
+							return &check.Deadman{
+								Base: check.Base{
+									ID:     influxTesting.MustIDBase16("020f755c3c082000"),
+									OrgID:  influxTesting.MustIDBase16("020f755c3c082000"),
+									Name:   "hello",
+									Status: influxdb.Active,
+									TaskID: 3,
+									Tags: []notification.Tag{
+										{Key: "aaa", Value: "vaaa"},
+										{Key: "bbb", Value: "vbbb"},
+									},
+									Every:                 mustDuration("1h"),
+									StatusMessageTemplate: "whoa! {check.yeah}",
+									Query: influxdb.DashboardQuery{
+										Text: `from(bucket: "foo") |> range(start: -1d, stop: now()) |> aggregateWindow(every: 1m, fn: mean) |> yield()`,
+										BuilderConfig: influxdb.BuilderConfig{
+											Tags: []struct {
+												Key    string   `json:"key"`
+												Values []string `json:"values"`
+											}{
+												{
+													Key:    "_field",
+													Values: []string{"usage_user"},
+												},
+											},
+										},
+									},
+								},
+								TimeSince: 60,
+								Level:     notification.Critical,
+								Fields:    []string{"usage_user", "usage_system", "usage_idle"},
+							}, nil
+						}
+						return nil, fmt.Errorf("not found")
+					},
+				},
+			},
+			args: args{
+				id: "020f755c3c082000",
+			},
+			wants: wants{
+				statusCode:  http.StatusOK,
+				contentType: "application/json; charset=utf-8",
+				body:        `{"flux":"package main\nimport \"influxdata/influxdb/monitor\"\nimport \"experimental\"\n\ndata = from(bucket: \"foo\")\n\t|> range(start: -1h)\n\t|> aggregateWindow(every: 1h, fn: mean)\n\noption task = {name: \"hello\", every: 1h}\n\ncheck = {\n\t_check_id: \"020f755c3c082000\",\n\t_check_name: \"hello\",\n\t_check_type: \"deadman\",\n\ttags: {aaa: \"vaaa\", bbb: \"vbbb\"},\n}\ncrit = (r) =>\n\t(r.dead)\nmessageFn = (r) =>\n\t(\"whoa! {check.yeah}\")\n\ndata\n\t|> filter(fn: (r) =>\n\t\t(r._field == \"usage_user\" or r._field == \"usage_system\" or r._field == \"usage_idle\"))\n\t|> monitor.deadman(t: experimental.subDuration(from: now(), d: 60s))\n\t|> monitor.check(data: check, messageFn: messageFn, crit: crit)"}`,
+			},
+		},
+		{
+			name: "get a deadman check query reporting tags by id",
+			fields: fields{
+				&mock.CheckService{
+					FindCheckByIDFn: func(ctx context.Context, id influxdb.ID) (influxdb.Check, error) {
+						if id == influxTesting.MustIDBase16("020f755c3c082000") {
+							return &check.Deadman{
+								Base: check.Base{
+									ID:                    influxTesting.MustIDBase16("020f755c3c082000"),
+									OrgID:                 influxTesting.MustIDBase16("020f755c3c082000"),
+									Name:                  "hello",
+									Status:                influxdb.Active,
+									TaskID:                3,
+									Every:                 mustDuration("1h"),
+									StatusMessageTemplate: "silent",
+									Query: influxdb.DashboardQuery{
+										Text: `from(bucket: "foo") |> range(start: -1d, stop: now()) |> aggregateWindow(every: 1m, fn: mean) |> yield()`,
+									},
+								},
+								TimeSince:  60,
+								Level:      notification.Warn,
+								ReportTags: []string{"host", "region"},
+							}, nil
+						}
+						return nil, fmt.Errorf("not found")
+					},
+				},
+			},
+			args: args{
+				id: "020f755c3c082000",
+			},
+			wants: wants{
+				statusCode:  http.StatusOK,
+				contentType: "application/json; charset=utf-8",
+				body:        `{"flux":"package main\nimport \"influxdata/influxdb/monitor\"\nimport \"experimental\"\n\ndata = from(bucket: \"foo\")\n\t|> range(start: -1h)\n\t|> aggregateWindow(every: 1h, fn: mean)\n\noption task = {name: \"hello\", every: 1h}\n\ncheck = {\n\t_check_id: \"020f755c3c082000\",\n\t_check_name: \"hello\",\n\t_check_type: \"deadman\",\n\ttags: {},\n}\nwarn = (r) =>\n\t(r.dead)\nmessageFn = (r) =>\n\t(\"silent\" + \" (host: \" + r.host + \", region: \" + r.region + \")\")\n\ndata\n\t|> monitor.deadman(t: experimental.subDuration(from: now(), d: 60s))\n\t|> monitor.check(data: check, messageFn: messageFn, warn: warn)"}`,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -605,6 +899,66 @@ func TestService_handleGetCheck(t *testing.T) {
 	}
 }
 
+func TestService_handleGetCheck_latestCompleted(t *testing.T) {
+	checkID := influxTesting.MustIDBase16("020f755c3c082000")
+	taskID := influxTesting.MustIDBase16("020f755c3c082001")
+
+	checkBackend := NewMockCheckBackend()
+	checkBackend.HTTPErrorHandler = ErrorHandler(0)
+	checkBackend.CheckService = &mock.CheckService{
+		FindCheckByIDFn: func(ctx context.Context, id influxdb.ID) (influxdb.Check, error) {
+			return &check.Deadman{
+				Base: check.Base{
+					ID:     checkID,
+					OrgID:  checkID,
+					Name:   "hello",
+					Status: influxdb.Active,
+					Every:  mustDuration("3h"),
+					TaskID: taskID,
+				},
+				Level: notification.Critical,
+			}, nil
+		},
+	}
+	checkBackend.TaskService = &mock.TaskService{
+		FindTaskByIDFn: func(ctx context.Context, id influxdb.ID) (*influxdb.Task, error) {
+			if id != taskID {
+				return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "task not found"}
+			}
+			return &influxdb.Task{ID: taskID, LatestCompleted: "2019-10-01T12:00:00Z"}, nil
+		},
+	}
+	h := NewCheckHandler(checkBackend)
+
+	r := httptest.NewRequest("GET", "http://any.url", nil)
+	r = r.WithContext(context.WithValue(
+		context.Background(),
+		httprouter.ParamsKey,
+		httprouter.Params{{Key: "id", Value: checkID.String()}},
+	))
+	w := httptest.NewRecorder()
+
+	h.handleGetCheck(w, r)
+
+	res := w.Result()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("handleGetCheck() = %v, want %v", res.StatusCode, http.StatusOK)
+	}
+	var resp struct {
+		TaskID          string `json:"taskID"`
+		LatestCompleted string `json:"latestCompleted"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.LatestCompleted != "2019-10-01T12:00:00Z" {
+		t.Errorf("unexpected latestCompleted: %q", resp.LatestCompleted)
+	}
+	if resp.TaskID != "" {
+		t.Errorf("task ID should not be exposed, got %q", resp.TaskID)
+	}
+}
+
 func TestService_handlePostCheck(t *testing.T) {
 	type fields struct {
 		CheckService        influxdb.CheckService
@@ -861,6 +1215,138 @@ func TestService_handleDeleteCheck(t *testing.T) {
 	}
 }
 
+func TestService_handlePostCheckClone(t *testing.T) {
+	type fields struct {
+		CheckService influxdb.CheckService
+	}
+	type args struct {
+		id   string
+		body string
+	}
+	type wants struct {
+		statusCode int
+		name       string
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "clone a check by id",
+			fields: fields{
+				&mock.CheckService{
+					CloneCheckFn: func(ctx context.Context, id influxdb.ID, newName string) (influxdb.Check, error) {
+						if id != influxTesting.MustIDBase16("020f755c3c082000") {
+							return nil, fmt.Errorf("wrong id")
+						}
+						return &check.Deadman{
+							Base: check.Base{
+								ID:      influxTesting.MustIDBase16("020f755c3c082001"),
+								OrgID:   influxTesting.MustIDBase16("020f755c3c082002"),
+								OwnerID: influxTesting.MustIDBase16("020f755c3c082003"),
+								Name:    newName,
+								Status:  influxdb.Inactive,
+								Every:   mustDuration("1m"),
+							},
+						}, nil
+					},
+				},
+			},
+			args: args{
+				id:   "020f755c3c082000",
+				body: `{"name": "clone"}`,
+			},
+			wants: wants{
+				statusCode: http.StatusCreated,
+				name:       "clone",
+			},
+		},
+		{
+			name: "clone name is required",
+			fields: fields{
+				&mock.CheckService{},
+			},
+			args: args{
+				id:   "020f755c3c082000",
+				body: `{}`,
+			},
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			name: "check not found",
+			fields: fields{
+				&mock.CheckService{
+					CloneCheckFn: func(ctx context.Context, id influxdb.ID, newName string) (influxdb.Check, error) {
+						return nil, &influxdb.Error{
+							Code: influxdb.ENotFound,
+							Msg:  "check not found",
+						}
+					},
+				},
+			},
+			args: args{
+				id:   "020f755c3c082000",
+				body: `{"name": "clone"}`,
+			},
+			wants: wants{
+				statusCode: http.StatusNotFound,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkBackend := NewMockCheckBackend()
+			checkBackend.HTTPErrorHandler = ErrorHandler(0)
+			checkBackend.CheckService = tt.fields.CheckService
+			h := NewCheckHandler(checkBackend)
+
+			r := httptest.NewRequest("POST", "http://any.url", bytes.NewBufferString(tt.args.body))
+
+			r = r.WithContext(context.WithValue(
+				context.Background(),
+				httprouter.ParamsKey,
+				httprouter.Params{
+					{
+						Key:   "id",
+						Value: tt.args.id,
+					},
+				}))
+
+			w := httptest.NewRecorder()
+
+			h.handlePostCheckClone(w, r)
+
+			res := w.Result()
+			if res.StatusCode != tt.wants.statusCode {
+				t.Errorf("%q. handlePostCheckClone() = %v, want %v", tt.name, res.StatusCode, tt.wants.statusCode)
+			}
+			if tt.wants.name == "" {
+				return
+			}
+
+			var got struct {
+				Name   string          `json:"name"`
+				Status influxdb.Status `json:"status"`
+			}
+			if err := json.NewDecoder(res.Body).Decode(&got); err != nil {
+				t.Fatalf("%q. handlePostCheckClone() error decoding response: %v", tt.name, err)
+			}
+			if got.Name != tt.wants.name {
+				t.Errorf("%q. handlePostCheckClone() name = %v, want %v", tt.name, got.Name, tt.wants.name)
+			}
+			if got.Status != influxdb.Inactive {
+				t.Errorf("%q. handlePostCheckClone() status = %v, want %v", tt.name, got.Status, influxdb.Inactive)
+			}
+		})
+	}
+}
+
 func TestService_handlePatchCheck(t *testing.T) {
 	type fields struct {
 		CheckService influxdb.CheckService
@@ -605,6 +605,21 @@ func (s *LabelService) FindResourceLabels(ctx context.Context, filter influxdb.L
 	return r.Labels, nil
 }
 
+// FindResourceLabelsForResources returns the labels of each of the resources.
+// There is no batch endpoint, so it requests the labels of one resource at a time.
+func (s *LabelService) FindResourceLabelsForResources(ctx context.Context, rt influxdb.ResourceType, ids []influxdb.ID) (map[influxdb.ID][]*influxdb.Label, error) {
+	m := make(map[influxdb.ID][]*influxdb.Label, len(ids))
+	for _, id := range ids {
+		ls, err := s.FindResourceLabels(ctx, influxdb.LabelMappingFilter{ResourceID: id, ResourceType: rt})
+		if err != nil {
+			return nil, err
+		}
+		m[id] = ls
+	}
+
+	return m, nil
+}
+
 // CreateLabel creates a new label.
 func (s *LabelService) CreateLabel(ctx context.Context, l *influxdb.Label) error {
 	u, err := NewURL(s.Addr, labelsPath)
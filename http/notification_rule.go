@@ -6,10 +6,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/lang"
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
 	pctx "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/notification/rule"
+	"github.com/influxdata/influxdb/query"
 	"github.com/julienschmidt/httprouter"
 	"go.uber.org/zap"
 )
@@ -25,6 +31,9 @@ type NotificationRuleBackend struct {
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
 	OrganizationService        influxdb.OrganizationService
+
+	NotificationEndpointService influxdb.NotificationEndpointService
+	QueryService                query.ProxyQueryService
 }
 
 // NewNotificationRuleBackend returns a new instance of NotificationRuleBackend.
@@ -38,6 +47,9 @@ func NewNotificationRuleBackend(b *APIBackend) *NotificationRuleBackend {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		OrganizationService:        b.OrganizationService,
+
+		NotificationEndpointService: b.NotificationEndpointService,
+		QueryService:                b.FluxService,
 	}
 }
 
@@ -52,11 +64,16 @@ type NotificationRuleHandler struct {
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
 	OrganizationService        influxdb.OrganizationService
+
+	NotificationEndpointService influxdb.NotificationEndpointService
+	QueryService                query.ProxyQueryService
 }
 
 const (
 	notificationRulesPath            = "/api/v2/notificationRules"
 	notificationRulesIDPath          = "/api/v2/notificationRules/:id"
+	notificationRulesIDTestPath      = "/api/v2/notificationRules/:id/test"
+	notificationRulesIDQueryPath     = "/api/v2/notificationRules/:id/query"
 	notificationRulesIDMembersPath   = "/api/v2/notificationRules/:id/members"
 	notificationRulesIDMembersIDPath = "/api/v2/notificationRules/:id/members/:userID"
 	notificationRulesIDOwnersPath    = "/api/v2/notificationRules/:id/owners"
@@ -77,13 +94,18 @@ func NewNotificationRuleHandler(b *NotificationRuleBackend) *NotificationRuleHan
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		OrganizationService:        b.OrganizationService,
+
+		NotificationEndpointService: b.NotificationEndpointService,
+		QueryService:                b.QueryService,
 	}
 	h.HandlerFunc("POST", notificationRulesPath, h.handlePostNotificationRule)
 	h.HandlerFunc("GET", notificationRulesPath, h.handleGetNotificationRules)
 	h.HandlerFunc("GET", notificationRulesIDPath, h.handleGetNotificationRule)
+	h.HandlerFunc("GET", notificationRulesIDQueryPath, h.handleGetNotificationRuleQuery)
 	h.HandlerFunc("DELETE", notificationRulesIDPath, h.handleDeleteNotificationRule)
 	h.HandlerFunc("PUT", notificationRulesIDPath, h.handlePutNotificationRule)
 	h.HandlerFunc("PATCH", notificationRulesIDPath, h.handlePatchNotificationRule)
+	h.HandlerFunc("POST", notificationRulesIDTestPath, h.handlePostNotificationRuleTest)
 
 	memberBackend := MemberBackend{
 		HTTPErrorHandler:           b.HTTPErrorHandler,
@@ -281,7 +303,11 @@ func decodeNotificationRuleFilter(ctx context.Context, r *http.Request) (*influx
 		}
 		f.OrgID = orgID
 	} else if orgNameStr := q.Get("org"); orgNameStr != "" {
-		*f.Organization = orgNameStr
+		f.Organization = &orgNameStr
+	}
+
+	if name := q.Get("name"); name != "" {
+		f.Name = &name
 	}
 	return f, opts, err
 }
@@ -513,3 +539,177 @@ func (h *NotificationRuleHandler) handleDeleteNotificationRule(w http.ResponseWr
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// handleGetNotificationRuleQuery is the HTTP handler for the GET /api/v2/notificationRules/:id/query route.
+func (h *NotificationRuleHandler) handleGetNotificationRuleQuery(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := decodeGetNotificationRuleRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	nr, err := h.NotificationRuleStore.FindNotificationRuleByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	orgID := nr.GetOrgID()
+	p := influxdb.Permission{
+		Action: influxdb.ReadAction,
+		Resource: influxdb.Resource{
+			Type: influxdb.OrgsResourceType,
+			ID:   &orgID,
+		},
+	}
+	if err := authorizer.IsAllowed(ctx, p); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	e, err := h.NotificationEndpointService.FindNotificationEndpointByID(ctx, nr.GetEndpointID())
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	flux, err := nr.GenerateFlux(e)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("notification rule query retrieved", zap.String("notification rule query", flux))
+	if err := encodeResponse(ctx, w, http.StatusOK, newFluxResponse(flux)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+type notificationRuleTestResponse struct {
+	Sent    bool   `json:"sent"`
+	Message string `json:"message,omitempty"`
+}
+
+// handlePostNotificationRuleTest is the HTTP handler for the POST /api/v2/notificationRules/:id/test route.
+// It sends a synthetic critical status through the rule to its endpoint.
+func (h *NotificationRuleHandler) handlePostNotificationRuleTest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("notification rule test request", zap.String("r", fmt.Sprint(r)))
+	id, err := decodeGetNotificationRuleRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	nr, err := h.NotificationRuleStore.FindNotificationRuleByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	// Sending a notification has side effects, so it requires the same
+	// write access to the rule's organization as updating the rule.
+	orgID := nr.GetOrgID()
+	p := influxdb.Permission{
+		Action: influxdb.WriteAction,
+		Resource: influxdb.Resource{
+			Type: influxdb.OrgsResourceType,
+			ID:   &orgID,
+		},
+	}
+	if err := authorizer.IsAllowed(ctx, p); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	e, err := h.NotificationEndpointService.FindNotificationEndpointByID(ctx, nr.GetEndpointID())
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	script, err := rule.GenerateTestFlux(nr, e, time.Now())
+	if err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "failed to generate the notification rule test",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	resp := notificationRuleTestResponse{Sent: true}
+	if err := h.runNotificationRuleTest(ctx, nr.GetOrgID(), script); err != nil {
+		resp = notificationRuleTestResponse{Sent: false, Message: err.Error()}
+	}
+	h.Logger.Debug("notification rule tested", zap.String("notificationRuleID", fmt.Sprint(id)), zap.Bool("sent", resp.Sent))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, resp); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// runNotificationRuleTest runs the test script of a notification rule and returns
+// an error if the notification could not be sent.
+func (h *NotificationRuleHandler) runNotificationRuleTest(ctx context.Context, orgID influxdb.ID, script string) error {
+	a, err := pctx.GetAuthorizer(ctx)
+	if err != nil {
+		return err
+	}
+
+	var token *influxdb.Authorization
+	switch a := a.(type) {
+	case *influxdb.Authorization:
+		token = a
+	case *influxdb.Session:
+		token = a.EphemeralAuth(orgID)
+	default:
+		return influxdb.ErrAuthorizerNotSupported
+	}
+
+	qs := query.QueryServiceProxyBridge{ProxyQueryService: h.QueryService}
+	results, err := qs.Query(ctx, &query.Request{
+		Authorization:  token,
+		OrganizationID: orgID,
+		Compiler:       lang.FluxCompiler{Query: script},
+	})
+	if err != nil {
+		return err
+	}
+	defer results.Release()
+
+	var n, failed int
+	for results.More() {
+		err := results.Next().Tables().Do(func(tbl flux.Table) error {
+			return tbl.Do(func(cr flux.ColReader) error {
+				j := execute.ColIdx("_sent", cr.Cols())
+				if j < 0 {
+					return nil
+				}
+				for i := 0; i < cr.Len(); i++ {
+					n++
+					if cr.Strings(j).ValueString(i) != "true" {
+						failed++
+					}
+				}
+				return nil
+			})
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if err := results.Err(); err != nil {
+		return err
+	}
+
+	switch {
+	case n == 0:
+		return fmt.Errorf("no notification was sent")
+	case failed > 0:
+		return fmt.Errorf("the endpoint did not accept the notification")
+	}
+	return nil
+}
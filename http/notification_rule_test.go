@@ -3,12 +3,29 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
 	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/endpoint"
+	"github.com/influxdata/influxdb/query"
+	querymock "github.com/influxdata/influxdb/query/mock"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
 
 	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/mock"
 	"github.com/influxdata/influxdb/notification/rule"
 	influxTesting "github.com/influxdata/influxdb/testing"
@@ -310,3 +327,362 @@ func Test_newNotificationRuleResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestService_handlePostNotificationRuleTest(t *testing.T) {
+	const sentCSV = `#datatype,string,long,string
+#group,false,false,false
+#default,_result,,
+,result,table,_sent
+,,0,%s
+
+`
+	type args struct {
+		permissions []influxdb.Permission
+		sent        string
+	}
+	type wants struct {
+		statusCode int
+		body       string
+	}
+
+	orgID := influxTesting.MustIDBase16("020f755c3c082000")
+	tests := []struct {
+		name  string
+		args  args
+		wants wants
+	}{
+		{
+			name: "send a test notification",
+			args: args{
+				permissions: influxdb.OwnerPermissions(orgID),
+				sent:        "true",
+			},
+			wants: wants{
+				statusCode: http.StatusOK,
+				body:       `{"sent": true}`,
+			},
+		},
+		{
+			name: "endpoint rejects the test notification",
+			args: args{
+				permissions: influxdb.OwnerPermissions(orgID),
+				sent:        "false",
+			},
+			wants: wants{
+				statusCode: http.StatusOK,
+				body:       `{"sent": false, "message": "the endpoint did not accept the notification"}`,
+			},
+		},
+		{
+			name: "read access is not enough to send a test notification",
+			args: args{
+				permissions: influxdb.MemberPermissions(orgID),
+				sent:        "true",
+			},
+			wants: wants{
+				statusCode: http.StatusUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var script string
+			backend := &NotificationRuleBackend{
+				HTTPErrorHandler: ErrorHandler(0),
+				Logger:           zap.NewNop(),
+				NotificationRuleStore: &mock.NotificationRuleStore{
+					FindNotificationRuleByIDF: func(ctx context.Context, id influxdb.ID) (influxdb.NotificationRule, error) {
+						return &rule.Slack{
+							Channel:         "#alerts",
+							MessageTemplate: "msg",
+							Base: rule.Base{
+								ID:         id,
+								OrgID:      orgID,
+								EndpointID: influxTesting.MustIDBase16("020f755c3c082001"),
+								Name:       "rule1",
+								Every:      mustDuration("1h"),
+							},
+						}, nil
+					},
+				},
+				NotificationEndpointService: &mock.NotificationEndpointService{
+					FindNotificationEndpointByIDF: func(ctx context.Context, id influxdb.ID) (influxdb.NotificationEndpoint, error) {
+						return &endpoint.Slack{
+							Base: endpoint.Base{
+								ID:    id,
+								OrgID: orgID,
+								Name:  "endpoint1",
+							},
+							URL:   "http://localhost:7777",
+							Token: influxdb.SecretField{Key: "slack_token"},
+						}, nil
+					},
+				},
+				QueryService: &querymock.ProxyQueryService{
+					QueryF: func(ctx context.Context, w io.Writer, req *query.ProxyRequest) (flux.Statistics, error) {
+						script = req.Request.Compiler.(lang.FluxCompiler).Query
+						_, err := io.WriteString(w, strings.Replace(sentCSV, "%s", tt.args.sent, 1))
+						return flux.Statistics{}, err
+					},
+				},
+			}
+			h := NewNotificationRuleHandler(backend)
+
+			r := httptest.NewRequest("POST", "http://any.url", nil)
+			ctx := pcontext.SetAuthorizer(context.Background(), &influxdb.Authorization{
+				Status:      influxdb.Active,
+				OrgID:       orgID,
+				Permissions: tt.args.permissions,
+			})
+			r = r.WithContext(context.WithValue(ctx, httprouter.ParamsKey, httprouter.Params{
+				{
+					Key:   "id",
+					Value: "020f755c3c082002",
+				},
+			}))
+
+			w := httptest.NewRecorder()
+			h.handlePostNotificationRuleTest(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.wants.statusCode {
+				t.Fatalf("%q. handlePostNotificationRuleTest() = %v, want %v: %s", tt.name, res.StatusCode, tt.wants.statusCode, body)
+			}
+			if tt.wants.body == "" {
+				return
+			}
+			if eq, diff, err := jsonEqual(string(body), tt.wants.body); err != nil || !eq {
+				t.Errorf("%q. handlePostNotificationRuleTest() = ***%s***", tt.name, diff)
+			}
+
+			if !strings.Contains(script, `channel: "#alerts"`) {
+				t.Errorf("%q. test script does not target the rule's channel:\n%s", tt.name, script)
+			}
+		})
+	}
+}
+
+func TestService_handleGetNotificationRuleQuery(t *testing.T) {
+	orgID := influxTesting.MustIDBase16("020f755c3c082000")
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		statusCode  int
+	}{
+		{
+			name:        "get the query of a slack rule",
+			permissions: influxdb.MemberPermissions(orgID),
+			statusCode:  http.StatusOK,
+		},
+		{
+			name:       "access to the org is required",
+			statusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := &NotificationRuleBackend{
+				HTTPErrorHandler: ErrorHandler(0),
+				Logger:           zap.NewNop(),
+				NotificationRuleStore: &mock.NotificationRuleStore{
+					FindNotificationRuleByIDF: func(ctx context.Context, id influxdb.ID) (influxdb.NotificationRule, error) {
+						return &rule.Slack{
+							Channel:         "#alerts",
+							MessageTemplate: "msg",
+							Base: rule.Base{
+								ID:         id,
+								OrgID:      orgID,
+								EndpointID: influxTesting.MustIDBase16("020f755c3c082001"),
+								Name:       "rule1",
+								Every:      mustDuration("1h"),
+							},
+						}, nil
+					},
+				},
+				NotificationEndpointService: &mock.NotificationEndpointService{
+					FindNotificationEndpointByIDF: func(ctx context.Context, id influxdb.ID) (influxdb.NotificationEndpoint, error) {
+						return &endpoint.Slack{
+							Base: endpoint.Base{
+								ID:    id,
+								OrgID: orgID,
+								Name:  "endpoint1",
+							},
+							URL:   "http://localhost:7777",
+							Token: influxdb.SecretField{Key: "slack_token"},
+						}, nil
+					},
+				},
+			}
+			h := NewNotificationRuleHandler(backend)
+
+			r := httptest.NewRequest("GET", "http://any.url", nil)
+			ctx := pcontext.SetAuthorizer(context.Background(), &influxdb.Authorization{
+				Status:      influxdb.Active,
+				OrgID:       orgID,
+				Permissions: tt.permissions,
+			})
+			r = r.WithContext(context.WithValue(ctx, httprouter.ParamsKey, httprouter.Params{
+				{
+					Key:   "id",
+					Value: "020f755c3c082002",
+				},
+			}))
+
+			w := httptest.NewRecorder()
+			h.handleGetNotificationRuleQuery(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.statusCode {
+				t.Fatalf("%q. handleGetNotificationRuleQuery() = %v, want %v: %s", tt.name, res.StatusCode, tt.statusCode, body)
+			}
+			if tt.statusCode != http.StatusOK {
+				return
+			}
+
+			var resp fluxResp
+			if err := json.Unmarshal(body, &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if !strings.Contains(resp.Flux, "slack.endpoint(") {
+				t.Errorf("%q. query does not send a slack message:\n%s", tt.name, resp.Flux)
+			}
+			if !strings.Contains(resp.Flux, `channel: "#alerts"`) {
+				t.Errorf("%q. query does not target the rule's channel:\n%s", tt.name, resp.Flux)
+			}
+		})
+	}
+}
+
+func TestService_handleGetNotificationRules_Paging(t *testing.T) {
+	ctx := context.Background()
+	svc := kv.NewService(inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	orgID := influxTesting.MustIDBase16("020f755c3c082000")
+	if err := svc.PutOrganization(ctx, &influxdb.Organization{ID: orgID, Name: "org"}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 3; i++ {
+		nr := &rule.Slack{
+			Channel:         "ch",
+			MessageTemplate: "msg",
+			Base: rule.Base{
+				ID:         influxdb.ID(i),
+				OrgID:      orgID,
+				OwnerID:    influxTesting.MustIDBase16("020f755c3c082001"),
+				EndpointID: influxTesting.MustIDBase16("020f755c3c082002"),
+				Name:       fmt.Sprintf("rule%d", i),
+				Status:     influxdb.Active,
+				Every:      mustDuration("1h"),
+			},
+		}
+		if err := svc.PutNotificationRule(ctx, nr); err != nil {
+			t.Fatal(err)
+		}
+		if err := svc.CreateUserResourceMapping(ctx, &influxdb.UserResourceMapping{
+			ResourceID:   nr.ID,
+			ResourceType: influxdb.NotificationRuleResourceType,
+			UserID:       nr.OwnerID,
+			UserType:     influxdb.Owner,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	h := NewNotificationRuleHandler(&NotificationRuleBackend{
+		HTTPErrorHandler:      ErrorHandler(0),
+		Logger:                zap.NewNop(),
+		NotificationRuleStore: svc,
+		LabelService:          mock.NewLabelService(),
+	})
+
+	getPage := func(u string) (ids []string, next string) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.handleGetNotificationRules(w, httptest.NewRequest("GET", u, nil))
+
+		res := w.Result()
+		if res.StatusCode != http.StatusOK {
+			body, _ := ioutil.ReadAll(res.Body)
+			t.Fatalf("GET %s = %v: %s", u, res.StatusCode, body)
+		}
+		var page struct {
+			NotificationRules []struct {
+				ID string `json:"id"`
+			} `json:"notificationRules"`
+			Links influxdb.PagingLinks `json:"links"`
+		}
+		if err := json.NewDecoder(res.Body).Decode(&page); err != nil {
+			t.Fatal(err)
+		}
+		for _, nr := range page.NotificationRules {
+			ids = append(ids, nr.ID)
+		}
+		return ids, page.Links.Next
+	}
+
+	first, next := getPage("http://any.url/api/v2/notificationRules?orgID=" + orgID.String() + "&limit=2")
+	if exp := []string{influxdb.ID(1).String(), influxdb.ID(2).String()}; !reflect.DeepEqual(first, exp) {
+		t.Fatalf("unexpected first page: got %v, exp %v", first, exp)
+	}
+	if next == "" {
+		t.Fatal("expected a link to the next page")
+	}
+
+	second, _ := getPage("http://any.url" + next)
+	if exp := []string{influxdb.ID(3).String()}; !reflect.DeepEqual(second, exp) {
+		t.Fatalf("unexpected second page: got %v, exp %v", second, exp)
+	}
+	for _, id := range second {
+		for _, fid := range first {
+			if id == fid {
+				t.Fatalf("rule %s is on both pages", id)
+			}
+		}
+	}
+}
+
+func Test_decodeNotificationRuleFilter(t *testing.T) {
+	orgID := influxTesting.MustIDBase16("020f755c3c082000")
+	org, name := "org1", "rule1"
+	tests := []struct {
+		name string
+		url  string
+		want influxdb.NotificationRuleFilter
+	}{
+		{
+			name: "by org id and name",
+			url:  "http://any.url/api/v2/notificationRules?orgID=" + orgID.String() + "&name=" + name,
+			want: influxdb.NotificationRuleFilter{
+				OrgID: &orgID,
+				Name:  &name,
+			},
+		},
+		{
+			name: "by org name",
+			url:  "http://any.url/api/v2/notificationRules?org=" + org,
+			want: influxdb.NotificationRuleFilter{
+				Organization: &org,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got, _, err := decodeNotificationRuleFilter(context.Background(), r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got.UserResourceMappingFilter = influxdb.UserResourceMappingFilter{}
+			if !reflect.DeepEqual(tt.want, *got) {
+				t.Errorf("unexpected filter: got %+v, exp %+v", *got, tt.want)
+			}
+		})
+	}
+}
@@ -6,20 +6,27 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"math"
 	"net/http"
 	"net/url"
 	"path"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
 	"github.com/influxdata/influxdb"
 	pcontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/kv"
 	"github.com/influxdata/influxdb/task/backend"
+	"github.com/influxdata/influxdb/task/options"
 	"github.com/julienschmidt/httprouter"
 	"go.uber.org/zap"
+	"golang.org/x/time/rate"
 )
 
 // TaskBackend is all services and associated parameters required to construct
@@ -35,6 +42,18 @@ type TaskBackend struct {
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
 	BucketService              influxdb.BucketService
+	// TaskHealthService is read by the health check, which is served without
+	// authentication. It defaults to TaskService.
+	TaskHealthService influxdb.TaskService
+
+	// ValidateBuckets rejects tasks that write to buckets that do not exist.
+	ValidateBuckets bool
+	// ForceRunMaxFuture rejects manual runs scheduled further than it in the
+	// future. Zero allows any time.
+	ForceRunMaxFuture time.Duration
+	// OrgRequestsPerSecond limits the rate of requests each organization can
+	// make to the task API. Zero disables the limit.
+	OrgRequestsPerSecond int
 }
 
 // NewTaskBackend returns a new instance of TaskBackend.
@@ -49,6 +68,10 @@ func NewTaskBackend(b *APIBackend) *TaskBackend {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		BucketService:              b.BucketService,
+		TaskHealthService:          b.TaskHealthService,
+		ValidateBuckets:            b.TaskValidateBuckets,
+		ForceRunMaxFuture:          b.TaskForceRunMaxFuture,
+		OrgRequestsPerSecond:       b.TaskOrgRequestsPerSecond,
 	}
 }
 
@@ -65,12 +88,22 @@ type TaskHandler struct {
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
 	BucketService              influxdb.BucketService
+
+	healthService     influxdb.TaskService
+	validateBuckets   bool
+	forceRunMaxFuture time.Duration
+	orgLimiter        *orgRateLimiter
 }
 
 const (
 	tasksPath              = "/api/v2/tasks"
+	tasksValidatePath      = "/api/v2/tasks/validate"
+	tasksImportPath        = "/api/v2/tasks/import"
+	tasksHealthPath        = "/api/v2/tasks/health"
 	tasksIDPath            = "/api/v2/tasks/:id"
+	tasksIDExportPath      = "/api/v2/tasks/:id/export"
 	tasksIDLogsPath        = "/api/v2/tasks/:id/logs"
+	tasksIDNextPath        = "/api/v2/tasks/:id/next"
 	tasksIDMembersPath     = "/api/v2/tasks/:id/members"
 	tasksIDMembersIDPath   = "/api/v2/tasks/:id/members/:userID"
 	tasksIDOwnersPath      = "/api/v2/tasks/:id/owners"
@@ -97,6 +130,15 @@ func NewTaskHandler(b *TaskBackend) *TaskHandler {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		BucketService:              b.BucketService,
+		healthService:              b.TaskHealthService,
+		validateBuckets:            b.ValidateBuckets,
+		forceRunMaxFuture:          b.ForceRunMaxFuture,
+	}
+	if h.healthService == nil {
+		h.healthService = b.TaskService
+	}
+	if b.OrgRequestsPerSecond > 0 {
+		h.orgLimiter = newOrgRateLimiter(b.OrgRequestsPerSecond)
 	}
 
 	h.HandlerFunc("GET", tasksPath, h.handleGetTasks)
@@ -105,6 +147,8 @@ func NewTaskHandler(b *TaskBackend) *TaskHandler {
 	h.HandlerFunc("GET", tasksIDPath, h.handleGetTask)
 	h.HandlerFunc("PATCH", tasksIDPath, h.handleUpdateTask)
 	h.HandlerFunc("DELETE", tasksIDPath, h.handleDeleteTask)
+	h.HandlerFunc("GET", tasksIDExportPath, h.handleExportTask)
+	h.HandlerFunc("GET", tasksIDNextPath, h.handleGetNextRuns)
 
 	h.HandlerFunc("GET", tasksIDLogsPath, h.handleGetLogs)
 	h.HandlerFunc("GET", tasksIDRunsIDLogsPath, h.handleGetLogs)
@@ -137,6 +181,7 @@ func NewTaskHandler(b *TaskBackend) *TaskHandler {
 	h.HandlerFunc("POST", tasksIDRunsPath, h.handleForceRun)
 	h.HandlerFunc("GET", tasksIDRunsIDPath, h.handleGetRun)
 	h.HandlerFunc("POST", tasksIDRunsIDRetryPath, h.handleRetryRun)
+	h.HandlerFunc("POST", tasksIDRunsIDPath, h.handlePostRun)
 	h.HandlerFunc("DELETE", tasksIDRunsIDPath, h.handleCancelRun)
 
 	labelBackend := &LabelBackend{
@@ -152,6 +197,156 @@ func NewTaskHandler(b *TaskBackend) *TaskHandler {
 	return h
 }
 
+// ServeHTTP serves the task validation, import and health endpoints and delegates every other request to the router.
+// These paths are matched here because httprouter cannot register them alongside the :id wildcard.
+func (h *TaskHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// The health of the tasks is not rate limited, so it can be monitored.
+	if r.Method == "GET" && r.URL.Path == tasksHealthPath {
+		h.handleGetTasksHealth(w, r)
+		return
+	}
+	if h.orgLimiter != nil {
+		if wait, ok := h.orgLimiter.allow(r.Context()); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			h.HandleHTTPError(r.Context(), &influxdb.Error{
+				Code: influxdb.ETooManyRequests,
+				Msg:  "too many task requests for the organization, try again later",
+			}, w)
+			return
+		}
+	}
+	if r.Method == "POST" && r.URL.Path == tasksValidatePath {
+		h.handleValidateTask(w, r)
+		return
+	}
+	if r.Method == "POST" && r.URL.Path == tasksImportPath {
+		h.handleImportTask(w, r)
+		return
+	}
+	h.Router.ServeHTTP(w, r)
+}
+
+// orgRateLimiterIdle is how long the limiter of an organization is kept without
+// requests. Its bucket refills long before that, so dropping it is the same as
+// keeping it.
+const orgRateLimiterIdle = 10 * time.Minute
+
+// orgRateLimiter limits the rate of requests with a token bucket for each organization.
+// Limiters of organizations without requests for orgRateLimiterIdle are evicted.
+type orgRateLimiter struct {
+	limit rate.Limit
+	burst int
+	now   func() time.Time
+
+	mu        sync.Mutex
+	limiters  map[influxdb.ID]*orgLimiter
+	lastSweep time.Time
+}
+
+// orgLimiter is the limiter of an organization and the time it was last used.
+type orgLimiter struct {
+	*rate.Limiter
+	lastSeen time.Time
+}
+
+func newOrgRateLimiter(perSecond int) *orgRateLimiter {
+	return &orgRateLimiter{
+		limit:    rate.Limit(perSecond),
+		burst:    perSecond,
+		now:      time.Now,
+		limiters: make(map[influxdb.ID]*orgLimiter),
+	}
+}
+
+// allow reports whether the request made with ctx is within the limit of its
+// organization and, if not, how long to wait before trying again. Requests made
+// with a token are limited by the token's organization. Requests made by any
+// other authorizer, such as a session, are limited by its user.
+func (l *orgRateLimiter) allow(ctx context.Context) (time.Duration, bool) {
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return 0, true
+	}
+	id := a.GetUserID()
+	if auth, ok := a.(*influxdb.Authorization); ok {
+		id = auth.OrgID
+	}
+
+	now := l.now()
+	l.mu.Lock()
+	if now.Sub(l.lastSweep) >= orgRateLimiterIdle {
+		l.sweep(now)
+	}
+	limiter, ok := l.limiters[id]
+	if !ok {
+		limiter = &orgLimiter{Limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.limiters[id] = limiter
+	}
+	limiter.lastSeen = now
+	l.mu.Unlock()
+
+	res := limiter.ReserveN(now, 1)
+	if wait := res.DelayFrom(now); wait > 0 {
+		res.CancelAt(now)
+		return wait, false
+	}
+	return 0, true
+}
+
+// sweep evicts the limiters that have not been used for orgRateLimiterIdle.
+// l.mu must be held.
+func (l *orgRateLimiter) sweep(now time.Time) {
+	for id, limiter := range l.limiters {
+		if now.Sub(limiter.lastSeen) >= orgRateLimiterIdle {
+			delete(l.limiters, id)
+		}
+	}
+	l.lastSweep = now
+}
+
+type tasksHealthCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+type tasksHealthResponse struct {
+	Name    string             `json:"name"`
+	Message string             `json:"message"`
+	Status  string             `json:"status"`
+	Checks  []tasksHealthCheck `json:"checks"`
+}
+
+// handleGetTasksHealth reports whether the task store can be read, by
+// listing at most one task. It is served without authentication, like /health.
+func (h *TaskHandler) handleGetTasksHealth(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	check := tasksHealthCheck{Name: "store", Status: "pass"}
+	if _, _, err := h.healthService.FindTasks(ctx, influxdb.TaskFilter{Limit: 1}); err != nil {
+		h.logger.Info("task health check failed", zap.Error(err))
+		check.Status = "fail"
+		check.Message = err.Error()
+	}
+
+	res := tasksHealthResponse{
+		Name:    "tasks",
+		Message: "ready for tasks",
+		Status:  check.Status,
+		Checks:  []tasksHealthCheck{check},
+	}
+	code := http.StatusOK
+	if check.Status != "pass" {
+		res.Message = "unable to read tasks"
+		code = http.StatusServiceUnavailable
+	}
+
+	if err := encodeResponse(ctx, w, code, res); err != nil {
+		logEncodingError(h.logger, r, err)
+		return
+	}
+}
+
 type taskResponse struct {
 	Links  map[string]string `json:"links"`
 	Labels []influxdb.Label  `json:"labels"`
@@ -180,7 +375,7 @@ func newTaskResponse(t influxdb.Task, labels []*influxdb.Label) taskResponse {
 }
 
 func newTasksPagingLinks(basePath string, ts []*influxdb.Task, f influxdb.TaskFilter) *influxdb.PagingLinks {
-	var self, next string
+	var self, next, prev string
 	u := url.URL{
 		Path: basePath,
 	}
@@ -197,13 +392,28 @@ func newTasksPagingLinks(basePath string, ts []*influxdb.Task, f influxdb.TaskFi
 	u.RawQuery = values.Encode()
 	self = u.String()
 
-	if len(ts) >= f.Limit {
-		values.Set("after", ts[f.Limit-1].ID.String())
+	// a page reached by paging backwards always has a page after it.
+	if len(ts) >= f.Limit || (f.Before != nil && len(ts) > 0) {
+		last := len(ts) - 1
+		if last >= f.Limit {
+			last = f.Limit - 1
+		}
+		values.Del("before")
+		values.Set("after", ts[last].ID.String())
 		u.RawQuery = values.Encode()
 		next = u.String()
 	}
 
+	// a page reached by paging forwards always has a page before it.
+	if (f.After != nil && len(ts) > 0) || (f.Before != nil && len(ts) >= f.Limit) {
+		values.Del("after")
+		values.Set("before", ts[0].ID.String())
+		u.RawQuery = values.Encode()
+		prev = u.String()
+	}
+
 	links := &influxdb.PagingLinks{
+		Prev: prev,
 		Self: self,
 		Next: next,
 	}
@@ -222,19 +432,28 @@ func newTasksResponse(ctx context.Context, ts []*influxdb.Task, f influxdb.TaskF
 		Tasks: make([]taskResponse, len(ts)),
 	}
 
+	ids := make([]influxdb.ID, len(ts))
+	for i := range ts {
+		ids[i] = ts[i].ID
+	}
+	labels, _ := labelService.FindResourceLabelsForResources(ctx, influxdb.TasksResourceType, ids)
 	for i := range ts {
-		labels, _ := labelService.FindResourceLabels(ctx, influxdb.LabelMappingFilter{ResourceID: ts[i].ID})
-		rs.Tasks[i] = newTaskResponse(*ts[i], labels)
+		rs.Tasks[i] = newTaskResponse(*ts[i], labels[ts[i].ID])
 	}
 	return rs
 }
 
 type runResponse struct {
-	Links map[string]string `json:"links,omitempty"`
+	Links    map[string]string `json:"links,omitempty"`
+	Duration string            `json:"duration,omitempty"`
 	influxdb.Run
 }
 
 func newRunResponse(r influxdb.Run) runResponse {
+	var duration string
+	if d, ok := r.Duration(); ok {
+		duration = d.String()
+	}
 	return runResponse{
 		Links: map[string]string{
 			"self":  fmt.Sprintf("/api/v2/tasks/%s/runs/%s", r.TaskID, r.ID),
@@ -242,7 +461,8 @@ func newRunResponse(r influxdb.Run) runResponse {
 			"logs":  fmt.Sprintf("/api/v2/tasks/%s/runs/%s/logs", r.TaskID, r.ID),
 			"retry": fmt.Sprintf("/api/v2/tasks/%s/runs/%s/retry", r.TaskID, r.ID),
 		},
-		Run: r,
+		Duration: duration,
+		Run:      r,
 	}
 }
 
@@ -281,6 +501,19 @@ func (h *TaskHandler) handleGetTasks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.count {
+		n, err := h.TaskService.CountTasks(ctx, req.filter)
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		if err := encodeResponse(ctx, w, http.StatusOK, &tasksCountResponse{Count: n}); err != nil {
+			logEncodingError(h.logger, r, err)
+			return
+		}
+		return
+	}
+
 	tasks, _, err := h.TaskService.FindTasks(ctx, req.filter)
 	if err != nil {
 		h.HandleHTTPError(ctx, err, w)
@@ -293,8 +526,13 @@ func (h *TaskHandler) handleGetTasks(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+type tasksCountResponse struct {
+	Count int `json:"count"`
+}
+
 type getTasksRequest struct {
 	filter influxdb.TaskFilter
+	count  bool
 }
 
 func decodeGetTasksRequest(ctx context.Context, r *http.Request, orgs influxdb.OrganizationService) (*getTasksRequest, error) {
@@ -309,6 +547,18 @@ func decodeGetTasksRequest(ctx context.Context, r *http.Request, orgs influxdb.O
 		req.filter.After = id
 	}
 
+	if before := qp.Get("before"); before != "" {
+		id, err := influxdb.IDFromString(before)
+		if err != nil {
+			return nil, err
+		}
+		req.filter.Before = id
+	}
+
+	if req.filter.After != nil && req.filter.Before != nil {
+		return nil, influxdb.ErrAfterAndBeforeTaskFilter
+	}
+
 	if orgName := qp.Get("org"); orgName != "" {
 		o, err := orgs.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &orgName})
 		if err != nil {
@@ -341,6 +591,22 @@ func decodeGetTasksRequest(ctx context.Context, r *http.Request, orgs influxdb.O
 		req.filter.User = id
 	}
 
+	if ownerID := qp.Get("ownerID"); ownerID != "" {
+		id, err := influxdb.IDFromString(ownerID)
+		if err != nil {
+			return nil, err
+		}
+		req.filter.OwnerID = id
+	}
+
+	if labelID := qp.Get("labelID"); labelID != "" {
+		id, err := influxdb.IDFromString(labelID)
+		if err != nil {
+			return nil, err
+		}
+		req.filter.LabelID = id
+	}
+
 	if limit := qp.Get("limit"); limit != "" {
 		lim, err := strconv.Atoi(limit)
 		if err != nil {
@@ -361,18 +627,522 @@ func decodeGetTasksRequest(ctx context.Context, r *http.Request, orgs influxdb.O
 		req.filter.Type = &ttype
 	}
 
-	if name := qp.Get("name"); name != "" {
-		req.filter.Name = &name
+	if name := qp.Get("name"); name != "" {
+		req.filter.Name = &name
+	}
+
+	if count := qp.Get("count"); count != "" {
+		c, err := strconv.ParseBool(count)
+		if err != nil {
+			return nil, err
+		}
+		if c && req.filter.Before != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "before cannot be used when counting tasks",
+			}
+		}
+		req.count = c
+	}
+
+	return req, nil
+}
+
+func (h *TaskHandler) handlePostTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.logger.Debug("task create request", zap.String("r", fmt.Sprint(r)))
+
+	req, err := decodePostTaskRequest(ctx, r)
+	if err != nil {
+		err = &influxdb.Error{
+			Err:  err,
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.populateTaskCreateOrg(ctx, &req.TaskCreate); err != nil {
+		err = &influxdb.Error{
+			Err: err,
+			Msg: "could not identify organization",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if !req.TaskCreate.OrganizationID.Valid() {
+		err := &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid organization id",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if h.validateBuckets {
+		if err := h.validateTaskBuckets(ctx, req.TaskCreate.OrganizationID, req.TaskCreate.Flux); err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+	}
+
+	task, err := h.TaskService.CreateTask(ctx, req.TaskCreate)
+	if err != nil {
+		if e, ok := err.(AuthzError); ok {
+			h.logger.Error("failed authentication", zap.Errors("error messages", []error{err, e.AuthzError()}))
+		}
+
+		// if the error is not already a influxdb.error then make it into one
+		if _, ok := err.(*influxdb.Error); !ok {
+			err = &influxdb.Error{
+				Err:  err,
+				Code: influxdb.EInternal,
+				Msg:  "failed to create task",
+			}
+		}
+
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	labels, err := h.attachTaskLabels(ctx, task, req.TaskCreate.Labels)
+	if err != nil {
+		// Roll back, so that a retry of the request doesn't create the task twice.
+		if derr := h.TaskService.DeleteTask(ctx, task.ID); derr != nil {
+			h.logger.Error("failed to delete task after failing to attach its labels", zap.Stringer("task_id", task.ID), zap.Error(derr))
+		}
+		err = &influxdb.Error{
+			Err: err,
+			Msg: "failed to attach labels to task",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newTaskResponse(*task, labels)); err != nil {
+		logEncodingError(h.logger, r, err)
+		return
+	}
+}
+
+// attachTaskLabels attaches the labels with the given IDs to the task and
+// returns them. If a label can't be attached, the labels attached before it
+// are detached again.
+func (h *TaskHandler) attachTaskLabels(ctx context.Context, task *influxdb.Task, ids []influxdb.ID) ([]*influxdb.Label, error) {
+	labels := make([]*influxdb.Label, 0, len(ids))
+	var mappings []*influxdb.LabelMapping
+	detach := func() {
+		for _, m := range mappings {
+			if err := h.LabelService.DeleteLabelMapping(ctx, m); err != nil {
+				h.logger.Error("failed to detach label from task", zap.Stringer("task_id", task.ID), zap.Stringer("label_id", m.LabelID), zap.Error(err))
+			}
+		}
+	}
+
+	for _, id := range ids {
+		l, err := h.LabelService.FindLabelByID(ctx, id)
+		if err != nil {
+			detach()
+			return nil, err
+		}
+		if l.OrgID != task.OrganizationID {
+			detach()
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("label %s does not belong to the task's organization", id),
+			}
+		}
+
+		m := &influxdb.LabelMapping{
+			LabelID:      l.ID,
+			ResourceID:   task.ID,
+			ResourceType: influxdb.TasksResourceType,
+		}
+		if err := h.LabelService.CreateLabelMapping(ctx, m); err != nil {
+			detach()
+			return nil, err
+		}
+		mappings = append(mappings, m)
+		labels = append(labels, l)
+	}
+	return labels, nil
+}
+
+type postTaskRequest struct {
+	TaskCreate influxdb.TaskCreate
+}
+
+func decodePostTaskRequest(ctx context.Context, r *http.Request) (*postTaskRequest, error) {
+	var tc influxdb.TaskCreate
+	if err := json.NewDecoder(r.Body).Decode(&tc); err != nil {
+		return nil, err
+	}
+
+	// pull auth from ctx, populate OwnerID
+	auth, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tc.OwnerID = auth.GetUserID()
+
+	if err := tc.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &postTaskRequest{
+		TaskCreate: tc,
+	}, nil
+}
+
+func (h *TaskHandler) handleValidateTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.logger.Debug("task validate request", zap.String("r", fmt.Sprint(r)))
+
+	req, err := decodeValidateTaskRequest(ctx, r)
+	if err != nil {
+		err = &influxdb.Error{
+			Err:  err,
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	opts, err := options.FromScript(req.Flux)
+	if err != nil {
+		err = &influxdb.Error{
+			Err:  err,
+			Code: influxdb.EInvalid,
+			Msg:  "invalid flux script",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newValidateTaskResponse(opts)); err != nil {
+		logEncodingError(h.logger, r, err)
+		return
+	}
+}
+
+// validateTaskBuckets checks that the buckets written to by the `to` calls
+// in script exist. Buckets that are not given as literals, or that are on
+// another host, are not checked. Scripts that do not parse are left to the
+// task service to reject.
+func (h *TaskHandler) validateTaskBuckets(ctx context.Context, orgID influxdb.ID, script string) error {
+	pkg := parser.ParseSource(script)
+	if ast.Check(pkg) > 0 {
+		return nil
+	}
+
+	var filters []influxdb.BucketFilter
+	ast.Walk(ast.CreateVisitor(func(node ast.Node) {
+		call, ok := node.(*ast.CallExpression)
+		if !ok || len(call.Arguments) != 1 {
+			return
+		}
+		if callee, ok := call.Callee.(*ast.Identifier); !ok || callee.Name != "to" {
+			return
+		}
+		if obj, ok := call.Arguments[0].(*ast.ObjectExpression); ok {
+			if filter, ok := toBucketFilter(obj, orgID); ok {
+				filters = append(filters, filter)
+			}
+		}
+	}), pkg)
+
+	for _, filter := range filters {
+		if _, err := h.BucketService.FindBucket(ctx, filter); err != nil {
+			if influxdb.ErrorCode(err) != influxdb.ENotFound {
+				return err
+			}
+			var bucket string
+			if filter.Name != nil {
+				bucket = *filter.Name
+			} else {
+				bucket = filter.ID.String()
+			}
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("task writes to bucket %q, which does not exist", bucket),
+			}
+		}
+	}
+	return nil
+}
+
+// toBucketFilter returns a filter for the bucket written to by the
+// arguments of a `to` call.
+func toBucketFilter(args *ast.ObjectExpression, orgID influxdb.ID) (influxdb.BucketFilter, bool) {
+	literals := make(map[string]string, len(args.Properties))
+	for _, p := range args.Properties {
+		if p.Key.Key() == "host" {
+			return influxdb.BucketFilter{}, false
+		}
+		if lit, ok := p.Value.(*ast.StringLiteral); ok {
+			literals[p.Key.Key()] = lit.Value
+		}
+	}
+
+	var filter influxdb.BucketFilter
+	if name, ok := literals["bucket"]; ok {
+		filter.Name = &name
+	} else if id, ok := literals["bucketID"]; ok {
+		bucketID, err := influxdb.IDFromString(id)
+		if err != nil {
+			return filter, false
+		}
+		filter.ID = bucketID
+	} else {
+		return filter, false
+	}
+
+	if org, ok := literals["org"]; ok {
+		filter.Org = &org
+	} else if id, ok := literals["orgID"]; ok {
+		oid, err := influxdb.IDFromString(id)
+		if err != nil {
+			return filter, false
+		}
+		filter.OrganizationID = oid
+	} else {
+		filter.OrganizationID = &orgID
+	}
+	return filter, true
+}
+
+type validateTaskRequest struct {
+	Flux string `json:"flux"`
+}
+
+func decodeValidateTaskRequest(ctx context.Context, r *http.Request) (*validateTaskRequest, error) {
+	req := &validateTaskRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		return nil, err
+	}
+
+	if req.Flux == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "flux required",
+		}
+	}
+
+	return req, nil
+}
+
+type validateTaskResponse struct {
+	Name   string `json:"name"`
+	Cron   string `json:"cron,omitempty"`
+	Every  string `json:"every,omitempty"`
+	Offset string `json:"offset,omitempty"`
+}
+
+func newValidateTaskResponse(opts options.Options) validateTaskResponse {
+	res := validateTaskResponse{
+		Name: opts.Name,
+		Cron: opts.Cron,
+	}
+	if !opts.Every.IsZero() {
+		res.Every = opts.Every.String()
+	}
+	if opts.Offset != nil && !opts.Offset.IsZero() {
+		res.Offset = opts.Offset.String()
+	}
+	return res
+}
+
+func (h *TaskHandler) handleGetTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.logger.Debug("task retrieve request", zap.String("r", fmt.Sprint(r)))
+	req, err := decodeGetTaskRequest(ctx, r)
+	if err != nil {
+		err = &influxdb.Error{
+			Err:  err,
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	task, err := h.TaskService.FindTaskByID(ctx, req.TaskID)
+	if err != nil {
+		err = &influxdb.Error{
+			Err:  err,
+			Code: influxdb.ENotFound,
+			Msg:  "failed to find task",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	labels, err := h.LabelService.FindResourceLabels(ctx, influxdb.LabelMappingFilter{ResourceID: task.ID})
+	if err != nil {
+		err = &influxdb.Error{
+			Err: err,
+			Msg: "failed to find resource labels",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.logger.Debug("task retrived", zap.String("tasks", fmt.Sprint(task)))
+	if err := encodeResponse(ctx, w, http.StatusOK, newTaskResponse(*task, labels)); err != nil {
+		logEncodingError(h.logger, r, err)
+		return
+	}
+}
+
+type getTaskRequest struct {
+	TaskID influxdb.ID
+}
+
+// maxNextRuns is the largest number of upcoming runs that can be previewed at once.
+const maxNextRuns = 100
+
+// handleGetNextRuns previews when the task is next scheduled to run, without
+// creating any runs.
+func (h *TaskHandler) handleGetNextRuns(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetNextRunsRequest(ctx, r)
+	if err != nil {
+		err = &influxdb.Error{
+			Err:  err,
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	task, err := h.TaskService.FindTaskByID(ctx, req.TaskID)
+	if err != nil {
+		err = &influxdb.Error{
+			Err:  err,
+			Code: influxdb.ENotFound,
+			Msg:  "failed to find task",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	next, err := nextRuns(task, req.Count)
+	if err != nil {
+		h.HandleHTTPError(ctx, influxdb.ErrTaskTimeParse(err), w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newNextRunsResponse(task.ID, next)); err != nil {
+		logEncodingError(h.logger, r, err)
+		return
+	}
+}
+
+type getNextRunsRequest struct {
+	TaskID influxdb.ID
+	Count  int
+}
+
+func decodeGetNextRunsRequest(ctx context.Context, r *http.Request) (*getNextRunsRequest, error) {
+	tr, err := decodeGetTaskRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &getNextRunsRequest{TaskID: tr.TaskID, Count: 1}
+	if c := r.URL.Query().Get("count"); c != "" {
+		i, err := strconv.Atoi(c)
+		if err != nil {
+			return nil, err
+		}
+		if i < 1 || i > maxNextRuns {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("count must be between 1 and %d", maxNextRuns),
+			}
+		}
+		req.Count = i
+	}
+	return req, nil
+}
+
+type nextRun struct {
+	ScheduledFor time.Time `json:"scheduledFor"`
+	DueAt        time.Time `json:"dueAt"`
+}
+
+type nextRunsResponse struct {
+	Links map[string]string `json:"links"`
+	Next  []nextRun         `json:"next"`
+}
+
+func newNextRunsResponse(taskID influxdb.ID, next []nextRun) nextRunsResponse {
+	if next == nil {
+		next = []nextRun{}
+	}
+	return nextRunsResponse{
+		Links: map[string]string{
+			"self": path.Join(taskIDPath(taskID), "next"),
+			"task": taskIDPath(taskID),
+		},
+		Next: next,
+	}
+}
+
+// nextRuns returns the next count runs of the task after its latest
+// scheduled run, along with when each of them is due once the task's offset
+// is applied.
+func nextRuns(task *influxdb.Task, count int) ([]nextRun, error) {
+	opts := options.Options{Cron: task.Cron}
+	if task.Every != "" {
+		if err := opts.Every.Parse(task.Every); err != nil {
+			return nil, err
+		}
+	}
+	offset := options.Duration{}
+	if task.Offset != "" {
+		if err := offset.Parse(task.Offset); err != nil {
+			return nil, err
+		}
+	}
+
+	latest := task.LatestCompleted
+	if latest == "" {
+		latest = task.CreatedAt
+	}
+	latestTime, err := time.Parse(time.RFC3339, latest)
+	if err != nil {
+		return nil, err
+	}
+
+	times, err := opts.NextScheduled(latestTime, count)
+	if err != nil {
+		return nil, err
+	}
+
+	next := make([]nextRun, 0, len(times))
+	for _, t := range times {
+		dueAt, err := offset.Add(t)
+		if err != nil {
+			return nil, err
+		}
+		next = append(next, nextRun{ScheduledFor: t, DueAt: dueAt})
 	}
-
-	return req, nil
+	return next, nil
 }
 
-func (h *TaskHandler) handlePostTask(w http.ResponseWriter, r *http.Request) {
+func (h *TaskHandler) handleExportTask(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	h.logger.Debug("task create request", zap.String("r", fmt.Sprint(r)))
-
-	req, err := decodePostTaskRequest(ctx, r)
+	h.logger.Debug("task export request", zap.String("r", fmt.Sprint(r)))
+	req, err := decodeGetTaskRequest(ctx, r)
 	if err != nil {
 		err = &influxdb.Error{
 			Err:  err,
@@ -383,123 +1153,178 @@ func (h *TaskHandler) handlePostTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.populateTaskCreateOrg(ctx, &req.TaskCreate); err != nil {
+	task, err := h.TaskService.FindTaskByID(ctx, req.TaskID)
+	if err != nil {
+		err = &influxdb.Error{
+			Err:  err,
+			Code: influxdb.ENotFound,
+			Msg:  "failed to find task",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	labels, err := h.LabelService.FindResourceLabels(ctx, influxdb.LabelMappingFilter{ResourceID: task.ID})
+	if err != nil {
 		err = &influxdb.Error{
 			Err: err,
-			Msg: "could not identify organization",
+			Msg: "failed to find resource labels",
 		}
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
 
-	if !req.TaskCreate.OrganizationID.Valid() {
-		err := &influxdb.Error{
+	if err := encodeResponse(ctx, w, http.StatusOK, influxdb.NewTaskExport(*task, labels)); err != nil {
+		logEncodingError(h.logger, r, err)
+		return
+	}
+}
+
+func (h *TaskHandler) handleImportTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.logger.Debug("task import request", zap.String("r", fmt.Sprint(r)))
+	req, err := decodeImportTaskRequest(ctx, r)
+	if err != nil {
+		err = &influxdb.Error{
+			Err:  err,
 			Code: influxdb.EInvalid,
-			Msg:  "invalid organization id",
+			Msg:  "failed to decode request",
 		}
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
 
-	if err != nil {
+	if err := h.populateTaskCreateOrg(ctx, &req.TaskCreate); err != nil {
+		err = &influxdb.Error{
+			Err: err,
+			Msg: "could not identify organization",
+		}
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
 
-	task, err := h.TaskService.CreateTask(ctx, req.TaskCreate)
-	if err != nil {
-		if e, ok := err.(AuthzError); ok {
-			h.logger.Error("failed authentication", zap.Errors("error messages", []error{err, e.AuthzError()}))
+	if h.validateBuckets {
+		if err := h.validateTaskBuckets(ctx, req.TaskCreate.OrganizationID, req.TaskCreate.Flux); err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
 		}
+	}
 
-		// if the error is not already a influxdb.error then make it into one
+	task, err := h.TaskService.CreateTask(ctx, req.TaskCreate)
+	if err != nil {
 		if _, ok := err.(*influxdb.Error); !ok {
 			err = &influxdb.Error{
 				Err:  err,
 				Code: influxdb.EInternal,
-				Msg:  "failed to create task",
+				Msg:  "failed to import task",
 			}
 		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
 
+	labels, err := h.importTaskLabels(ctx, task, req.Labels)
+	if err != nil {
+		// Roll back, so that a retry of the request doesn't import the task twice.
+		if derr := h.TaskService.DeleteTask(ctx, task.ID); derr != nil {
+			h.logger.Error("failed to delete task after failing to import its labels", zap.Stringer("task_id", task.ID), zap.Error(derr))
+		}
+		err = &influxdb.Error{
+			Err: err,
+			Msg: "failed to import task labels",
+		}
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
 
-	if err := encodeResponse(ctx, w, http.StatusCreated, newTaskResponse(*task, []*influxdb.Label{})); err != nil {
+	if err := encodeResponse(ctx, w, http.StatusCreated, newTaskResponse(*task, labels)); err != nil {
 		logEncodingError(h.logger, r, err)
 		return
 	}
 }
 
-type postTaskRequest struct {
-	TaskCreate influxdb.TaskCreate
-}
-
-func decodePostTaskRequest(ctx context.Context, r *http.Request) (*postTaskRequest, error) {
-	var tc influxdb.TaskCreate
-	if err := json.NewDecoder(r.Body).Decode(&tc); err != nil {
-		return nil, err
+// importTaskLabels attaches the exported labels to the imported task and returns
+// them. Labels the org of the task has no label with the name of are created.
+func (h *TaskHandler) importTaskLabels(ctx context.Context, task *influxdb.Task, els []influxdb.TaskExportLabel) ([]*influxdb.Label, error) {
+	ids := make([]influxdb.ID, 0, len(els))
+	for _, el := range els {
+		l, err := h.findOrCreateLabel(ctx, task.OrganizationID, el)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, l.ID)
 	}
+	return h.attachTaskLabels(ctx, task, ids)
+}
 
-	// pull auth from ctx, populate OwnerID
-	auth, err := pcontext.GetAuthorizer(ctx)
+// findOrCreateLabel returns the label of the org with the name of el,
+// creating it if the org has no such label.
+func (h *TaskHandler) findOrCreateLabel(ctx context.Context, orgID influxdb.ID, el influxdb.TaskExportLabel) (*influxdb.Label, error) {
+	ls, err := h.LabelService.FindLabels(ctx, influxdb.LabelFilter{Name: el.Name, OrgID: &orgID})
 	if err != nil {
 		return nil, err
 	}
-	tc.OwnerID = auth.GetUserID()
+	if len(ls) > 0 {
+		return ls[0], nil
+	}
 
-	if err := tc.Validate(); err != nil {
+	l := &influxdb.Label{
+		OrgID:      orgID,
+		Name:       el.Name,
+		Properties: el.Properties,
+	}
+	if err := h.LabelService.CreateLabel(ctx, l); err != nil {
 		return nil, err
 	}
+	return l, nil
+}
 
-	return &postTaskRequest{
-		TaskCreate: tc,
-	}, nil
+type importTaskRequest struct {
+	TaskCreate influxdb.TaskCreate
+	Labels     []influxdb.TaskExportLabel
 }
 
-func (h *TaskHandler) handleGetTask(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	h.logger.Debug("task retrieve request", zap.String("r", fmt.Sprint(r)))
-	req, err := decodeGetTaskRequest(ctx, r)
-	if err != nil {
-		err = &influxdb.Error{
-			Err:  err,
-			Code: influxdb.EInvalid,
-			Msg:  "failed to decode request",
-		}
-		h.HandleHTTPError(ctx, err, w)
-		return
+func decodeImportTaskRequest(ctx context.Context, r *http.Request) (*importTaskRequest, error) {
+	var te influxdb.TaskExport
+	if err := json.NewDecoder(r.Body).Decode(&te); err != nil {
+		return nil, err
+	}
+	if err := te.Validate(); err != nil {
+		return nil, err
 	}
 
-	task, err := h.TaskService.FindTaskByID(ctx, req.TaskID)
-	if err != nil {
-		err = &influxdb.Error{
-			Err:  err,
-			Code: influxdb.ENotFound,
-			Msg:  "failed to find task",
+	tc := influxdb.TaskCreate{
+		Flux:        te.Flux,
+		Description: te.Description,
+		Status:      te.Status,
+	}
+	qp := r.URL.Query()
+	if id := qp.Get("orgID"); id != "" {
+		if err := tc.OrganizationID.DecodeFromString(id); err != nil {
+			return nil, err
 		}
-		h.HandleHTTPError(ctx, err, w)
-		return
 	}
+	tc.Organization = qp.Get("org")
 
-	labels, err := h.LabelService.FindResourceLabels(ctx, influxdb.LabelMappingFilter{ResourceID: task.ID})
+	auth, err := pcontext.GetAuthorizer(ctx)
 	if err != nil {
-		err = &influxdb.Error{
-			Err: err,
-			Msg: "failed to find resource labels",
-		}
-		h.HandleHTTPError(ctx, err, w)
-		return
+		return nil, err
 	}
-	h.logger.Debug("task retrived", zap.String("tasks", fmt.Sprint(task)))
-	if err := encodeResponse(ctx, w, http.StatusOK, newTaskResponse(*task, labels)); err != nil {
-		logEncodingError(h.logger, r, err)
-		return
+	tc.OwnerID = auth.GetUserID()
+
+	// Without an org, the task is imported into the org of the authorization.
+	if a, ok := auth.(*influxdb.Authorization); ok && !tc.OrganizationID.Valid() && tc.Organization == "" {
+		tc.OrganizationID = a.OrgID
 	}
-}
 
-type getTaskRequest struct {
-	TaskID influxdb.ID
+	if err := tc.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &importTaskRequest{
+		TaskCreate: tc,
+		Labels:     te.Labels,
+	}, nil
 }
 
 func decodeGetTaskRequest(ctx context.Context, r *http.Request) (*getTaskRequest, error) {
@@ -537,6 +1362,26 @@ func (h *TaskHandler) handleUpdateTask(w http.ResponseWriter, r *http.Request) {
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
+
+	if h.validateBuckets && req.Update.Flux != nil {
+		task, err := h.TaskService.FindTaskByID(ctx, req.TaskID)
+		if err != nil {
+			err := &influxdb.Error{
+				Err: err,
+				Msg: "failed to update task",
+			}
+			if err.Err == influxdb.ErrTaskNotFound {
+				err.Code = influxdb.ENotFound
+			}
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		if err := h.validateTaskBuckets(ctx, task.OrganizationID, *req.Update.Flux); err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+	}
+
 	task, err := h.TaskService.UpdateTask(ctx, req.TaskID, req.Update)
 	if err != nil {
 		err := &influxdb.Error{
@@ -743,6 +1588,38 @@ func decodeGetLogsRequest(ctx context.Context, r *http.Request) (*getLogsRequest
 		req.filter.Run = id
 	}
 
+	if level := r.URL.Query().Get("minLevel"); level != "" {
+		l := influxdb.LogLevel(level)
+		if err := l.Valid(); err != nil {
+			return nil, err
+		}
+		req.filter.MinLevel = &l
+	}
+
+	qp := r.URL.Query()
+	if at := qp.Get("afterTime"); at != "" {
+		afterTime, err := time.Parse(time.RFC3339, at)
+		if err != nil {
+			return nil, err
+		}
+		req.filter.AfterTime = &afterTime
+	}
+
+	if bt := qp.Get("beforeTime"); bt != "" {
+		beforeTime, err := time.Parse(time.RFC3339, bt)
+		if err != nil {
+			return nil, err
+		}
+		req.filter.BeforeTime = &beforeTime
+	}
+
+	if req.filter.AfterTime != nil && req.filter.BeforeTime != nil && !req.filter.BeforeTime.After(*req.filter.AfterTime) {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnprocessableEntity,
+			Msg:  "beforeTime must be later than afterTime",
+		}
+	}
+
 	return req, nil
 }
 
@@ -796,7 +1673,11 @@ func (h *TaskHandler) handleGetRuns(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := encodeResponse(ctx, w, http.StatusOK, newRunsResponse(runs, req.filter.Task)); err != nil {
+	var resp interface{} = newRunsResponse(runs, req.filter.Task)
+	if len(req.fields) > 0 {
+		resp = newCompactRunsResponse(runs, req.fields)
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, resp); err != nil {
 		logEncodingError(h.logger, r, err)
 		return
 	}
@@ -804,6 +1685,63 @@ func (h *TaskHandler) handleGetRuns(w http.ResponseWriter, r *http.Request) {
 
 type getRunsRequest struct {
 	filter influxdb.RunFilter
+	fields map[string]bool
+}
+
+// compactRunFields are the run fields that can be selected with the fields
+// parameter when listing runs.
+var compactRunFields = []string{"id", "status", "scheduledFor"}
+
+type compactRunResponse struct {
+	ID           influxdb.ID `json:"id,omitempty"`
+	Status       string      `json:"status,omitempty"`
+	ScheduledFor string      `json:"scheduledFor,omitempty"`
+}
+
+type compactRunsResponse struct {
+	Runs []compactRunResponse `json:"runs"`
+}
+
+// newCompactRunsResponse returns only the selected fields of the runs, without
+// the links of the full response.
+func newCompactRunsResponse(rs []*influxdb.Run, fields map[string]bool) compactRunsResponse {
+	r := compactRunsResponse{
+		Runs: make([]compactRunResponse, len(rs)),
+	}
+	for i, run := range rs {
+		if fields["id"] {
+			r.Runs[i].ID = run.ID
+		}
+		if fields["status"] {
+			r.Runs[i].Status = run.Status
+		}
+		if fields["scheduledFor"] {
+			r.Runs[i].ScheduledFor = run.ScheduledFor
+		}
+	}
+	return r
+}
+
+func decodeRunFields(s string) (map[string]bool, error) {
+	fields := make(map[string]bool)
+	for _, f := range strings.Split(s, ",") {
+		f = strings.TrimSpace(f)
+		valid := false
+		for _, cf := range compactRunFields {
+			if f == cf {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("invalid run field %q, must be one of %s", f, strings.Join(compactRunFields, ", ")),
+			}
+		}
+		fields[f] = true
+	}
+	return fields, nil
 }
 
 func decodeGetRunsRequest(ctx context.Context, r *http.Request) (*getRunsRequest, error) {
@@ -843,6 +1781,8 @@ func decodeGetRunsRequest(ctx context.Context, r *http.Request) (*getRunsRequest
 			return nil, influxdb.ErrOutOfBoundsLimit
 		}
 		req.filter.Limit = i
+	} else {
+		req.filter.Limit = influxdb.RunDefaultPageSize
 	}
 
 	var at, bt string
@@ -870,6 +1810,12 @@ func decodeGetRunsRequest(ctx context.Context, r *http.Request) (*getRunsRequest
 		}
 	}
 
+	if f := qp.Get("fields"); f != "" {
+		if req.fields, err = decodeRunFields(f); err != nil {
+			return nil, err
+		}
+	}
+
 	return req, nil
 }
 
@@ -887,7 +1833,12 @@ func (h *TaskHandler) handleForceRun(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	run, err := h.TaskService.ForceRun(ctx, req.TaskID, req.Timestamp)
+	if err := h.checkScheduledFor(req.Timestamp); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	run, err := h.TaskService.ForceRunWithNote(ctx, req.TaskID, req.Timestamp, req.Note)
 	if err != nil {
 		err := &influxdb.Error{
 			Err: err,
@@ -896,6 +1847,10 @@ func (h *TaskHandler) handleForceRun(w http.ResponseWriter, r *http.Request) {
 		if err.Err == influxdb.ErrTaskNotFound {
 			err.Code = influxdb.ENotFound
 		}
+		if queued, ok := err.Err.(influxdb.RunAlreadyQueuedError); ok {
+			err.Code = influxdb.EConflict
+			setRetryAfter(w, queued.ScheduledFor)
+		}
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
@@ -905,9 +1860,26 @@ func (h *TaskHandler) handleForceRun(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// checkScheduledFor returns an error if a manual run is scheduled later than
+// forceRunMaxFuture from now. Runs scheduled in the past are allowed in order
+// to backfill.
+func (h *TaskHandler) checkScheduledFor(scheduledFor int64) error {
+	if h.forceRunMaxFuture <= 0 {
+		return nil
+	}
+	if limit := time.Now().Add(h.forceRunMaxFuture); scheduledFor > limit.Unix() {
+		return &influxdb.Error{
+			Code: influxdb.EUnprocessableEntity,
+			Msg:  fmt.Sprintf("scheduledFor must not be later than %s", limit.UTC().Format(time.RFC3339)),
+		}
+	}
+	return nil
+}
+
 type forceRunRequest struct {
 	TaskID    influxdb.ID
 	Timestamp int64
+	Note      string
 }
 
 func decodeForceRunRequest(ctx context.Context, r *http.Request) (forceRunRequest, error) {
@@ -927,6 +1899,7 @@ func decodeForceRunRequest(ctx context.Context, r *http.Request) (forceRunReques
 
 	var req struct {
 		ScheduledFor string `json:"scheduledFor"`
+		Note         string `json:"note"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		return forceRunRequest{}, err
@@ -946,6 +1919,7 @@ func decodeForceRunRequest(ctx context.Context, r *http.Request) (forceRunReques
 	return forceRunRequest{
 		TaskID:    ti,
 		Timestamp: t.Unix(),
+		Note:      req.Note,
 	}, nil
 }
 
@@ -1081,29 +2055,105 @@ func decodeCancelRunRequest(ctx context.Context, r *http.Request) (*cancelRunReq
 func (h *TaskHandler) handleCancelRun(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	req, err := decodeCancelRunRequest(ctx, r)
+	req, err := decodeCancelRunRequest(ctx, r)
+	if err != nil {
+		err = &influxdb.Error{
+			Err:  err,
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	err = h.TaskService.CancelRun(ctx, req.TaskID, req.RunID)
+	if err != nil {
+		err := &influxdb.Error{
+			Err: err,
+			Msg: "failed to cancel run",
+		}
+		if err.Err == influxdb.ErrTaskNotFound || err.Err == influxdb.ErrRunNotFound {
+			err.Code = influxdb.ENotFound
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+}
+
+// handlePostRun serves POST /api/v2/tasks/:id/runs/cancel.
+// The router can't register the static "cancel" segment next to the :rid wildcard,
+// so it is matched as a run ID here.
+func (h *TaskHandler) handlePostRun(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if httprouter.ParamsFromContext(ctx).ByName("rid") != "cancel" {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EMethodNotAllowed,
+			Msg:  "runs can only be retried or canceled",
+		}, w)
+		return
+	}
+
+	h.handleCancelTaskRuns(w, r)
+}
+
+type cancelTaskRunsResponse struct {
+	Canceled []influxdb.ID `json:"canceled"`
+}
+
+func (h *TaskHandler) handleCancelTaskRuns(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var taskID influxdb.ID
+	if err := taskID.DecodeFromString(httprouter.ParamsFromContext(ctx).ByName("id")); err != nil {
+		err = &influxdb.Error{
+			Err:  err,
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	auth, err := pcontext.GetAuthorizer(ctx)
 	if err != nil {
 		err = &influxdb.Error{
 			Err:  err,
-			Code: influxdb.EInvalid,
-			Msg:  "failed to decode request",
+			Code: influxdb.EUnauthorized,
+			Msg:  "failed to get authorizer",
 		}
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
 
-	err = h.TaskService.CancelRun(ctx, req.TaskID, req.RunID)
+	if k := auth.Kind(); k != influxdb.AuthorizationKind {
+		// Get the authorization for the task, if allowed.
+		authz, err := h.getAuthorizationForTask(ctx, auth, taskID)
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+
+		// We were able to access the authorizer for the task, so reassign that on the context for the rest of this call.
+		ctx = pcontext.SetAuthorizer(ctx, authz)
+	}
+
+	ids, err := h.TaskService.CancelTaskRuns(ctx, taskID)
 	if err != nil {
 		err := &influxdb.Error{
 			Err: err,
-			Msg: "failed to cancel run",
+			Msg: "failed to cancel runs",
 		}
-		if err.Err == influxdb.ErrTaskNotFound || err.Err == influxdb.ErrRunNotFound {
+		if err.Err == influxdb.ErrTaskNotFound {
 			err.Code = influxdb.ENotFound
 		}
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, &cancelTaskRunsResponse{Canceled: ids}); err != nil {
+		logEncodingError(h.logger, r, err)
+		return
+	}
 }
 
 func (h *TaskHandler) handleRetryRun(w http.ResponseWriter, r *http.Request) {
@@ -1120,6 +2170,13 @@ func (h *TaskHandler) handleRetryRun(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.ScheduledFor != nil {
+		if err := h.checkScheduledFor(*req.ScheduledFor); err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+	}
+
 	auth, err := pcontext.GetAuthorizer(ctx)
 	if err != nil {
 		err = &influxdb.Error{
@@ -1143,7 +2200,7 @@ func (h *TaskHandler) handleRetryRun(w http.ResponseWriter, r *http.Request) {
 		ctx = pcontext.SetAuthorizer(ctx, authz)
 	}
 
-	run, err := h.TaskService.RetryRun(ctx, req.TaskID, req.RunID)
+	run, err := h.TaskService.RetryRun(ctx, req.TaskID, req.RunID, req.ScheduledFor)
 	if err != nil {
 		err := &influxdb.Error{
 			Err: err,
@@ -1152,6 +2209,10 @@ func (h *TaskHandler) handleRetryRun(w http.ResponseWriter, r *http.Request) {
 		if err.Err == influxdb.ErrTaskNotFound || err.Err == influxdb.ErrRunNotFound {
 			err.Code = influxdb.ENotFound
 		}
+		if queued, ok := err.Err.(backend.RequestStillQueuedError); ok {
+			err.Code = influxdb.EConflict
+			setRetryAfter(w, queued.End)
+		}
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
@@ -1163,6 +2224,8 @@ func (h *TaskHandler) handleRetryRun(w http.ResponseWriter, r *http.Request) {
 
 type retryRunRequest struct {
 	RunID, TaskID influxdb.ID
+	// ScheduledFor optionally overrides the scheduled time of the retried run.
+	ScheduledFor *int64
 }
 
 func decodeRetryRunRequest(ctx context.Context, r *http.Request) (*retryRunRequest, error) {
@@ -1190,9 +2253,30 @@ func decodeRetryRunRequest(ctx context.Context, r *http.Request) (*retryRunReque
 		return nil, err
 	}
 
+	// The body is optional, retrying at the run's original scheduled time.
+	var req struct {
+		ScheduledFor string `json:"scheduledFor"`
+	}
+	if r.Body != nil {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+			return nil, err
+		}
+	}
+
+	var scheduledFor *int64
+	if req.ScheduledFor != "" {
+		t, err := time.Parse(time.RFC3339, req.ScheduledFor)
+		if err != nil {
+			return nil, err
+		}
+		sf := t.Unix()
+		scheduledFor = &sf
+	}
+
 	return &retryRunRequest{
-		RunID:  ri,
-		TaskID: ti,
+		RunID:        ri,
+		TaskID:       ti,
+		ScheduledFor: scheduledFor,
 	}, nil
 }
 
@@ -1244,7 +2328,8 @@ func (h *TaskHandler) getAuthorizationForTask(ctx context.Context, auth influxdb
 		}
 	}
 
-	return sess.EphemeralAuth(t.OrganizationID), nil
+	// Reading runs and logs only needs the task and the buckets it touches.
+	return sess.EphemeralAuthForResources(t.OrganizationID, influxdb.TasksResourceType, influxdb.BucketsResourceType), nil
 }
 
 // TaskService connects to Influx via HTTP using tokens to manage tasks.
@@ -1305,29 +2390,7 @@ func (t TaskService) FindTasks(ctx context.Context, filter influxdb.TaskFilter)
 	if err != nil {
 		return nil, 0, err
 	}
-
-	val := url.Values{}
-	if filter.After != nil {
-		val.Add("after", filter.After.String())
-	}
-	if filter.OrganizationID != nil {
-		val.Add("orgID", filter.OrganizationID.String())
-	}
-	if filter.Organization != "" {
-		val.Add("org", filter.Organization)
-	}
-	if filter.User != nil {
-		val.Add("user", filter.User.String())
-	}
-	if filter.Limit != 0 {
-		val.Add("limit", strconv.Itoa(filter.Limit))
-	}
-
-	if filter.Type != nil {
-		val.Add("type", *filter.Type)
-	}
-
-	u.RawQuery = val.Encode()
+	u.RawQuery = taskFilterValues(filter).Encode()
 
 	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
@@ -1358,6 +2421,81 @@ func (t TaskService) FindTasks(ctx context.Context, filter influxdb.TaskFilter)
 	return tasks, len(tasks), nil
 }
 
+// CountTasks returns the number of tasks that match a filter, regardless of its
+// limit and paging.
+func (t TaskService) CountTasks(ctx context.Context, filter influxdb.TaskFilter) (int, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(t.Addr, tasksPath)
+	if err != nil {
+		return 0, err
+	}
+	val := taskFilterValues(filter)
+	val.Add("count", "true")
+	u.RawQuery = val.Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return 0, err
+	}
+	SetToken(t.Token, req)
+
+	hc := NewClient(u.Scheme, t.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return 0, err
+	}
+
+	var cr tasksCountResponse
+	if err := json.NewDecoder(resp.Body).Decode(&cr); err != nil {
+		return 0, err
+	}
+	return cr.Count, nil
+}
+
+// taskFilterValues encodes a task filter as the query parameters of the task list endpoint.
+func taskFilterValues(filter influxdb.TaskFilter) url.Values {
+	val := url.Values{}
+	if filter.After != nil {
+		val.Add("after", filter.After.String())
+	}
+	if filter.Before != nil {
+		val.Add("before", filter.Before.String())
+	}
+	if filter.OrganizationID != nil {
+		val.Add("orgID", filter.OrganizationID.String())
+	}
+	if filter.Organization != "" {
+		val.Add("org", filter.Organization)
+	}
+	if filter.User != nil {
+		val.Add("user", filter.User.String())
+	}
+	if filter.OwnerID != nil {
+		val.Add("ownerID", filter.OwnerID.String())
+	}
+	if filter.LabelID != nil {
+		val.Add("labelID", filter.LabelID.String())
+	}
+	if filter.Limit != 0 {
+		val.Add("limit", strconv.Itoa(filter.Limit))
+	}
+
+	if filter.Name != nil {
+		val.Add("name", *filter.Name)
+	}
+	if filter.Type != nil {
+		val.Add("type", *filter.Type)
+	}
+	return val
+}
+
 // CreateTask creates a new task.
 func (t TaskService) CreateTask(ctx context.Context, tc influxdb.TaskCreate) (*influxdb.Task, error) {
 	span, _ := tracing.StartSpanFromContext(ctx)
@@ -1472,6 +2610,88 @@ func (t TaskService) DeleteTask(ctx context.Context, id influxdb.ID) error {
 	return CheckErrorStatus(http.StatusNoContent, resp)
 }
 
+// ExportTask returns a portable export of the task.
+func (t TaskService) ExportTask(ctx context.Context, id influxdb.ID) (*influxdb.TaskExport, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(t.Addr, path.Join(taskIDPath(id), "export"))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(t.Token, req)
+
+	hc := NewClient(u.Scheme, t.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		if influxdb.ErrorCode(err) == influxdb.ENotFound {
+			return nil, influxdb.ErrTaskNotFound
+		}
+		return nil, err
+	}
+
+	var te influxdb.TaskExport
+	if err := json.NewDecoder(resp.Body).Decode(&te); err != nil {
+		return nil, err
+	}
+	return &te, nil
+}
+
+// ImportTask creates a task in the organization from an export.
+func (t TaskService) ImportTask(ctx context.Context, orgID influxdb.ID, te influxdb.TaskExport) (*influxdb.Task, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(t.Addr, tasksImportPath)
+	if err != nil {
+		return nil, err
+	}
+
+	val := url.Values{}
+	val.Add("orgID", orgID.String())
+	u.RawQuery = val.Encode()
+
+	b, err := json.Marshal(te)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(t.Token, req)
+
+	hc := NewClient(u.Scheme, t.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var tr taskResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tr); err != nil {
+		return nil, err
+	}
+	return &tr.Task, nil
+}
+
 // FindLogs returns logs for a run.
 func (t TaskService) FindLogs(ctx context.Context, filter influxdb.LogFilter) ([]*influxdb.Log, int, error) {
 	span, _ := tracing.StartSpanFromContext(ctx)
@@ -1493,6 +2713,18 @@ func (t TaskService) FindLogs(ctx context.Context, filter influxdb.LogFilter) ([
 		return nil, 0, err
 	}
 
+	val := url.Values{}
+	if filter.MinLevel != nil {
+		val.Set("minLevel", string(*filter.MinLevel))
+	}
+	if filter.AfterTime != nil {
+		val.Set("afterTime", filter.AfterTime.Format(time.RFC3339Nano))
+	}
+	if filter.BeforeTime != nil {
+		val.Set("beforeTime", filter.BeforeTime.Format(time.RFC3339Nano))
+	}
+	u.RawQuery = val.Encode()
+
 	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
 		return nil, 0, err
@@ -1541,7 +2773,10 @@ func (t TaskService) FindRuns(ctx context.Context, filter influxdb.RunFilter) ([
 	if filter.Limit < 0 || filter.Limit > influxdb.TaskMaxPageSize {
 		return nil, 0, influxdb.ErrOutOfBoundsLimit
 	}
-	val.Set("limit", strconv.Itoa(filter.Limit))
+	// Leave the limit out when it is not set so the server default applies.
+	if filter.Limit > 0 {
+		val.Set("limit", strconv.Itoa(filter.Limit))
+	}
 
 	u.RawQuery = val.Encode()
 	req, err := http.NewRequest("GET", u.String(), nil)
@@ -1620,7 +2855,7 @@ func (t TaskService) FindRunByID(ctx context.Context, taskID, runID influxdb.ID)
 }
 
 // RetryRun creates and returns a new run (which is a retry of another run).
-func (t TaskService) RetryRun(ctx context.Context, taskID, runID influxdb.ID) (*influxdb.Run, error) {
+func (t TaskService) RetryRun(ctx context.Context, taskID, runID influxdb.ID, scheduledFor *int64) (*influxdb.Run, error) {
 	span, _ := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
@@ -1630,7 +2865,20 @@ func (t TaskService) RetryRun(ctx context.Context, taskID, runID influxdb.ID) (*
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", u.String(), nil)
+	var body io.Reader
+	if scheduledFor != nil {
+		b, err := json.Marshal(struct {
+			ScheduledFor string `json:"scheduledFor"`
+		}{
+			ScheduledFor: time.Unix(*scheduledFor, 0).UTC().Format(time.RFC3339),
+		})
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest("POST", u.String(), body)
 	if err != nil {
 		return nil, err
 	}
@@ -1653,7 +2901,7 @@ func (t TaskService) RetryRun(ctx context.Context, taskID, runID influxdb.ID) (*
 			return nil, influxdb.ErrRunNotFound
 		}
 		// RequestStillQueuedError is also part of the contract.
-		if e := backend.ParseRequestStillQueuedError(err.Error()); e != nil {
+		if e := parseRequestStillQueuedError(err, resp); e != nil {
 			return nil, *e
 		}
 
@@ -1669,6 +2917,11 @@ func (t TaskService) RetryRun(ctx context.Context, taskID, runID influxdb.ID) (*
 
 // ForceRun starts a run manually right now.
 func (t TaskService) ForceRun(ctx context.Context, taskID influxdb.ID, scheduledFor int64) (*influxdb.Run, error) {
+	return t.ForceRunWithNote(ctx, taskID, scheduledFor, "")
+}
+
+// ForceRunWithNote starts a run manually right now, recording the note on the run.
+func (t TaskService) ForceRunWithNote(ctx context.Context, taskID influxdb.ID, scheduledFor int64, note string) (*influxdb.Run, error) {
 	span, _ := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
@@ -1677,8 +2930,18 @@ func (t TaskService) ForceRun(ctx context.Context, taskID influxdb.ID, scheduled
 		return nil, err
 	}
 
-	body := fmt.Sprintf(`{"scheduledFor": %q}`, time.Unix(scheduledFor, 0).UTC().Format(time.RFC3339))
-	req, err := http.NewRequest("POST", u.String(), strings.NewReader(body))
+	body, err := json.Marshal(struct {
+		ScheduledFor string `json:"scheduledFor"`
+		Note         string `json:"note,omitempty"`
+	}{
+		ScheduledFor: time.Unix(scheduledFor, 0).UTC().Format(time.RFC3339),
+		Note:         note,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
@@ -1700,8 +2963,8 @@ func (t TaskService) ForceRun(ctx context.Context, taskID influxdb.ID, scheduled
 			return nil, influxdb.ErrRunNotFound
 		}
 
-		// RequestStillQueuedError is also part of the contract.
-		if e := backend.ParseRequestStillQueuedError(err.Error()); e != nil {
+		// RunAlreadyQueuedError is also part of the contract.
+		if e := parseRunAlreadyQueuedError(err, resp); e != nil {
 			return nil, *e
 		}
 
@@ -1715,6 +2978,51 @@ func (t TaskService) ForceRun(ctx context.Context, taskID influxdb.ID, scheduled
 	return &rs.Run, nil
 }
 
+// setRetryAfter suggests retrying a request that conflicts with a queued run
+// once the unix timestamp end, at which the queued run is scheduled, has passed.
+func setRetryAfter(w http.ResponseWriter, end int64) {
+	secs := end - time.Now().Unix()
+	if secs < 1 {
+		secs = 1
+	}
+	w.Header().Set("Retry-After", strconv.FormatInt(secs, 10))
+}
+
+// parseRequestStillQueuedError parses a RequestStillQueuedError from err,
+// along with the wait suggested by the response's Retry-After header.
+func parseRequestStillQueuedError(err error, resp *http.Response) *backend.RequestStillQueuedError {
+	e := backend.ParseRequestStillQueuedError(err.Error())
+	if e == nil {
+		return nil
+	}
+	e.RetryAfter = retryAfter(resp)
+	return e
+}
+
+// parseRunAlreadyQueuedError parses a RunAlreadyQueuedError from err, along
+// with the wait suggested by the response's Retry-After header.
+func parseRunAlreadyQueuedError(err error, resp *http.Response) *influxdb.RunAlreadyQueuedError {
+	e := influxdb.ParseRunAlreadyQueuedError(err.Error())
+	if e == nil {
+		return nil
+	}
+	e.RetryAfter = retryAfter(resp)
+	return e
+}
+
+// retryAfter returns the wait suggested by the Retry-After header of resp, if any.
+func retryAfter(resp *http.Response) time.Duration {
+	// Retry-After is either a number of seconds or an HTTP date.
+	if ra := resp.Header.Get("Retry-After"); ra != "" {
+		if secs, err := strconv.Atoi(ra); err == nil && secs > 0 {
+			return time.Duration(secs) * time.Second
+		} else if t, err := http.ParseTime(ra); err == nil && time.Until(t) > 0 {
+			return time.Until(t)
+		}
+	}
+	return 0
+}
+
 func cancelPath(taskID, runID influxdb.ID) string {
 	return path.Join(taskID.String(), runID.String())
 }
@@ -1751,6 +3059,42 @@ func (t TaskService) CancelRun(ctx context.Context, taskID, runID influxdb.ID) e
 	return nil
 }
 
+// CancelTaskRuns stops every currently running run of a task.
+func (t TaskService) CancelTaskRuns(ctx context.Context, taskID influxdb.ID) ([]influxdb.ID, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(t.Addr, path.Join(taskIDRunsPath(taskID), "cancel"))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	SetToken(t.Token, req)
+
+	hc := NewClient(u.Scheme, t.InsecureSkipVerify)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var cr cancelTaskRunsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&cr); err != nil {
+		return nil, err
+	}
+	return cr.Canceled, nil
+}
+
 func taskIDPath(id influxdb.ID) string {
 	return path.Join(tasksPath, id.String())
 }
@@ -13,6 +13,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	platform "github.com/influxdata/influxdb"
 	pcontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/inmem"
@@ -226,6 +227,7 @@ func TestTaskHandler_handleGetTasks(t *testing.T) {
 				body: `
 {
   "links": {
+    "prev": "/api/v2/tasks?before=0000000000000002&limit=1",
     "self": "/api/v2/tasks?after=0000000000000001&limit=1",
     "next": "/api/v2/tasks?after=0000000000000002&limit=1"
   },
@@ -424,6 +426,270 @@ func TestTaskHandler_handleGetTasks(t *testing.T) {
 	}
 }
 
+func TestTaskHandler_handleGetTasks_pagingRoundTrip(t *testing.T) {
+	var data []*platform.Task
+	for i := 1; i <= 5; i++ {
+		data = append(data, &platform.Task{ID: platform.ID(i), Name: fmt.Sprintf("task%d", i), OrganizationID: 1, OwnerID: 1})
+	}
+
+	taskBackend := NewMockTaskBackend(t)
+	taskBackend.HTTPErrorHandler = ErrorHandler(0)
+	taskBackend.TaskService = &mock.TaskService{
+		FindTasksFn: func(ctx context.Context, f platform.TaskFilter) ([]*platform.Task, int, error) {
+			var ts []*platform.Task
+			switch {
+			case f.After != nil:
+				for _, task := range data {
+					if task.ID > *f.After && len(ts) < f.Limit {
+						ts = append(ts, task)
+					}
+				}
+			case f.Before != nil:
+				for i := len(data) - 1; i >= 0; i-- {
+					if data[i].ID < *f.Before && len(ts) < f.Limit {
+						ts = append([]*platform.Task{data[i]}, ts...)
+					}
+				}
+			default:
+				for _, task := range data {
+					if len(ts) < f.Limit {
+						ts = append(ts, task)
+					}
+				}
+			}
+			return ts, len(ts), nil
+		},
+	}
+	h := NewTaskHandler(taskBackend)
+
+	getPage := func(link string) tasksResponse {
+		t.Helper()
+		r := httptest.NewRequest("GET", "http://any.url"+link, nil)
+		w := httptest.NewRecorder()
+		h.handleGetTasks(w, r)
+
+		res := w.Result()
+		if res.StatusCode != http.StatusOK {
+			body, _ := ioutil.ReadAll(res.Body)
+			t.Fatalf("GET %s = %d: %s", link, res.StatusCode, body)
+		}
+		var tr tasksResponse
+		if err := json.NewDecoder(res.Body).Decode(&tr); err != nil {
+			t.Fatal(err)
+		}
+		return tr
+	}
+	ids := func(tr tasksResponse) []platform.ID {
+		var ids []platform.ID
+		for _, task := range tr.Tasks {
+			ids = append(ids, task.ID)
+		}
+		return ids
+	}
+
+	// walk forwards to the last page, recording every page.
+	var forward []tasksResponse
+	page := getPage("/api/v2/tasks?limit=2")
+	forward = append(forward, page)
+	for page.Links.Next != "" {
+		page = getPage(page.Links.Next)
+		forward = append(forward, page)
+	}
+	if len(forward) != 3 {
+		t.Fatalf("expected 3 pages walking forwards, got %d", len(forward))
+	}
+	if forward[0].Links.Prev != "" {
+		t.Fatalf("first page should not have a prev link, got %q", forward[0].Links.Prev)
+	}
+
+	// walk backwards from the last page, each prev page must match the forward page.
+	page = forward[len(forward)-1]
+	for i := len(forward) - 2; i >= 0; i-- {
+		if page.Links.Prev == "" {
+			t.Fatalf("page %d is missing a prev link", i+1)
+		}
+		page = getPage(page.Links.Prev)
+		if diff := cmp.Diff(ids(forward[i]), ids(page)); diff != "" {
+			t.Fatalf("prev page %d does not match the forward page: -want/+got: %s", i, diff)
+		}
+		if page.Links.Next == "" {
+			t.Fatalf("page %d reached backwards is missing a next link", i)
+		}
+		if diff := cmp.Diff(ids(forward[i+1]), ids(getPage(page.Links.Next))); diff != "" {
+			t.Fatalf("next of page %d does not match the forward page: -want/+got: %s", i, diff)
+		}
+	}
+}
+
+func TestTaskHandler_handleGetTasks_batchesLabels(t *testing.T) {
+	var data []*platform.Task
+	for i := 1; i <= 10; i++ {
+		data = append(data, &platform.Task{ID: platform.ID(i), Name: fmt.Sprintf("task%d", i), OrganizationID: 1, OwnerID: 1})
+	}
+
+	var single, batch int
+	taskBackend := NewMockTaskBackend(t)
+	taskBackend.HTTPErrorHandler = ErrorHandler(0)
+	taskBackend.TaskService = &mock.TaskService{
+		FindTasksFn: func(ctx context.Context, f platform.TaskFilter) ([]*platform.Task, int, error) {
+			return data, len(data), nil
+		},
+	}
+	taskBackend.LabelService = &mock.LabelService{
+		FindResourceLabelsFn: func(ctx context.Context, f platform.LabelMappingFilter) ([]*platform.Label, error) {
+			single++
+			return []*platform.Label{}, nil
+		},
+		FindResourceLabelsForResourcesFn: func(ctx context.Context, rt platform.ResourceType, ids []platform.ID) (map[platform.ID][]*platform.Label, error) {
+			batch++
+			if rt != platform.TasksResourceType {
+				t.Errorf("expected resource type %q, got %q", platform.TasksResourceType, rt)
+			}
+			return map[platform.ID][]*platform.Label{
+				platform.ID(3): {{ID: 1, Name: "label"}},
+			}, nil
+		},
+	}
+	h := NewTaskHandler(taskBackend)
+
+	w := httptest.NewRecorder()
+	h.handleGetTasks(w, httptest.NewRequest("GET", "http://any.url/api/v2/tasks", nil))
+
+	res := w.Result()
+	if res.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(res.Body)
+		t.Fatalf("expected status OK, got %v: %s", res.StatusCode, body)
+	}
+	if single != 0 || batch != 1 {
+		t.Fatalf("expected a single batched label lookup, got %d batched and %d single lookups", batch, single)
+	}
+
+	var tr tasksResponse
+	if err := json.NewDecoder(res.Body).Decode(&tr); err != nil {
+		t.Fatal(err)
+	}
+	for _, task := range tr.Tasks {
+		if exp := task.ID == platform.ID(3); exp != (len(task.Labels) == 1) {
+			t.Errorf("unexpected labels for task %s: %v", task.ID, task.Labels)
+		}
+	}
+}
+
+func TestTaskHandler_handleGetTasks_count(t *testing.T) {
+	taskBackend := NewMockTaskBackend(t)
+	taskBackend.HTTPErrorHandler = ErrorHandler(0)
+	taskBackend.TaskService = &mock.TaskService{
+		FindTasksFn: func(ctx context.Context, f platform.TaskFilter) ([]*platform.Task, int, error) {
+			t.Fatal("tasks listed while counting tasks")
+			return nil, 0, nil
+		},
+		CountTasksFn: func(ctx context.Context, f platform.TaskFilter) (int, error) {
+			if f.Name == nil || *f.Name != "hello" {
+				t.Fatalf("expected the name filter to be passed, got %v", f.Name)
+			}
+			return 2*platform.TaskMaxPageSize + 3, nil
+		},
+	}
+	h := NewTaskHandler(taskBackend)
+
+	w := httptest.NewRecorder()
+	h.handleGetTasks(w, httptest.NewRequest("GET", "http://any.url/api/v2/tasks?count=true&limit=1&name=hello", nil))
+
+	res := w.Result()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected status OK, got %v: %s", res.StatusCode, body)
+	}
+	if eq, diff, _ := jsonEqual(string(body), fmt.Sprintf(`{"count": %d}`, 2*platform.TaskMaxPageSize+3)); !eq {
+		t.Errorf("unexpected response body -got/+want: %s", diff)
+	}
+}
+
+func TestTaskHandler_OrgRequestsPerSecond(t *testing.T) {
+	taskBackend := NewMockTaskBackend(t)
+	taskBackend.HTTPErrorHandler = ErrorHandler(0)
+	taskBackend.OrgRequestsPerSecond = 1
+	taskBackend.TaskService = &mock.TaskService{
+		FindTasksFn: func(ctx context.Context, f platform.TaskFilter) ([]*platform.Task, int, error) {
+			return nil, 0, nil
+		},
+	}
+	h := NewTaskHandler(taskBackend)
+
+	getTasks := func(orgID platform.ID) *http.Response {
+		r := httptest.NewRequest("GET", "http://any.url/api/v2/tasks", nil)
+		r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{OrgID: orgID}))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Result()
+	}
+
+	if res := getTasks(1); res.StatusCode != http.StatusOK {
+		t.Fatalf("expected status OK, got %v", res.StatusCode)
+	}
+
+	res := getTasks(1)
+	if res.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected status too many requests, got %v", res.StatusCode)
+	}
+	if got := res.Header.Get("Retry-After"); got != "1" {
+		t.Fatalf("unexpected Retry-After: got %q, want %q", got, "1")
+	}
+
+	// Another organization has its own limit.
+	if res := getTasks(2); res.StatusCode != http.StatusOK {
+		t.Fatalf("expected status OK for another org, got %v", res.StatusCode)
+	}
+
+	// The health check is not limited.
+	r := httptest.NewRequest("GET", "http://any.url/api/v2/tasks/health", nil)
+	r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{OrgID: 1}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if res := w.Result(); res.StatusCode != http.StatusOK {
+		t.Fatalf("expected status OK for the health check, got %v", res.StatusCode)
+	}
+}
+
+func TestOrgRateLimiter_evictsIdle(t *testing.T) {
+	now := time.Now()
+	l := newOrgRateLimiter(1)
+	l.now = func() time.Time { return now }
+
+	allow := func(orgID platform.ID) bool {
+		_, ok := l.allow(pcontext.SetAuthorizer(context.Background(), &platform.Authorization{OrgID: orgID}))
+		return ok
+	}
+
+	for _, id := range []platform.ID{1, 2, 3} {
+		if !allow(id) {
+			t.Fatalf("expected the first request of org %s to be allowed", id)
+		}
+	}
+	if allow(1) {
+		t.Fatal("expected a second request of org 1 within the same second to be limited")
+	}
+	now = now.Add(orgRateLimiterIdle / 2)
+	if !allow(1) {
+		t.Fatal("expected a request of org 1 after its bucket refilled to be allowed")
+	}
+
+	// Orgs 2 and 3 have been idle long enough to be evicted, org 1 has not.
+	now = now.Add(orgRateLimiterIdle / 2)
+	if !allow(4) {
+		t.Fatal("expected the first request of org 4 to be allowed")
+	}
+	if got, want := len(l.limiters), 2; got != want {
+		t.Fatalf("unexpected number of limiters: got %d, want %d", got, want)
+	}
+	if _, ok := l.limiters[1]; !ok {
+		t.Fatal("expected the limiter of org 1 to be kept")
+	}
+}
+
 func TestTaskHandler_handlePostTasks(t *testing.T) {
 	type args struct {
 		taskCreate platform.TaskCreate
@@ -516,36 +782,440 @@ func TestTaskHandler_handlePostTasks(t *testing.T) {
 				contentType: "application/json; charset=utf-8",
 				body: `
 {
-    "code": "invalid",
-    "message": "something really went wrong",
-    "error": "something went wrong"
+    "code": "invalid",
+    "message": "something really went wrong",
+    "error": "something went wrong"
+}
+`,
+			},
+		},
+		{
+			name: "create task - error creating task",
+			args: args{
+				taskCreate: platform.TaskCreate{
+					OrganizationID: 1,
+					Flux:           "abc",
+				},
+			},
+			fields: fields{
+				taskService: &mock.TaskService{
+					CreateTaskFn: func(ctx context.Context, tc platform.TaskCreate) (*platform.Task, error) {
+						return nil, errors.New("something bad happened")
+					},
+				},
+			},
+			wants: wants{
+				statusCode:  http.StatusInternalServerError,
+				contentType: "application/json; charset=utf-8",
+				body: `
+{
+    "code": "internal error",
+    "message": "failed to create task",
+    "error": "something bad happened"
+}
+`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.args.taskCreate)
+			if err != nil {
+				t.Fatalf("failed to unmarshal task: %v", err)
+			}
+
+			r := httptest.NewRequest("POST", "http://any.url", bytes.NewReader(b))
+			ctx := pcontext.SetAuthorizer(context.TODO(), new(platform.Authorization))
+			r = r.WithContext(ctx)
+
+			w := httptest.NewRecorder()
+
+			taskBackend := NewMockTaskBackend(t)
+			taskBackend.HTTPErrorHandler = ErrorHandler(0)
+			taskBackend.TaskService = tt.fields.taskService
+			h := NewTaskHandler(taskBackend)
+			h.handlePostTask(w, r)
+
+			res := w.Result()
+			content := res.Header.Get("Content-Type")
+			body, _ := ioutil.ReadAll(res.Body)
+
+			if res.StatusCode != tt.wants.statusCode {
+				t.Errorf("%q. handlePostTask() = %v, want %v", tt.name, res.StatusCode, tt.wants.statusCode)
+			}
+			if tt.wants.contentType != "" && content != tt.wants.contentType {
+				t.Errorf("%q. handlePostTask() = %v, want %v", tt.name, content, tt.wants.contentType)
+			}
+			if tt.wants.body != "" {
+				if eq, diff, err := jsonEqual(string(body), tt.wants.body); err != nil {
+					t.Errorf("%q, handlePostTask(). error unmarshaling json %v", tt.name, err)
+				} else if !eq {
+					t.Errorf("%q. handlePostTask() = ***%s***", tt.name, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestTaskHandler_handlePostTask_Labels(t *testing.T) {
+	labels := map[platform.ID]*platform.Label{
+		1: {ID: 1, OrgID: 1, Name: "l1"},
+		2: {ID: 2, OrgID: 1, Name: "l2"},
+		3: {ID: 3, OrgID: 2, Name: "other-org"},
+	}
+
+	newHandler := func(t *testing.T, deleted *[]platform.ID, mapped *[]platform.ID) *TaskHandler {
+		taskBackend := NewMockTaskBackend(t)
+		taskBackend.HTTPErrorHandler = ErrorHandler(0)
+		taskBackend.TaskService = &mock.TaskService{
+			CreateTaskFn: func(ctx context.Context, tc platform.TaskCreate) (*platform.Task, error) {
+				return &platform.Task{ID: 1, OrganizationID: tc.OrganizationID, OwnerID: 2, Flux: tc.Flux}, nil
+			},
+			DeleteTaskFn: func(ctx context.Context, id platform.ID) error {
+				*deleted = append(*deleted, id)
+				return nil
+			},
+		}
+		ls := mock.NewLabelService()
+		ls.FindLabelByIDFn = func(ctx context.Context, id platform.ID) (*platform.Label, error) {
+			if l, ok := labels[id]; ok {
+				return l, nil
+			}
+			return nil, &platform.Error{Code: platform.ENotFound, Msg: "label not found"}
+		}
+		ls.CreateLabelMappingFn = func(ctx context.Context, m *platform.LabelMapping) error {
+			*mapped = append(*mapped, m.LabelID)
+			return nil
+		}
+		ls.DeleteLabelMappingFn = func(ctx context.Context, m *platform.LabelMapping) error {
+			for i, id := range *mapped {
+				if id == m.LabelID {
+					*mapped = append((*mapped)[:i], (*mapped)[i+1:]...)
+					break
+				}
+			}
+			return nil
+		}
+		taskBackend.LabelService = ls
+		return NewTaskHandler(taskBackend)
+	}
+
+	post := func(h *TaskHandler, tc platform.TaskCreate) *http.Response {
+		b, err := json.Marshal(tc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := httptest.NewRequest("POST", "http://any.url", bytes.NewReader(b))
+		r = r.WithContext(pcontext.SetAuthorizer(context.TODO(), new(platform.Authorization)))
+		w := httptest.NewRecorder()
+		h.handlePostTask(w, r)
+		return w.Result()
+	}
+
+	t.Run("attaches labels", func(t *testing.T) {
+		var deleted, mapped []platform.ID
+		h := newHandler(t, &deleted, &mapped)
+
+		res := post(h, platform.TaskCreate{OrganizationID: 1, Flux: "abc", Labels: []platform.ID{1, 2}})
+		if res.StatusCode != http.StatusCreated {
+			b, _ := ioutil.ReadAll(res.Body)
+			t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, res.StatusCode, b)
+		}
+
+		var tr taskResponse
+		if err := json.NewDecoder(res.Body).Decode(&tr); err != nil {
+			t.Fatal(err)
+		}
+		if len(tr.Labels) != 2 || tr.Labels[0].Name != "l1" || tr.Labels[1].Name != "l2" {
+			t.Fatalf("expected labels l1 and l2, got %+v", tr.Labels)
+		}
+		if len(mapped) != 2 {
+			t.Fatalf("expected 2 label mappings, got %v", mapped)
+		}
+		if len(deleted) != 0 {
+			t.Fatalf("expected no task to be deleted, got %v", deleted)
+		}
+	})
+
+	t.Run("rolls back on a label of another org", func(t *testing.T) {
+		var deleted, mapped []platform.ID
+		h := newHandler(t, &deleted, &mapped)
+
+		res := post(h, platform.TaskCreate{OrganizationID: 1, Flux: "abc", Labels: []platform.ID{1, 3}})
+		if res.StatusCode != http.StatusBadRequest {
+			b, _ := ioutil.ReadAll(res.Body)
+			t.Fatalf("expected status %d, got %d: %s", http.StatusBadRequest, res.StatusCode, b)
+		}
+		if len(mapped) != 0 {
+			t.Fatalf("expected attached labels to be detached, got %v", mapped)
+		}
+		if len(deleted) != 1 || deleted[0] != 1 {
+			t.Fatalf("expected the created task to be deleted, got %v", deleted)
+		}
+	})
+}
+
+func TestTaskHandler_handleImportTask(t *testing.T) {
+	const orgID = platform.ID(1)
+
+	newHandler := func(t *testing.T, created *bool, deleted *[]platform.ID, labelErr error) *TaskHandler {
+		bs := mock.NewBucketService()
+		bs.FindBucketFn = func(_ context.Context, f platform.BucketFilter) (*platform.Bucket, error) {
+			if f.Name == nil || *f.Name != "b-dst" {
+				return nil, &platform.Error{Code: platform.ENotFound, Msg: "bucket not found"}
+			}
+			return &platform.Bucket{ID: 2, OrgID: orgID, Name: *f.Name}, nil
+		}
+
+		ls := mock.NewLabelService()
+		ls.FindLabelsFn = func(context.Context, platform.LabelFilter) ([]*platform.Label, error) {
+			return nil, labelErr
+		}
+		ls.CreateLabelFn = func(_ context.Context, l *platform.Label) error {
+			l.ID = 3
+			return nil
+		}
+		ls.FindLabelByIDFn = func(_ context.Context, id platform.ID) (*platform.Label, error) {
+			return &platform.Label{ID: id, OrgID: orgID, Name: "nightly"}, nil
+		}
+
+		return NewTaskHandler(&TaskBackend{
+			HTTPErrorHandler: ErrorHandler(0),
+			Logger:           zaptest.NewLogger(t),
+
+			TaskService: &mock.TaskService{
+				CreateTaskFn: func(_ context.Context, tc platform.TaskCreate) (*platform.Task, error) {
+					*created = true
+					return &platform.Task{ID: 9, OrganizationID: tc.OrganizationID, OwnerID: 3, Name: "x", Flux: tc.Flux}, nil
+				},
+				DeleteTaskFn: func(_ context.Context, id platform.ID) error {
+					*deleted = append(*deleted, id)
+					return nil
+				},
+			},
+			OrganizationService: &mock.OrganizationService{
+				FindOrganizationByIDF: func(_ context.Context, id platform.ID) (*platform.Organization, error) {
+					return &platform.Organization{ID: id, Name: "o"}, nil
+				},
+			},
+			LabelService:    ls,
+			BucketService:   bs,
+			ValidateBuckets: true,
+		})
+	}
+
+	post := func(t *testing.T, h *TaskHandler, bucket string) *http.Response {
+		b, err := json.Marshal(platform.TaskExport{
+			Version: platform.TaskExportVersion,
+			Name:    "x",
+			Status:  platform.TaskStatusActive,
+			Flux:    fmt.Sprintf(`option task = {name:"x", every:1m} from(bucket:"b-src") |> range(start:-1m) |> to(bucket:%q)`, bucket),
+			Labels:  []platform.TaskExportLabel{{Name: "nightly"}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		authz := &platform.Authorization{OrgID: orgID, UserID: 3, Permissions: platform.OperPermissions()}
+		r := httptest.NewRequest("POST", "http://localhost:9999/api/v2/tasks/import", bytes.NewReader(b)).WithContext(
+			pcontext.SetAuthorizer(context.Background(), authz),
+		)
+		w := httptest.NewRecorder()
+		h.handleImportTask(w, r)
+		return w.Result()
+	}
+
+	t.Run("imports task", func(t *testing.T) {
+		var created bool
+		var deleted []platform.ID
+		h := newHandler(t, &created, &deleted, nil)
+
+		res := post(t, h, "b-dst")
+		if res.StatusCode != http.StatusCreated {
+			b, _ := ioutil.ReadAll(res.Body)
+			t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, res.StatusCode, b)
+		}
+		var tr taskResponse
+		if err := json.NewDecoder(res.Body).Decode(&tr); err != nil {
+			t.Fatal(err)
+		}
+		if len(tr.Labels) != 1 || tr.Labels[0].ID != 3 {
+			t.Fatalf("expected the imported label, got %+v", tr.Labels)
+		}
+		if len(deleted) != 0 {
+			t.Fatalf("expected no task to be deleted, got %v", deleted)
+		}
+	})
+
+	t.Run("rejects an unknown bucket", func(t *testing.T) {
+		var created bool
+		var deleted []platform.ID
+		h := newHandler(t, &created, &deleted, nil)
+
+		res := post(t, h, "b-missing")
+		if res.StatusCode != http.StatusBadRequest {
+			b, _ := ioutil.ReadAll(res.Body)
+			t.Fatalf("expected status %d, got %d: %s", http.StatusBadRequest, res.StatusCode, b)
+		}
+		if created {
+			t.Fatal("expected no task to be created")
+		}
+	})
+
+	t.Run("rolls back on a failed label", func(t *testing.T) {
+		var created bool
+		var deleted []platform.ID
+		h := newHandler(t, &created, &deleted, &platform.Error{Code: platform.EInternal, Msg: "label store down"})
+
+		res := post(t, h, "b-dst")
+		if res.StatusCode != http.StatusInternalServerError {
+			b, _ := ioutil.ReadAll(res.Body)
+			t.Fatalf("expected status %d, got %d: %s", http.StatusInternalServerError, res.StatusCode, b)
+		}
+		if len(deleted) != 1 || deleted[0] != 9 {
+			t.Fatalf("expected the imported task to be deleted, got %v", deleted)
+		}
+	})
+}
+
+func TestTaskHandler_handleGetTasksHealth(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "task store reachable",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"name":"tasks","message":"ready for tasks","status":"pass","checks":[{"name":"store","status":"pass"}]}`,
+		},
+		{
+			name:       "task store unreachable",
+			err:        errors.New("store unavailable"),
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   `{"name":"tasks","message":"unable to read tasks","status":"fail","checks":[{"name":"store","status":"fail","message":"store unavailable"}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskBackend := NewMockTaskBackend(t)
+			taskBackend.HTTPErrorHandler = ErrorHandler(0)
+			taskBackend.TaskService = &mock.TaskService{
+				FindTasksFn: func(ctx context.Context, f platform.TaskFilter) ([]*platform.Task, int, error) {
+					if f.Limit != 1 {
+						t.Errorf("expected the health check to find at most 1 task, got limit %d", f.Limit)
+					}
+					if tt.err != nil {
+						return nil, 0, tt.err
+					}
+					return []*platform.Task{{ID: 1, Name: "task1", OrganizationID: 1, OwnerID: 1}}, 1, nil
+				},
+			}
+			h := NewTaskHandler(taskBackend)
+
+			r := httptest.NewRequest("GET", "http://any.url/api/v2/tasks/health", nil)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("unexpected status: got %d, want %d: %s", res.StatusCode, tt.wantStatus, body)
+			}
+			if eq, diff, err := jsonEqual(string(body), tt.wantBody); err != nil {
+				t.Fatalf("error unmarshaling json %v", err)
+			} else if !eq {
+				t.Errorf("unexpected body -got/+want\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTaskHandler_handleGetTasksHealth_unauthenticated(t *testing.T) {
+	b := &APIBackend{
+		HTTPErrorHandler: ErrorHandler(0),
+		Logger:           zaptest.NewLogger(t),
+		TaskService: &mock.TaskService{
+			FindTasksFn: func(ctx context.Context, f platform.TaskFilter) ([]*platform.Task, int, error) {
+				t.Error("expected the health check not to read through the authorized task service")
+				return nil, 0, nil
+			},
+		},
+		TaskHealthService: &mock.TaskService{
+			FindTasksFn: func(ctx context.Context, f platform.TaskFilter) ([]*platform.Task, int, error) {
+				return nil, 0, nil
+			},
+		},
+	}
+	h := NewPlatformHandler(b)
+
+	r := httptest.NewRequest("GET", "http://any.url/api/v2/tasks/health", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	res := w.Result()
+	body, _ := ioutil.ReadAll(res.Body)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status: got %d, want %d: %s", res.StatusCode, http.StatusOK, body)
+	}
+}
+
+func TestTaskHandler_handleValidateTask(t *testing.T) {
+	type wants struct {
+		statusCode  int
+		contentType string
+		body        string
+	}
+
+	tests := []struct {
+		name  string
+		flux  string
+		wants wants
+	}{
+		{
+			name: "valid script",
+			flux: `option task = {name: "my task", every: 1h, offset: 10m}
+from(bucket: "b") |> range(start: -1h)`,
+			wants: wants{
+				statusCode:  http.StatusOK,
+				contentType: "application/json; charset=utf-8",
+				body: `
+{
+  "name": "my task",
+  "every": "1h",
+  "offset": "10m"
+}
+`,
+			},
+		},
+		{
+			name: "missing task option",
+			flux: `from(bucket: "b") |> range(start: -1h)`,
+			wants: wants{
+				statusCode:  http.StatusBadRequest,
+				contentType: "application/json; charset=utf-8",
+				body: `
+{
+  "code": "invalid",
+  "message": "invalid flux script",
+  "error": "missing required option: task"
 }
 `,
 			},
 		},
 		{
-			name: "create task - error creating task",
-			args: args{
-				taskCreate: platform.TaskCreate{
-					OrganizationID: 1,
-					Flux:           "abc",
-				},
-			},
-			fields: fields{
-				taskService: &mock.TaskService{
-					CreateTaskFn: func(ctx context.Context, tc platform.TaskCreate) (*platform.Task, error) {
-						return nil, errors.New("something bad happened")
-					},
-				},
-			},
+			name: "conflicting cron and every",
+			flux: `option task = {name: "my task", every: 1h, cron: "* * * * *"}
+from(bucket: "b") |> range(start: -1h)`,
 			wants: wants{
-				statusCode:  http.StatusInternalServerError,
+				statusCode:  http.StatusBadRequest,
 				contentType: "application/json; charset=utf-8",
 				body: `
 {
-    "code": "internal error",
-    "message": "failed to create task",
-    "error": "something bad happened"
+  "code": "invalid",
+  "message": "invalid flux script",
+  "error": "cannot use both cron and every in task options"
 }
 `,
 			},
@@ -554,38 +1224,40 @@ func TestTaskHandler_handlePostTasks(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			b, err := json.Marshal(tt.args.taskCreate)
+			b, err := json.Marshal(map[string]string{"flux": tt.flux})
 			if err != nil {
-				t.Fatalf("failed to unmarshal task: %v", err)
+				t.Fatalf("failed to marshal request: %v", err)
 			}
 
-			r := httptest.NewRequest("POST", "http://any.url", bytes.NewReader(b))
-			ctx := pcontext.SetAuthorizer(context.TODO(), new(platform.Authorization))
-			r = r.WithContext(ctx)
-
+			r := httptest.NewRequest("POST", "http://any.url/api/v2/tasks/validate", bytes.NewReader(b))
 			w := httptest.NewRecorder()
 
 			taskBackend := NewMockTaskBackend(t)
 			taskBackend.HTTPErrorHandler = ErrorHandler(0)
-			taskBackend.TaskService = tt.fields.taskService
+			taskBackend.TaskService = &mock.TaskService{
+				CreateTaskFn: func(ctx context.Context, tc platform.TaskCreate) (*platform.Task, error) {
+					t.Fatal("validate must not create a task")
+					return nil, nil
+				},
+			}
 			h := NewTaskHandler(taskBackend)
-			h.handlePostTask(w, r)
+			h.ServeHTTP(w, r)
 
 			res := w.Result()
 			content := res.Header.Get("Content-Type")
 			body, _ := ioutil.ReadAll(res.Body)
 
 			if res.StatusCode != tt.wants.statusCode {
-				t.Errorf("%q. handlePostTask() = %v, want %v", tt.name, res.StatusCode, tt.wants.statusCode)
+				t.Errorf("%q. handleValidateTask() = %v, want %v", tt.name, res.StatusCode, tt.wants.statusCode)
 			}
 			if tt.wants.contentType != "" && content != tt.wants.contentType {
-				t.Errorf("%q. handlePostTask() = %v, want %v", tt.name, content, tt.wants.contentType)
+				t.Errorf("%q. handleValidateTask() = %v, want %v", tt.name, content, tt.wants.contentType)
 			}
 			if tt.wants.body != "" {
 				if eq, diff, err := jsonEqual(string(body), tt.wants.body); err != nil {
-					t.Errorf("%q, handlePostTask(). error unmarshaling json %v", tt.name, err)
+					t.Errorf("%q, handleValidateTask(). error unmarshaling json %v", tt.name, err)
 				} else if !eq {
-					t.Errorf("%q. handlePostTask() = ***%s***", tt.name, diff)
+					t.Errorf("%q. handleValidateTask() = ***%s***", tt.name, diff)
 				}
 			}
 		})
@@ -651,7 +1323,8 @@ func TestTaskHandler_handleGetRun(t *testing.T) {
   "scheduledFor": "2018-12-01T17:00:13Z",
   "startedAt": "2018-12-01T17:00:03.155645Z",
   "finishedAt": "2018-12-01T17:00:13.155645Z",
-  "requestedAt": "2018-12-01T17:00:13Z"
+  "requestedAt": "2018-12-01T17:00:13Z",
+  "duration": "10s"
 }`,
 			},
 		},
@@ -767,7 +1440,8 @@ func TestTaskHandler_handleGetRuns(t *testing.T) {
       "scheduledFor": "2018-12-01T17:00:13Z",
       "startedAt": "2018-12-01T17:00:03.155645Z",
       "finishedAt": "2018-12-01T17:00:13.155645Z",
-      "requestedAt": "2018-12-01T17:00:13Z"
+      "requestedAt": "2018-12-01T17:00:13Z",
+      "duration": "10s"
     }
   ]
 }`,
@@ -816,6 +1490,338 @@ func TestTaskHandler_handleGetRuns(t *testing.T) {
 	}
 }
 
+func TestTaskHandler_handleGetRuns_fields(t *testing.T) {
+	ts := &mock.TaskService{
+		FindRunsFn: func(ctx context.Context, f platform.RunFilter) ([]*platform.Run, int, error) {
+			runs := []*platform.Run{
+				{
+					ID:           platform.ID(2),
+					TaskID:       f.Task,
+					Status:       "success",
+					ScheduledFor: "2018-12-01T17:00:13Z",
+					StartedAt:    "2018-12-01T17:00:03.155645Z",
+					FinishedAt:   "2018-12-01T17:00:13.155645Z",
+				},
+				{
+					ID:           platform.ID(3),
+					TaskID:       f.Task,
+					Status:       "started",
+					ScheduledFor: "2018-12-01T17:01:13Z",
+					StartedAt:    "2018-12-01T17:01:13.155645Z",
+				},
+			}
+			return runs, len(runs), nil
+		},
+	}
+
+	getRuns := func(query string) *http.Response {
+		t.Helper()
+		r := httptest.NewRequest("GET", "http://any.url"+query, nil)
+		r = r.WithContext(context.WithValue(
+			context.Background(),
+			httprouter.ParamsKey,
+			httprouter.Params{{Key: "id", Value: platform.ID(1).String()}},
+		))
+		r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{Permissions: platform.OperPermissions()}))
+		w := httptest.NewRecorder()
+		taskBackend := NewMockTaskBackend(t)
+		taskBackend.HTTPErrorHandler = ErrorHandler(0)
+		taskBackend.TaskService = ts
+		NewTaskHandler(taskBackend).handleGetRuns(w, r)
+		return w.Result()
+	}
+
+	res := getRuns("")
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("handleGetRuns() = %v, want %v", res.StatusCode, http.StatusOK)
+	}
+	var full runsResponse
+	if err := json.NewDecoder(res.Body).Decode(&full); err != nil {
+		t.Fatal(err)
+	}
+
+	res = getRuns("?fields=id,status,scheduledFor")
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("handleGetRuns() = %v, want %v", res.StatusCode, http.StatusOK)
+	}
+	body, _ := ioutil.ReadAll(res.Body)
+	want := `
+{
+  "runs": [
+    {"id": "0000000000000002", "status": "success", "scheduledFor": "2018-12-01T17:00:13Z"},
+    {"id": "0000000000000003", "status": "started", "scheduledFor": "2018-12-01T17:01:13Z"}
+  ]
+}`
+	if eq, diff, err := jsonEqual(string(body), want); err != nil {
+		t.Fatalf("error unmarshaling json %v", err)
+	} else if !eq {
+		t.Errorf("handleGetRuns() = ***%s***", diff)
+	}
+
+	var compact compactRunsResponse
+	if err := json.Unmarshal(body, &compact); err != nil {
+		t.Fatal(err)
+	}
+	if len(compact.Runs) != len(full.Runs) {
+		t.Fatalf("got %d compact runs, want %d", len(compact.Runs), len(full.Runs))
+	}
+	for i := range full.Runs {
+		if got, exp := compact.Runs[i].ID, full.Runs[i].ID; got != exp {
+			t.Errorf("run %d: got ID %s, want %s", i, got, exp)
+		}
+	}
+
+	res = getRuns("?fields=status")
+	body, _ = ioutil.ReadAll(res.Body)
+	want = `{"runs": [{"status": "success"}, {"status": "started"}]}`
+	if eq, diff, err := jsonEqual(string(body), want); err != nil {
+		t.Fatalf("error unmarshaling json %v", err)
+	} else if !eq {
+		t.Errorf("handleGetRuns() = ***%s***", diff)
+	}
+
+	if res := getRuns("?fields=id,log"); res.StatusCode != http.StatusBadRequest {
+		t.Errorf("handleGetRuns() with an unknown field = %v, want %v", res.StatusCode, http.StatusBadRequest)
+	}
+}
+
+func Test_decodeGetRunsRequest_limit(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		limit   int
+		wantErr bool
+	}{
+		{name: "defaults without a limit", query: "", limit: platform.RunDefaultPageSize},
+		{name: "uses the given limit", query: "?limit=10", limit: 10},
+		{name: "rejects a zero limit", query: "?limit=0", wantErr: true},
+		{name: "rejects a limit over the max", query: fmt.Sprintf("?limit=%d", platform.TaskMaxPageSize+1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "http://any.url"+tt.query, nil)
+			ctx := context.WithValue(context.Background(), httprouter.ParamsKey, httprouter.Params{
+				{Key: "id", Value: platform.ID(1).String()},
+			})
+
+			req, err := decodeGetRunsRequest(ctx, r)
+			if tt.wantErr {
+				if err != platform.ErrOutOfBoundsLimit {
+					t.Fatalf("expected out of bounds limit error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if req.filter.Limit != tt.limit {
+				t.Fatalf("unexpected limit: got %d, exp %d", req.filter.Limit, tt.limit)
+			}
+		})
+	}
+}
+
+func TestTaskService_FindRuns_defaultLimit(t *testing.T) {
+	var query string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Write([]byte(`{"runs":[]}`))
+	}))
+	defer ts.Close()
+
+	s := TaskService{Addr: ts.URL}
+	if _, _, err := s.FindRuns(context.Background(), platform.RunFilter{Task: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if query != "" {
+		t.Fatalf("expected no limit to be sent, got query %q", query)
+	}
+
+	if _, _, err := s.FindRuns(context.Background(), platform.RunFilter{Task: 1, Limit: -1}); err != platform.ErrOutOfBoundsLimit {
+		t.Fatalf("expected out of bounds limit error, got %v", err)
+	}
+}
+
+func TestTaskHandler_handleForceRun_maxFuture(t *testing.T) {
+	const taskID = platform.ID(0xCCCCCC)
+	now := time.Now()
+
+	tests := []struct {
+		name         string
+		scheduledFor time.Time
+		wantStatus   int
+	}{
+		{
+			name:         "a year in the future",
+			scheduledFor: now.AddDate(1, 0, 0),
+			wantStatus:   http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "yesterday",
+			scheduledFor: now.AddDate(0, 0, -1),
+			wantStatus:   http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var forced bool
+			taskBackend := NewMockTaskBackend(t)
+			taskBackend.HTTPErrorHandler = ErrorHandler(0)
+			taskBackend.ForceRunMaxFuture = time.Hour
+			taskBackend.TaskService = &mock.TaskService{
+				ForceRunWithNoteFn: func(_ context.Context, tid platform.ID, scheduledFor int64, _ string) (*platform.Run, error) {
+					forced = true
+					if scheduledFor != tt.scheduledFor.Unix() {
+						t.Errorf("unexpected scheduled for: got %d, want %d", scheduledFor, tt.scheduledFor.Unix())
+					}
+					return &platform.Run{ID: 1, TaskID: tid, Status: backend.RunScheduled.String()}, nil
+				},
+			}
+			h := NewTaskHandler(taskBackend)
+
+			body := fmt.Sprintf(`{"scheduledFor":%q}`, tt.scheduledFor.Format(time.RFC3339))
+			r := httptest.NewRequest("POST", "http://any.url/api/v2/tasks/"+taskID.String()+"/runs", strings.NewReader(body))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			b, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("unexpected status: got %d, want %d: %s", res.StatusCode, tt.wantStatus, b)
+			}
+			if want := tt.wantStatus == http.StatusOK; forced != want {
+				t.Fatalf("unexpected force run: got %v, want %v", forced, want)
+			}
+		})
+	}
+}
+
+func TestTaskHandler_handleRetryRun_maxFuture(t *testing.T) {
+	const taskID, runID = platform.ID(0xCCCCCC), platform.ID(0xAAAA)
+	now := time.Now()
+
+	tests := []struct {
+		name         string
+		scheduledFor time.Time
+		wantStatus   int
+	}{
+		{
+			name:         "a year in the future",
+			scheduledFor: now.AddDate(1, 0, 0),
+			wantStatus:   http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "yesterday",
+			scheduledFor: now.AddDate(0, 0, -1),
+			wantStatus:   http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var retried bool
+			taskBackend := NewMockTaskBackend(t)
+			taskBackend.HTTPErrorHandler = ErrorHandler(0)
+			taskBackend.ForceRunMaxFuture = time.Hour
+			taskBackend.TaskService = &mock.TaskService{
+				RetryRunFn: func(_ context.Context, tid, rid platform.ID, scheduledFor *int64) (*platform.Run, error) {
+					retried = true
+					if scheduledFor == nil || *scheduledFor != tt.scheduledFor.Unix() {
+						t.Errorf("unexpected scheduled for: got %v, want %d", scheduledFor, tt.scheduledFor.Unix())
+					}
+					return &platform.Run{ID: rid, TaskID: tid, Status: backend.RunScheduled.String()}, nil
+				},
+			}
+			h := NewTaskHandler(taskBackend)
+
+			body := fmt.Sprintf(`{"scheduledFor":%q}`, tt.scheduledFor.Format(time.RFC3339))
+			r := httptest.NewRequest("POST", "http://any.url/api/v2/tasks/"+taskID.String()+"/runs/"+runID.String()+"/retry", strings.NewReader(body))
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{}))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			b, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("unexpected status: got %d, want %d: %s", res.StatusCode, tt.wantStatus, b)
+			}
+			if want := tt.wantStatus == http.StatusOK; retried != want {
+				t.Fatalf("unexpected retry: got %v, want %v", retried, want)
+			}
+		})
+	}
+}
+
+func TestTaskHandler_handleGetNextRuns(t *testing.T) {
+	const taskID = platform.ID(0xCCCCCC)
+	now := time.Now().UTC()
+
+	getNext := func(t *testing.T, task *platform.Task, count int) []nextRun {
+		t.Helper()
+		taskBackend := NewMockTaskBackend(t)
+		taskBackend.HTTPErrorHandler = ErrorHandler(0)
+		taskBackend.TaskService = &mock.TaskService{
+			FindTaskByIDFn: func(_ context.Context, id platform.ID) (*platform.Task, error) {
+				if id != taskID {
+					return nil, platform.ErrTaskNotFound
+				}
+				return task, nil
+			},
+		}
+		h := NewTaskHandler(taskBackend)
+
+		r := httptest.NewRequest("GET", fmt.Sprintf("http://any.url/api/v2/tasks/%s/next?count=%d", taskID, count), nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		res := w.Result()
+		b, _ := ioutil.ReadAll(res.Body)
+		if res.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status: got %d, want %d: %s", res.StatusCode, http.StatusOK, b)
+		}
+		var resp nextRunsResponse
+		if err := json.Unmarshal(b, &resp); err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Next) != count {
+			t.Fatalf("unexpected number of runs: got %d, want %d", len(resp.Next), count)
+		}
+		return resp.Next
+	}
+
+	t.Run("cron", func(t *testing.T) {
+		next := getNext(t, &platform.Task{
+			ID:              taskID,
+			Cron:            "* * * * *",
+			Offset:          "10s",
+			LatestCompleted: now.Format(time.RFC3339),
+		}, 1)
+
+		latest := now.Truncate(time.Second)
+		if s := next[0].ScheduledFor; !s.After(latest) || s.After(latest.Add(time.Minute)) {
+			t.Fatalf("next run should be scheduled within a minute of %s, got %s", latest, s)
+		}
+		if got, want := next[0].DueAt.Sub(next[0].ScheduledFor), 10*time.Second; got != want {
+			t.Fatalf("unexpected offset: got %s, want %s", got, want)
+		}
+	})
+
+	t.Run("every", func(t *testing.T) {
+		next := getNext(t, &platform.Task{
+			ID:        taskID,
+			Every:     "30s",
+			CreatedAt: now.Format(time.RFC3339),
+		}, 3)
+
+		for i := 1; i < len(next); i++ {
+			if got, want := next[i].ScheduledFor.Sub(next[i-1].ScheduledFor), 30*time.Second; got != want {
+				t.Fatalf("unexpected spacing between runs %d and %d: got %s, want %s", i-1, i, got, want)
+			}
+		}
+	})
+}
+
 func TestTaskHandler_NotFoundStatus(t *testing.T) {
 	// Ensure that the HTTP handlers return 404s for missing resources, and OKs for matching.
 
@@ -981,7 +1987,7 @@ func TestTaskHandler_NotFoundStatus(t *testing.T) {
 		{
 			name: "force run",
 			svc: &mock.TaskService{
-				ForceRunFn: func(_ context.Context, tid platform.ID, _ int64) (*platform.Run, error) {
+				ForceRunWithNoteFn: func(_ context.Context, tid platform.ID, _ int64, _ string) (*platform.Run, error) {
 					if tid != taskID {
 						return nil, platform.ErrTaskNotFound
 					}
@@ -1017,7 +2023,7 @@ func TestTaskHandler_NotFoundStatus(t *testing.T) {
 		{
 			name: "retry run",
 			svc: &mock.TaskService{
-				RetryRunFn: func(_ context.Context, tid, rid platform.ID) (*platform.Run, error) {
+				RetryRunFn: func(_ context.Context, tid, rid platform.ID, _ *int64) (*platform.Run, error) {
 					if tid != taskID {
 						return nil, platform.ErrTaskNotFound
 					}
@@ -1299,6 +2305,156 @@ func TestTaskHandler_CreateTaskWithOrgName(t *testing.T) {
 	}
 }
 
+func TestTaskHandler_CreateTaskValidateBuckets(t *testing.T) {
+	orgID := platform.ID(1)
+
+	bs := mock.NewBucketService()
+	bs.FindBucketFn = func(_ context.Context, f platform.BucketFilter) (*platform.Bucket, error) {
+		if f.Name == nil || *f.Name != "b-dst" || f.OrganizationID == nil || *f.OrganizationID != orgID {
+			return nil, &platform.Error{Code: platform.ENotFound, Msg: "bucket not found"}
+		}
+		return &platform.Bucket{ID: 2, OrgID: orgID, Name: *f.Name}, nil
+	}
+
+	var created bool
+	ts := &mock.TaskService{
+		CreateTaskFn: func(_ context.Context, tc platform.TaskCreate) (*platform.Task, error) {
+			created = true
+			return &platform.Task{ID: 9, OrganizationID: tc.OrganizationID, OwnerID: 3, Name: "x", Flux: tc.Flux}, nil
+		},
+	}
+
+	h := NewTaskHandler(&TaskBackend{
+		HTTPErrorHandler: ErrorHandler(0),
+		Logger:           zaptest.NewLogger(t),
+
+		TaskService:     ts,
+		LabelService:    mock.NewLabelService(),
+		BucketService:   bs,
+		ValidateBuckets: true,
+	})
+
+	tests := []struct {
+		name       string
+		bucket     string
+		wantStatus int
+	}{
+		{
+			name:       "existing bucket",
+			bucket:     "b-dst",
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "unknown bucket",
+			bucket:     "b-missing",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created = false
+			script := fmt.Sprintf(`option task = {name:"x", every:1m} from(bucket:"b-src") |> range(start:-1m) |> to(bucket:%q)`, tt.bucket)
+			b, err := json.Marshal(platform.TaskCreate{
+				Flux:           script,
+				OrganizationID: orgID,
+				Organization:   "o",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			authz := &platform.Authorization{OrgID: orgID, UserID: 3, Permissions: platform.OperPermissions()}
+			r := httptest.NewRequest("POST", "http://localhost:9999/api/v2/tasks", bytes.NewReader(b)).WithContext(
+				pcontext.SetAuthorizer(context.Background(), authz),
+			)
+			w := httptest.NewRecorder()
+			h.handlePostTask(w, r)
+
+			res := w.Result()
+			defer res.Body.Close()
+			body, err := ioutil.ReadAll(res.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("unexpected status: got %d, want %d: %s", res.StatusCode, tt.wantStatus, body)
+			}
+			if created != (tt.wantStatus == http.StatusCreated) {
+				t.Fatalf("unexpected task creation: got %v", created)
+			}
+			if tt.wantStatus == http.StatusBadRequest && !bytes.Contains(body, []byte(tt.bucket)) {
+				t.Fatalf("expected the error to name bucket %q, got %s", tt.bucket, body)
+			}
+		})
+	}
+}
+
+func TestTaskHandler_handleGetRuns_sessionScope(t *testing.T) {
+	const taskID = platform.ID(12345)
+	orgID := platformtesting.MustIDBase16("020f755c3c082000")
+	userID := platformtesting.MustIDBase16("020f755c3c082001")
+
+	sess := &platform.Session{
+		ID:          platformtesting.MustIDBase16("020f755c3c082002"),
+		UserID:      userID,
+		Permissions: platform.OwnerPermissions(orgID),
+		ExpiresAt:   time.Now().Add(24 * time.Hour),
+	}
+	readAuths := platform.Permission{
+		Action:   platform.ReadAction,
+		Resource: platform.Resource{Type: platform.AuthorizationsResourceType, OrgID: &orgID},
+	}
+	if !sess.Allowed(readAuths) {
+		t.Fatal("expected the session to be able to read authorizations")
+	}
+
+	var findRunsCtx context.Context
+	b := NewMockTaskBackend(t)
+	b.HTTPErrorHandler = ErrorHandler(0)
+	b.TaskService = &mock.TaskService{
+		FindTaskByIDFn: func(ctx context.Context, id platform.ID) (*platform.Task, error) {
+			return &platform.Task{ID: taskID, OrganizationID: orgID}, nil
+		},
+		FindRunsFn: func(ctx context.Context, f platform.RunFilter) ([]*platform.Run, int, error) {
+			findRunsCtx = ctx
+			return []*platform.Run{}, 0, nil
+		},
+	}
+	h := NewTaskHandler(b)
+
+	url := fmt.Sprintf("http://any.url/api/v2/tasks/%s/runs", taskID)
+	ctx := pcontext.SetAuthorizer(context.Background(), sess)
+	ctx = context.WithValue(ctx, httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: taskID.String()}})
+	w := httptest.NewRecorder()
+	h.handleGetRuns(w, httptest.NewRequest("GET", url, nil).WithContext(ctx))
+
+	res := w.Result()
+	if res.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(res.Body)
+		t.Fatalf("expected status OK, got %v: %s", res.StatusCode, body)
+	}
+
+	authr, err := pcontext.GetAuthorizer(findRunsCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth, ok := authr.(*platform.Authorization)
+	if !ok {
+		t.Fatalf("expected context's authorizer to be an authorization, got %T", authr)
+	}
+	if auth.Allowed(readAuths) {
+		t.Error("expected the ephemeral authorization not to be able to read authorizations")
+	}
+	tid := taskID
+	readTask := platform.Permission{
+		Action:   platform.ReadAction,
+		Resource: platform.Resource{Type: platform.TasksResourceType, OrgID: &orgID, ID: &tid},
+	}
+	if !auth.Allowed(readTask) {
+		t.Error("expected the ephemeral authorization to be able to read the task")
+	}
+}
+
 func TestTaskHandler_Sessions(t *testing.T) {
 	t.Skip("rework these")
 	// Common setup to get a working base for using tasks.
@@ -1653,7 +2809,7 @@ func TestTaskHandler_Sessions(t *testing.T) {
 
 		var retryRunCtx context.Context
 		ts := &mock.TaskService{
-			RetryRunFn: func(ctx context.Context, tid, rid platform.ID) (*platform.Run, error) {
+			RetryRunFn: func(ctx context.Context, tid, rid platform.ID, _ *int64) (*platform.Run, error) {
 				retryRunCtx = ctx
 				if tid != taskID {
 					t.Fatalf("expected task ID %v, got %v", taskID, tid)
@@ -1736,3 +2892,56 @@ func TestTaskHandler_Sessions(t *testing.T) {
 		}
 	})
 }
+
+func TestTaskService_stillQueued(t *testing.T) {
+	// The queued run's window ends a minute from now.
+	end := time.Now().Add(time.Minute).Unix()
+	queued := backend.RequestStillQueuedError{Start: end - 60, End: end}
+
+	taskBackend := NewMockTaskBackend(t)
+	taskBackend.HTTPErrorHandler = ErrorHandler(0)
+	taskBackend.TaskService = &mock.TaskService{
+		RetryRunFn: func(context.Context, platform.ID, platform.ID, *int64) (*platform.Run, error) {
+			return nil, queued
+		},
+		ForceRunWithNoteFn: func(context.Context, platform.ID, int64, string) (*platform.Run, error) {
+			return nil, platform.RunAlreadyQueuedError{ScheduledFor: end}
+		},
+	}
+	h := NewTaskHandler(taskBackend)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{})))
+	}))
+	defer ts.Close()
+	s := TaskService{Addr: ts.URL}
+
+	t.Run("retry run", func(t *testing.T) {
+		_, err := s.RetryRun(context.Background(), 1, 2, nil)
+		got, ok := err.(backend.RequestStillQueuedError)
+		if !ok {
+			t.Fatalf("expected a RequestStillQueuedError, got %v", err)
+		}
+		if got.Start != queued.Start || got.End != queued.End {
+			t.Fatalf("unexpected queued window: got %d-%d, exp %d-%d", got.Start, got.End, queued.Start, queued.End)
+		}
+		// Retry-After is the time left in the queued window.
+		if got.RetryAfter <= 0 || got.RetryAfter > time.Minute {
+			t.Fatalf("expected to retry within a minute, got %v", got.RetryAfter)
+		}
+	})
+
+	t.Run("force run", func(t *testing.T) {
+		_, err := s.ForceRun(context.Background(), 1, end)
+		got, ok := err.(platform.RunAlreadyQueuedError)
+		if !ok {
+			t.Fatalf("expected a RunAlreadyQueuedError, got %v", err)
+		}
+		if got.ScheduledFor != end {
+			t.Fatalf("unexpected scheduled time of queued run: got %d, exp %d", got.ScheduledFor, end)
+		}
+		// Retry-After is the time left until the queued run is scheduled.
+		if got.RetryAfter <= 0 || got.RetryAfter > time.Minute {
+			t.Fatalf("expected to retry within a minute, got %v", got.RetryAfter)
+		}
+	})
+}
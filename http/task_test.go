@@ -93,3 +93,100 @@ func TestTaskService(t *testing.T) {
 		"transactional",
 	)
 }
+
+func TestTaskService_ExportImport(t *testing.T) {
+	service := kv.NewService(inmem.NewKVStore())
+	ctx := context.Background()
+	if err := service.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing kv service: %v", err)
+	}
+
+	h := http.NewAuthenticationHandler(http.ErrorHandler(0))
+	h.AuthorizationService = service
+	h.Handler = http.NewTaskHandler(&http.TaskBackend{
+		HTTPErrorHandler:           http.ErrorHandler(0),
+		Logger:                     zaptest.NewLogger(t).With(zap.String("handler", "task")),
+		TaskService:                service,
+		AuthorizationService:       service,
+		OrganizationService:        service,
+		UserResourceMappingService: service,
+		LabelService:               service,
+		UserService:                service,
+		BucketService:              service,
+	})
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	src := &platform.Organization{Name: "src"}
+	if err := service.CreateOrganization(ctx, src); err != nil {
+		t.Fatal(err)
+	}
+	dst := &platform.Organization{Name: "dst"}
+	if err := service.CreateOrganization(ctx, dst); err != nil {
+		t.Fatal(err)
+	}
+	user := &platform.User{Name: "user"}
+	if err := service.CreateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	auth := platform.Authorization{UserID: user.ID, OrgID: src.ID}
+	if err := service.CreateAuthorization(ctx, &auth); err != nil {
+		t.Fatal(err)
+	}
+
+	taskService := http.TaskService{
+		Addr:  server.URL,
+		Token: auth.Token,
+	}
+
+	const script = `option task = {name: "exported", every: 1m, offset: 5s} from(bucket: "b") |> range(start: -1m) |> to(bucket: "c")`
+	task, err := taskService.CreateTask(ctx, platform.TaskCreate{
+		OrganizationID: src.ID,
+		Flux:           script,
+		Description:    "moved between orgs",
+		Status:         platform.TaskStatusInactive,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	label := &platform.Label{OrgID: src.ID, Name: "nightly", Properties: map[string]string{"color": "blue"}}
+	if err := service.CreateLabel(ctx, label); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.CreateLabelMapping(ctx, &platform.LabelMapping{LabelID: label.ID, ResourceID: task.ID, ResourceType: platform.TasksResourceType}); err != nil {
+		t.Fatal(err)
+	}
+
+	te, err := taskService.ExportTask(ctx, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if te.Version != platform.TaskExportVersion || te.Name != "exported" || te.Offset != "5s" || te.Status != platform.TaskStatusInactive {
+		t.Fatalf("unexpected export: %+v", te)
+	}
+
+	imported, err := taskService.ImportTask(ctx, dst.ID, *te)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.ID == task.ID {
+		t.Fatal("expected the import to create a new task")
+	}
+	if imported.OrganizationID != dst.ID {
+		t.Fatalf("unexpected org of imported task: got %s, want %s", imported.OrganizationID, dst.ID)
+	}
+	if imported.Flux != task.Flux {
+		t.Fatalf("unexpected flux of imported task:\n got: %s\nwant: %s", imported.Flux, task.Flux)
+	}
+	if imported.Description != task.Description || imported.Status != task.Status {
+		t.Fatalf("unexpected imported task: %+v", imported)
+	}
+
+	labels, err := service.FindResourceLabels(ctx, platform.LabelMappingFilter{ResourceID: imported.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(labels) != 1 || labels[0].OrgID != dst.ID || labels[0].Name != "nightly" || labels[0].Properties["color"] != "blue" {
+		t.Fatalf("unexpected labels of imported task: %+v", labels)
+	}
+}
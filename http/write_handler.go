@@ -229,8 +229,13 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.PointsWriter.WritePoints(ctx, points); err != nil {
 		logger.Error("Error writing points", zap.Error(err))
+		code := platform.EInternal
+		if platform.ErrorCode(err) == platform.EUnavailable {
+			// The write may succeed if it is retried later.
+			code = platform.EUnavailable
+		}
 		h.HandleHTTPError(ctx, &platform.Error{
-			Code: platform.EInternal,
+			Code: code,
 			Op:   "http/handleWrite",
 			Msg:  fmt.Sprintf("unable to write points to database: %v", err),
 			Err:  err,
@@ -132,6 +132,20 @@ func (s *Service) FindResourceLabels(ctx context.Context, filter influxdb.LabelM
 	return ls, nil
 }
 
+// FindResourceLabelsForResources returns the labels that are mapped to each of the resources.
+func (s *Service) FindResourceLabelsForResources(ctx context.Context, rt influxdb.ResourceType, ids []influxdb.ID) (map[influxdb.ID][]*influxdb.Label, error) {
+	m := make(map[influxdb.ID][]*influxdb.Label, len(ids))
+	for _, id := range ids {
+		ls, err := s.FindResourceLabels(ctx, influxdb.LabelMappingFilter{ResourceID: id, ResourceType: rt})
+		if err != nil {
+			return nil, err
+		}
+		m[id] = ls
+	}
+
+	return m, nil
+}
+
 // CreateLabel creates a new label.
 func (s *Service) CreateLabel(ctx context.Context, l *influxdb.Label) error {
 	l.ID = s.IDGenerator.ID()
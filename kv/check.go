@@ -183,12 +183,23 @@ func (s *Service) FindCheck(ctx context.Context, filter influxdb.CheckFilter) (i
 }
 
 func filterChecksFn(filter influxdb.CheckFilter) func(c influxdb.Check) bool {
+	var ids map[influxdb.ID]bool
+	if len(filter.IDs) > 0 {
+		ids = make(map[influxdb.ID]bool, len(filter.IDs))
+		for _, id := range filter.IDs {
+			ids[id] = true
+		}
+	}
+
 	return func(c influxdb.Check) bool {
 		if filter.ID != nil {
 			if c.GetID() != *filter.ID {
 				return false
 			}
 		}
+		if ids != nil && !ids[c.GetID()] {
+			return false
+		}
 		if filter.Name != nil {
 			if c.GetName() != *filter.Name {
 				return false
@@ -199,6 +210,11 @@ func filterChecksFn(filter influxdb.CheckFilter) func(c influxdb.Check) bool {
 				return false
 			}
 		}
+		if filter.Type != nil {
+			if c.Type() != *filter.Type {
+				return false
+			}
+		}
 		return true
 	}
 }
@@ -324,6 +340,10 @@ func (s *Service) createCheck(ctx context.Context, tx Tx, c influxdb.Check, user
 	c.SetCreatedAt(now)
 	c.SetUpdatedAt(now)
 
+	if err := c.Valid(); err != nil {
+		return err
+	}
+
 	t, err := s.createCheckTask(ctx, tx, c)
 	if err != nil {
 		return err
@@ -341,6 +361,60 @@ func (s *Service) createCheck(ctx context.Context, tx Tx, c influxdb.Check, user
 	return nil
 }
 
+// CloneCheck creates an inactive copy of the check with a new ID and name.
+func (s *Service) CloneCheck(ctx context.Context, id influxdb.ID, newName string) (influxdb.Check, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var c influxdb.Check
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		chk, err := s.cloneCheck(ctx, tx, id, newName)
+		if err != nil {
+			return err
+		}
+		c = chk
+		return nil
+	})
+
+	return c, err
+}
+
+func (s *Service) cloneCheck(ctx context.Context, tx Tx, id influxdb.ID, newName string) (influxdb.Check, error) {
+	current, err := s.findCheckByID(ctx, tx, id)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpCloneCheck,
+			Err: err,
+		}
+	}
+
+	c, err := check.Copy(current)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpCloneCheck,
+			Err: err,
+		}
+	}
+
+	if newName == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   influxdb.OpCloneCheck,
+			Msg:  "Check Name can't be empty",
+		}
+	}
+
+	c.SetID(0)
+	c.SetTaskID(0)
+	c.SetName(newName)
+	c.SetStatus(influxdb.Inactive)
+
+	if err := s.createCheck(ctx, tx, c, current.GetOwnerID()); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
 func (s *Service) createCheckTask(ctx context.Context, tx Tx, c influxdb.Check) (*influxdb.Task, error) {
 	script, err := c.GenerateFlux()
 	if err != nil {
@@ -352,6 +426,7 @@ func (s *Service) createCheckTask(ctx context.Context, tx Tx, c influxdb.Check)
 		Flux:           script,
 		OwnerID:        c.GetOwnerID(),
 		OrganizationID: c.GetOrgID(),
+		Status:         string(c.GetStatus()),
 	}
 
 	t, err := s.createTask(ctx, tx, tc)
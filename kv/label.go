@@ -190,6 +190,26 @@ func (s *Service) FindResourceLabels(ctx context.Context, filter influxdb.LabelM
 	return ls, nil
 }
 
+// FindResourceLabelsForResources returns the labels of each of the resources in a single transaction.
+func (s *Service) FindResourceLabelsForResources(ctx context.Context, rt influxdb.ResourceType, ids []influxdb.ID) (map[influxdb.ID][]*influxdb.Label, error) {
+	m := make(map[influxdb.ID][]*influxdb.Label, len(ids))
+	if err := s.kv.View(ctx, func(tx Tx) error {
+		for _, id := range ids {
+			ls := []*influxdb.Label{}
+			filter := influxdb.LabelMappingFilter{ResourceID: id, ResourceType: rt}
+			if err := s.findResourceLabels(ctx, tx, filter, &ls); err != nil {
+				return err
+			}
+			m[id] = ls
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
 // CreateLabelMapping creates a new mapping between a resource and a label.
 func (s *Service) CreateLabelMapping(ctx context.Context, m *influxdb.LabelMapping) error {
 	return s.kv.Update(ctx, func(tx Tx) error {
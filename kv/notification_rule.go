@@ -281,7 +281,7 @@ func (s *Service) findNotificationRuleByID(ctx context.Context, tx Tx, id influx
 // Additional options provide pagination & sorting.
 func (s *Service) FindNotificationRules(ctx context.Context, filter influxdb.NotificationRuleFilter, opt ...influxdb.FindOptions) (nrs []influxdb.NotificationRule, n int, err error) {
 	err = s.kv.View(ctx, func(tx Tx) error {
-		nrs, n, err = s.findNotificationRules(ctx, tx, filter)
+		nrs, n, err = s.findNotificationRules(ctx, tx, filter, opt...)
 		return err
 	})
 	return nrs, n, err
@@ -384,16 +384,17 @@ func (s *Service) forEachNotificationRule(ctx context.Context, tx Tx, descending
 func filterNotificationRulesFn(
 	idMap map[influxdb.ID]bool,
 	filter influxdb.NotificationRuleFilter) func(nr influxdb.NotificationRule) bool {
-	if filter.OrgID != nil {
-		return func(nr influxdb.NotificationRule) bool {
-			_, ok := idMap[nr.GetID()]
-			return nr.GetOrgID() == *filter.OrgID && ok
-		}
-	}
-
 	return func(nr influxdb.NotificationRule) bool {
-		_, ok := idMap[nr.GetID()]
-		return ok
+		if _, ok := idMap[nr.GetID()]; !ok {
+			return false
+		}
+		if filter.OrgID != nil && nr.GetOrgID() != *filter.OrgID {
+			return false
+		}
+		if filter.Name != nil && nr.GetName() != *filter.Name {
+			return false
+		}
+		return true
 	}
 }
 
@@ -53,6 +53,9 @@ func NewService(kv Store, configs ...ServiceConfig) *Service {
 // ServiceConfig allows us to configure Services
 type ServiceConfig struct {
 	SessionLength time.Duration
+	// MaxLogsPerRun is the number of log entries kept for each task run, the
+	// oldest entries being dropped beyond it. Zero keeps every entry.
+	MaxLogsPerRun int
 }
 
 // Initialize creates Buckets needed.
@@ -1,8 +1,10 @@
 package kv
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"sort"
 	"strings"
 	"time"
 
@@ -66,6 +68,34 @@ func (s *Service) FindTaskByID(ctx context.Context, id influxdb.ID) (*influxdb.T
 	return t, nil
 }
 
+// storedTask is a task as persisted in the task bucket. The authorization a
+// task was explicitly created with is stored next to the task rather than in
+// the task's public JSON.
+type storedTask struct {
+	*influxdb.Task
+	TokenAuthorizationID influxdb.ID `json:"tokenAuthorizationID,omitempty"`
+}
+
+// decodeTask decodes a persisted task. Its AuthorizationID is only set when
+// it was created with an explicit token.
+func decodeTask(v []byte) (*influxdb.Task, error) {
+	st := storedTask{Task: &influxdb.Task{}}
+	if err := json.Unmarshal(v, &st); err != nil {
+		return nil, influxdb.ErrInternalTaskServiceError(err)
+	}
+	st.Task.AuthorizationID = st.TokenAuthorizationID
+	return st.Task, nil
+}
+
+// encodeTask encodes a task to be persisted.
+func encodeTask(t *influxdb.Task) ([]byte, error) {
+	b, err := json.Marshal(storedTask{Task: t, TokenAuthorizationID: t.AuthorizationID})
+	if err != nil {
+		return nil, influxdb.ErrInternalTaskServiceError(err)
+	}
+	return b, nil
+}
+
 // findTaskByIDWithAuth is a task lookup that populates the auth
 // This is to be used when we want to satisfy the FindTaskByID method
 // But is more taxing on the system then if we want to find the task alone.
@@ -75,6 +105,24 @@ func (s *Service) findTaskByIDWithAuth(ctx context.Context, tx Tx, id influxdb.I
 		return nil, err
 	}
 
+	// AuthorizationID is only set for tasks created with an explicit token;
+	// every other task runs with the permissions of its owner.
+	if t.AuthorizationID.Valid() {
+		auth, err := s.findAuthorizationByID(ctx, tx, t.AuthorizationID)
+		if influxdb.ErrorCode(err) == influxdb.ENotFound {
+			// the token was deleted, so the task has nothing to run with.
+			auth = &influxdb.Authorization{
+				Status: influxdb.Inactive,
+				ID:     t.AuthorizationID,
+				OrgID:  t.OrganizationID,
+			}
+		} else if err != nil {
+			return nil, err
+		}
+		t.Authorization = auth
+		return t, nil
+	}
+
 	t.Authorization = &influxdb.Authorization{
 		Status: influxdb.Active,
 		ID:     influxdb.ID(1),
@@ -113,9 +161,9 @@ func (s *Service) findTaskByID(ctx context.Context, tx Tx, id influxdb.ID) (*inf
 	if err != nil {
 		return nil, err
 	}
-	t := &influxdb.Task{}
-	if err := json.Unmarshal(v, t); err != nil {
-		return nil, influxdb.ErrInternalTaskServiceError(err)
+	t, err := decodeTask(v)
+	if err != nil {
+		return nil, err
 	}
 	latestCompletedRun, err := s.findLatestCompleted(ctx, tx, t.ID)
 	if err != nil {
@@ -177,6 +225,34 @@ func (s *Service) FindTasks(ctx context.Context, filter influxdb.TaskFilter) ([]
 	return ts, len(ts), nil
 }
 
+// CountTasks returns the number of tasks that match a filter, regardless of its
+// limit and paging.
+func (s *Service) CountTasks(ctx context.Context, filter influxdb.TaskFilter) (int, error) {
+	filter.After, filter.Before = nil, nil
+	filter.Limit = influxdb.TaskMaxPageSize
+
+	var n int
+	err := s.kv.View(ctx, func(tx Tx) error {
+		for {
+			ts, _, err := s.findTasks(ctx, tx, filter)
+			if err != nil {
+				return err
+			}
+			n += len(ts)
+
+			if len(ts) < filter.Limit {
+				return nil
+			}
+			filter.After = &ts[len(ts)-1].ID
+		}
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
 func (s *Service) findTasks(ctx context.Context, tx Tx, filter influxdb.TaskFilter) ([]*influxdb.Task, int, error) {
 
 	var org *influxdb.Organization
@@ -204,6 +280,10 @@ func (s *Service) findTasks(ctx context.Context, tx Tx, filter influxdb.TaskFilt
 		filter.Limit = influxdb.TaskDefaultPageSize
 	}
 
+	if filter.After != nil && filter.Before != nil {
+		return nil, 0, influxdb.ErrAfterAndBeforeTaskFilter
+	}
+
 	// if no user or organization is passed, assume contexts auth is the user we are looking for.
 	// it is possible for a  internal system to call this with no auth so we shouldnt fail if no auth is found.
 	if org == nil && filter.User == nil {
@@ -214,14 +294,20 @@ func (s *Service) findTasks(ctx context.Context, tx Tx, filter influxdb.TaskFilt
 		}
 	}
 
+	var ts []*influxdb.Task
 	// filter by user id.
 	if filter.User != nil {
-		return s.findTasksByUser(ctx, tx, filter)
+		ts, _, err = s.findTasksByUser(ctx, tx, filter)
 	} else if org != nil {
-		return s.findTasksByOrg(ctx, tx, filter)
+		ts, _, err = s.findTasksByOrg(ctx, tx, filter)
+	} else {
+		ts, _, err = s.findAllTasks(ctx, tx, filter)
+	}
+	if err != nil {
+		return nil, 0, err
 	}
 
-	return s.findAllTasks(ctx, tx, filter)
+	return ts, len(ts), nil
 }
 
 // findTasksByUser is a subset of the find tasks function. Used for cleanliness
@@ -279,15 +365,33 @@ func (s *Service) findTasksByUser(ctx context.Context, tx Tx, filter influxdb.Ta
 			continue
 		}
 
-		ts = append(ts, task)
+		match, err := s.filterTask(ctx, tx, task, filter)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !match {
+			continue
+		}
 
-		if len(ts) >= filter.Limit {
-			break
+		if filter.Before != nil && task.ID >= *filter.Before {
+			continue
+		}
+		if filter.After != nil && task.ID <= *filter.After {
+			continue
 		}
+
+		ts = append(ts, task)
 	}
 
-	if filter.Name != nil {
-		ts = filterByName(ts, *filter.Name)
+	// keep every matching task and trim to the limit, since the mappings are not
+	// ordered by task ID.
+	sort.Slice(ts, func(i, j int) bool { return ts[i].ID < ts[j].ID })
+	if len(ts) > filter.Limit {
+		if filter.Before != nil {
+			ts = ts[len(ts)-filter.Limit:]
+		} else {
+			ts = ts[:filter.Limit]
+		}
 	}
 
 	return ts, len(ts), nil
@@ -324,6 +428,61 @@ func (s *Service) findTasksByOrg(ctx context.Context, tx Tx, filter influxdb.Tas
 	if err != nil {
 		return nil, 0, influxdb.ErrUnexpectedTaskBucketErr(err)
 	}
+
+	if filter.Before != nil {
+		key, err := taskOrgKey(org.ID, *filter.Before)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		// walk backwards from the task before the "before" key until we leave the org
+		for k, v := seekBefore(c, key); k != nil; k, v = c.Prev() {
+			id, err := influxdb.IDFromString(string(v))
+			if err != nil {
+				return nil, 0, influxdb.ErrInvalidTaskID
+			}
+
+			t, err := s.findTaskByIDWithAuth(ctx, tx, *id)
+			if err != nil {
+				if err == influxdb.ErrTaskNotFound {
+					// we might have some crufty index's
+					continue
+				}
+				return nil, 0, err
+			}
+
+			// If the new task doesn't belong to the org we have looped outside the org filter
+			if t.OrganizationID != org.ID {
+				break
+			}
+
+			if filter.Type == nil {
+				ft := ""
+				filter.Type = &ft
+			}
+			if *filter.Type != influxdb.TaskTypeWildcard && *filter.Type != t.Type {
+				continue
+			}
+
+			match, err := s.filterTask(ctx, tx, t, filter)
+			if err != nil {
+				return nil, 0, err
+			}
+			if !match {
+				continue
+			}
+
+			ts = append(ts, t)
+
+			if len(ts) >= filter.Limit {
+				break
+			}
+		}
+		reverseTasks(ts)
+
+		return ts, len(ts), nil
+	}
+
 	// we can filter by orgID
 	if filter.After != nil {
 		key, err := taskOrgKey(org.ID, *filter.After)
@@ -359,8 +518,13 @@ func (s *Service) findTasksByOrg(ctx context.Context, tx Tx, filter influxdb.Tas
 					typ = *filter.Type
 				}
 
+				match, err := s.filterTask(ctx, tx, t, filter)
+				if err != nil {
+					return nil, 0, err
+				}
+
 				// if the filter type matches task type or filter type is a wildcard
-				if typ == t.Type || typ == influxdb.TaskTypeWildcard {
+				if match && (typ == t.Type || typ == influxdb.TaskTypeWildcard) {
 					ts = append(ts, t)
 				}
 			}
@@ -405,6 +569,14 @@ func (s *Service) findTasksByOrg(ctx context.Context, tx Tx, filter influxdb.Tas
 			continue
 		}
 
+		match, err := s.filterTask(ctx, tx, t, filter)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !match {
+			continue
+		}
+
 		// insert the new task into the list
 		ts = append(ts, t)
 
@@ -414,10 +586,6 @@ func (s *Service) findTasksByOrg(ctx context.Context, tx Tx, filter influxdb.Tas
 		}
 	}
 
-	if filter.Name != nil {
-		ts = filterByName(ts, *filter.Name)
-	}
-
 	return ts, len(ts), err
 }
 
@@ -436,6 +604,47 @@ func (s *Service) findAllTasks(ctx context.Context, tx Tx, filter influxdb.TaskF
 	if err != nil {
 		return nil, 0, influxdb.ErrUnexpectedTaskBucketErr(err)
 	}
+
+	if filter.Before != nil {
+		key, err := taskKey(*filter.Before)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		for k, v := seekBefore(c, key); k != nil; k, v = c.Prev() {
+			t, err := decodeTask(v)
+			if err != nil {
+				return nil, 0, err
+			}
+			latestCompleted, err := s.findLatestScheduledTime(ctx, tx, t.ID)
+			if err != nil {
+				return nil, 0, err
+			}
+			if !latestCompleted.IsZero() {
+				t.LatestCompleted = latestCompleted.Format(time.RFC3339)
+			} else {
+				t.LatestCompleted = t.CreatedAt
+			}
+
+			match, err := s.filterTask(ctx, tx, t, filter)
+			if err != nil {
+				return nil, 0, err
+			}
+			if !match {
+				continue
+			}
+
+			ts = append(ts, t)
+
+			if len(ts) >= filter.Limit {
+				break
+			}
+		}
+		reverseTasks(ts)
+
+		return ts, len(ts), nil
+	}
+
 	// we can filter by orgID
 	if filter.After != nil {
 
@@ -452,9 +661,9 @@ func (s *Service) findAllTasks(ctx context.Context, tx Tx, filter influxdb.TaskF
 			return ts, len(ts), nil
 		}
 
-		t := &influxdb.Task{}
-		if err := json.Unmarshal(v, t); err != nil {
-			return nil, 0, influxdb.ErrInternalTaskServiceError(err)
+		t, err := decodeTask(v)
+		if err != nil {
+			return nil, 0, err
 		}
 		latestCompleted, err := s.findLatestScheduledTime(ctx, tx, t.ID)
 		if err != nil {
@@ -465,8 +674,15 @@ func (s *Service) findAllTasks(ctx context.Context, tx Tx, filter influxdb.TaskF
 		} else {
 			t.LatestCompleted = t.CreatedAt
 		}
-		// insert the new task into the list
-		ts = append(ts, t)
+
+		match, err := s.filterTask(ctx, tx, t, filter)
+		if err != nil {
+			return nil, 0, err
+		}
+		if match {
+			// insert the new task into the list
+			ts = append(ts, t)
+		}
 	}
 
 	// if someone has a limit of 1
@@ -479,9 +695,9 @@ func (s *Service) findAllTasks(ctx context.Context, tx Tx, filter influxdb.TaskF
 		if k == nil {
 			break
 		}
-		t := &influxdb.Task{}
-		if err := json.Unmarshal(v, t); err != nil {
-			return nil, 0, influxdb.ErrInternalTaskServiceError(err)
+		t, err := decodeTask(v)
+		if err != nil {
+			return nil, 0, err
 		}
 		latestCompleted, err := s.findLatestScheduledTime(ctx, tx, t.ID)
 		if err != nil {
@@ -492,6 +708,15 @@ func (s *Service) findAllTasks(ctx context.Context, tx Tx, filter influxdb.TaskF
 		} else {
 			t.LatestCompleted = t.CreatedAt
 		}
+
+		match, err := s.filterTask(ctx, tx, t, filter)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !match {
+			continue
+		}
+
 		// insert the new task into the list
 		ts = append(ts, t)
 
@@ -501,23 +726,61 @@ func (s *Service) findAllTasks(ctx context.Context, tx Tx, filter influxdb.TaskF
 		}
 	}
 
-	if filter.Name != nil {
-		ts = filterByName(ts, *filter.Name)
+	return ts, len(ts), err
+}
+
+// seekBefore moves the cursor to the last key that sorts strictly before key.
+func seekBefore(c Cursor, key []byte) ([]byte, []byte) {
+	k, v := c.Seek(key)
+	if k == nil {
+		k, v = c.Last()
+	}
+	for k != nil && bytes.Compare(k, key) >= 0 {
+		k, v = c.Prev()
 	}
+	return k, v
+}
 
-	return ts, len(ts), err
+// reverseTasks reverses ts in place, turning a backwards cursor walk into ascending ID order.
+func reverseTasks(ts []*influxdb.Task) {
+	for i, j := 0, len(ts)-1; i < j; i, j = i+1, j-1 {
+		ts[i], ts[j] = ts[j], ts[i]
+	}
 }
 
-func filterByName(ts []*influxdb.Task, taskName string) []*influxdb.Task {
-	filtered := []*influxdb.Task{}
+// filterTask reports whether a task found while walking the tasks matches the
+// filter. It is applied before the page limit, so that a page is filled with
+// matching tasks.
+func (s *Service) filterTask(ctx context.Context, tx Tx, t *influxdb.Task, filter influxdb.TaskFilter) (bool, error) {
+	if filter.Name != nil && t.Name != *filter.Name {
+		return false, nil
+	}
+
+	if filter.OwnerID != nil && t.OwnerID != *filter.OwnerID {
+		return false, nil
+	}
 
-	for _, task := range ts {
-		if task.Name == taskName {
-			filtered = append(filtered, task)
+	if filter.LabelID != nil {
+		idx, err := tx.Bucket(labelMappingBucket)
+		if err != nil {
+			return false, err
+		}
+
+		key, err := labelMappingKey(&influxdb.LabelMapping{LabelID: *filter.LabelID, ResourceID: t.ID})
+		if err != nil {
+			return false, err
+		}
+
+		_, err = idx.Get(key)
+		if IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
 		}
 	}
 
-	return filtered
+	return true, nil
 }
 
 // CreateTask creates a new task.
@@ -557,6 +820,15 @@ func (s *Service) createTask(ctx context.Context, tx Tx, tc influxdb.TaskCreate)
 		return nil, influxdb.ErrOrgNotFound
 	}
 
+	// the task runs with the given token's authorization, which must be in the task's org.
+	var auth *influxdb.Authorization
+	if tc.Token != "" {
+		auth, err = s.findAuthorizationByToken(ctx, tx, tc.Token)
+		if err != nil || auth.OrgID != org.ID {
+			return nil, influxdb.ErrInvalidTaskToken
+		}
+	}
+
 	// TODO: Uncomment this once the checks/notifications no longer create tasks in kv
 	// confirm the owner is a real user.
 	// if _, err = s.findUserByID(ctx, tx, tc.OwnerID); err != nil {
@@ -591,6 +863,13 @@ func (s *Service) createTask(ctx context.Context, tx Tx, tc influxdb.TaskCreate)
 	if opt.Offset != nil {
 		task.Offset = opt.Offset.String()
 	}
+	task.EffectiveConcurrency = effectiveConcurrency(opt)
+	if task.Status == string(backend.TaskInactive) {
+		task.InactiveSince = createdAt
+	}
+	if auth != nil {
+		task.AuthorizationID = auth.ID
+	}
 
 	taskBucket, err := tx.Bucket(taskBucket)
 	if err != nil {
@@ -602,9 +881,9 @@ func (s *Service) createTask(ctx context.Context, tx Tx, tc influxdb.TaskCreate)
 		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
 	}
 
-	taskBytes, err := json.Marshal(task)
+	taskBytes, err := encodeTask(task)
 	if err != nil {
-		return nil, influxdb.ErrInternalTaskServiceError(err)
+		return nil, err
 	}
 
 	taskKey, err := taskKey(task.ID)
@@ -633,6 +912,11 @@ func (s *Service) createTask(ctx context.Context, tx Tx, tc influxdb.TaskCreate)
 		s.Logger.Info("error creating user resource mapping for task", zap.Stringer("taskID", task.ID), zap.Error(err))
 	}
 
+	if auth != nil {
+		task.Authorization = auth
+		return task, nil
+	}
+
 	// populate permissions so the task can be used immediately
 	// if we cant populate here we shouldn't error.
 	ps, _ := s.maxPermissions(ctx, tx, task.OwnerID)
@@ -677,6 +961,15 @@ func (s *Service) UpdateTask(ctx context.Context, id influxdb.ID, upd influxdb.T
 	return t, nil
 }
 
+// effectiveConcurrency returns the number of runs of a task with the options opt
+// that may execute at once.
+func effectiveConcurrency(opt options.Options) int64 {
+	if opt.Concurrency == nil {
+		return options.DefaultConcurrency
+	}
+	return *opt.Concurrency
+}
+
 func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd influxdb.TaskUpdate) (*influxdb.Task, error) {
 	// retrieve the task
 	task, err := s.findTaskByID(ctx, tx, id)
@@ -701,6 +994,14 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 		if options.Offset != nil {
 			task.Offset = options.Offset.String()
 		}
+		task.EffectiveConcurrency = effectiveConcurrency(options)
+	} else if task.EffectiveConcurrency == 0 {
+		// The task was stored before its effective concurrency was recorded.
+		options, err := options.FromScript(task.Flux)
+		if err != nil {
+			return nil, influxdb.ErrTaskOptionParse(err)
+		}
+		task.EffectiveConcurrency = effectiveConcurrency(options)
 	}
 
 	if upd.Description != nil {
@@ -708,6 +1009,14 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 	}
 
 	if upd.Status != nil {
+		if *upd.Status != task.Status {
+			// keep track of when the task was paused
+			if *upd.Status == string(backend.TaskInactive) {
+				task.InactiveSince = time.Now().UTC().Format(time.RFC3339)
+			} else {
+				task.InactiveSince = ""
+			}
+		}
 		task.Status = *upd.Status
 	}
 
@@ -732,9 +1041,9 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 		return nil, err
 	}
 
-	taskBytes, err := json.Marshal(task)
+	taskBytes, err := encodeTask(task)
 	if err != nil {
-		return nil, influxdb.ErrInternalTaskServiceError(err)
+		return nil, err
 	}
 
 	return task, bucket.Put(key, taskBytes)
@@ -857,9 +1166,12 @@ func (s *Service) findLogs(ctx context.Context, tx Tx, filter influxdb.LogFilter
 		if err != nil {
 			return nil, 0, err
 		}
-		rtn := make([]*influxdb.Log, len(r.Log))
+		rtn := make([]*influxdb.Log, 0, len(r.Log))
 		for i := 0; i < len(r.Log); i++ {
-			rtn[i] = &r.Log[i]
+			r.Log[i].Level = r.Log[i].GetLevel()
+			if filter.Matches(r.Log[i]) {
+				rtn = append(rtn, &r.Log[i])
+			}
 		}
 		return rtn, len(rtn), nil
 	}
@@ -871,8 +1183,10 @@ func (s *Service) findLogs(ctx context.Context, tx Tx, filter influxdb.LogFilter
 	var logs []*influxdb.Log
 	for _, run := range runs {
 		for i := 0; i < len(run.Log); i++ {
-			logs = append(logs, &run.Log[i])
-
+			run.Log[i].Level = run.Log[i].GetLevel()
+			if filter.Matches(run.Log[i]) {
+				logs = append(logs, &run.Log[i])
+			}
 		}
 	}
 	return logs, len(logs), nil
@@ -898,7 +1212,7 @@ func (s *Service) FindRuns(ctx context.Context, filter influxdb.RunFilter) ([]*i
 
 func (s *Service) findRuns(ctx context.Context, tx Tx, filter influxdb.RunFilter) ([]*influxdb.Run, int, error) {
 	if filter.Limit == 0 {
-		filter.Limit = influxdb.TaskDefaultPageSize
+		filter.Limit = influxdb.RunDefaultPageSize
 	}
 
 	if filter.Limit < 0 || filter.Limit > influxdb.TaskMaxPageSize {
@@ -1000,7 +1314,7 @@ func (s *Service) cancelRun(ctx context.Context, tx Tx, taskID, runID influxdb.I
 	run.Status = "canceled"
 
 	// save
-	bucket, err := tx.Bucket(taskBucket)
+	bucket, err := tx.Bucket(taskRunBucket)
 	if err != nil {
 		return influxdb.ErrUnexpectedTaskBucketErr(err)
 	}
@@ -1022,11 +1336,49 @@ func (s *Service) cancelRun(ctx context.Context, tx Tx, taskID, runID influxdb.I
 	return nil
 }
 
+// CancelTaskRuns cancels every currently running run of a task.
+func (s *Service) CancelTaskRuns(ctx context.Context, taskID influxdb.ID) ([]influxdb.ID, error) {
+	var ids []influxdb.ID
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		canceled, err := s.cancelTaskRuns(ctx, tx, taskID)
+		if err != nil {
+			return err
+		}
+		ids = canceled
+		return nil
+	})
+	return ids, err
+}
+
+func (s *Service) cancelTaskRuns(ctx context.Context, tx Tx, taskID influxdb.ID) ([]influxdb.ID, error) {
+	if _, err := s.findTaskByID(ctx, tx, taskID); err != nil {
+		return nil, err
+	}
+
+	runs, err := s.currentlyRunning(ctx, tx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []influxdb.ID{}
+	for _, run := range runs {
+		if run.Status != backend.RunStarted.String() {
+			continue
+		}
+		if err := s.cancelRun(ctx, tx, taskID, run.ID); err != nil {
+			return nil, err
+		}
+		ids = append(ids, run.ID)
+	}
+	return ids, nil
+}
+
 // RetryRun creates and returns a new run (which is a retry of another run).
-func (s *Service) RetryRun(ctx context.Context, taskID, runID influxdb.ID) (*influxdb.Run, error) {
+// The new run is scheduled for the unix timestamp scheduledFor if it is set.
+func (s *Service) RetryRun(ctx context.Context, taskID, runID influxdb.ID, scheduledFor *int64) (*influxdb.Run, error) {
 	var r *influxdb.Run
 	err := s.kv.Update(ctx, func(tx Tx) error {
-		run, err := s.retryRun(ctx, tx, taskID, runID)
+		run, err := s.retryRun(ctx, tx, taskID, runID, scheduledFor)
 		if err != nil {
 			return err
 		}
@@ -1036,13 +1388,16 @@ func (s *Service) RetryRun(ctx context.Context, taskID, runID influxdb.ID) (*inf
 	return r, err
 }
 
-func (s *Service) retryRun(ctx context.Context, tx Tx, taskID, runID influxdb.ID) (*influxdb.Run, error) {
+func (s *Service) retryRun(ctx context.Context, tx Tx, taskID, runID influxdb.ID, scheduledFor *int64) (*influxdb.Run, error) {
 	// find the run
 	r, err := s.findRunByID(ctx, tx, taskID, runID)
 	if err != nil {
 		return nil, err
 	}
 
+	if scheduledFor != nil {
+		r.ScheduledFor = time.Unix(*scheduledFor, 0).UTC().Format(time.RFC3339)
+	}
 	r.ID = s.IDGenerator.ID()
 	r.Status = backend.RunScheduled.String()
 	r.StartedAt = ""
@@ -1094,9 +1449,14 @@ func (s *Service) retryRun(ctx context.Context, tx Tx, taskID, runID influxdb.ID
 // ForceRun forces a run to occur with unix timestamp scheduledFor, to be executed as soon as possible.
 // The value of scheduledFor may or may not align with the task's schedule.
 func (s *Service) ForceRun(ctx context.Context, taskID influxdb.ID, scheduledFor int64) (*influxdb.Run, error) {
+	return s.ForceRunWithNote(ctx, taskID, scheduledFor, "")
+}
+
+// ForceRunWithNote is ForceRun with a note that is stored on the created run.
+func (s *Service) ForceRunWithNote(ctx context.Context, taskID influxdb.ID, scheduledFor int64, note string) (*influxdb.Run, error) {
 	var r *influxdb.Run
 	err := s.kv.Update(ctx, func(tx Tx) error {
-		run, err := s.forceRun(ctx, tx, taskID, scheduledFor)
+		run, err := s.forceRun(ctx, tx, taskID, scheduledFor, note)
 		if err != nil {
 			return err
 		}
@@ -1106,7 +1466,7 @@ func (s *Service) ForceRun(ctx context.Context, taskID influxdb.ID, scheduledFor
 	return r, err
 }
 
-func (s *Service) forceRun(ctx context.Context, tx Tx, taskID influxdb.ID, scheduledFor int64) (*influxdb.Run, error) {
+func (s *Service) forceRun(ctx context.Context, tx Tx, taskID influxdb.ID, scheduledFor int64, note string) (*influxdb.Run, error) {
 	// create a run
 	t := time.Unix(scheduledFor, 0).UTC()
 	r := &influxdb.Run{
@@ -1116,6 +1476,7 @@ func (s *Service) forceRun(ctx context.Context, tx Tx, taskID influxdb.ID, sched
 		RequestedAt:  time.Now().UTC().Format(time.RFC3339),
 		ScheduledFor: t.Format(time.RFC3339),
 		Log:          []influxdb.Log{},
+		Note:         note,
 	}
 
 	// add a clean copy of the run to the manual runs
@@ -1132,7 +1493,7 @@ func (s *Service) forceRun(ctx context.Context, tx Tx, taskID influxdb.ID, sched
 	// check to see if this run is already queued
 	for _, run := range runs {
 		if run.ScheduledFor == r.ScheduledFor {
-			return nil, influxdb.ErrTaskRunAlreadyQueued
+			return nil, influxdb.RunAlreadyQueuedError{ScheduledFor: t.Unix()}
 		}
 	}
 	runs = append(runs, r)
@@ -1667,6 +2028,9 @@ func (s *Service) updateRunState(ctx context.Context, tx Tx, taskID, runID influ
 	case backend.RunSuccess, backend.RunFail, backend.RunCanceled:
 		run.FinishedAt = when.UTC().Format(time.RFC3339Nano)
 	}
+	if state == backend.RunFail {
+		run.ErrorMessage = influxdb.RunErrorMessage(run.Log)
+	}
 
 	// save run
 	b, err := tx.Bucket(taskRunBucket)
@@ -1692,8 +2056,30 @@ func (s *Service) updateRunState(ctx context.Context, tx Tx, taskID, runID influ
 
 // AddRunLog adds a log line to the run.
 func (s *Service) AddRunLog(ctx context.Context, taskID, runID influxdb.ID, when time.Time, log string) error {
+	return s.AddRunLogLevel(ctx, taskID, runID, when, influxdb.LogLevelInfo, log)
+}
+
+// AddRunLogLevel adds a log line to the run at the given level.
+func (s *Service) AddRunLogLevel(ctx context.Context, taskID, runID influxdb.ID, when time.Time, level influxdb.LogLevel, log string) error {
+	return s.AddRunLogs(ctx, taskID, runID, []influxdb.Log{
+		{Time: when.Format(time.RFC3339Nano), Level: level, Message: log},
+	})
+}
+
+// AddRunLogs adds the log lines to the run in order, in a single transaction.
+// Lines without a level are added at the info level.
+func (s *Service) AddRunLogs(ctx context.Context, taskID, runID influxdb.ID, logs []influxdb.Log) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	for _, l := range logs {
+		if err := l.GetLevel().Valid(); err != nil {
+			return err
+		}
+	}
+
 	err := s.kv.Update(ctx, func(tx Tx) error {
-		err := s.addRunLog(ctx, tx, taskID, runID, when, log)
+		err := s.addRunLogs(ctx, tx, taskID, runID, logs)
 		if err != nil {
 			return err
 		}
@@ -1702,15 +2088,20 @@ func (s *Service) AddRunLog(ctx context.Context, taskID, runID influxdb.ID, when
 	return err
 }
 
-func (s *Service) addRunLog(ctx context.Context, tx Tx, taskID, runID influxdb.ID, when time.Time, log string) error {
+func (s *Service) addRunLogs(ctx context.Context, tx Tx, taskID, runID influxdb.ID, logs []influxdb.Log) error {
 	// find run
 	run, err := s.findRunByID(ctx, tx, taskID, runID)
 	if err != nil {
 		return err
 	}
 	// update log
-	l := influxdb.Log{RunID: runID, Time: when.Format(time.RFC3339Nano), Message: log}
-	run.Log = append(run.Log, l)
+	for _, l := range logs {
+		run.Log = append(run.Log, influxdb.Log{RunID: runID, Time: l.Time, Level: l.GetLevel(), Message: l.Message})
+	}
+	if max := s.Config.MaxLogsPerRun; max > 0 && len(run.Log) > max {
+		// drop the oldest entries beyond the cap
+		run.Log = run.Log[len(run.Log)-max:]
+	}
 	// save run
 	b, err := tx.Bucket(taskRunBucket)
 	if err != nil {
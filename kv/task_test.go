@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
@@ -248,3 +250,616 @@ func TestRetrieveTaskWithBadAuth(t *testing.T) {
 		t.Fatal("failed to return task")
 	}
 }
+
+func TestUpdateTaskEffectiveConcurrency(t *testing.T) {
+	store, close, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close()
+
+	service := kv.NewService(store)
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	if err := service.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing urm service: %v", err)
+	}
+	defer cancelFunc()
+	u := &influxdb.User{Name: t.Name() + "-user"}
+	if err := service.CreateUser(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+	o := &influxdb.Organization{Name: t.Name() + "-org"}
+	if err := service.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+	ctx = icontext.SetAuthorizer(ctx, &influxdb.Authorization{OrgID: o.ID, UserID: u.ID, Permissions: influxdb.OperPermissions()})
+
+	task, err := service.CreateTask(ctx, influxdb.TaskCreate{
+		Flux:           `option task = {name: "a task", every: 1h, concurrency: 4} from(bucket:"test") |> range(start:-1h)`,
+		OrganizationID: o.ID,
+		OwnerID:        u.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// convert task to one stored before its effective concurrency was recorded
+	err = store.Update(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket([]byte("tasksv1"))
+		if err != nil {
+			return err
+		}
+		bID, err := task.ID.Encode()
+		if err != nil {
+			return err
+		}
+		old := *task
+		old.EffectiveConcurrency = 0
+		tbyte, err := json.Marshal(old)
+		if err != nil {
+			return err
+		}
+		return b.Put(bID, tbyte)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An update that does not change the flux still records the effective concurrency.
+	desc := "updated"
+	updated, err := service.UpdateTask(ctx, task.ID, influxdb.TaskUpdate{Description: &desc})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.EffectiveConcurrency != 4 {
+		t.Fatalf("unexpected effective concurrency: got %d, want %d", updated.EffectiveConcurrency, 4)
+	}
+}
+
+func TestTaskAuthorization(t *testing.T) {
+	store, close, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close()
+
+	service := kv.NewService(store)
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	if err := service.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing urm service: %v", err)
+	}
+	defer cancelFunc()
+	u := &influxdb.User{Name: t.Name() + "-user"}
+	if err := service.CreateUser(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+	o := &influxdb.Organization{Name: t.Name() + "-org"}
+	if err := service.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := service.CreateUserResourceMapping(ctx, &influxdb.UserResourceMapping{
+		ResourceType: influxdb.OrgsResourceType,
+		ResourceID:   o.ID,
+		UserID:       u.ID,
+		UserType:     influxdb.Owner,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	authz := influxdb.Authorization{
+		OrgID:       o.ID,
+		UserID:      u.ID,
+		Permissions: influxdb.OperPermissions(),
+	}
+	if err := service.CreateAuthorization(context.Background(), &authz); err != nil {
+		t.Fatal(err)
+	}
+	tokenAuthz := influxdb.Authorization{
+		OrgID:       o.ID,
+		UserID:      u.ID,
+		Permissions: influxdb.OperPermissions(),
+	}
+	if err := service.CreateAuthorization(context.Background(), &tokenAuthz); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx = icontext.SetAuthorizer(ctx, &authz)
+
+	t.Run("legacy task", func(t *testing.T) {
+		task, err := service.CreateTask(ctx, influxdb.TaskCreate{
+			Flux:           `option task = {name: "a task",every: 1h} from(bucket:"test") |> range(start:-1h)`,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// store the task the way older versions did, with the authorization it was created with
+		err = store.Update(ctx, func(tx kv.Tx) error {
+			b, err := tx.Bucket([]byte("tasksv1"))
+			if err != nil {
+				return err
+			}
+			bID, err := task.ID.Encode()
+			if err != nil {
+				return err
+			}
+			tbyte, err := json.Marshal(task)
+			if err != nil {
+				return err
+			}
+			legacy := map[string]interface{}{}
+			if err := json.Unmarshal(tbyte, &legacy); err != nil {
+				return err
+			}
+			legacy["authorizationID"] = authz.ID.String()
+			if tbyte, err = json.Marshal(legacy); err != nil {
+				return err
+			}
+			return b.Put(bID, tbyte)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		f, err := service.FindTaskByID(ctx, task.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f.Authorization == nil || f.Authorization.ID == authz.ID {
+			t.Fatalf("expected legacy task to run with its owner's permissions, got authorization %+v", f.Authorization)
+		}
+		if len(f.Authorization.Permissions) == 0 {
+			t.Fatal("expected legacy task to have its owner's permissions")
+		}
+	})
+
+	t.Run("task with token", func(t *testing.T) {
+		task, err := service.CreateTask(ctx, influxdb.TaskCreate{
+			Flux:           `option task = {name: "a task",every: 1h} from(bucket:"test") |> range(start:-1h)`,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+			Token:          tokenAuthz.Token,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		f, err := service.FindTaskByID(ctx, task.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f.Authorization == nil || f.Authorization.ID != tokenAuthz.ID {
+			t.Fatalf("expected task to run with authorization %v, got %+v", tokenAuthz.ID, f.Authorization)
+		}
+
+		b, err := json.Marshal(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(b, []byte(tokenAuthz.ID.String())) {
+			t.Fatalf("expected task JSON to omit its authorization, got %s", b)
+		}
+
+		// the task must not fall back to its owner's permissions once the token is gone
+		if err := service.DeleteAuthorization(context.Background(), tokenAuthz.ID); err != nil {
+			t.Fatal(err)
+		}
+		f, err = service.FindTaskByID(ctx, task.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f.Authorization.IsActive() || len(f.Authorization.Permissions) != 0 {
+			t.Fatalf("expected an inactive authorization without permissions, got %+v", f.Authorization)
+		}
+	})
+}
+
+func TestFindTasksByLabel(t *testing.T) {
+	store, close, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close()
+
+	service := kv.NewService(store)
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	if err := service.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing urm service: %v", err)
+	}
+	defer cancelFunc()
+	u := &influxdb.User{Name: t.Name() + "-user"}
+	if err := service.CreateUser(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+	o := &influxdb.Organization{Name: t.Name() + "-org"}
+	if err := service.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+
+	authz := influxdb.Authorization{
+		OrgID:       o.ID,
+		UserID:      u.ID,
+		Permissions: influxdb.OperPermissions(),
+	}
+	if err := service.CreateAuthorization(context.Background(), &authz); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx = icontext.SetAuthorizer(ctx, &authz)
+
+	var tasks []*influxdb.Task
+	for _, name := range []string{"labeled", "unlabeled", "also labeled"} {
+		task, err := service.CreateTask(ctx, influxdb.TaskCreate{
+			Flux:           `option task = {name: "` + name + `", every: 1h} from(bucket:"test") |> range(start:-1h)`,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	l := &influxdb.Label{OrgID: o.ID, Name: "env:prod"}
+	if err := service.CreateLabel(ctx, l); err != nil {
+		t.Fatal(err)
+	}
+	for _, task := range []*influxdb.Task{tasks[0], tasks[2]} {
+		if err := service.CreateLabelMapping(ctx, &influxdb.LabelMapping{
+			LabelID:      l.ID,
+			ResourceID:   task.ID,
+			ResourceType: influxdb.TasksResourceType,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	found, n, err := service.FindTasks(ctx, influxdb.TaskFilter{OrganizationID: &o.ID, LabelID: &l.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 || len(found) != 2 {
+		t.Fatalf("expected 2 labeled tasks, got %d", len(found))
+	}
+	for i, task := range found {
+		if exp := []influxdb.ID{tasks[0].ID, tasks[2].ID}[i]; task.ID != exp {
+			t.Errorf("expected task %d to be %s, got %s", i, exp, task.ID)
+		}
+	}
+}
+
+func TestFindTasksByLabelPaging(t *testing.T) {
+	store, close, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close()
+
+	service := kv.NewService(store)
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	if err := service.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing urm service: %v", err)
+	}
+	defer cancelFunc()
+	u := &influxdb.User{Name: t.Name() + "-user"}
+	if err := service.CreateUser(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+	o := &influxdb.Organization{Name: t.Name() + "-org"}
+	if err := service.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+
+	authz := influxdb.Authorization{
+		OrgID:       o.ID,
+		UserID:      u.ID,
+		Permissions: influxdb.OperPermissions(),
+	}
+	if err := service.CreateAuthorization(context.Background(), &authz); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx = icontext.SetAuthorizer(ctx, &authz)
+
+	var tasks []*influxdb.Task
+	for i := 0; i < 5; i++ {
+		task, err := service.CreateTask(ctx, influxdb.TaskCreate{
+			Flux:           fmt.Sprintf(`option task = {name: "task %d", every: 1h} from(bucket:"test") |> range(start:-1h)`, i),
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	// Only label the last tasks, so that the first page of all tasks has no labeled task.
+	l := &influxdb.Label{OrgID: o.ID, Name: "env:prod"}
+	if err := service.CreateLabel(ctx, l); err != nil {
+		t.Fatal(err)
+	}
+	for _, task := range tasks[3:] {
+		if err := service.CreateLabelMapping(ctx, &influxdb.LabelMapping{
+			LabelID:      l.ID,
+			ResourceID:   task.ID,
+			ResourceType: influxdb.TasksResourceType,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, filter := range []influxdb.TaskFilter{
+		{OrganizationID: &o.ID, LabelID: &l.ID, Limit: 1},
+		{User: &u.ID, LabelID: &l.ID, Limit: 1},
+	} {
+		var found []*influxdb.Task
+		for {
+			page, _, err := service.FindTasks(ctx, filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(page) == 0 {
+				break
+			}
+			found = append(found, page...)
+			filter.After = &page[len(page)-1].ID
+		}
+
+		if len(found) != 2 {
+			t.Fatalf("expected 2 labeled tasks, got %d", len(found))
+		}
+		for i, task := range found {
+			if exp := tasks[3+i].ID; task.ID != exp {
+				t.Errorf("expected task %d to be %s, got %s", i, exp, task.ID)
+			}
+		}
+	}
+}
+
+func TestCountTasks(t *testing.T) {
+	store, close, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close()
+
+	service := kv.NewService(store)
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	if err := service.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing urm service: %v", err)
+	}
+	defer cancelFunc()
+	u := &influxdb.User{Name: t.Name() + "-user"}
+	if err := service.CreateUser(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+	o := &influxdb.Organization{Name: t.Name() + "-org"}
+	if err := service.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+
+	authz := influxdb.Authorization{
+		OrgID:       o.ID,
+		UserID:      u.ID,
+		Permissions: influxdb.OperPermissions(),
+	}
+	if err := service.CreateAuthorization(context.Background(), &authz); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx = icontext.SetAuthorizer(ctx, &authz)
+
+	// More tasks than fit in a page.
+	const total = influxdb.TaskMaxPageSize + 2
+	for i := 0; i < total; i++ {
+		if _, err := service.CreateTask(ctx, influxdb.TaskCreate{
+			Flux:           fmt.Sprintf(`option task = {name: "task %d", every: 1h} from(bucket:"test") |> range(start:-1h)`, i%2),
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	name := "task 1"
+	for _, tt := range []struct {
+		name   string
+		filter influxdb.TaskFilter
+		exp    int
+	}{
+		{name: "org", filter: influxdb.TaskFilter{OrganizationID: &o.ID}, exp: total},
+		{name: "user", filter: influxdb.TaskFilter{User: &u.ID}, exp: total},
+		{name: "authorizer", filter: influxdb.TaskFilter{Limit: 1}, exp: total},
+		{name: "name", filter: influxdb.TaskFilter{OrganizationID: &o.ID, Name: &name}, exp: total / 2},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := service.CountTasks(ctx, tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != tt.exp {
+				t.Fatalf("expected %d tasks, got %d", tt.exp, n)
+			}
+		})
+	}
+}
+
+func TestFindTasksByOwner(t *testing.T) {
+	store, close, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close()
+
+	service := kv.NewService(store)
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	if err := service.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing urm service: %v", err)
+	}
+	defer cancelFunc()
+	u1 := &influxdb.User{Name: t.Name() + "-user1"}
+	if err := service.CreateUser(ctx, u1); err != nil {
+		t.Fatal(err)
+	}
+	u2 := &influxdb.User{Name: t.Name() + "-user2"}
+	if err := service.CreateUser(ctx, u2); err != nil {
+		t.Fatal(err)
+	}
+	o := &influxdb.Organization{Name: t.Name() + "-org"}
+	if err := service.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+
+	authz := influxdb.Authorization{
+		OrgID:       o.ID,
+		UserID:      u1.ID,
+		Permissions: influxdb.OperPermissions(),
+	}
+	if err := service.CreateAuthorization(context.Background(), &authz); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx = icontext.SetAuthorizer(ctx, &authz)
+
+	var tasks []*influxdb.Task
+	for i, owner := range []influxdb.ID{u1.ID, u2.ID, u1.ID} {
+		task, err := service.CreateTask(ctx, influxdb.TaskCreate{
+			Flux:           fmt.Sprintf(`option task = {name: "task %d", every: 1h} from(bucket:"test") |> range(start:-1h)`, i),
+			OrganizationID: o.ID,
+			OwnerID:        owner,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	found, n, err := service.FindTasks(ctx, influxdb.TaskFilter{OrganizationID: &o.ID, OwnerID: &u2.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || len(found) != 1 {
+		t.Fatalf("expected 1 task owned by the second user, got %d", len(found))
+	}
+	if found[0].ID != tasks[1].ID {
+		t.Fatalf("expected task %s, got %s", tasks[1].ID, found[0].ID)
+	}
+
+	// The first task is not owned by the second user, so a page of one task has
+	// to skip it.
+	found, _, err = service.FindTasks(ctx, influxdb.TaskFilter{OrganizationID: &o.ID, OwnerID: &u2.ID, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].ID != tasks[1].ID {
+		t.Fatalf("expected a page with task %s, got %v", tasks[1].ID, found)
+	}
+
+	found, _, err = service.FindTasks(ctx, influxdb.TaskFilter{OrganizationID: &o.ID, OwnerID: &u1.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 2 {
+		t.Fatalf("expected 2 tasks owned by the first user, got %d", len(found))
+	}
+	for i, task := range found {
+		if exp := []influxdb.ID{tasks[0].ID, tasks[2].ID}[i]; task.ID != exp {
+			t.Errorf("expected task %d to be %s, got %s", i, exp, task.ID)
+		}
+	}
+}
+
+func TestAddRunLogMaxLogsPerRun(t *testing.T) {
+	store, close, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close()
+
+	service := kv.NewService(store, kv.ServiceConfig{
+		SessionLength: influxdb.DefaultSessionLength,
+		MaxLogsPerRun: 3,
+	})
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	if err := service.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing urm service: %v", err)
+	}
+	defer cancelFunc()
+	u := &influxdb.User{Name: t.Name() + "-user"}
+	if err := service.CreateUser(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+	o := &influxdb.Organization{Name: t.Name() + "-org"}
+	if err := service.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+
+	authz := influxdb.Authorization{
+		OrgID:       o.ID,
+		UserID:      u.ID,
+		Permissions: influxdb.OperPermissions(),
+	}
+	if err := service.CreateAuthorization(context.Background(), &authz); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx = icontext.SetAuthorizer(ctx, &authz)
+
+	task, err := service.CreateTask(ctx, influxdb.TaskCreate{
+		Flux:           `option task = {name: "a task",every: 1m} from(bucket:"test") |> range(start:-1h)`,
+		OrganizationID: o.ID,
+		OwnerID:        u.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requestedAt := time.Now().Add(5 * time.Minute).UTC().Unix()
+	rc0, err := service.CreateNextRun(ctx, task.ID, requestedAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc1, err := service.CreateNextRun(ctx, task.ID, requestedAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, l := range []struct {
+		runID influxdb.ID
+		msg   string
+	}{
+		{rc0.Created.RunID, "0-0"},
+		{rc0.Created.RunID, "0-1"},
+		{rc0.Created.RunID, "0-2"},
+		{rc1.Created.RunID, "1-0"},
+		{rc1.Created.RunID, "1-1"},
+		{rc1.Created.RunID, "1-2"},
+		{rc1.Created.RunID, "1-3"},
+	} {
+		if err := service.AddRunLog(ctx, task.ID, l.runID, time.Now(), l.msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for runID, exp := range map[influxdb.ID][]string{
+		rc0.Created.RunID: {"0-0", "0-1", "0-2"},
+		rc1.Created.RunID: {"1-1", "1-2", "1-3"},
+	} {
+		runID := runID
+		logs, _, err := service.FindLogs(ctx, influxdb.LogFilter{Task: task.ID, Run: &runID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, l := range logs {
+			got = append(got, l.Message)
+		}
+		if !reflect.DeepEqual(got, exp) {
+			t.Errorf("unexpected logs for run %s, got %v, exp %v", runID, got, exp)
+		}
+	}
+}
@@ -29,6 +29,10 @@ type LabelService interface {
 	// FindResourceLabels returns a list of labels that belong to a resource
 	FindResourceLabels(ctx context.Context, filter LabelMappingFilter) ([]*Label, error)
 
+	// FindResourceLabelsForResources returns the labels that belong to each of the
+	// resources of a type, keyed by resource ID.
+	FindResourceLabelsForResources(ctx context.Context, rt ResourceType, ids []ID) (map[ID][]*Label, error)
+
 	// CreateLabel creates a new label
 	CreateLabel(ctx context.Context, l *Label) error
 
@@ -20,6 +20,7 @@ type CheckService struct {
 	UpdateCheckFn   func(context.Context, influxdb.ID, influxdb.Check) (influxdb.Check, error)
 	PatchCheckFn    func(context.Context, influxdb.ID, influxdb.CheckUpdate) (influxdb.Check, error)
 	DeleteCheckFn   func(context.Context, influxdb.ID) error
+	CloneCheckFn    func(context.Context, influxdb.ID, string) (influxdb.Check, error)
 }
 
 // NewCheckService returns a mock CheckService where its methods will return
@@ -35,6 +36,7 @@ func NewCheckService() *CheckService {
 		UpdateCheckFn: func(context.Context, influxdb.ID, influxdb.Check) (influxdb.Check, error) { return nil, nil },
 		PatchCheckFn:  func(context.Context, influxdb.ID, influxdb.CheckUpdate) (influxdb.Check, error) { return nil, nil },
 		DeleteCheckFn: func(context.Context, influxdb.ID) error { return nil },
+		CloneCheckFn:  func(context.Context, influxdb.ID, string) (influxdb.Check, error) { return nil, nil },
 	}
 }
 
@@ -72,3 +74,8 @@ func (s *CheckService) PatchCheck(ctx context.Context, id influxdb.ID, upd influ
 func (s *CheckService) DeleteCheck(ctx context.Context, id influxdb.ID) error {
 	return s.DeleteCheckFn(ctx, id)
 }
+
+// CloneCheck creates a copy of the check with a new ID and name.
+func (s *CheckService) CloneCheck(ctx context.Context, id influxdb.ID, newName string) (influxdb.Check, error) {
+	return s.CloneCheckFn(ctx, id, newName)
+}
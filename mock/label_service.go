@@ -13,11 +13,13 @@ type LabelService struct {
 	FindLabelByIDFn      func(ctx context.Context, id platform.ID) (*platform.Label, error)
 	FindLabelsFn         func(context.Context, platform.LabelFilter) ([]*platform.Label, error)
 	FindResourceLabelsFn func(context.Context, platform.LabelMappingFilter) ([]*platform.Label, error)
-	CreateLabelFn        func(context.Context, *platform.Label) error
-	CreateLabelMappingFn func(context.Context, *platform.LabelMapping) error
-	UpdateLabelFn        func(context.Context, platform.ID, platform.LabelUpdate) (*platform.Label, error)
-	DeleteLabelFn        func(context.Context, platform.ID) error
-	DeleteLabelMappingFn func(context.Context, *platform.LabelMapping) error
+	// FindResourceLabelsForResourcesFn falls back to FindResourceLabelsFn when not set.
+	FindResourceLabelsForResourcesFn func(context.Context, platform.ResourceType, []platform.ID) (map[platform.ID][]*platform.Label, error)
+	CreateLabelFn                    func(context.Context, *platform.Label) error
+	CreateLabelMappingFn             func(context.Context, *platform.LabelMapping) error
+	UpdateLabelFn                    func(context.Context, platform.ID, platform.LabelUpdate) (*platform.Label, error)
+	DeleteLabelFn                    func(context.Context, platform.ID) error
+	DeleteLabelMappingFn             func(context.Context, *platform.LabelMapping) error
 }
 
 // NewLabelService returns a mock of LabelService
@@ -56,6 +58,23 @@ func (s *LabelService) FindResourceLabels(ctx context.Context, filter platform.L
 	return s.FindResourceLabelsFn(ctx, filter)
 }
 
+// FindResourceLabelsForResources finds the labels of each of the resources.
+func (s *LabelService) FindResourceLabelsForResources(ctx context.Context, rt platform.ResourceType, ids []platform.ID) (map[platform.ID][]*platform.Label, error) {
+	if s.FindResourceLabelsForResourcesFn != nil {
+		return s.FindResourceLabelsForResourcesFn(ctx, rt, ids)
+	}
+
+	m := make(map[platform.ID][]*platform.Label, len(ids))
+	for _, id := range ids {
+		ls, err := s.FindResourceLabelsFn(ctx, platform.LabelMappingFilter{ResourceID: id, ResourceType: rt})
+		if err != nil {
+			return nil, err
+		}
+		m[id] = ls
+	}
+	return m, nil
+}
+
 // CreateLabel creates a new Label.
 func (s *LabelService) CreateLabel(ctx context.Context, l *platform.Label) error {
 	return s.CreateLabelFn(ctx, l)
@@ -9,17 +9,20 @@ import (
 var _ platform.TaskService = (*TaskService)(nil)
 
 type TaskService struct {
-	FindTaskByIDFn func(context.Context, platform.ID) (*platform.Task, error)
-	FindTasksFn    func(context.Context, platform.TaskFilter) ([]*platform.Task, int, error)
-	CreateTaskFn   func(context.Context, platform.TaskCreate) (*platform.Task, error)
-	UpdateTaskFn   func(context.Context, platform.ID, platform.TaskUpdate) (*platform.Task, error)
-	DeleteTaskFn   func(context.Context, platform.ID) error
-	FindLogsFn     func(context.Context, platform.LogFilter) ([]*platform.Log, int, error)
-	FindRunsFn     func(context.Context, platform.RunFilter) ([]*platform.Run, int, error)
-	FindRunByIDFn  func(context.Context, platform.ID, platform.ID) (*platform.Run, error)
-	CancelRunFn    func(context.Context, platform.ID, platform.ID) error
-	RetryRunFn     func(context.Context, platform.ID, platform.ID) (*platform.Run, error)
-	ForceRunFn     func(context.Context, platform.ID, int64) (*platform.Run, error)
+	FindTaskByIDFn     func(context.Context, platform.ID) (*platform.Task, error)
+	FindTasksFn        func(context.Context, platform.TaskFilter) ([]*platform.Task, int, error)
+	CountTasksFn       func(context.Context, platform.TaskFilter) (int, error)
+	CreateTaskFn       func(context.Context, platform.TaskCreate) (*platform.Task, error)
+	UpdateTaskFn       func(context.Context, platform.ID, platform.TaskUpdate) (*platform.Task, error)
+	DeleteTaskFn       func(context.Context, platform.ID) error
+	FindLogsFn         func(context.Context, platform.LogFilter) ([]*platform.Log, int, error)
+	FindRunsFn         func(context.Context, platform.RunFilter) ([]*platform.Run, int, error)
+	FindRunByIDFn      func(context.Context, platform.ID, platform.ID) (*platform.Run, error)
+	CancelRunFn        func(context.Context, platform.ID, platform.ID) error
+	CancelTaskRunsFn   func(context.Context, platform.ID) ([]platform.ID, error)
+	RetryRunFn         func(context.Context, platform.ID, platform.ID, *int64) (*platform.Run, error)
+	ForceRunFn         func(context.Context, platform.ID, int64) (*platform.Run, error)
+	ForceRunWithNoteFn func(context.Context, platform.ID, int64, string) (*platform.Run, error)
 }
 
 func (s *TaskService) FindTaskByID(ctx context.Context, id platform.ID) (*platform.Task, error) {
@@ -30,6 +33,10 @@ func (s *TaskService) FindTasks(ctx context.Context, filter platform.TaskFilter)
 	return s.FindTasksFn(ctx, filter)
 }
 
+func (s *TaskService) CountTasks(ctx context.Context, filter platform.TaskFilter) (int, error) {
+	return s.CountTasksFn(ctx, filter)
+}
+
 func (s *TaskService) CreateTask(ctx context.Context, t platform.TaskCreate) (*platform.Task, error) {
 	return s.CreateTaskFn(ctx, t)
 }
@@ -58,10 +65,18 @@ func (s *TaskService) CancelRun(ctx context.Context, taskID, runID platform.ID)
 	return s.CancelRunFn(ctx, taskID, runID)
 }
 
-func (s *TaskService) RetryRun(ctx context.Context, taskID, runID platform.ID) (*platform.Run, error) {
-	return s.RetryRunFn(ctx, taskID, runID)
+func (s *TaskService) CancelTaskRuns(ctx context.Context, taskID platform.ID) ([]platform.ID, error) {
+	return s.CancelTaskRunsFn(ctx, taskID)
+}
+
+func (s *TaskService) RetryRun(ctx context.Context, taskID, runID platform.ID, scheduledFor *int64) (*platform.Run, error) {
+	return s.RetryRunFn(ctx, taskID, runID, scheduledFor)
 }
 
 func (s *TaskService) ForceRun(ctx context.Context, taskID platform.ID, scheduledFor int64) (*platform.Run, error) {
 	return s.ForceRunFn(ctx, taskID, scheduledFor)
 }
+
+func (s *TaskService) ForceRunWithNote(ctx context.Context, taskID platform.ID, scheduledFor int64, note string) (*platform.Run, error) {
+	return s.ForceRunWithNoteFn(ctx, taskID, scheduledFor, note)
+}
@@ -58,6 +58,7 @@ type Limit struct {
 type NotificationRuleFilter struct {
 	OrgID        *ID
 	Organization *string
+	Name         *string
 	UserResourceMappingFilter
 }
 
@@ -73,6 +74,10 @@ func (f NotificationRuleFilter) QueryParams() map[string][]string {
 		qp["org"] = []string{*f.Organization}
 	}
 
+	if f.Name != nil {
+		qp["name"] = []string{*f.Name}
+	}
+
 	return qp
 }
 
@@ -3,6 +3,7 @@ package check
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
 
 	"github.com/influxdata/flux/ast"
 	"github.com/influxdata/influxdb"
@@ -29,11 +30,36 @@ type Base struct {
 	// Offset represents a delay before execution.
 	// It gets marshalled from a string duration, i.e.: "10s" is 10 seconds
 	Offset *notification.Duration `json:"offset,omitempty"`
+	// SuppressFor is the window after a CRIT status is emitted during which
+	// subsequent CRIT statuses of the same series are not emitted again.
+	SuppressFor *notification.Duration `json:"suppressFor,omitempty"`
 
 	Tags []notification.Tag `json:"tags"`
 	influxdb.CRUDLog
 }
 
+// critCountColumn is the column counting the recent CRIT statuses of a
+// series, used to suppress repeated CRIT statuses.
+const critCountColumn = "_crit_count"
+
+// StatusMessageTemplateVariables are the status fields that a status message
+// template may reference as {check.<name>}. The keys of the check's tags may
+// be referenced as well.
+var StatusMessageTemplateVariables = []string{
+	"_check_id",
+	"_check_name",
+	"_check_type",
+	"_field",
+	"_level",
+	"_measurement",
+	"_source_measurement",
+	"_source_timestamp",
+	"_time",
+	"_value",
+}
+
+var statusMessageTemplateVariableRegexp = regexp.MustCompile(`\{check\.([^{}]*)\}`)
+
 // Valid returns err if the check is invalid.
 func (b Base) Valid() error {
 	if !b.ID.Valid() {
@@ -66,15 +92,64 @@ func (b Base) Valid() error {
 			Msg:  "invalid status",
 		}
 	}
+	if b.SuppressFor != nil && !positiveDuration(b.SuppressFor) {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "if suppressFor is set, it must be larger than 0",
+		}
+	}
 	for _, tag := range b.Tags {
 		if err := tag.Valid(); err != nil {
 			return err
 		}
 	}
+	if err := b.validStatusMessageTemplate(); err != nil {
+		return err
+	}
 
 	return nil
 }
 
+func positiveDuration(d *notification.Duration) bool {
+	if len(d.Values) == 0 {
+		return false
+	}
+	for _, v := range d.Values {
+		if v.Magnitude <= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// validStatusMessageTemplate returns an error if the status message template
+// references an unknown variable.
+func (b Base) validStatusMessageTemplate() error {
+	for _, m := range statusMessageTemplateVariableRegexp.FindAllStringSubmatch(b.StatusMessageTemplate, -1) {
+		if !b.isStatusMessageTemplateVariable(m[1]) {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("status message template references unknown variable %q", "check."+m[1]),
+			}
+		}
+	}
+	return nil
+}
+
+func (b Base) isStatusMessageTemplateVariable(name string) bool {
+	for _, v := range StatusMessageTemplateVariables {
+		if v == name {
+			return true
+		}
+	}
+	for _, tag := range b.Tags {
+		if tag.Key == name {
+			return true
+		}
+	}
+	return false
+}
+
 func (b Base) generateFluxASTMessageFunction() ast.Statement {
 	fn := flux.Function(flux.FunctionParams("r"), flux.String(b.StatusMessageTemplate))
 	return flux.DefineVariable("messageFn", fn)
@@ -100,6 +175,76 @@ func (b Base) generateTaskOption() ast.Statement {
 	return flux.DefineTaskOption(flux.Object(props...))
 }
 
+// generateFluxASTSuppressOption overrides monitor.write so that a CRIT status
+// is not written while its series has another CRIT status in the _monitoring
+// bucket from within SuppressFor. The stored statuses are counted in the
+// critCountColumn and sort before the new ones, whose _time is now(), so only
+// the new CRIT statuses of series without a recent CRIT status keep a zero
+// count. Statuses of any other level are always written.
+func (b Base) generateFluxASTSuppressOption() ast.Statement {
+	critCount := func(n int64) *ast.CallExpression {
+		return flux.Call(flux.Identifier("map"), flux.Object(
+			flux.Property("fn", flux.Function(flux.FunctionParams("r"), &ast.ObjectExpression{
+				With:       flux.Identifier("r"),
+				Properties: []*ast.Property{flux.Property(critCountColumn, flux.Integer(n))},
+			})),
+		))
+	}
+	dropColumns := func(cols ...string) *ast.CallExpression {
+		elems := make([]ast.Expression, 0, len(cols))
+		for _, col := range cols {
+			elems = append(elems, flux.String(col))
+		}
+		return flux.Call(flux.Identifier("drop"), flux.Object(
+			flux.Property("columns", &ast.ArrayExpression{Elements: elems}),
+		))
+	}
+
+	crits := flux.Call(flux.Member("monitor", "from"), flux.Object(
+		flux.Property("start", flux.Negative((*ast.DurationLiteral)(b.SuppressFor))),
+		flux.Property("fn", flux.Function(flux.FunctionParams("r"), flux.And(
+			flux.Equal(flux.Member("r", "_check_id"), flux.String(b.ID.String())),
+			flux.Equal(flux.Member("r", "_level"), flux.String("crit")),
+		))),
+	))
+	union := flux.Call(flux.Identifier("union"), flux.Object(
+		flux.Property("tables", &ast.ArrayExpression{Elements: []ast.Expression{
+			flux.Pipe(crits, dropColumns("_start", "_stop"), critCount(1)),
+			flux.Pipe(flux.Identifier("tables"), dropColumns("_start", "_stop"), critCount(0)),
+		}}),
+	))
+	write := flux.Pipe(union,
+		flux.Call(flux.Member("experimental", "group"), flux.Object(
+			flux.Property("mode", flux.String("extend")),
+			flux.Property("columns", &ast.ArrayExpression{}),
+		)),
+		flux.Call(flux.Identifier("sort"), flux.Object(
+			flux.Property("columns", &ast.ArrayExpression{Elements: []ast.Expression{flux.String("_time")}}),
+		)),
+		flux.Call(flux.Identifier("cumulativeSum"), flux.Object(
+			flux.Property("columns", &ast.ArrayExpression{Elements: []ast.Expression{flux.String(critCountColumn)}}),
+		)),
+		flux.Call(flux.Identifier("filter"), flux.Object(
+			flux.Property("fn", flux.Function(flux.FunctionParams("r"), flux.Or(
+				flux.NotEqual(flux.Member("r", "_level"), flux.String("crit")),
+				flux.Equal(flux.Member("r", critCountColumn), flux.Integer(0)),
+			))),
+		)),
+		dropColumns(critCountColumn),
+		flux.Call(flux.Member("experimental", "to"), flux.Object(
+			flux.Property("bucket", flux.Member("monitor", "bucket")),
+		)),
+	)
+
+	params := []*ast.Property{{Key: flux.Identifier("tables"), Value: &ast.PipeLiteral{}}}
+	return &ast.OptionStatement{
+		Assignment: &ast.MemberAssignment{
+			Member: flux.Member("monitor", "write"),
+			Init:   flux.Function(params, write),
+		},
+	}
+}
+
 func (b Base) generateFluxASTCheckDefinition(checkType string) ast.Statement {
 	props := []*ast.Property{}
 	props = append(props, flux.Property("_check_id", flux.String(b.ID.String())))
@@ -202,6 +347,12 @@ var typeToCheck = map[string](func() influxdb.Check){
 	"threshold": func() influxdb.Check { return &Threshold{} },
 }
 
+// IsValidType returns whether typ is the type of a known check.
+func IsValidType(typ string) bool {
+	_, ok := typeToCheck[typ]
+	return ok
+}
+
 type rawRuleJSON struct {
 	Typ string `json:"type"`
 }
@@ -224,3 +375,12 @@ func UnmarshalJSON(b []byte) (influxdb.Check, error) {
 	err := json.Unmarshal(b, converted)
 	return converted, err
 }
+
+// Copy returns a deep copy of the check, sharing no state with the original.
+func Copy(c influxdb.Check) (influxdb.Check, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return UnmarshalJSON(b)
+}
@@ -122,6 +122,55 @@ func TestValidCheck(t *testing.T) {
 				Msg:  "tag must contain a key and a value",
 			},
 		},
+		{
+			name: "known status message template variables",
+			src: &check.Deadman{
+				Base: check.Base{
+					ID:                    influxTesting.MustIDBase16(id1),
+					Name:                  "name1",
+					OwnerID:               influxTesting.MustIDBase16(id2),
+					OrgID:                 influxTesting.MustIDBase16(id3),
+					Status:                influxdb.Active,
+					StatusMessageTemplate: "{check._check_name} is {check._level}: {check._value} on {check.k1}",
+					Tags:                  []notification.Tag{{Key: "k1", Value: "v1"}},
+				},
+			},
+		},
+		{
+			name: "unknown status message template variable",
+			src: &check.Deadman{
+				Base: check.Base{
+					ID:                    influxTesting.MustIDBase16(id1),
+					Name:                  "name1",
+					OwnerID:               influxTesting.MustIDBase16(id2),
+					OrgID:                 influxTesting.MustIDBase16(id3),
+					Status:                influxdb.Active,
+					StatusMessageTemplate: "{check._value} {check.nonsense}",
+				},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  `status message template references unknown variable "check.nonsense"`,
+			},
+		},
+		{
+			name: "non-positive suppressFor",
+			src: &check.Deadman{
+				Base: check.Base{
+					ID:                    influxTesting.MustIDBase16(id1),
+					Name:                  "name1",
+					OwnerID:               influxTesting.MustIDBase16(id2),
+					OrgID:                 influxTesting.MustIDBase16(id3),
+					Status:                influxdb.Active,
+					StatusMessageTemplate: "temp1",
+					SuppressFor:           mustDuration("0s"),
+				},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "if suppressFor is set, it must be larger than 0",
+			},
+		},
 		{
 			name: "bad thredshold",
 			src: &check.Threshold{
@@ -135,6 +184,59 @@ func TestValidCheck(t *testing.T) {
 				Msg:  "range threshold min can't be larger than max",
 			},
 		},
+		{
+			name: "bad percent change thredshold",
+			src: &check.Threshold{
+				Base: goodBase,
+				Thresholds: []check.ThresholdConfig{
+					&check.PercentChange{Min: 50, Max: -50},
+				},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "percent change threshold min can't be larger than max",
+			},
+		},
+		{
+			name: "empty deadman report tag",
+			src: &check.Deadman{
+				Base:       goodBase,
+				ReportTags: []string{"host", ""},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  `deadman report tag "" is not a valid identifier`,
+			},
+		},
+		{
+			name: "invalid deadman report tag",
+			src: &check.Deadman{
+				Base:       goodBase,
+				ReportTags: []string{"host-name"},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  `deadman report tag "host-name" is not a valid identifier`,
+			},
+		},
+		{
+			name: "keyword deadman report tag",
+			src: &check.Deadman{
+				Base:       goodBase,
+				ReportTags: []string{"if"},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  `deadman report tag "if" is not a valid identifier`,
+			},
+		},
+		{
+			name: "valid deadman report tags",
+			src: &check.Deadman{
+				Base:       goodBase,
+				ReportTags: []string{"host", "_measurement", "région2"},
+			},
+		},
 	}
 	for _, c := range cases {
 		got := c.src.Valid()
@@ -189,6 +291,37 @@ func TestJSON(t *testing.T) {
 				Level:      notification.Warn,
 			},
 		},
+		{
+			name: "multi-field Deadman",
+			src: &check.Deadman{
+				Base: check.Base{
+					ID:      influxTesting.MustIDBase16(id1),
+					OwnerID: influxTesting.MustIDBase16(id2),
+					Name:    "name1",
+					OrgID:   influxTesting.MustIDBase16(id3),
+					Status:  influxdb.Active,
+					Every:   mustDuration("1h"),
+					Tags: []notification.Tag{
+						{
+							Key:   "k1",
+							Value: "v1",
+						},
+						{
+							Key:   "k2",
+							Value: "v2",
+						},
+					},
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				TimeSince:  33,
+				ReportZero: true,
+				Level:      notification.Warn,
+				Fields:     []string{"usage_user", "usage_system"},
+			},
+		},
 		{
 			name: "simple threshold",
 			src: &check.Threshold{
@@ -218,6 +351,9 @@ func TestJSON(t *testing.T) {
 					&check.Greater{ThresholdConfigBase: check.ThresholdConfigBase{AllValues: true}, Value: -1.36},
 					&check.Range{Min: -10000, Max: 500},
 					&check.Lesser{ThresholdConfigBase: check.ThresholdConfigBase{Level: notification.Critical}},
+					&check.GreaterEqual{ThresholdConfigBase: check.ThresholdConfigBase{Level: notification.Info}, Value: 2.5},
+					&check.LesserEqual{ThresholdConfigBase: check.ThresholdConfigBase{Level: notification.Ok}, Value: 100},
+					&check.PercentChange{ThresholdConfigBase: check.ThresholdConfigBase{Level: notification.Warn}, Min: -20, Max: 20},
 				},
 			},
 		},
@@ -236,3 +372,43 @@ func TestJSON(t *testing.T) {
 		}
 	}
 }
+
+func TestCopy(t *testing.T) {
+	src := &check.Threshold{
+		Base: check.Base{
+			ID:      influxTesting.MustIDBase16(id1),
+			Name:    "name1",
+			OwnerID: influxTesting.MustIDBase16(id2),
+			OrgID:   influxTesting.MustIDBase16(id3),
+			Status:  influxdb.Active,
+			Every:   mustDuration("1h"),
+			Tags: []notification.Tag{
+				{Key: "k1", Value: "v1"},
+			},
+		},
+		Thresholds: []check.ThresholdConfig{
+			&check.Greater{ThresholdConfigBase: check.ThresholdConfigBase{Level: notification.Warn}, Value: 2000},
+			&check.Range{ThresholdConfigBase: check.ThresholdConfigBase{Level: notification.Info}, Min: 1500, Max: 1900, Within: true},
+			&check.Lesser{ThresholdConfigBase: check.ThresholdConfigBase{Level: notification.Ok}, Value: 1000},
+		},
+	}
+
+	got, err := check.Copy(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, influxdb.Check(src), cmpopts.IgnoreFields(notification.Duration{}, "BaseNode")); diff != "" {
+		t.Fatalf("copy is different -got/+want\ndiff %s", diff)
+	}
+
+	// Changing the copy must leave the original untouched.
+	cp := got.(*check.Threshold)
+	cp.Thresholds[0].(*check.Greater).Value = 3000
+	cp.Tags[0].Value = "changed"
+	if v := src.Thresholds[0].(*check.Greater).Value; v != 2000 {
+		t.Fatalf("original threshold was modified: got %v", v)
+	}
+	if v := src.Tags[0].Value; v != "v1" {
+		t.Fatalf("original tag was modified: got %v", v)
+	}
+}
@@ -23,6 +23,12 @@ type Deadman struct {
 	// TODO(desa): Is this implemented in Flux?
 	ReportZero bool                    `json:"reportZero"`
 	Level      notification.CheckLevel `json:"level"`
+	// Fields optionally limits the check to the named fields, alerting
+	// if any of them stops reporting.
+	Fields []string `json:"fields,omitempty"`
+	// ReportTags optionally names the tags whose values are appended to
+	// the status message, identifying the series that stopped reporting.
+	ReportTags []string `json:"reportTags,omitempty"`
 }
 
 // Type returns the type of the check.
@@ -30,6 +36,41 @@ func (c Deadman) Type() string {
 	return "deadman"
 }
 
+// Valid returns error if something is invalid.
+func (c Deadman) Valid() error {
+	if err := c.Base.Valid(); err != nil {
+		return err
+	}
+	for _, tag := range c.ReportTags {
+		if !validIdentifier(tag) {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("deadman report tag %q is not a valid identifier", tag),
+			}
+		}
+	}
+	return nil
+}
+
+// validIdentifier reports whether name can be the property of the member
+// expression r.name of the message function.
+func validIdentifier(name string) bool {
+	p := parser.ParseSource("r." + name)
+	if ast.Check(p) != 0 || len(p.Files) != 1 || len(p.Files[0].Body) != 1 {
+		return false
+	}
+	stmt, ok := p.Files[0].Body[0].(*ast.ExpressionStatement)
+	if !ok {
+		return false
+	}
+	m, ok := stmt.Expression.(*ast.MemberExpression)
+	if !ok {
+		return false
+	}
+	id, ok := m.Property.(*ast.Identifier)
+	return ok && id.Name == name
+}
+
 // GenerateFlux returns a flux script for the Deadman provided.
 func (c Deadman) GenerateFlux() (string, error) {
 	p, err := c.GenerateFluxAST()
@@ -70,6 +111,9 @@ func (c Deadman) GenerateFluxAST() (*ast.Package, error) {
 func (c Deadman) generateFluxASTBody() []ast.Statement {
 	var statements []ast.Statement
 	statements = append(statements, c.generateTaskOption())
+	if c.SuppressFor != nil && c.Level == notification.Critical {
+		statements = append(statements, c.generateFluxASTSuppressOption())
+	}
 	statements = append(statements, c.generateFluxASTCheckDefinition("deadman"))
 	statements = append(statements, c.generateLevelFn())
 	statements = append(statements, c.generateFluxASTMessageFunction())
@@ -85,15 +129,56 @@ func (c Deadman) generateLevelFn() ast.Statement {
 	return flux.DefineVariable(lvl, fn)
 }
 
+// generateFluxASTMessageFunction appends the values of the report tags of
+// each series to the status message.
+func (c Deadman) generateFluxASTMessageFunction() ast.Statement {
+	if len(c.ReportTags) == 0 {
+		return c.Base.generateFluxASTMessageFunction()
+	}
+
+	var msg ast.Expression = flux.String(c.StatusMessageTemplate)
+	for i, tag := range c.ReportTags {
+		sep := ", "
+		if i == 0 {
+			sep = " ("
+		}
+		msg = flux.Add(msg, flux.String(sep+tag+": "))
+		msg = flux.Add(msg, flux.Member("r", tag))
+	}
+	msg = flux.Add(msg, flux.String(")"))
+
+	fn := flux.Function(flux.FunctionParams("r"), msg)
+	return flux.DefineVariable("messageFn", fn)
+}
+
 func (c Deadman) generateFluxASTChecksFunction() ast.Statement {
 	dur := flux.Duration(int64(c.TimeSince), "s")
 	now := flux.Call(flux.Identifier("now"), flux.Object())
 	sub := flux.Call(flux.Member("experimental", "subDuration"), flux.Object(flux.Property("from", now), flux.Property("d", dur)))
-	return flux.ExpressionStatement(flux.Pipe(
-		flux.Identifier("data"),
+	var calls []*ast.CallExpression
+	if len(c.Fields) > 0 {
+		calls = append(calls, c.generateFluxASTFieldsFilter())
+	}
+	calls = append(calls,
 		flux.Call(flux.Member("monitor", "deadman"), flux.Object(flux.Property("t", sub))),
 		c.generateFluxASTChecksCall(),
-	))
+	)
+	return flux.ExpressionStatement(flux.Pipe(flux.Identifier("data"), calls...))
+}
+
+func (c Deadman) generateFluxASTFieldsFilter() *ast.CallExpression {
+	var fnBody ast.Expression
+	for _, field := range c.Fields {
+		eq := flux.Equal(flux.Member("r", "_field"), flux.String(field))
+		if fnBody == nil {
+			fnBody = eq
+			continue
+		}
+		fnBody = flux.Or(fnBody, eq)
+	}
+	fn := flux.Function(flux.FunctionParams("r"), fnBody)
+
+	return flux.Call(flux.Identifier("filter"), flux.Object(flux.Property("fn", fn)))
 }
 
 func (c Deadman) generateFluxASTChecksCall() *ast.CallExpression {
@@ -80,6 +80,112 @@ data
 	|> monitor.check(data: check, messageFn: messageFn, info: info)`,
 			},
 		},
+		{
+			name: "with report tags",
+			args: args{
+				deadman: check.Deadman{
+					Base: check.Base{
+						ID:                    10,
+						Name:                  "moo",
+						Every:                 mustDuration("1h"),
+						StatusMessageTemplate: "whoa! {r.dead}",
+						Query: influxdb.DashboardQuery{
+							Text: `from(bucket: "foo") |> range(start: -1d, stop: now()) |> aggregateWindow(every: 1m, fn: mean) |> yield()`,
+						},
+					},
+					TimeSince:  60,
+					Level:      notification.Critical,
+					ReportTags: []string{"host", "region"},
+				},
+			},
+			wants: wants{
+				script: `package main
+import "influxdata/influxdb/monitor"
+import "experimental"
+
+data = from(bucket: "foo")
+	|> range(start: -1h)
+	|> aggregateWindow(every: 1h, fn: mean)
+
+option task = {name: "moo", every: 1h}
+
+check = {
+	_check_id: "000000000000000a",
+	_check_name: "moo",
+	_check_type: "deadman",
+	tags: {},
+}
+crit = (r) =>
+	(r.dead)
+messageFn = (r) =>
+	("whoa! {r.dead}" + " (host: " + r.host + ", region: " + r.region + ")")
+
+data
+	|> monitor.deadman(t: experimental.subDuration(from: now(), d: 60s))
+	|> monitor.check(data: check, messageFn: messageFn, crit: crit)`,
+			},
+		},
+		{
+			name: "with a suppression window",
+			args: args{
+				deadman: check.Deadman{
+					Base: check.Base{
+						ID:                    10,
+						Name:                  "moo",
+						Every:                 mustDuration("1h"),
+						SuppressFor:           mustDuration("10m"),
+						StatusMessageTemplate: "whoa! {r.dead}",
+						Query: influxdb.DashboardQuery{
+							Text: `from(bucket: "foo") |> range(start: -1d, stop: now()) |> aggregateWindow(every: 1m, fn: mean) |> yield()`,
+						},
+					},
+					TimeSince: 60,
+					Level:     notification.Critical,
+				},
+			},
+			wants: wants{
+				script: `package main
+import "influxdata/influxdb/monitor"
+import "experimental"
+
+data = from(bucket: "foo")
+	|> range(start: -1h)
+	|> aggregateWindow(every: 1h, fn: mean)
+
+option task = {name: "moo", every: 1h}
+option monitor.write = (tables=<-) =>
+	(union(tables: [monitor.from(start: -10m, fn: (r) =>
+		(r._check_id == "000000000000000a" and r._level == "crit"))
+		|> drop(columns: ["_start", "_stop"])
+		|> map(fn: (r) =>
+			({r with _crit_count: 1})), tables
+		|> drop(columns: ["_start", "_stop"])
+		|> map(fn: (r) =>
+			({r with _crit_count: 0}))])
+		|> experimental.group(mode: "extend", columns: [])
+		|> sort(columns: ["_time"])
+		|> cumulativeSum(columns: ["_crit_count"])
+		|> filter(fn: (r) =>
+			(r._level != "crit" or r._crit_count == 0))
+		|> drop(columns: ["_crit_count"])
+		|> experimental.to(bucket: monitor.bucket))
+
+check = {
+	_check_id: "000000000000000a",
+	_check_name: "moo",
+	_check_type: "deadman",
+	tags: {},
+}
+crit = (r) =>
+	(r.dead)
+messageFn = (r) =>
+	("whoa! {r.dead}")
+
+data
+	|> monitor.deadman(t: experimental.subDuration(from: now(), d: 60s))
+	|> monitor.check(data: check, messageFn: messageFn, crit: crit)`,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -14,6 +14,10 @@ import (
 
 var _ influxdb.Check = &Threshold{}
 
+// priorDeltaColumn is the column holding the change of the selected field
+// since the prior window, used by percent change thresholds.
+const priorDeltaColumn = "_prior_delta"
+
 // Threshold is the threshold check.
 type Threshold struct {
 	Base
@@ -73,6 +77,18 @@ func (t *Threshold) UnmarshalJSON(b []byte) error {
 				Value:               tdRaw.Value,
 			}
 			t.Thresholds = append(t.Thresholds, td)
+		case "lesserEqual":
+			td := &LesserEqual{
+				ThresholdConfigBase: tdRaw.ThresholdConfigBase,
+				Value:               tdRaw.Value,
+			}
+			t.Thresholds = append(t.Thresholds, td)
+		case "greaterEqual":
+			td := &GreaterEqual{
+				ThresholdConfigBase: tdRaw.ThresholdConfigBase,
+				Value:               tdRaw.Value,
+			}
+			t.Thresholds = append(t.Thresholds, td)
 		case "range":
 			td := &Range{
 				ThresholdConfigBase: tdRaw.ThresholdConfigBase,
@@ -81,6 +97,13 @@ func (t *Threshold) UnmarshalJSON(b []byte) error {
 				Within:              tdRaw.Within,
 			}
 			t.Thresholds = append(t.Thresholds, td)
+		case "percentChange":
+			td := &PercentChange{
+				ThresholdConfigBase: tdRaw.ThresholdConfigBase,
+				Min:                 tdRaw.Min,
+				Max:                 tdRaw.Max,
+			}
+			t.Thresholds = append(t.Thresholds, td)
 		default:
 			return &influxdb.Error{
 				Msg: fmt.Sprintf("invalid threshold type %s", tdRaw.Type),
@@ -173,6 +196,42 @@ func replaceDurationsWithEvery(pkg *ast.Package, every *notification.Duration) {
 	})
 }
 
+// extendRangeStart moves the start of range further into the past by d, so
+// that the state of each series before the current window is known.
+func extendRangeStart(pkg *ast.Package, d *notification.Duration) {
+	ast.Visit(pkg, func(n ast.Node) {
+		if p, ok := n.(*ast.Property); ok && p.Key.Key() == "start" {
+			if u, ok := p.Value.(*ast.UnaryExpression); ok {
+				if start, ok := u.Argument.(*ast.DurationLiteral); ok {
+					p.Value = flux.Negative(&ast.DurationLiteral{
+						Values: addDurations(start.Values, d.Values),
+					})
+				}
+			}
+		}
+	})
+}
+
+// durationUnits are the units of flux durations from the largest to the smallest.
+var durationUnits = []string{"y", "mo", "w", "d", "h", "m", "s", "ms", "us", "µs", "ns"}
+
+// addDurations returns the sum of the durations a and b, with a single
+// magnitude for each unit.
+func addDurations(a, b []ast.Duration) []ast.Duration {
+	sums := make(map[string]int64)
+	for _, v := range append(append([]ast.Duration(nil), a...), b...) {
+		sums[v.Unit] += v.Magnitude
+	}
+
+	var values []ast.Duration
+	for _, unit := range durationUnits {
+		if m, ok := sums[unit]; ok {
+			values = append(values, ast.Duration{Magnitude: m, Unit: unit})
+		}
+	}
+	return values
+}
+
 // TODO(desa): we'll likely want to remove all other arguments to range that are provided, but for now this should work.
 // When we decide to implement the full feature we'll have to do something more sophisticated.
 func removeStopFromRange(pkg *ast.Package) {
@@ -228,6 +287,9 @@ func (t Threshold) GenerateFluxASTReal() (*ast.Package, error) {
 	p := parser.ParseSource(t.Query.Text)
 	replaceDurationsWithEvery(p, t.Every)
 	removeStopFromRange(p)
+	if t.hasPercentChange() {
+		extendRangeStart(p, t.Every)
+	}
 
 	if errs := ast.GetErrors(p); len(errs) != 0 {
 		return nil, multiError(errs)
@@ -243,6 +305,9 @@ func (t Threshold) GenerateFluxASTReal() (*ast.Package, error) {
 	assignPipelineToData(f)
 
 	f.Imports = append(f.Imports, flux.Imports("influxdata/influxdb/monitor", "influxdata/influxdb/v1")...)
+	if t.hasPercentChange() || t.suppressesCrit() {
+		f.Imports = append(f.Imports, flux.ImportDeclaration("experimental"))
+	}
 	f.Body = append(f.Body, t.generateFluxASTBody()...)
 
 	return p, nil
@@ -251,6 +316,9 @@ func (t Threshold) GenerateFluxASTReal() (*ast.Package, error) {
 func (t Threshold) generateFluxASTBody() []ast.Statement {
 	var statements []ast.Statement
 	statements = append(statements, t.generateTaskOption())
+	if t.suppressesCrit() {
+		statements = append(statements, t.generateFluxASTSuppressOption())
+	}
 	statements = append(statements, t.generateFluxASTCheckDefinition("threshold"))
 	statements = append(statements, t.generateFluxASTThresholdFunctions()...)
 	statements = append(statements, t.generateFluxASTMessageFunction())
@@ -259,13 +327,82 @@ func (t Threshold) generateFluxASTBody() []ast.Statement {
 }
 
 func (t Threshold) generateFluxASTChecksFunction() ast.Statement {
-	return flux.ExpressionStatement(flux.Pipe(
-		flux.Identifier("data"),
+	calls := []*ast.CallExpression{
 		flux.Call(flux.Member("v1", "fieldsAsCols"), flux.Object()),
-		t.generateFluxASTChecksCall(),
+	}
+	if t.hasPercentChange() {
+		calls = append(calls, t.generateFluxASTPriorDeltaCalls()...)
+		calls = append(calls, t.generateFluxASTCurrentWindowCall())
+	}
+	calls = append(calls, t.generateFluxASTChecksCall())
+
+	return flux.ExpressionStatement(flux.Pipe(flux.Identifier("data"), calls...))
+}
+
+func (t Threshold) hasPercentChange() bool {
+	for _, c := range t.Thresholds {
+		if c.Type() == "percentChange" {
+			return true
+		}
+	}
+	return false
+}
+
+// generateFluxASTCurrentWindowCall drops the rows that fall before the current
+// window, which were only queried to know the prior value of each series.
+// aggregateWindow sets _time to the stop of each window, so the rows of the
+// prior window are at now() - every and are dropped as well.
+func (t Threshold) generateFluxASTCurrentWindowCall() *ast.CallExpression {
+	every := (ast.DurationLiteral)(*t.Every)
+	return flux.Call(flux.Identifier("filter"), flux.Object(
+		flux.Property("fn", flux.Function(flux.FunctionParams("r"), flux.GreaterThan(
+			flux.Member("r", "_time"),
+			flux.Call(flux.Member("experimental", "subDuration"), flux.Object(
+				flux.Property("from", flux.Call(flux.Identifier("now"), flux.Object())),
+				flux.Property("d", &every),
+			)),
+		))),
 	))
 }
 
+// generateFluxASTPriorDeltaCalls stores the difference between each value of the
+// selected field and the value of the prior window in the priorDeltaColumn.
+// The range of the query is extended by one window so that the current window
+// has a prior one to be compared with.
+func (t Threshold) generateFluxASTPriorDeltaCalls() []*ast.CallExpression {
+	field, err := t.getSelectedField()
+	if err != nil {
+		// the error here should never happen since it should be validated before this
+		// function is ever called.
+		panic(err)
+	}
+
+	return []*ast.CallExpression{
+		flux.Call(flux.Identifier("duplicate"), flux.Object(
+			flux.Property("column", flux.String(field)),
+			flux.Property("as", flux.String(priorDeltaColumn)),
+		)),
+		flux.Call(flux.Identifier("difference"), flux.Object(
+			flux.Property("columns", &ast.ArrayExpression{
+				Elements: []ast.Expression{flux.String(priorDeltaColumn)},
+			}),
+		)),
+	}
+}
+
+// suppressesCrit reports whether repeated CRIT statuses should be suppressed.
+func (t Threshold) suppressesCrit() bool {
+	if t.SuppressFor == nil {
+		return false
+	}
+	for _, c := range t.Thresholds {
+		if c.GetLevel() == notification.Critical {
+			return true
+		}
+	}
+	return false
+}
+
 func (t Threshold) generateFluxASTChecksCall() *ast.CallExpression {
 	objectProps := append(([]*ast.Property)(nil), flux.Property("data", flux.Identifier("check")))
 	objectProps = append(objectProps, flux.Property("messageFn", flux.Identifier("messageFn")))
@@ -314,6 +451,24 @@ func (td Lesser) generateFluxASTThresholdFunction(field string) ast.Statement {
 	return flux.DefineVariable(lvl, fn)
 }
 
+func (td GreaterEqual) generateFluxASTThresholdFunction(field string) ast.Statement {
+	fnBody := flux.GreaterThanEqual(flux.Member("r", field), flux.Float(td.Value))
+	fn := flux.Function(flux.FunctionParams("r"), fnBody)
+
+	lvl := strings.ToLower(td.Level.String())
+
+	return flux.DefineVariable(lvl, fn)
+}
+
+func (td LesserEqual) generateFluxASTThresholdFunction(field string) ast.Statement {
+	fnBody := flux.LessThanEqual(flux.Member("r", field), flux.Float(td.Value))
+	fn := flux.Function(flux.FunctionParams("r"), fnBody)
+
+	lvl := strings.ToLower(td.Level.String())
+
+	return flux.DefineVariable(lvl, fn)
+}
+
 func (td Range) generateFluxASTThresholdFunction(field string) ast.Statement {
 	if !td.Within {
 		td.Min, td.Max = td.Max, td.Min
@@ -329,6 +484,32 @@ func (td Range) generateFluxASTThresholdFunction(field string) ast.Statement {
 	return flux.DefineVariable(lvl, fn)
 }
 
+func (td PercentChange) generateFluxASTThresholdFunction(field string) ast.Statement {
+	// The value of the prior window is the current value minus its delta.
+	prior := flux.Subtract(flux.Member("r", field), flux.Member("r", priorDeltaColumn))
+	percent := flux.Multiply(
+		flux.Divide(flux.Member("r", priorDeltaColumn), prior),
+		flux.Float(100),
+	)
+	// A series without a prior value, or with a prior value of 0, has no
+	// percent change; and short circuits before the division.
+	fnBody := flux.And(
+		flux.And(
+			flux.Exists(flux.Member("r", priorDeltaColumn)),
+			flux.NotEqual(prior, flux.Float(0)),
+		),
+		flux.And(
+			flux.LessThan(percent, flux.Float(td.Max)),
+			flux.GreaterThan(percent, flux.Float(td.Min)),
+		),
+	)
+	fn := flux.Function(flux.FunctionParams("r"), fnBody)
+
+	lvl := strings.ToLower(td.Level.String())
+
+	return flux.DefineVariable(lvl, fn)
+}
+
 type thresholdAlias Threshold
 
 // MarshalJSON implement json.Marshaler interface.
@@ -419,6 +600,56 @@ func (td Greater) MarshalJSON() ([]byte, error) {
 		})
 }
 
+// LesserEqual threshold type matches values less than or equal to Value.
+type LesserEqual struct {
+	ThresholdConfigBase
+	Value float64 `json:"value,omitempty"`
+}
+
+// Type of the threshold config.
+func (td LesserEqual) Type() string {
+	return "lesserEqual"
+}
+
+type lesserEqualAlias LesserEqual
+
+// MarshalJSON implement json.Marshaler interface.
+func (td LesserEqual) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			lesserEqualAlias
+			Type string `json:"type"`
+		}{
+			lesserEqualAlias: lesserEqualAlias(td),
+			Type:             "lesserEqual",
+		})
+}
+
+// GreaterEqual threshold type matches values greater than or equal to Value.
+type GreaterEqual struct {
+	ThresholdConfigBase
+	Value float64 `json:"value,omitempty"`
+}
+
+// Type of the threshold config.
+func (td GreaterEqual) Type() string {
+	return "greaterEqual"
+}
+
+type greaterEqualAlias GreaterEqual
+
+// MarshalJSON implement json.Marshaler interface.
+func (td GreaterEqual) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			greaterEqualAlias
+			Type string `json:"type"`
+		}{
+			greaterEqualAlias: greaterEqualAlias(td),
+			Type:              "greaterEqual",
+		})
+}
+
 // Range threshold type.
 type Range struct {
 	ThresholdConfigBase
@@ -456,3 +687,41 @@ func (td Range) Valid() error {
 	}
 	return nil
 }
+
+// PercentChange threshold type matches when the percentage change of a value
+// compared to the prior window is between Min and Max.
+type PercentChange struct {
+	ThresholdConfigBase
+	Min float64 `json:"min,omitempty"`
+	Max float64 `json:"max,omitempty"`
+}
+
+// Type of the threshold config.
+func (td PercentChange) Type() string {
+	return "percentChange"
+}
+
+type percentChangeAlias PercentChange
+
+// MarshalJSON implement json.Marshaler interface.
+func (td PercentChange) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			percentChangeAlias
+			Type string `json:"type"`
+		}{
+			percentChangeAlias: percentChangeAlias(td),
+			Type:               "percentChange",
+		})
+}
+
+// Valid overwrite the base threshold.
+func (td PercentChange) Valid() error {
+	if td.Min > td.Max {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "percent change threshold min can't be larger than max",
+		}
+	}
+	return nil
+}
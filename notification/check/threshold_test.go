@@ -1,12 +1,23 @@
 package check_test
 
 import (
+	"context"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/dependencies"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/parser"
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/notification"
 	"github.com/influxdata/influxdb/notification/check"
+	_ "github.com/influxdata/influxdb/query/builtin"
 )
 
 func TestThreshold_GenerateFlux(t *testing.T) {
@@ -343,3 +354,259 @@ data
 	}
 
 }
+
+func TestThreshold_PercentChange(t *testing.T) {
+	// now is aligned to the every of the check, as it is when the task runs.
+	now := time.Date(2019, 10, 16, 12, 0, 0, 0, time.UTC)
+	// The first row of each host is in the prior window, the second in the current one.
+	input := `#datatype,string,long,dateTime:RFC3339,string,string,string,double
+#group,false,false,false,true,true,true,false
+#default,_result,,,,,,
+,result,table,_time,_measurement,_field,host,_value
+,,0,2019-10-16T11:58:30Z,cpu,usage_user,a,10
+,,0,2019-10-16T11:59:30Z,cpu,usage_user,a,15
+,,1,2019-10-16T11:58:30Z,cpu,usage_user,b,0
+,,1,2019-10-16T11:59:30Z,cpu,usage_user,b,5
+,,2,2019-10-16T11:58:30Z,cpu,usage_user,c,10
+,,2,2019-10-16T11:59:30Z,cpu,usage_user,c,10
+,,3,2019-10-16T11:58:30Z,cpu,usage_user,d,20
+,,3,2019-10-16T11:59:30Z,cpu,usage_user,d,21
+,,4,2019-10-16T11:59:30Z,cpu,usage_user,e,100
+`
+
+	threshold := check.Threshold{
+		Base: check.Base{
+			ID:                    10,
+			Name:                  "moo",
+			Every:                 mustDuration("1m"),
+			StatusMessageTemplate: "whoa! {r.usage_user}",
+			Query: influxdb.DashboardQuery{
+				Text: `import "csv"
+csv.from(csv: input)
+	|> range(start: -1m)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "usage_user")
+	|> aggregateWindow(every: 1m, fn: mean)`,
+				BuilderConfig: influxdb.BuilderConfig{
+					Tags: []struct {
+						Key    string   `json:"key"`
+						Values []string `json:"values"`
+					}{
+						{
+							Key:    "_field",
+							Values: []string{"usage_user"},
+						},
+					},
+				},
+			},
+		},
+		Thresholds: []check.ThresholdConfig{
+			check.PercentChange{
+				ThresholdConfigBase: check.ThresholdConfigBase{
+					Level: notification.Critical,
+				},
+				Min: 40,
+				Max: 60,
+			},
+			check.Greater{
+				ThresholdConfigBase: check.ThresholdConfigBase{
+					Level: notification.Warn,
+				},
+				Value: 20,
+			},
+		},
+	}
+
+	p, err := threshold.GenerateFluxASTReal()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Return the statuses instead of writing them to the _monitoring bucket.
+	write := parser.ParseSource("option monitor.write = (tables=<-) => tables").Files[0].Body[0]
+	f := p.Files[0]
+	f.Body = append([]ast.Statement{write}, f.Body...)
+	// monitor.check of flux v0.41 reads the type of the check from _type.
+	script := strings.Replace(ast.Format(p), "_check_type:", "_type:", 1)
+
+	prog, err := lang.Compile(script, now, lang.WithExtern(&ast.File{Body: []ast.Statement{
+		&ast.VariableAssignment{
+			ID:   &ast.Identifier{Name: "input"},
+			Init: &ast.StringLiteral{Value: input},
+		},
+	}}))
+	if err != nil {
+		t.Fatalf("unexpected error compiling %s: %v", script, err)
+	}
+	prog.SetExecutorDependencies(execute.Dependencies{dependencies.InterpreterDepsKey: dependencies.NewDefaults()})
+	q, err := prog.Start(context.Background(), &memory.Allocator{})
+	if err != nil {
+		t.Fatalf("unexpected error starting %s: %v", script, err)
+	}
+	defer q.Done()
+
+	levels := make(map[string][]string)
+	for res := range q.Results() {
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			return tbl.Do(func(cr flux.ColReader) error {
+				host := execute.ColIdx("host", cr.Cols())
+				level := execute.ColIdx("_level", cr.Cols())
+				for i := 0; i < cr.Len(); i++ {
+					h := cr.Strings(host).ValueString(i)
+					levels[h] = append(levels[h], cr.Strings(level).ValueString(i))
+				}
+				return nil
+			})
+		}); err != nil {
+			t.Fatalf("unexpected error reading results: %v", err)
+		}
+	}
+	q.Done()
+	if err := q.Err(); err != nil {
+		t.Fatalf("unexpected error running %s: %v", script, err)
+	}
+
+	exp := map[string][]string{
+		// 10 -> 15 is a change of 50%.
+		"a": {"crit"},
+		// the prior value is 0, so there is no percent change.
+		"b": {"ok"},
+		"c": {"ok"},
+		// 20 -> 21 is a change of 5%, but the value is greater than 20.
+		"d": {"warn"},
+		// there is no prior value, but the value is greater than 20.
+		"e": {"warn"},
+	}
+	if diff := cmp.Diff(exp, levels); diff != "" {
+		t.Errorf("unexpected levels (-want +got):\n%s", diff)
+	}
+}
+
+func TestThreshold_SuppressFor(t *testing.T) {
+	// now is aligned to the every of the check, as it is when the task runs.
+	now := time.Date(2019, 10, 16, 12, 0, 0, 0, time.UTC)
+	input := `#datatype,string,long,dateTime:RFC3339,string,string,string,double
+#group,false,false,false,true,true,true,false
+#default,_result,,,,,,
+,result,table,_time,_measurement,_field,host,_value
+,,0,2019-10-16T11:59:30Z,cpu,usage_user,a,90
+,,1,2019-10-16T11:59:30Z,cpu,usage_user,b,90
+,,2,2019-10-16T11:59:30Z,cpu,usage_user,c,90
+,,3,2019-10-16T11:59:30Z,cpu,usage_user,d,10
+,,4,2019-10-16T11:59:30Z,cpu,usage_user,e,10
+`
+	// The CRIT statuses of the check stored in the _monitoring bucket.
+	statuses := `#datatype,string,long,dateTime:RFC3339,string,string,string,string,string,string,string,string,long,double
+#group,false,false,false,true,true,true,true,true,true,true,false,false,false
+#default,_result,,,,,,,,,,,,
+,result,table,_time,_measurement,_source_measurement,_type,_check_id,_check_name,_level,host,_message,_source_timestamp,usage_user
+,,0,2019-10-16T11:55:00Z,statuses,cpu,threshold,000000000000000a,moo,crit,a,whoa!,1571227020000000000,90
+,,1,2019-10-16T11:45:00Z,statuses,cpu,threshold,000000000000000a,moo,crit,b,whoa!,1571226420000000000,90
+,,2,2019-10-16T11:55:00Z,statuses,cpu,threshold,000000000000000b,other,crit,c,whoa!,1571227020000000000,90
+,,3,2019-10-16T11:55:00Z,statuses,cpu,threshold,000000000000000a,moo,crit,e,whoa!,1571227020000000000,90
+`
+
+	threshold := check.Threshold{
+		Base: check.Base{
+			ID:                    10,
+			Name:                  "moo",
+			Every:                 mustDuration("1m"),
+			SuppressFor:           mustDuration("10m"),
+			StatusMessageTemplate: "whoa!",
+			Query: influxdb.DashboardQuery{
+				Text: `import "csv"
+csv.from(csv: input)
+	|> range(start: -1m)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "usage_user")`,
+				BuilderConfig: influxdb.BuilderConfig{
+					Tags: []struct {
+						Key    string   `json:"key"`
+						Values []string `json:"values"`
+					}{
+						{
+							Key:    "_field",
+							Values: []string{"usage_user"},
+						},
+					},
+				},
+			},
+		},
+		Thresholds: []check.ThresholdConfig{
+			check.Greater{
+				ThresholdConfigBase: check.ThresholdConfigBase{
+					Level: notification.Critical,
+				},
+				Value: 50,
+			},
+		},
+	}
+
+	script, err := threshold.GenerateFlux()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Read the stored statuses from csv and return the statuses instead of
+	// writing them to the _monitoring bucket.
+	// monitor.check of flux v0.41 reads the type of the check from _type.
+	script = strings.Replace(script, "_check_type:", "_type:", 1)
+	script = strings.Replace(script, "monitor.from(", "statusesFrom(", 1)
+	script = strings.Replace(script, "experimental.to(bucket: monitor.bucket)", "filter(fn: (r) => true)", 1)
+	script = strings.Replace(script, "\ndata = ", `
+statusesFrom = (start, fn) => csv.from(csv: statuses)
+	|> range(start: start)
+	|> filter(fn: fn)
+data = `, 1)
+
+	prog, err := lang.Compile(script, now, lang.WithExtern(&ast.File{Body: []ast.Statement{
+		&ast.VariableAssignment{
+			ID:   &ast.Identifier{Name: "input"},
+			Init: &ast.StringLiteral{Value: input},
+		},
+		&ast.VariableAssignment{
+			ID:   &ast.Identifier{Name: "statuses"},
+			Init: &ast.StringLiteral{Value: statuses},
+		},
+	}}))
+	if err != nil {
+		t.Fatalf("unexpected error compiling %s: %v", script, err)
+	}
+	prog.SetExecutorDependencies(execute.Dependencies{dependencies.InterpreterDepsKey: dependencies.NewDefaults()})
+	q, err := prog.Start(context.Background(), &memory.Allocator{})
+	if err != nil {
+		t.Fatalf("unexpected error starting %s: %v", script, err)
+	}
+	defer q.Done()
+
+	levels := make(map[string][]string)
+	for res := range q.Results() {
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			return tbl.Do(func(cr flux.ColReader) error {
+				host := execute.ColIdx("host", cr.Cols())
+				level := execute.ColIdx("_level", cr.Cols())
+				for i := 0; i < cr.Len(); i++ {
+					h := cr.Strings(host).ValueString(i)
+					levels[h] = append(levels[h], cr.Strings(level).ValueString(i))
+				}
+				return nil
+			})
+		}); err != nil {
+			t.Fatalf("unexpected error reading results: %v", err)
+		}
+	}
+	q.Done()
+	if err := q.Err(); err != nil {
+		t.Fatalf("unexpected error running %s: %v", script, err)
+	}
+
+	exp := map[string][]string{
+		// a emitted a CRIT status 5m ago, so it is suppressed.
+		// b emitted its last CRIT status 15m ago.
+		"b": {"crit"},
+		// the recent CRIT status of c was emitted by another check.
+		"c": {"crit"},
+		"d": {"ok"},
+		// e recovered from its recent CRIT status, which is not suppressed.
+		"e": {"ok"},
+	}
+	if diff := cmp.Diff(exp, levels); diff != "" {
+		t.Errorf("unexpected levels (-want +got):\n%s", diff)
+	}
+}
@@ -1,6 +1,10 @@
 package flux
 
-import "github.com/influxdata/flux/ast"
+import (
+	"regexp"
+
+	"github.com/influxdata/flux/ast"
+)
 
 // File creates a new *ast.File.
 func File(name string, imports []*ast.ImportDeclaration, body []ast.Statement) *ast.File {
@@ -20,6 +24,15 @@ func GreaterThan(lhs, rhs ast.Expression) *ast.BinaryExpression {
 	}
 }
 
+// GreaterThanEqual returns a greater than or equal to *ast.BinaryExpression.
+func GreaterThanEqual(lhs, rhs ast.Expression) *ast.BinaryExpression {
+	return &ast.BinaryExpression{
+		Operator: ast.GreaterThanEqualOperator,
+		Left:     lhs,
+		Right:    rhs,
+	}
+}
+
 // LessThan returns a less than *ast.BinaryExpression.
 func LessThan(lhs, rhs ast.Expression) *ast.BinaryExpression {
 	return &ast.BinaryExpression{
@@ -29,6 +42,15 @@ func LessThan(lhs, rhs ast.Expression) *ast.BinaryExpression {
 	}
 }
 
+// LessThanEqual returns a less than or equal to *ast.BinaryExpression.
+func LessThanEqual(lhs, rhs ast.Expression) *ast.BinaryExpression {
+	return &ast.BinaryExpression{
+		Operator: ast.LessThanEqualOperator,
+		Left:     lhs,
+		Right:    rhs,
+	}
+}
+
 // Equal returns an equal to *ast.BinaryExpression.
 func Equal(lhs, rhs ast.Expression) *ast.BinaryExpression {
 	return &ast.BinaryExpression{
@@ -38,6 +60,42 @@ func Equal(lhs, rhs ast.Expression) *ast.BinaryExpression {
 	}
 }
 
+// NotEqual returns a not equal to *ast.BinaryExpression.
+func NotEqual(lhs, rhs ast.Expression) *ast.BinaryExpression {
+	return &ast.BinaryExpression{
+		Operator: ast.NotEqualOperator,
+		Left:     lhs,
+		Right:    rhs,
+	}
+}
+
+// RegexpMatch returns a regular expression match *ast.BinaryExpression.
+func RegexpMatch(lhs, rhs ast.Expression) *ast.BinaryExpression {
+	return &ast.BinaryExpression{
+		Operator: ast.RegexpMatchOperator,
+		Left:     lhs,
+		Right:    rhs,
+	}
+}
+
+// NotRegexpMatch returns a regular expression not match *ast.BinaryExpression.
+func NotRegexpMatch(lhs, rhs ast.Expression) *ast.BinaryExpression {
+	return &ast.BinaryExpression{
+		Operator: ast.NotRegexpMatchOperator,
+		Left:     lhs,
+		Right:    rhs,
+	}
+}
+
+// Add returns an addition *ast.BinaryExpression.
+func Add(lhs, rhs ast.Expression) *ast.BinaryExpression {
+	return &ast.BinaryExpression{
+		Operator: ast.AdditionOperator,
+		Left:     lhs,
+		Right:    rhs,
+	}
+}
+
 // Subtract returns a subtraction *ast.BinaryExpression.
 func Subtract(lhs, rhs ast.Expression) *ast.BinaryExpression {
 	return &ast.BinaryExpression{
@@ -47,6 +105,24 @@ func Subtract(lhs, rhs ast.Expression) *ast.BinaryExpression {
 	}
 }
 
+// Divide returns a division *ast.BinaryExpression.
+func Divide(lhs, rhs ast.Expression) *ast.BinaryExpression {
+	return &ast.BinaryExpression{
+		Operator: ast.DivisionOperator,
+		Left:     lhs,
+		Right:    rhs,
+	}
+}
+
+// Multiply returns a multiplication *ast.BinaryExpression.
+func Multiply(lhs, rhs ast.Expression) *ast.BinaryExpression {
+	return &ast.BinaryExpression{
+		Operator: ast.MultiplicationOperator,
+		Left:     lhs,
+		Right:    rhs,
+	}
+}
+
 // Member returns an *ast.MemberExpression where the key is p and the values is c.
 func Member(p, c string) *ast.MemberExpression {
 	return &ast.MemberExpression{
@@ -64,6 +140,15 @@ func And(lhs, rhs ast.Expression) *ast.LogicalExpression {
 	}
 }
 
+// Or returns an or *ast.LogicalExpression.
+func Or(lhs, rhs ast.Expression) *ast.LogicalExpression {
+	return &ast.LogicalExpression{
+		Operator: ast.OrOperator,
+		Left:     lhs,
+		Right:    rhs,
+	}
+}
+
 // Pipe returns a *ast.PipeExpression that is a piped sequence of call expressions starting at base.
 // It requires at least one call expression and will panic otherwise.
 func Pipe(base ast.Expression, calls ...*ast.CallExpression) *ast.PipeExpression {
@@ -115,6 +200,11 @@ func String(s string) *ast.StringLiteral {
 	}
 }
 
+// Regexp returns an *ast.RegexpLiteral.
+func Regexp(re *regexp.Regexp) *ast.RegexpLiteral {
+	return &ast.RegexpLiteral{Value: re}
+}
+
 // Duration returns an *ast.DurationLiteral for a single duration.
 func Duration(m int64, u string) *ast.DurationLiteral {
 	return &ast.DurationLiteral{
@@ -127,6 +217,13 @@ func Duration(m int64, u string) *ast.DurationLiteral {
 	}
 }
 
+// Bool returns an *ast.BooleanLiteral of b.
+func Bool(b bool) *ast.BooleanLiteral {
+	return &ast.BooleanLiteral{
+		Value: b,
+	}
+}
+
 // Identifier returns an *ast.Identifier of i.
 func Identifier(i string) *ast.Identifier {
 	return &ast.Identifier{Name: i}
@@ -139,6 +236,13 @@ func Float(f float64) *ast.FloatLiteral {
 	}
 }
 
+// Integer returns an *ast.IntegerLiteral of i.
+func Integer(i int64) *ast.IntegerLiteral {
+	return &ast.IntegerLiteral{
+		Value: i,
+	}
+}
+
 // Negative returns *ast.UnaryExpression for -(e).
 func Negative(e ast.Expression) *ast.UnaryExpression {
 	return &ast.UnaryExpression{
@@ -147,6 +251,14 @@ func Negative(e ast.Expression) *ast.UnaryExpression {
 	}
 }
 
+// Exists returns *ast.UnaryExpression for exists e.
+func Exists(e ast.Expression) *ast.UnaryExpression {
+	return &ast.UnaryExpression{
+		Operator: ast.ExistsOperator,
+		Argument: e,
+	}
+}
+
 // DefineVariable returns an *ast.VariableAssignment of id to the e. (e.g. id = <expression>)
 func DefineVariable(id string, e ast.Expression) *ast.VariableAssignment {
 	return &ast.VariableAssignment{
@@ -0,0 +1,415 @@
+package rule
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification/flux"
+)
+
+const (
+	minutesPerDay  = 24 * 60
+	minutesPerWeek = 7 * minutesPerDay
+	// minutesPerMonth spaces the months of the minutes of the year ym of
+	// the active hours filter, so that the day of a transition, which may
+	// be past the end of its month, stays within its month.
+	minutesPerMonth = 64 * minutesPerDay
+)
+
+// ActiveHours restricts the notifications of a rule to a daily time window.
+type ActiveHours struct {
+	// Days are the week days the window applies to, i.e. "Monday".
+	// An empty list means every day.
+	Days []string `json:"days,omitempty"`
+	// Start and End are the bounds of the window in the form "15:04".
+	// End is exclusive and must differ from Start. A window whose End is
+	// before its Start ends on the next day.
+	Start string `json:"start"`
+	End   string `json:"end"`
+	// Timezone is the IANA name of the location of the window, UTC by default.
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// Valid returns an error if the active hours are invalid.
+func (a ActiveHours) Valid() error {
+	if _, err := a.weekdays(); err != nil {
+		return err
+	}
+	start, end, err := a.bounds()
+	if err != nil {
+		return err
+	}
+	if end == start {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "activeHours end must differ from start",
+		}
+	}
+	if _, err := a.zoneRule(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a ActiveHours) weekdays() ([]time.Weekday, error) {
+	if len(a.Days) == 0 {
+		return []time.Weekday{
+			time.Sunday, time.Monday, time.Tuesday, time.Wednesday,
+			time.Thursday, time.Friday, time.Saturday,
+		}, nil
+	}
+	days := make([]time.Weekday, 0, len(a.Days))
+	for _, d := range a.Days {
+		day, ok := parseWeekday(d)
+		if !ok {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("activeHours day %q is invalid", d),
+			}
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+func parseWeekday(s string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(s, d.String()) {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// bounds returns the start and end of the window in minutes of the day.
+func (a ActiveHours) bounds() (int, int, error) {
+	start, err := parseTimeOfDay(a.Start)
+	if err != nil {
+		return 0, 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("activeHours start %q is invalid", a.Start),
+		}
+	}
+	end, err := parseTimeOfDay(a.End)
+	if err != nil {
+		return 0, 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("activeHours end %q is invalid", a.End),
+		}
+	}
+	return start, end, nil
+}
+
+func parseTimeOfDay(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func (a ActiveHours) location() (*time.Location, error) {
+	if a.Timezone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(a.Timezone)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("activeHours timezone %q is invalid", a.Timezone),
+			Err:  err,
+		}
+	}
+	return loc, nil
+}
+
+// zoneRuleYears is the number of years, starting with the current one, that
+// the yearly offset rule of a location must hold for. It is long enough for
+// the nth weekday of a month to differ from the last one in some year.
+const zoneRuleYears = 8
+
+// zoneRule is the yearly offset rule of a location, as flux has no time zone
+// support. A time is converted with the offset of the location at the start
+// of the year, and then shifted by delta between the start and end
+// transitions. A location without transitions has a zero delta.
+type zoneRule struct {
+	offset     int // minutes east of UTC at the start of the year
+	delta      int
+	start, end zoneTransition
+}
+
+// zoneTransition is a yearly change of the offset of a location. It happens
+// at minute of the first weekday on or after day of month, in the offset of
+// the location at the start of the year.
+type zoneTransition struct {
+	month   time.Month
+	day     int
+	weekday time.Weekday
+	minute  int
+}
+
+// at returns the time of the transition in year.
+func (z zoneTransition) at(year, offset int) time.Time {
+	t := time.Date(year, z.month, z.day, 0, 0, 0, 0, time.UTC)
+	t = t.AddDate(0, 0, (int(z.weekday)-int(t.Weekday())+7)%7)
+	return t.Add(time.Duration(z.minute-offset) * time.Minute)
+}
+
+// zoneTransitionCandidates returns the transitions that may describe the
+// change of the offset at t, on the first weekday of t on or after any of
+// the seven days up to t, from the earliest day.
+func zoneTransitionCandidates(t time.Time, offset int) []zoneTransition {
+	local := t.Add(time.Duration(offset) * time.Minute)
+	var candidates []zoneTransition
+	for day := local.Day() - 6; day <= local.Day(); day++ {
+		if day < 1 {
+			continue
+		}
+		candidates = append(candidates, zoneTransition{
+			month:   local.Month(),
+			day:     day,
+			weekday: local.Weekday(),
+			minute:  local.Hour()*60 + local.Minute(),
+		})
+	}
+	return candidates
+}
+
+func offsetAt(loc *time.Location, t time.Time) int {
+	_, offset := t.In(loc).Zone()
+	return offset / 60
+}
+
+// zoneTransitions returns the times the offset of loc changes in year.
+func zoneTransitions(loc *time.Location, year int) []time.Time {
+	var transitions []time.Time
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	until := from.AddDate(1, 0, 0)
+	for day := from; day.Before(until); day = day.Add(24 * time.Hour) {
+		prev := offsetAt(loc, day)
+		if offsetAt(loc, day.Add(24*time.Hour)) == prev {
+			continue
+		}
+		m := sort.Search(minutesPerDay, func(m int) bool {
+			return offsetAt(loc, day.Add(time.Duration(m+1)*time.Minute)) != prev
+		})
+		transitions = append(transitions, day.Add(time.Duration(m+1)*time.Minute))
+	}
+	return transitions
+}
+
+// newZoneRule returns the yearly offset rule of loc from year on. It returns
+// false if the offsets of loc do not follow such a rule.
+func newZoneRule(loc *time.Location, year int) (zoneRule, bool) {
+	r := zoneRule{offset: offsetAt(loc, time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC))}
+	transitions := zoneTransitions(loc, year)
+	switch len(transitions) {
+	case 0:
+		return r, r.holds(loc, year)
+	case 2:
+		r.delta = offsetAt(loc, transitions[0]) - r.offset
+		for _, start := range zoneTransitionCandidates(transitions[0], r.offset) {
+			for _, end := range zoneTransitionCandidates(transitions[1], r.offset) {
+				r.start, r.end = start, end
+				if r.holds(loc, year) {
+					return r, true
+				}
+			}
+		}
+	}
+	return zoneRule{}, false
+}
+
+// holds reports whether the rule gives the offsets of loc for zoneRuleYears
+// from year on.
+func (r zoneRule) holds(loc *time.Location, year int) bool {
+	for y := year; y < year+zoneRuleYears; y++ {
+		if offsetAt(loc, time.Date(y, time.January, 1, 0, 0, 0, 0, time.UTC)) != r.offset {
+			return false
+		}
+		transitions := zoneTransitions(loc, y)
+		if r.delta == 0 {
+			if len(transitions) != 0 {
+				return false
+			}
+			continue
+		}
+		if len(transitions) != 2 ||
+			!transitions[0].Equal(r.start.at(y, r.offset)) ||
+			!transitions[1].Equal(r.end.at(y, r.offset)) ||
+			offsetAt(loc, transitions[0]) != r.offset+r.delta {
+			return false
+		}
+	}
+	return true
+}
+
+func (a ActiveHours) zoneRule() (zoneRule, error) {
+	loc, err := a.location()
+	if err != nil {
+		return zoneRule{}, err
+	}
+	r, ok := newZoneRule(loc, time.Now().UTC().Year())
+	if !ok {
+		return zoneRule{}, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("activeHours timezone %q is not supported", a.Timezone),
+		}
+	}
+	return r, nil
+}
+
+// minuteRange is a half-open range of minutes of the week, starting on Sunday.
+type minuteRange struct {
+	start, end int
+}
+
+// ranges returns the window as ranges of local minutes of the week. A window
+// that ends on the next day wraps around the end of the week.
+func (a ActiveHours) ranges() ([]minuteRange, error) {
+	days, err := a.weekdays()
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := a.bounds()
+	if err != nil {
+		return nil, err
+	}
+
+	length := end - start
+	if length < 0 {
+		length += minutesPerDay
+	}
+	ranges := make([]minuteRange, 0, len(days))
+	for _, d := range days {
+		s := int(d)*minutesPerDay + start
+		if s+length <= minutesPerWeek {
+			ranges = append(ranges, minuteRange{start: s, end: s + length})
+			continue
+		}
+		ranges = append(ranges,
+			minuteRange{start: s, end: minutesPerWeek},
+			minuteRange{start: 0, end: s + length - minutesPerWeek},
+		)
+	}
+	return ranges, nil
+}
+
+// generateFluxASTFilter returns a filter that keeps the statuses whose time
+// falls within the active hours. The time of each status is converted to the
+// location of the active hours with the yearly offset rule of the location.
+func (a ActiveHours) generateFluxASTFilter() (*ast.CallExpression, error) {
+	ranges, err := a.ranges()
+	if err != nil {
+		return nil, err
+	}
+	zone, err := a.zoneRule()
+	if err != nil {
+		return nil, err
+	}
+
+	var body []ast.Statement
+	var t ast.Expression = flux.Member("r", "_time")
+	if zone.offset != 0 || zone.delta != 0 {
+		ns := flux.Call(flux.Identifier("int"), flux.Object(flux.Property("v", t)))
+		offset := int64(zone.offset) * int64(time.Minute)
+		var local ast.Expression = flux.Add(ns, flux.Integer(offset))
+		if offset < 0 {
+			local = flux.Subtract(ns, flux.Integer(-offset))
+		}
+		body = append(body, flux.DefineVariable("t",
+			flux.Call(flux.Identifier("time"), flux.Object(flux.Property("v", local)))))
+		t = flux.Identifier("t")
+	}
+	body = append(body,
+		flux.DefineVariable("wd", dateCall("weekDay", t)),
+		flux.DefineVariable("hm", flux.Add(
+			flux.Multiply(dateCall("hour", t), flux.Integer(60)),
+			dateCall("minute", t),
+		)),
+	)
+	var minute ast.Expression = flux.Add(
+		flux.Multiply(flux.Identifier("wd"), flux.Integer(minutesPerDay)),
+		flux.Identifier("hm"),
+	)
+
+	if zone.delta != 0 {
+		// the results of the date functions are compared through arithmetic
+		// on them, as flux cannot compare them directly.
+		body = append(body,
+			flux.DefineVariable("d", dateCall("monthDay", t)),
+			flux.DefineVariable("ym", flux.Add(
+				flux.Add(
+					flux.Multiply(dateCall("month", t), flux.Integer(minutesPerMonth)),
+					flux.Multiply(flux.Identifier("d"), flux.Integer(minutesPerDay)),
+				),
+				flux.Identifier("hm"),
+			)),
+			flux.DefineVariable("offset", &ast.ConditionalExpression{
+				Test: flux.And(
+					flux.GreaterThanEqual(flux.Identifier("ym"), zone.start.generateFluxAST()),
+					flux.LessThan(flux.Identifier("ym"), zone.end.generateFluxAST()),
+				),
+				Consequent: flux.Integer(int64(zone.delta)),
+				Alternate:  flux.Integer(0),
+			}),
+		)
+		// the week is added so that the minute is not negative.
+		minute = &ast.BinaryExpression{
+			Operator: ast.ModuloOperator,
+			Left:     flux.Add(flux.Add(minute, flux.Identifier("offset")), flux.Integer(minutesPerWeek)),
+			Right:    flux.Integer(minutesPerWeek),
+		}
+	}
+
+	m := flux.Identifier("m")
+	var active ast.Expression
+	for _, r := range ranges {
+		in := flux.And(
+			flux.GreaterThanEqual(m, flux.Integer(int64(r.start))),
+			flux.LessThan(m, flux.Integer(int64(r.end))),
+		)
+		if active == nil {
+			active = in
+			continue
+		}
+		active = flux.Or(active, in)
+	}
+
+	body = append(body,
+		flux.DefineVariable("m", minute),
+		&ast.ReturnStatement{Argument: active},
+	)
+	fn := &ast.FunctionExpression{
+		Params: flux.FunctionParams("r"),
+		Body:   &ast.Block{Body: body},
+	}
+	return flux.Call(flux.Identifier("filter"), flux.Object(flux.Property("fn", fn))), nil
+}
+
+// generateFluxAST returns the time of the transition in the year of the
+// filter, comparable with its minutes of the year ym.
+func (z zoneTransition) generateFluxAST() ast.Expression {
+	// the day of the first weekday on or after z.day, from the week day wd
+	// of the day of the month d; 42 keeps the remainder positive.
+	day := flux.Add(flux.Integer(int64(z.day)), &ast.BinaryExpression{
+		Operator: ast.ModuloOperator,
+		Left: flux.Add(
+			flux.Subtract(flux.Identifier("d"), flux.Identifier("wd")),
+			flux.Integer(int64(int(z.weekday)-z.day+42)),
+		),
+		Right: flux.Integer(7),
+	})
+	return flux.Add(
+		flux.Integer(int64(int(z.month)*minutesPerMonth+z.minute)),
+		flux.Multiply(day, flux.Integer(minutesPerDay)),
+	)
+}
+
+func dateCall(fn string, t ast.Expression) *ast.CallExpression {
+	return flux.Call(flux.Member("date", fn), flux.Object(flux.Property("t", t)))
+}
@@ -0,0 +1,140 @@
+package rule
+
+import (
+	"encoding/json"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification/flux"
+)
+
+// Discord is the notification rule config of discord.
+type Discord struct {
+	Base
+	// URL is the secret holding the webhook URL of the discord channel.
+	URL influxdb.SecretField `json:"url"`
+	// Username overrides the default username of the webhook.
+	Username string `json:"username,omitempty"`
+	// AvatarURL overrides the default avatar of the webhook.
+	AvatarURL       string `json:"avatarURL,omitempty"`
+	MessageTemplate string `json:"messageTemplate"`
+}
+
+// GenerateFlux generates a flux script for the discord notification rule.
+func (s *Discord) GenerateFlux(e influxdb.NotificationEndpoint) (string, error) {
+	p, err := s.GenerateFluxAST(e)
+	if err != nil {
+		return "", err
+	}
+	return ast.Format(p), nil
+}
+
+// GenerateFluxAST generates a flux AST for the discord notification rule.
+func (s *Discord) GenerateFluxAST(e influxdb.NotificationEndpoint) (*ast.Package, error) {
+	body, err := s.generateFluxASTBody(e)
+	if err != nil {
+		return nil, err
+	}
+	f := flux.File(
+		s.Name,
+		s.generateFluxASTImports("influxdata/influxdb/monitor", "http", "json", "influxdata/influxdb/secrets"),
+		body,
+	)
+	return &ast.Package{Package: "main", Files: []*ast.File{f}}, nil
+}
+
+func (s *Discord) generateFluxASTBody(e influxdb.NotificationEndpoint) ([]ast.Statement, error) {
+	var statements []ast.Statement
+	statements = append(statements, s.generateTaskOption())
+	statements = append(statements, s.generateFluxASTSecrets())
+	statements = append(statements, s.generateFluxASTEndpoint())
+	statements = append(statements, s.generateFluxASTNotificationDefinition(e))
+	statuses, err := s.generateFluxASTStatuses()
+	if err != nil {
+		return nil, err
+	}
+	statements = append(statements, statuses)
+	statements = append(statements, s.generateFluxASTNotifyPipe())
+
+	return statements, nil
+}
+
+func (s *Discord) generateFluxASTSecrets() ast.Statement {
+	call := flux.Call(flux.Member("secrets", "get"), flux.Object(flux.Property("key", flux.String(s.URL.Key))))
+
+	return flux.DefineVariable("discord_url", call)
+}
+
+func (s *Discord) generateFluxASTEndpoint() ast.Statement {
+	call := flux.Call(flux.Member("http", "endpoint"), flux.Object(flux.Property("url", flux.Identifier("discord_url"))))
+
+	return flux.DefineVariable("discord_endpoint", call)
+}
+
+func (s *Discord) generateFluxASTNotifyPipe() ast.Statement {
+	headers := flux.Object(&ast.Property{
+		Key:   flux.String("Content-Type"),
+		Value: flux.String("application/json"),
+	})
+	messageProps := []*ast.Property{}
+	messageProps = append(messageProps, flux.Property("content", flux.String(s.MessageTemplate)))
+	if s.Username != "" {
+		messageProps = append(messageProps, flux.Property("username", flux.String(s.Username)))
+	}
+	if s.AvatarURL != "" {
+		messageProps = append(messageProps, flux.Property("avatar_url", flux.String(s.AvatarURL)))
+	}
+	endpointProps := []*ast.Property{}
+	endpointProps = append(endpointProps, flux.Property("headers", headers))
+	endpointProps = append(endpointProps, flux.Property("data",
+		flux.Call(flux.Member("json", "encode"), flux.Object(flux.Property("v", flux.Object(messageProps...))))))
+	endpointFn := flux.Function(flux.FunctionParams("r"), flux.Object(endpointProps...))
+
+	props := []*ast.Property{}
+	props = append(props, flux.Property("data", flux.Identifier("notification")))
+	props = append(props, flux.Property("endpoint",
+		flux.Call(flux.Identifier("discord_endpoint"), flux.Object(flux.Property("mapFn", endpointFn)))))
+
+	call := flux.Call(flux.Member("monitor", "notify"), flux.Object(props...))
+
+	return flux.ExpressionStatement(flux.Pipe(flux.Identifier("statuses"), call))
+}
+
+type discordAlias Discord
+
+// MarshalJSON implement json.Marshaler interface.
+func (c Discord) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			discordAlias
+			Type string `json:"type"`
+		}{
+			discordAlias: discordAlias(c),
+			Type:         c.Type(),
+		})
+}
+
+// Valid returns where the config is valid.
+func (c Discord) Valid() error {
+	if err := c.Base.valid(); err != nil {
+		return err
+	}
+	if c.URL.Key == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "discord webhook url must reference a secret",
+		}
+	}
+	if c.MessageTemplate == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "discord msg template is empty",
+		}
+	}
+	return nil
+}
+
+// Type returns the type of the rule config.
+func (c Discord) Type() string {
+	return "discord"
+}
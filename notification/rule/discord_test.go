@@ -0,0 +1,76 @@
+package rule_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/endpoint"
+	"github.com/influxdata/influxdb/notification/rule"
+)
+
+func TestDiscord_GenerateFlux(t *testing.T) {
+	want := `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "http"
+import "json"
+import "influxdata/influxdb/secrets"
+
+option task = {name: "foo", every: 1h}
+
+discord_url = secrets.get(key: "discord-webhook")
+discord_endpoint = http.endpoint(url: discord_url)
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor.from(start: -1h, fn: (r) =>
+	(r.foo == "bar"))
+
+statuses
+	|> monitor.notify(data: notification, endpoint: discord_endpoint(mapFn: (r) =>
+		({headers: {"Content-Type": "application/json"}, data: json.encode(v: {content: "blah blah", username: "influxdb", avatar_url: "http://localhost/avatar.png"})})))`
+
+	s := &rule.Discord{
+		Base: rule.Base{
+			ID:         1,
+			Name:       "foo",
+			Every:      mustDuration("1h"),
+			EndpointID: 2,
+			TagRules: []notification.TagRule{
+				{
+					Tag: notification.Tag{
+						Key:   "foo",
+						Value: "bar",
+					},
+					Operator: notification.Equal,
+				},
+			},
+		},
+		URL: influxdb.SecretField{
+			Key: "discord-webhook",
+		},
+		Username:        "influxdb",
+		AvatarURL:       "http://localhost/avatar.png",
+		MessageTemplate: "blah blah",
+	}
+
+	e := &endpoint.HTTP{
+		Base: endpoint.Base{
+			ID:   2,
+			Name: "foo",
+		},
+	}
+
+	f, err := s.GenerateFlux(e)
+	if err != nil {
+		panic(err)
+	}
+
+	if f != want {
+		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
+	}
+}
@@ -3,8 +3,13 @@ package rule
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
 
 	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/notification/endpoint"
 	"github.com/influxdata/influxdb/notification/flux"
@@ -13,8 +18,22 @@ import (
 // HTTP is the notification rule config of http.
 type HTTP struct {
 	Base
+	// Method is the http method used to send the notification, only POST is supported.
+	Method string `json:"method,omitempty"`
+	// URL overrides the URL of the endpoint when set.
+	URL string `json:"url,omitempty"`
+	// Headers are static headers sent with every notification.
+	Headers map[string]string `json:"headers,omitempty"`
+	// AuthHeader is the name of the header carrying AuthSecret, defaults to Authorization.
+	AuthHeader string               `json:"authHeader,omitempty"`
+	AuthSecret influxdb.SecretField `json:"authSecret,omitempty"`
+	// BodyTemplate is a flux string template of the body, e.g. "${r._level}: ${r._message}".
+	// The status record is json encoded as the body when it is empty.
+	BodyTemplate string `json:"bodyTemplate,omitempty"`
 }
 
+const defaultHTTPAuthHeader = "Authorization"
+
 // GenerateFlux generates a flux script for the http notification rule.
 func (s *HTTP) GenerateFlux(e influxdb.NotificationEndpoint) (string, error) {
 	httpEndpoint, ok := e.(*endpoint.HTTP)
@@ -30,35 +49,104 @@ func (s *HTTP) GenerateFlux(e influxdb.NotificationEndpoint) (string, error) {
 
 // GenerateFluxAST generates a flux AST for the http notification rule.
 func (s *HTTP) GenerateFluxAST(e *endpoint.HTTP) (*ast.Package, error) {
+	imports := []string{"influxdata/influxdb/monitor", "http"}
+	if s.BodyTemplate == "" {
+		imports = append(imports, "json")
+	}
+	if s.AuthSecret.Key != "" {
+		imports = append(imports, "influxdata/influxdb/secrets")
+	}
+	body, err := s.generateFluxASTBody(e)
+	if err != nil {
+		return nil, err
+	}
 	f := flux.File(
 		s.Name,
-		flux.Imports("influxdata/influxdb/monitor", "http", "json"),
-		s.generateFluxASTBody(e),
+		s.generateFluxASTImports(imports...),
+		body,
 	)
 	return &ast.Package{Package: "main", Files: []*ast.File{f}}, nil
 }
 
-func (s *HTTP) generateFluxASTBody(e *endpoint.HTTP) []ast.Statement {
+func (s *HTTP) generateFluxASTBody(e *endpoint.HTTP) ([]ast.Statement, error) {
 	var statements []ast.Statement
 	statements = append(statements, s.generateTaskOption())
+	if s.AuthSecret.Key != "" {
+		statements = append(statements, s.generateFluxASTSecrets())
+	}
 	statements = append(statements, s.generateFluxASTEndpoint(e))
 	statements = append(statements, s.generateFluxASTNotificationDefinition(e))
-	statements = append(statements, s.generateFluxASTStatuses())
-	statements = append(statements, s.generateFluxASTNotifyPipe())
+	statuses, err := s.generateFluxASTStatuses()
+	if err != nil {
+		return nil, err
+	}
+	statements = append(statements, statuses)
+	pipe, err := s.generateFluxASTNotifyPipe()
+	if err != nil {
+		return nil, err
+	}
+	statements = append(statements, pipe)
 
-	return statements
+	return statements, nil
+}
+
+func (s *HTTP) generateFluxASTSecrets() ast.Statement {
+	call := flux.Call(flux.Member("secrets", "get"), flux.Object(flux.Property("key", flux.String(s.AuthSecret.Key))))
+
+	return flux.DefineVariable("http_auth", call)
 }
 
 func (s *HTTP) generateFluxASTEndpoint(e *endpoint.HTTP) ast.Statement {
-	// TODO(desa): where does <some key> come from
-	call := flux.Call(flux.Member("http", "endpoint"), flux.Object(flux.Property("url", flux.String(e.URL))))
+	u := e.URL
+	if s.URL != "" {
+		u = s.URL
+	}
+	call := flux.Call(flux.Member("http", "endpoint"), flux.Object(flux.Property("url", flux.String(u))))
 
 	return flux.DefineVariable("endpoint", call)
 }
 
-func (s *HTTP) generateFluxASTNotifyPipe() ast.Statement {
+func (s *HTTP) generateFluxASTHeaders() *ast.ObjectExpression {
+	keys := make([]string, 0, len(s.Headers))
+	for k := range s.Headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	headers := flux.Object()
+	for _, k := range keys {
+		headers.Properties = append(headers.Properties, &ast.Property{Key: flux.String(k), Value: flux.String(s.Headers[k])})
+	}
+	if s.AuthSecret.Key != "" {
+		name := s.AuthHeader
+		if name == "" {
+			name = defaultHTTPAuthHeader
+		}
+		headers.Properties = append(headers.Properties, &ast.Property{Key: flux.String(name), Value: flux.Identifier("http_auth")})
+	}
+	return headers
+}
+
+func (s *HTTP) generateFluxASTData() (ast.Expression, error) {
+	if s.BodyTemplate == "" {
+		return flux.Call(flux.Member("json", "encode"), flux.Object(flux.Property("v", flux.Identifier("r")))), nil
+	}
+	body, err := parseStringTemplate(s.BodyTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return flux.Call(flux.Identifier("bytes"), flux.Object(flux.Property("v", body))), nil
+}
+
+func (s *HTTP) generateFluxASTNotifyPipe() (ast.Statement, error) {
 	endpointProps := []*ast.Property{}
-	endpointBody := flux.Call(flux.Member("json", "encode"), flux.Object(flux.Property("v", flux.Identifier("r"))))
+	if len(s.Headers) > 0 || s.AuthSecret.Key != "" {
+		endpointProps = append(endpointProps, flux.Property("headers", s.generateFluxASTHeaders()))
+	}
+	endpointBody, err := s.generateFluxASTData()
+	if err != nil {
+		return nil, err
+	}
 	endpointProps = append(endpointProps, flux.Property("data", endpointBody))
 	endpointFn := flux.Function(flux.FunctionParams("r"), flux.Object(endpointProps...))
 
@@ -70,7 +158,45 @@ func (s *HTTP) generateFluxASTNotifyPipe() ast.Statement {
 
 	call := flux.Call(flux.Member("monitor", "notify"), flux.Object(props...))
 
-	return flux.ExpressionStatement(flux.Pipe(flux.Identifier("statuses"), call))
+	return flux.ExpressionStatement(flux.Pipe(flux.Identifier("statuses"), call)), nil
+}
+
+// parseStringTemplate parses tmpl as the contents of a flux string literal,
+// so that ${...} interpolations can reference the status record r.
+func parseStringTemplate(tmpl string) (ast.Expression, error) {
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(tmpl)
+	pkg := parser.ParseSource(`body = "` + escaped + `"`)
+	if ast.Check(pkg) > 0 {
+		return nil, ast.GetError(pkg)
+	}
+	if len(pkg.Files) != 1 || len(pkg.Files[0].Body) != 1 {
+		return nil, fmt.Errorf("template must be a single string")
+	}
+	asmt, ok := pkg.Files[0].Body[0].(*ast.VariableAssignment)
+	if !ok {
+		return nil, fmt.Errorf("template must be a single string")
+	}
+	expr := asmt.Init.Copy().(ast.Expression)
+	clearBaseNodes(expr)
+	return expr, nil
+}
+
+// clearBaseNodes strips the source locations from a parsed string so it formats like generated nodes.
+func clearBaseNodes(e ast.Expression) {
+	switch n := e.(type) {
+	case *ast.StringLiteral:
+		n.BaseNode = ast.BaseNode{}
+	case *ast.StringExpression:
+		n.BaseNode = ast.BaseNode{}
+		for _, p := range n.Parts {
+			switch pt := p.(type) {
+			case *ast.TextPart:
+				pt.BaseNode = ast.BaseNode{}
+			case *ast.InterpolatedPart:
+				pt.BaseNode = ast.BaseNode{}
+			}
+		}
+	}
 }
 
 type httpAlias HTTP
@@ -92,6 +218,34 @@ func (c HTTP) Valid() error {
 	if err := c.Base.valid(); err != nil {
 		return err
 	}
+	if c.Method != "" && c.Method != http.MethodPost {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("http rule method %q is not supported, only POST", c.Method),
+		}
+	}
+	if c.URL != "" {
+		if _, err := url.Parse(c.URL); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("http rule URL is invalid: %s", err.Error()),
+			}
+		}
+	}
+	if c.AuthSecret.Key == "" && c.AuthSecret.Value != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "http rule auth header must reference a secret",
+		}
+	}
+	if c.BodyTemplate != "" {
+		if _, err := parseStringTemplate(c.BodyTemplate); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("http rule body template is invalid: %s", err.Error()),
+			}
+		}
+	}
 	return nil
 }
 
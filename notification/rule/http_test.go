@@ -3,6 +3,7 @@ package rule_test
 import (
 	"testing"
 
+	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/notification"
 	"github.com/influxdata/influxdb/notification/endpoint"
 	"github.com/influxdata/influxdb/notification/rule"
@@ -74,3 +75,60 @@ statuses
 		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
 	}
 }
+
+func TestHTTP_GenerateFlux_Templated(t *testing.T) {
+	want := `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "http"
+import "influxdata/influxdb/secrets"
+
+option task = {name: "foo", every: 1h}
+
+http_auth = secrets.get(key: "http-auth")
+endpoint = http.endpoint(url: "http://localhost:8888/alerts")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor.from(start: -1h)
+
+statuses
+	|> monitor.notify(name: "foo", data: notification, endpoint: endpoint(mapFn: (r) =>
+		({headers: {"Content-Type": "application/json", "X-Source": "influxdb", "Authorization": http_auth}, data: bytes(v: "{\"check\": \"${r._check_name}\", \"level\": \"${r._level}\"}")})))`
+
+	s := &rule.HTTP{
+		Base: rule.Base{
+			ID:         1,
+			Name:       "foo",
+			Every:      mustDuration("1h"),
+			EndpointID: 2,
+		},
+		URL: "http://localhost:8888/alerts",
+		Headers: map[string]string{
+			"X-Source":     "influxdb",
+			"Content-Type": "application/json",
+		},
+		AuthSecret:   influxdb.SecretField{Key: "http-auth"},
+		BodyTemplate: `{"check": "${r._check_name}", "level": "${r._level}"}`,
+	}
+
+	e := &endpoint.HTTP{
+		Base: endpoint.Base{
+			ID:   2,
+			Name: "foo",
+		},
+		URL: "http://localhost:7777",
+	}
+
+	f, err := s.GenerateFlux(e)
+	if err != nil {
+		panic(err)
+	}
+
+	if f != want {
+		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
+	}
+}
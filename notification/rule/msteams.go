@@ -0,0 +1,134 @@
+package rule
+
+import (
+	"encoding/json"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification/flux"
+)
+
+// MSTeams is the notification rule config of microsoft teams.
+type MSTeams struct {
+	Base
+	// URL is the secret holding the incoming webhook URL of the teams channel.
+	URL             influxdb.SecretField `json:"url"`
+	TitleTemplate   string               `json:"titleTemplate"`
+	MessageTemplate string               `json:"messageTemplate"`
+}
+
+// GenerateFlux generates a flux script for the microsoft teams notification rule.
+func (s *MSTeams) GenerateFlux(e influxdb.NotificationEndpoint) (string, error) {
+	p, err := s.GenerateFluxAST(e)
+	if err != nil {
+		return "", err
+	}
+	return ast.Format(p), nil
+}
+
+// GenerateFluxAST generates a flux AST for the microsoft teams notification rule.
+func (s *MSTeams) GenerateFluxAST(e influxdb.NotificationEndpoint) (*ast.Package, error) {
+	body, err := s.generateFluxASTBody(e)
+	if err != nil {
+		return nil, err
+	}
+	f := flux.File(
+		s.Name,
+		s.generateFluxASTImports("influxdata/influxdb/monitor", "http", "json", "influxdata/influxdb/secrets"),
+		body,
+	)
+	return &ast.Package{Package: "main", Files: []*ast.File{f}}, nil
+}
+
+func (s *MSTeams) generateFluxASTBody(e influxdb.NotificationEndpoint) ([]ast.Statement, error) {
+	var statements []ast.Statement
+	statements = append(statements, s.generateTaskOption())
+	statements = append(statements, s.generateFluxASTSecrets())
+	statements = append(statements, s.generateFluxASTEndpoint())
+	statements = append(statements, s.generateFluxASTNotificationDefinition(e))
+	statuses, err := s.generateFluxASTStatuses()
+	if err != nil {
+		return nil, err
+	}
+	statements = append(statements, statuses)
+	statements = append(statements, s.generateFluxASTNotifyPipe())
+
+	return statements, nil
+}
+
+func (s *MSTeams) generateFluxASTSecrets() ast.Statement {
+	call := flux.Call(flux.Member("secrets", "get"), flux.Object(flux.Property("key", flux.String(s.URL.Key))))
+
+	return flux.DefineVariable("msteams_url", call)
+}
+
+func (s *MSTeams) generateFluxASTEndpoint() ast.Statement {
+	call := flux.Call(flux.Member("http", "endpoint"), flux.Object(flux.Property("url", flux.Identifier("msteams_url"))))
+
+	return flux.DefineVariable("msteams_endpoint", call)
+}
+
+func (s *MSTeams) generateFluxASTNotifyPipe() ast.Statement {
+	// http.endpoint posts obj.data with obj.headers, so the card is json encoded here.
+	headers := flux.Object(&ast.Property{
+		Key:   flux.String("Content-Type"),
+		Value: flux.String("application/json"),
+	})
+	card := flux.Object(
+		flux.Property("title", flux.String(s.TitleTemplate)),
+		flux.Property("text", flux.String(s.MessageTemplate)),
+	)
+	endpointProps := []*ast.Property{}
+	endpointProps = append(endpointProps, flux.Property("headers", headers))
+	endpointProps = append(endpointProps, flux.Property("data",
+		flux.Call(flux.Member("json", "encode"), flux.Object(flux.Property("v", card)))))
+	endpointFn := flux.Function(flux.FunctionParams("r"), flux.Object(endpointProps...))
+
+	props := []*ast.Property{}
+	props = append(props, flux.Property("data", flux.Identifier("notification")))
+	props = append(props, flux.Property("endpoint",
+		flux.Call(flux.Identifier("msteams_endpoint"), flux.Object(flux.Property("mapFn", endpointFn)))))
+
+	call := flux.Call(flux.Member("monitor", "notify"), flux.Object(props...))
+
+	return flux.ExpressionStatement(flux.Pipe(flux.Identifier("statuses"), call))
+}
+
+type msTeamsAlias MSTeams
+
+// MarshalJSON implement json.Marshaler interface.
+func (c MSTeams) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			msTeamsAlias
+			Type string `json:"type"`
+		}{
+			msTeamsAlias: msTeamsAlias(c),
+			Type:         c.Type(),
+		})
+}
+
+// Valid returns where the config is valid.
+func (c MSTeams) Valid() error {
+	if err := c.Base.valid(); err != nil {
+		return err
+	}
+	if c.URL.Key == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "msteams webhook url must reference a secret",
+		}
+	}
+	if c.MessageTemplate == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "msteams msg template is empty",
+		}
+	}
+	return nil
+}
+
+// Type returns the type of the rule config.
+func (c MSTeams) Type() string {
+	return "msteams"
+}
@@ -15,8 +15,14 @@ var typeToRule = map[string](func() influxdb.NotificationRule){
 	"slack":     func() influxdb.NotificationRule { return &Slack{} },
 	"pagerduty": func() influxdb.NotificationRule { return &PagerDuty{} },
 	"http":      func() influxdb.NotificationRule { return &HTTP{} },
+	"msteams":   func() influxdb.NotificationRule { return &MSTeams{} },
+	"discord":   func() influxdb.NotificationRule { return &Discord{} },
 }
 
+// cooldownCountColumn is the column counting the statuses of each series and
+// level within the cooldown.
+const cooldownCountColumn = "_cooldown_count"
+
 type rawRuleJSON struct {
 	Typ string `json:"type"`
 }
@@ -56,10 +62,16 @@ type Base struct {
 	Every      *notification.Duration `json:"every,omitempty"`
 	// Offset represents a delay before execution.
 	// It gets marshalled from a string duration, i.e.: "10s" is 10 seconds
-	Offset      *notification.Duration    `json:"offset,omitempty"`
-	RunbookLink string                    `json:"runbookLink"`
-	TagRules    []notification.TagRule    `json:"tagRules,omitempty"`
-	StatusRules []notification.StatusRule `json:"statusRules,omitempty"`
+	Offset *notification.Duration `json:"offset,omitempty"`
+	// CooldownEvery suppresses repeated notifications: a status is only sent
+	// if its series had no status of the same level within this duration
+	// before it.
+	CooldownEvery *notification.Duration    `json:"cooldownEvery,omitempty"`
+	RunbookLink   string                    `json:"runbookLink"`
+	TagRules      []notification.TagRule    `json:"tagRules,omitempty"`
+	StatusRules   []notification.StatusRule `json:"statusRules,omitempty"`
+	// ActiveHours optionally restricts the notifications to a time window.
+	ActiveHours *ActiveHours `json:"activeHours,omitempty"`
 	*influxdb.Limit
 	influxdb.CRUDLog
 }
@@ -106,6 +118,17 @@ func (b Base) valid() error {
 			return err
 		}
 	}
+	if b.CooldownEvery != nil && !positiveDuration(b.CooldownEvery) {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "if cooldownEvery is set, it must be larger than 0",
+		}
+	}
+	if b.ActiveHours != nil {
+		if err := b.ActiveHours.Valid(); err != nil {
+			return err
+		}
+	}
 	if b.Limit != nil {
 		if b.Limit.Every <= 0 || b.Limit.Rate <= 0 {
 			return &influxdb.Error{
@@ -117,6 +140,19 @@ func (b Base) valid() error {
 
 	return nil
 }
+
+func positiveDuration(d *notification.Duration) bool {
+	if len(d.Values) == 0 {
+		return false
+	}
+	for _, v := range d.Values {
+		if v.Magnitude <= 0 {
+			return false
+		}
+	}
+	return true
+}
+
 func (b *Base) generateFluxASTNotificationDefinition(e influxdb.NotificationEndpoint) ast.Statement {
 	ruleID := flux.Property("_notification_rule_id", flux.String(b.ID.String()))
 	ruleName := flux.Property("_notification_rule_name", flux.String(b.Name))
@@ -146,23 +182,84 @@ func (b *Base) generateTaskOption() ast.Statement {
 	return flux.DefineTaskOption(flux.Object(props...))
 }
 
-func (b *Base) generateFluxASTStatuses() ast.Statement {
+func (b *Base) generateFluxASTImports(pkgs ...string) []*ast.ImportDeclaration {
+	if b.ActiveHours != nil {
+		pkgs = append(pkgs, "date")
+	}
+	return flux.Imports(pkgs...)
+}
+
+func (b *Base) generateFluxASTStatuses() (ast.Statement, error) {
 	props := []*ast.Property{}
 
-	props = append(props, flux.Property("start", flux.Negative((*ast.DurationLiteral)(b.Every))))
+	start := b.Every
+	if b.CooldownEvery != nil {
+		// Look back over the cooldown so repeated levels within it can be dropped.
+		start = b.CooldownEvery
+	}
+	props = append(props, flux.Property("start", flux.Negative((*ast.DurationLiteral)(start))))
 
 	if len(b.TagRules) > 0 {
-		r := b.TagRules[0]
-		var body ast.Expression = r.GenerateFluxAST()
-		for _, r := range b.TagRules[1:] {
-			body = flux.And(body, r.GenerateFluxAST())
+		var body ast.Expression
+		for i, r := range b.TagRules {
+			e, err := r.GenerateFluxAST()
+			if err != nil {
+				return nil, err
+			}
+			if i == 0 {
+				body = e
+				continue
+			}
+			body = flux.And(body, e)
 		}
 		props = append(props, flux.Property("fn", flux.Function(flux.FunctionParams("r"), body)))
 	}
 
 	base := flux.Call(flux.Member("monitor", "from"), flux.Object(props...))
 
-	return flux.DefineVariable("statuses", base)
+	var calls []*ast.CallExpression
+	if b.CooldownEvery != nil {
+		calls = append(calls, b.generateFluxASTCooldownCalls()...)
+	}
+	if b.ActiveHours != nil {
+		filter, err := b.ActiveHours.generateFluxASTFilter()
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, filter)
+	}
+	if len(calls) == 0 {
+		return flux.DefineVariable("statuses", base), nil
+	}
+
+	return flux.DefineVariable("statuses", flux.Pipe(base, calls...)), nil
+}
+
+// generateFluxASTCooldownCalls keeps the first status of each series and level
+// within the cooldown, and then only the statuses of the current run. The
+// level is part of the group key of the statuses, so a level seen earlier in the
+// cooldown has a count larger than 1.
+func (b *Base) generateFluxASTCooldownCalls() []*ast.CallExpression {
+	return []*ast.CallExpression{
+		flux.Call(flux.Identifier("stateCount"), flux.Object(
+			flux.Property("fn", flux.Function(flux.FunctionParams("r"), flux.Bool(true))),
+			flux.Property("column", flux.String(cooldownCountColumn)),
+		)),
+		flux.Call(flux.Identifier("filter"), flux.Object(
+			flux.Property("fn", flux.Function(flux.FunctionParams("r"),
+				flux.Equal(flux.Member("r", cooldownCountColumn), flux.Integer(1)),
+			)),
+		)),
+		flux.Call(flux.Identifier("drop"), flux.Object(
+			flux.Property("columns", &ast.ArrayExpression{
+				Elements: []ast.Expression{flux.String(cooldownCountColumn)},
+			}),
+		)),
+		// Only notify the statuses written since the last run.
+		flux.Call(flux.Identifier("range"), flux.Object(
+			flux.Property("start", flux.Negative((*ast.DurationLiteral)(b.Every))),
+		)),
+	}
 }
 
 // GetID implements influxdb.Getter interface.
@@ -126,6 +126,72 @@ func TestValidRule(t *testing.T) {
 				Msg:  "slack msg template is empty",
 			},
 		},
+		{
+			name: "unsupported http method",
+			src: &rule.HTTP{
+				Base:   goodBase,
+				Method: "PUT",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  `http rule method "PUT" is not supported, only POST`,
+			},
+		},
+		{
+			name: "http auth header without secret",
+			src: &rule.HTTP{
+				Base:       goodBase,
+				AuthSecret: influxdb.SecretField{Value: strPtr("token")},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "http rule auth header must reference a secret",
+			},
+		},
+		{
+			name: "msteams url without secret",
+			src: &rule.MSTeams{
+				Base:            goodBase,
+				MessageTemplate: "msg1",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "msteams webhook url must reference a secret",
+			},
+		},
+		{
+			name: "empty msteams message",
+			src: &rule.MSTeams{
+				Base: goodBase,
+				URL:  influxdb.SecretField{Key: "msteams-webhook"},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "msteams msg template is empty",
+			},
+		},
+		{
+			name: "discord url without secret",
+			src: &rule.Discord{
+				Base:            goodBase,
+				MessageTemplate: "msg1",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "discord webhook url must reference a secret",
+			},
+		},
+		{
+			name: "empty discord message",
+			src: &rule.Discord{
+				Base: goodBase,
+				URL:  influxdb.SecretField{Key: "discord-webhook"},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "discord msg template is empty",
+			},
+		},
 		{
 			name: "empty pagerDuty message",
 			src: &rule.PagerDuty{
@@ -193,6 +259,117 @@ func TestValidRule(t *testing.T) {
 				Msg:  `if limit is set, limit and limitEvery must be larger than 0`,
 			},
 		},
+		{
+			name: "zero cooldown",
+			src: &rule.Slack{
+				Base: rule.Base{
+					ID:            influxTesting.MustIDBase16(id1),
+					Name:          "name1",
+					OwnerID:       influxTesting.MustIDBase16(id2),
+					OrgID:         influxTesting.MustIDBase16(id3),
+					EndpointID:    1,
+					Status:        influxdb.Active,
+					CooldownEvery: mustDuration("0m"),
+				},
+				MessageTemplate: "body {var2}",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "if cooldownEvery is set, it must be larger than 0",
+			},
+		},
+		{
+			name: "invalid active hours timezone",
+			src: &rule.Slack{
+				Base: rule.Base{
+					ID:         influxTesting.MustIDBase16(id1),
+					Name:       "name1",
+					OwnerID:    influxTesting.MustIDBase16(id2),
+					OrgID:      influxTesting.MustIDBase16(id3),
+					EndpointID: 1,
+					Status:     influxdb.Active,
+					ActiveHours: &rule.ActiveHours{
+						Start:    "08:00",
+						End:      "17:00",
+						Timezone: "Mars/Olympus_Mons",
+					},
+				},
+				MessageTemplate: "body {var2}",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  `activeHours timezone "Mars/Olympus_Mons" is invalid`,
+			},
+		},
+		{
+			name: "active hours end equal to start",
+			src: &rule.Slack{
+				Base: rule.Base{
+					ID:         influxTesting.MustIDBase16(id1),
+					Name:       "name1",
+					OwnerID:    influxTesting.MustIDBase16(id2),
+					OrgID:      influxTesting.MustIDBase16(id3),
+					EndpointID: 1,
+					Status:     influxdb.Active,
+					ActiveHours: &rule.ActiveHours{
+						Days:  []string{"Monday"},
+						Start: "17:00",
+						End:   "17:00",
+					},
+				},
+				MessageTemplate: "body {var2}",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "activeHours end must differ from start",
+			},
+		},
+		{
+			name: "unsupported active hours timezone",
+			src: &rule.Slack{
+				Base: rule.Base{
+					ID:         influxTesting.MustIDBase16(id1),
+					Name:       "name1",
+					OwnerID:    influxTesting.MustIDBase16(id2),
+					OrgID:      influxTesting.MustIDBase16(id3),
+					EndpointID: 1,
+					Status:     influxdb.Active,
+					ActiveHours: &rule.ActiveHours{
+						Start:    "08:00",
+						End:      "17:00",
+						Timezone: "Africa/Casablanca",
+					},
+				},
+				MessageTemplate: "body {var2}",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  `activeHours timezone "Africa/Casablanca" is not supported`,
+			},
+		},
+		{
+			name: "invalid active hours day",
+			src: &rule.Slack{
+				Base: rule.Base{
+					ID:         influxTesting.MustIDBase16(id1),
+					Name:       "name1",
+					OwnerID:    influxTesting.MustIDBase16(id2),
+					OrgID:      influxTesting.MustIDBase16(id3),
+					EndpointID: 1,
+					Status:     influxdb.Active,
+					ActiveHours: &rule.ActiveHours{
+						Days:  []string{"Funday"},
+						Start: "08:00",
+						End:   "17:00",
+					},
+				},
+				MessageTemplate: "body {var2}",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  `activeHours day "Funday" is invalid`,
+			},
+		},
 	}
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
@@ -284,6 +461,92 @@ func TestJSON(t *testing.T) {
 				MessageTemplate: "msg1",
 			},
 		},
+		{
+			name: "simple http",
+			src: &rule.HTTP{
+				Base: rule.Base{
+					ID:          influxTesting.MustIDBase16(id1),
+					OwnerID:     influxTesting.MustIDBase16(id2),
+					Name:        "name1",
+					OrgID:       influxTesting.MustIDBase16(id3),
+					Status:      influxdb.Active,
+					RunbookLink: "runbooklink1",
+					SleepUntil:  &time3,
+					Every:       mustDuration("1h"),
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				Method:       "POST",
+				URL:          "http://localhost:8888/alerts",
+				Headers:      map[string]string{"X-Source": "influxdb"},
+				AuthHeader:   "X-Token",
+				AuthSecret:   influxdb.SecretField{Key: "http-auth"},
+				BodyTemplate: "${r._level}: ${r._message}",
+			},
+		},
+		{
+			name: "simple msteams",
+			src: &rule.MSTeams{
+				Base: rule.Base{
+					ID:          influxTesting.MustIDBase16(id1),
+					OwnerID:     influxTesting.MustIDBase16(id2),
+					Name:        "name1",
+					OrgID:       influxTesting.MustIDBase16(id3),
+					Status:      influxdb.Active,
+					RunbookLink: "runbooklink1",
+					SleepUntil:  &time3,
+					Every:       mustDuration("1h"),
+					TagRules: []notification.TagRule{
+						{
+							Tag: notification.Tag{
+								Key:   "k1",
+								Value: "v1",
+							},
+							Operator: notification.NotEqual,
+						},
+					},
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				URL:             influxdb.SecretField{Key: "msteams-webhook"},
+				TitleTemplate:   "title1",
+				MessageTemplate: "msg1",
+			},
+		},
+		{
+			name: "simple discord",
+			src: &rule.Discord{
+				Base: rule.Base{
+					ID:            influxTesting.MustIDBase16(id1),
+					OwnerID:       influxTesting.MustIDBase16(id2),
+					Name:          "name1",
+					OrgID:         influxTesting.MustIDBase16(id3),
+					Status:        influxdb.Active,
+					RunbookLink:   "runbooklink1",
+					SleepUntil:    &time3,
+					Every:         mustDuration("1h"),
+					CooldownEvery: mustDuration("30m"),
+					ActiveHours: &rule.ActiveHours{
+						Days:     []string{"Monday", "Friday"},
+						Start:    "09:00",
+						End:      "18:00",
+						Timezone: "Europe/Berlin",
+					},
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				URL:             influxdb.SecretField{Key: "discord-webhook"},
+				Username:        "influxdb",
+				AvatarURL:       "http://localhost/avatar.png",
+				MessageTemplate: "msg1",
+			},
+		},
 		{
 			name: "simple pagerDuty",
 			src: &rule.PagerDuty{
@@ -339,3 +602,7 @@ func TestJSON(t *testing.T) {
 		}
 	}
 }
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -32,24 +32,32 @@ func (s *Slack) GenerateFlux(e influxdb.NotificationEndpoint) (string, error) {
 
 // GenerateFluxAST generates a flux AST for the slack notification rule.
 func (s *Slack) GenerateFluxAST(e *endpoint.Slack) (*ast.Package, error) {
+	body, err := s.generateFluxASTBody(e)
+	if err != nil {
+		return nil, err
+	}
 	f := flux.File(
 		s.Name,
-		flux.Imports("influxdata/influxdb/monitor", "slack", "influxdata/influxdb/secrets"),
-		s.generateFluxASTBody(e),
+		s.generateFluxASTImports("influxdata/influxdb/monitor", "slack", "influxdata/influxdb/secrets"),
+		body,
 	)
 	return &ast.Package{Package: "main", Files: []*ast.File{f}}, nil
 }
 
-func (s *Slack) generateFluxASTBody(e *endpoint.Slack) []ast.Statement {
+func (s *Slack) generateFluxASTBody(e *endpoint.Slack) ([]ast.Statement, error) {
 	var statements []ast.Statement
 	statements = append(statements, s.generateTaskOption())
 	statements = append(statements, s.generateFluxASTSecrets(e))
 	statements = append(statements, s.generateFluxASTEndpoint(e))
 	statements = append(statements, s.generateFluxASTNotificationDefinition(e))
-	statements = append(statements, s.generateFluxASTStatuses())
+	statuses, err := s.generateFluxASTStatuses()
+	if err != nil {
+		return nil, err
+	}
+	statements = append(statements, statuses)
 	statements = append(statements, s.generateFluxASTNotifyPipe())
 
-	return statements
+	return statements, nil
 }
 
 func (s *Slack) generateFluxASTSecrets(e *endpoint.Slack) ast.Statement {
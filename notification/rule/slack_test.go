@@ -1,13 +1,25 @@
 package rule_test
 
 import (
+	"context"
+	"sort"
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/dependencies"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/flux/values"
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/notification"
 	"github.com/influxdata/influxdb/notification/endpoint"
 	"github.com/influxdata/influxdb/notification/rule"
+	_ "github.com/influxdata/influxdb/query/builtin"
 )
 
 func mustDuration(d string) *notification.Duration {
@@ -89,3 +101,524 @@ statuses
 		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
 	}
 }
+
+func TestSlack_GenerateFlux_TagRuleOperators(t *testing.T) {
+	want := `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "slack"
+import "influxdata/influxdb/secrets"
+
+option task = {name: "foo", every: 1h}
+
+slack_secret = secrets.get(key: "slack_token")
+slack_endpoint = slack.endpoint(token: slack_secret, url: "http://localhost:7777")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor.from(start: -1h, fn: (r) =>
+	(r.host =~ /server[0-9]+/ and r.region != "us-west" and r.env !~ /dev.*/))
+
+statuses
+	|> monitor.notify(data: notification, endpoint: slack_endpoint(mapFn: (r) =>
+		({channel: "bar", text: "blah"})))`
+
+	s := &rule.Slack{
+		Channel:         "bar",
+		MessageTemplate: "blah",
+		Base: rule.Base{
+			ID:         1,
+			EndpointID: 2,
+			Name:       "foo",
+			Every:      mustDuration("1h"),
+			TagRules: []notification.TagRule{
+				{
+					Tag: notification.Tag{
+						Key:   "host",
+						Value: "server[0-9]+",
+					},
+					Operator: notification.RegexEqual,
+				},
+				{
+					Tag: notification.Tag{
+						Key:   "region",
+						Value: "us-west",
+					},
+					Operator: notification.NotEqual,
+				},
+				{
+					Tag: notification.Tag{
+						Key:   "env",
+						Value: "dev.*",
+					},
+					Operator: notification.NotRegexEqual,
+				},
+			},
+		},
+	}
+	e := &endpoint.Slack{
+		Base: endpoint.Base{
+			ID:   2,
+			Name: "foo",
+		},
+		URL: "http://localhost:7777",
+		Token: influxdb.SecretField{
+			Key: "slack_token",
+		},
+	}
+
+	f, err := s.GenerateFlux(e)
+	if err != nil {
+		panic(err)
+	}
+
+	if f != want {
+		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
+	}
+}
+
+func TestSlack_GenerateFlux_Cooldown(t *testing.T) {
+	want := `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "slack"
+import "influxdata/influxdb/secrets"
+
+option task = {name: "foo", every: 1h}
+
+slack_secret = secrets.get(key: "slack_token")
+slack_endpoint = slack.endpoint(token: slack_secret, url: "http://localhost:7777")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor.from(start: -6h, fn: (r) =>
+	(r.foo == "bar"))
+	|> stateCount(fn: (r) =>
+		(true), column: "_cooldown_count")
+	|> filter(fn: (r) =>
+		(r._cooldown_count == 1))
+	|> drop(columns: ["_cooldown_count"])
+	|> range(start: -1h)
+
+statuses
+	|> monitor.notify(data: notification, endpoint: slack_endpoint(mapFn: (r) =>
+		({channel: "bar", text: "blah"})))`
+
+	s := &rule.Slack{
+		Channel:         "bar",
+		MessageTemplate: "blah",
+		Base: rule.Base{
+			ID:            1,
+			EndpointID:    2,
+			Name:          "foo",
+			Every:         mustDuration("1h"),
+			CooldownEvery: mustDuration("6h"),
+			TagRules: []notification.TagRule{
+				{
+					Tag: notification.Tag{
+						Key:   "foo",
+						Value: "bar",
+					},
+					Operator: notification.Equal,
+				},
+			},
+		},
+	}
+	e := &endpoint.Slack{
+		Base: endpoint.Base{
+			ID:   2,
+			Name: "foo",
+		},
+		URL: "http://localhost:7777",
+		Token: influxdb.SecretField{
+			Key: "slack_token",
+		},
+	}
+
+	f, err := s.GenerateFlux(e)
+	if err != nil {
+		panic(err)
+	}
+
+	if f != want {
+		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
+	}
+}
+
+func TestSlack_GenerateFlux_CooldownStatuses(t *testing.T) {
+	now := time.Date(2019, 10, 16, 12, 0, 0, 0, time.UTC)
+	// Each series and level is a table, as the level is a tag of the statuses.
+	input := `#datatype,string,long,dateTime:RFC3339,string,string,string,string
+#group,false,false,false,true,true,true,false
+#default,_result,,,,,,
+,result,table,_time,_level,foo,host,_message
+,,0,2019-10-16T08:00:00Z,crit,bar,a,repeated within the cooldown
+,,0,2019-10-16T11:30:00Z,crit,bar,a,repeated within the cooldown
+,,1,2019-10-16T08:00:00Z,ok,bar,b,level changed
+,,2,2019-10-16T11:30:00Z,crit,bar,b,level changed
+,,3,2019-10-16T04:00:00Z,crit,bar,c,repeated after the cooldown
+,,3,2019-10-16T11:30:00Z,crit,bar,c,repeated after the cooldown
+,,4,2019-10-16T11:10:00Z,crit,bar,d,repeated within the run
+,,4,2019-10-16T11:40:00Z,crit,bar,d,repeated within the run
+,,5,2019-10-16T10:00:00Z,crit,bar,e,before the run
+`
+
+	s := &rule.Slack{
+		Channel:         "bar",
+		MessageTemplate: "blah",
+		Base: rule.Base{
+			ID:            1,
+			EndpointID:    2,
+			Name:          "foo",
+			Every:         mustDuration("1h"),
+			CooldownEvery: mustDuration("6h"),
+			TagRules: []notification.TagRule{
+				{
+					Tag: notification.Tag{
+						Key:   "foo",
+						Value: "bar",
+					},
+					Operator: notification.Equal,
+				},
+			},
+		},
+	}
+	e := &endpoint.Slack{
+		Base: endpoint.Base{
+			ID:   2,
+			Name: "foo",
+		},
+		URL: "http://localhost:7777",
+		Token: influxdb.SecretField{
+			Key: "slack_token",
+		},
+	}
+
+	p, err := s.GenerateFluxAST(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var statuses ast.Statement
+	for _, stmt := range p.Files[0].Body {
+		if v, ok := stmt.(*ast.VariableAssignment); ok && v.ID.Name == "statuses" {
+			statuses = v
+		}
+	}
+	if statuses == nil {
+		t.Fatal("expected a statuses statement")
+	}
+	// Read the statuses from the input instead of the _monitoring bucket.
+	ast.Visit(statuses, func(n ast.Node) {
+		if call, ok := n.(*ast.CallExpression); ok {
+			if m, ok := call.Callee.(*ast.MemberExpression); ok && m.Property.Key() == "from" {
+				call.Callee = &ast.Identifier{Name: "statusesFrom"}
+			}
+		}
+	})
+	script := `import "csv"
+
+statusesFrom = (start, fn) => csv.from(csv: input)
+	|> range(start: start)
+	|> filter(fn: fn)
+
+` + ast.Format(statuses) + `
+
+statuses |> yield()`
+
+	prog, err := lang.Compile(script, now, lang.WithExtern(&ast.File{Body: []ast.Statement{
+		&ast.VariableAssignment{
+			ID:   &ast.Identifier{Name: "input"},
+			Init: &ast.StringLiteral{Value: input},
+		},
+	}}))
+	if err != nil {
+		t.Fatalf("unexpected error compiling %s: %v", script, err)
+	}
+	prog.SetExecutorDependencies(execute.Dependencies{dependencies.InterpreterDepsKey: dependencies.NewDefaults()})
+	q, err := prog.Start(context.Background(), &memory.Allocator{})
+	if err != nil {
+		t.Fatalf("unexpected error starting %s: %v", script, err)
+	}
+	defer q.Done()
+
+	var sent []string
+	for res := range q.Results() {
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			return tbl.Do(func(cr flux.ColReader) error {
+				host := execute.ColIdx("host", cr.Cols())
+				tm := execute.ColIdx("_time", cr.Cols())
+				for i := 0; i < cr.Len(); i++ {
+					ts := values.Time(cr.Times(tm).Value(i)).Time()
+					sent = append(sent, cr.Strings(host).ValueString(i)+"@"+ts.Format("15:04"))
+				}
+				return nil
+			})
+		}); err != nil {
+			t.Fatalf("unexpected error reading results: %v", err)
+		}
+	}
+	q.Done()
+	if err := q.Err(); err != nil {
+		t.Fatalf("unexpected error running %s: %v", script, err)
+	}
+
+	sort.Strings(sent)
+	if want := []string{"b@11:30", "c@11:30", "d@11:10"}; !cmp.Equal(want, sent) {
+		t.Errorf("unexpected statuses sent (-want +got):\n%s", cmp.Diff(want, sent))
+	}
+}
+
+func TestSlack_GenerateFlux_ActiveHours(t *testing.T) {
+	want := `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "slack"
+import "influxdata/influxdb/secrets"
+import "date"
+
+option task = {name: "foo", every: 1h}
+
+slack_secret = secrets.get(key: "slack_token")
+slack_endpoint = slack.endpoint(token: slack_secret, url: "http://localhost:7777")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor.from(start: -1h)
+	|> filter(fn: (r) => {
+		t = time(v: int(v: r._time) - 18000000000000)
+		wd = date.weekDay(t: t)
+		hm = date.hour(t: t) * 60 + date.minute(t: t)
+		d = date.monthDay(t: t)
+		ym = date.month(t: t) * 92160 + d * 1440 + hm
+		offset = if ym >= 276600 + (8 + (d - wd + 34) % 7) * 1440 and ym < 1013820 + (1 + (d - wd + 41) % 7) * 1440 then 60 else 0
+		m = (wd * 1440 + hm + offset + 10080) % 10080
+
+		return m >= 1920 and m < 2490 or m >= 480 and m < 1050
+	})
+
+statuses
+	|> monitor.notify(data: notification, endpoint: slack_endpoint(mapFn: (r) =>
+		({channel: "bar", text: "blah"})))`
+
+	s := &rule.Slack{
+		Channel:         "bar",
+		MessageTemplate: "blah",
+		Base: rule.Base{
+			ID:         1,
+			EndpointID: 2,
+			Name:       "foo",
+			Every:      mustDuration("1h"),
+			ActiveHours: &rule.ActiveHours{
+				Days:     []string{"Monday", "Sunday"},
+				Start:    "08:00",
+				End:      "17:30",
+				Timezone: "America/New_York",
+			},
+		},
+	}
+	e := &endpoint.Slack{
+		Base: endpoint.Base{
+			ID:   2,
+			Name: "foo",
+		},
+		URL: "http://localhost:7777",
+		Token: influxdb.SecretField{
+			Key: "slack_token",
+		},
+	}
+
+	f, err := s.GenerateFlux(e)
+	if err != nil {
+		panic(err)
+	}
+
+	if f != want {
+		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
+	}
+}
+
+func TestSlack_GenerateFlux_ActiveHoursStatuses(t *testing.T) {
+	tests := []struct {
+		name        string
+		activeHours *rule.ActiveHours
+		input       string
+		want        []string
+	}{
+		{
+			name: "daylight saving time",
+			activeHours: &rule.ActiveHours{
+				Days:     []string{"Monday"},
+				Start:    "09:00",
+				End:      "17:00",
+				Timezone: "America/New_York",
+			},
+			input: `#datatype,string,long,dateTime:RFC3339,string,string
+#group,false,false,false,true,false
+#default,_result,,,,
+,result,table,_time,host,_message
+,,0,2019-07-01T13:30:00Z,a,Monday 09:30 EDT
+,,1,2019-01-07T13:30:00Z,b,Monday 08:30 EST
+,,2,2019-01-07T14:30:00Z,c,Monday 09:30 EST
+,,3,2019-07-01T21:30:00Z,d,Monday 17:30 EDT
+,,4,2019-03-11T13:30:00Z,e,Monday 09:30 EDT after the change to daylight saving time
+,,5,2019-11-04T13:30:00Z,f,Monday 08:30 EST after the change to standard time
+`,
+			want: []string{"a", "c", "e"},
+		},
+		{
+			name: "southern hemisphere daylight saving time",
+			activeHours: &rule.ActiveHours{
+				Days:     []string{"Monday"},
+				Start:    "09:00",
+				End:      "17:00",
+				Timezone: "Australia/Sydney",
+			},
+			input: `#datatype,string,long,dateTime:RFC3339,string,string
+#group,false,false,false,true,false
+#default,_result,,,,
+,result,table,_time,host,_message
+,,0,2019-01-06T22:30:00Z,a,Monday 09:30 AEDT
+,,1,2019-07-07T22:30:00Z,b,Monday 08:30 AEST
+,,2,2019-07-07T23:30:00Z,c,Monday 09:30 AEST
+,,3,2019-01-07T06:30:00Z,d,Monday 17:30 AEDT
+,,4,2019-04-07T22:30:00Z,e,Monday 08:30 AEST after the change to standard time
+,,5,2019-10-06T22:30:00Z,f,Monday 09:30 AEDT after the change to daylight saving time
+`,
+			want: []string{"a", "c", "f"},
+		},
+		{
+			name: "overnight",
+			activeHours: &rule.ActiveHours{
+				Days:  []string{"Saturday"},
+				Start: "22:00",
+				End:   "06:00",
+			},
+			input: `#datatype,string,long,dateTime:RFC3339,string,string
+#group,false,false,false,true,false
+#default,_result,,,,
+,result,table,_time,host,_message
+,,0,2019-07-06T23:00:00Z,a,Saturday 23:00
+,,1,2019-07-07T05:30:00Z,b,Sunday 05:30
+,,2,2019-07-07T06:00:00Z,c,Sunday 06:00
+,,3,2019-07-06T21:30:00Z,d,Saturday 21:30
+,,4,2019-07-05T23:00:00Z,e,Friday 23:00
+,,5,2019-07-06T02:00:00Z,f,Saturday 02:00
+`,
+			want: []string{"a", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sent := activeHoursStatuses(t, tt.activeHours, tt.input)
+			if !cmp.Equal(tt.want, sent) {
+				t.Errorf("unexpected statuses sent (-want +got):\n%s", cmp.Diff(tt.want, sent))
+			}
+		})
+	}
+}
+
+// activeHoursStatuses returns the hosts of the statuses of input that the
+// filter of a rule with activeHours keeps.
+func activeHoursStatuses(t *testing.T, activeHours *rule.ActiveHours, input string) []string {
+	t.Helper()
+	now := time.Date(2019, 12, 31, 0, 0, 0, 0, time.UTC)
+	s := &rule.Slack{
+		Channel:         "bar",
+		MessageTemplate: "blah",
+		Base: rule.Base{
+			ID:          1,
+			EndpointID:  2,
+			Name:        "foo",
+			Every:       mustDuration("1h"),
+			ActiveHours: activeHours,
+		},
+	}
+	e := &endpoint.Slack{
+		Base: endpoint.Base{
+			ID:   2,
+			Name: "foo",
+		},
+		URL: "http://localhost:7777",
+		Token: influxdb.SecretField{
+			Key: "slack_token",
+		},
+	}
+
+	p, err := s.GenerateFluxAST(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var statuses ast.Statement
+	for _, stmt := range p.Files[0].Body {
+		if v, ok := stmt.(*ast.VariableAssignment); ok && v.ID.Name == "statuses" {
+			statuses = v
+		}
+	}
+	if statuses == nil {
+		t.Fatal("expected a statuses statement")
+	}
+	// Read all of the statuses from the input instead of the _monitoring bucket.
+	ast.Visit(statuses, func(n ast.Node) {
+		if call, ok := n.(*ast.CallExpression); ok {
+			if m, ok := call.Callee.(*ast.MemberExpression); ok && m.Property.Key() == "from" {
+				call.Callee = &ast.Identifier{Name: "statusesFrom"}
+			}
+		}
+	})
+	script := `import "csv"
+import "date"
+
+statusesFrom = (start) => csv.from(csv: input)
+	|> range(start: 2019-01-01T00:00:00Z)
+
+` + ast.Format(statuses) + `
+
+statuses |> yield()`
+
+	prog, err := lang.Compile(script, now, lang.WithExtern(&ast.File{Body: []ast.Statement{
+		&ast.VariableAssignment{
+			ID:   &ast.Identifier{Name: "input"},
+			Init: &ast.StringLiteral{Value: input},
+		},
+	}}))
+	if err != nil {
+		t.Fatalf("unexpected error compiling %s: %v", script, err)
+	}
+	prog.SetExecutorDependencies(execute.Dependencies{dependencies.InterpreterDepsKey: dependencies.NewDefaults()})
+	q, err := prog.Start(context.Background(), &memory.Allocator{})
+	if err != nil {
+		t.Fatalf("unexpected error starting %s: %v", script, err)
+	}
+	defer q.Done()
+
+	var sent []string
+	for res := range q.Results() {
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			return tbl.Do(func(cr flux.ColReader) error {
+				host := execute.ColIdx("host", cr.Cols())
+				for i := 0; i < cr.Len(); i++ {
+					sent = append(sent, cr.Strings(host).ValueString(i))
+				}
+				return nil
+			})
+		}); err != nil {
+			t.Fatalf("unexpected error reading results: %v", err)
+		}
+	}
+	q.Done()
+	if err := q.Err(); err != nil {
+		t.Fatalf("unexpected error running %s: %v", script, err)
+	}
+
+	sort.Strings(sent)
+	return sent
+}
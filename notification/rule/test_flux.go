@@ -0,0 +1,91 @@
+package rule
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification/flux"
+)
+
+// TestMessage is the message of the status sent by a test notification.
+const TestMessage = "This is a test notification from InfluxDB"
+
+// testCheckID is the check ID of the status sent by a test notification.
+const testCheckID = "0000000000000000"
+
+// GenerateTestFlux generates a flux script that sends a single synthetic critical
+// status through the notification rule to its endpoint. The script does not read
+// the statuses of the monitoring bucket nor log the notification to it.
+func GenerateTestFlux(nr influxdb.NotificationRule, e influxdb.NotificationEndpoint, now time.Time) (string, error) {
+	script, err := nr.GenerateFlux(e)
+	if err != nil {
+		return "", err
+	}
+
+	p := parser.ParseSource(script)
+	if ast.Check(p) > 0 {
+		return "", ast.GetError(p)
+	}
+	if len(p.Files) != 1 {
+		return "", fmt.Errorf("expected a single file in the notification rule script, got %d", len(p.Files))
+	}
+	f := p.Files[0]
+
+	var found bool
+	body := []ast.Statement{generateFluxASTTestLog()}
+	for _, s := range f.Body {
+		switch s := s.(type) {
+		case *ast.OptionStatement:
+			// The test is not run as a task.
+			if a, ok := s.Assignment.(*ast.VariableAssignment); ok && a.ID.Name == "task" {
+				continue
+			}
+		case *ast.VariableAssignment:
+			if s.ID.Name == "statuses" {
+				body = append(body, flux.DefineVariable("statuses", generateFluxASTTestStatuses(nr, now)))
+				found = true
+				continue
+			}
+		}
+		body = append(body, s)
+	}
+	if !found {
+		return "", fmt.Errorf("notification rule script does not define statuses")
+	}
+
+	f.Body = body
+	f.Imports = append(f.Imports, flux.ImportDeclaration("csv"))
+	return ast.Format(p), nil
+}
+
+// generateFluxASTTestLog discards the notifications instead of logging them.
+func generateFluxASTTestLog() ast.Statement {
+	return &ast.OptionStatement{
+		Assignment: &ast.MemberAssignment{
+			Member: flux.Member("monitor", "log"),
+			Init: flux.Function(
+				[]*ast.Property{{Key: flux.Identifier("tables"), Value: &ast.PipeLiteral{}}},
+				flux.Identifier("tables"),
+			),
+		},
+	}
+}
+
+func generateFluxASTTestStatuses(nr influxdb.NotificationRule, now time.Time) ast.Expression {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	w.WriteAll([][]string{
+		{"#datatype", "string", "long", "dateTime:RFC3339", "string", "string", "string", "string", "string"},
+		{"#group", "false", "false", "false", "true", "true", "true", "true", "false"},
+		{"#default", "_result", "", "", "", "", "", "", ""},
+		{"", "result", "table", "_time", "_measurement", "_check_id", "_check_name", "_level", "_message"},
+		{"", "", "0", now.UTC().Format(time.RFC3339Nano), "statuses", testCheckID, "Test check for " + nr.GetName(), "crit", TestMessage},
+	})
+
+	return flux.Call(flux.Member("csv", "from"), flux.Object(flux.Property("csv", flux.String(b.String()))))
+}
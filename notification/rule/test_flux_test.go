@@ -0,0 +1,71 @@
+package rule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification/endpoint"
+	"github.com/influxdata/influxdb/notification/rule"
+)
+
+func TestGenerateTestFlux(t *testing.T) {
+	want := `package main
+import "influxdata/influxdb/monitor"
+import "slack"
+import "influxdata/influxdb/secrets"
+import "csv"
+
+option monitor.log = (tables=<-) =>
+	(tables)
+
+slack_secret = secrets.get(key: "slack_token")
+slack_endpoint = slack.endpoint(token: slack_secret, url: "http://localhost:7777")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = csv.from(csv: "#datatype,string,long,dateTime:RFC3339,string,string,string,string,string
+#group,false,false,false,true,true,true,true,false
+#default,_result,,,,,,,
+,result,table,_time,_measurement,_check_id,_check_name,_level,_message
+,,0,2019-09-01T10:00:00Z,statuses,0000000000000000,Test check for foo,crit,This is a test notification from InfluxDB
+")
+
+statuses
+	|> monitor.notify(data: notification, endpoint: slack_endpoint(mapFn: (r) =>
+		({channel: "#alerts", text: "blah"})))`
+
+	s := &rule.Slack{
+		Channel:         "#alerts",
+		MessageTemplate: "blah",
+		Base: rule.Base{
+			ID:            1,
+			EndpointID:    2,
+			Name:          "foo",
+			Every:         mustDuration("1h"),
+			CooldownEvery: mustDuration("6h"),
+		},
+	}
+	e := &endpoint.Slack{
+		Base: endpoint.Base{
+			ID:   2,
+			Name: "foo",
+		},
+		URL: "http://localhost:7777",
+		Token: influxdb.SecretField{
+			Key: "slack_token",
+		},
+	}
+
+	f, err := rule.GenerateTestFlux(s, e, time.Date(2019, 9, 1, 10, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f != want {
+		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
+	}
+}
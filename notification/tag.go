@@ -2,6 +2,7 @@ package notification
 
 import (
 	"fmt"
+	"regexp"
 
 	"github.com/influxdata/flux/ast"
 	"github.com/influxdata/influxdb"
@@ -32,17 +33,24 @@ type TagRule struct {
 }
 
 // GenerateFluxAST generates the AST expression for a tag rule.
-func (r TagRule) GenerateFluxAST() ast.Expression {
+func (r TagRule) GenerateFluxAST() (ast.Expression, error) {
 	k := flux.Member("r", r.Key)
-	v := flux.String(r.Value)
 
 	switch r.Operator {
-	case Equal:
-		return flux.Equal(k, v)
-		// TODO(desa): have this work for all operator types
+	case NotEqual:
+		return flux.NotEqual(k, flux.String(r.Value)), nil
+	case RegexEqual, NotRegexEqual:
+		re, err := regexp.Compile(r.Value)
+		if err != nil {
+			return nil, invalidRegexError(r.Value, err)
+		}
+		if r.Operator == NotRegexEqual {
+			return flux.NotRegexpMatch(k, flux.Regexp(re)), nil
+		}
+		return flux.RegexpMatch(k, flux.Regexp(re)), nil
 	}
 
-	return flux.Equal(k, v)
+	return flux.Equal(k, flux.String(r.Value)), nil
 }
 
 // Operator is an Enum value of
@@ -74,5 +82,18 @@ func (tr TagRule) Valid() error {
 			Msg:  fmt.Sprintf(`Operator %q is invalid`, tr.Operator),
 		}
 	}
+	if tr.Operator == RegexEqual || tr.Operator == NotRegexEqual {
+		if _, err := regexp.Compile(tr.Value); err != nil {
+			return invalidRegexError(tr.Value, err)
+		}
+	}
 	return nil
 }
+
+func invalidRegexError(value string, err error) error {
+	return &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  fmt.Sprintf("tag value %q is not a valid regular expression", value),
+		Err:  err,
+	}
+}
@@ -49,6 +49,17 @@ func TestTagValid(t *testing.T) {
 				Msg:  "tag must contain a key and a value",
 			},
 		},
+		{
+			name: "invalid regex",
+			src: notification.TagRule{
+				Tag:      notification.Tag{Key: "k1", Value: "v1("},
+				Operator: notification.RegexEqual,
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "tag value \"v1(\" is not a valid regular expression",
+			},
+		},
 		{
 			name: "invalid operator",
 			src: notification.TagRule{
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/influxdata/flux/dependencies"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/influxdata/flux"
@@ -24,6 +25,7 @@ import (
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/storage"
 	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
 )
 
 // ToKind is the kind for the `to` flux function
@@ -34,32 +36,85 @@ const defaultFieldColLabel = "_field"
 const DefaultMeasurementColLabel = "_measurement"
 const DefaultBufferSize = 1 << 14
 
+// defaultTimePrecision is the precision points are written with when no
+// timePrecision is given to the `to` function.
+const defaultTimePrecision = "ns"
+
+// The ways the `to` function can handle rows that cannot be written.
+const (
+	// errorModeFail stops writing at the first invalid row.
+	errorModeFail = "fail"
+	// errorModeContinue skips invalid rows and reports them once all
+	// of the valid rows have been written.
+	errorModeContinue = "continue"
+)
+
+// The ways the `to` function can handle points that already exist.
+const (
+	// writeModeOverwrite writes every point, replacing existing values.
+	writeModeOverwrite = "overwrite"
+	// writeModeSkipExisting only writes the points that do not exist yet.
+	writeModeSkipExisting = "skipExisting"
+)
+
+// maxReportedRowErrors is the number of skipped rows described in the error
+// returned when the `to` function is run with errorMode "continue".
+const maxReportedRowErrors = 10
+
+// The backoff between retried writes starts at retryInitialBackoff and
+// doubles after every failed attempt, up to retryMaxBackoff.
+const (
+	retryInitialBackoff = 10 * time.Millisecond
+	retryMaxBackoff     = time.Second
+)
+
 // ToOpSpec is the flux.OperationSpec for the `to` flux function.
+//
+// FieldFn maps each row to a record of the fields written for it. Every
+// property of the record with a basic type becomes a field, and none of the
+// columns it references are inferred to be tags. Properties of other types,
+// such as nested records, are skipped.
 type ToOpSpec struct {
-	Bucket            string                       `json:"bucket"`
-	BucketID          string                       `json:"bucketID"`
-	Org               string                       `json:"org"`
-	OrgID             string                       `json:"orgID"`
-	Host              string                       `json:"host"`
-	Token             string                       `json:"token"`
-	TimeColumn        string                       `json:"timeColumn"`
-	MeasurementColumn string                       `json:"measurementColumn"`
-	TagColumns        []string                     `json:"tagColumns"`
-	FieldFn           interpreter.ResolvedFunction `json:"fieldFn"`
+	Bucket                string                       `json:"bucket"`
+	BucketID              string                       `json:"bucketID"`
+	Org                   string                       `json:"org"`
+	OrgID                 string                       `json:"orgID"`
+	Host                  string                       `json:"host"`
+	Token                 string                       `json:"token"`
+	TimeColumn            string                       `json:"timeColumn"`
+	MeasurementColumn     string                       `json:"measurementColumn"`
+	TagColumns            []string                     `json:"tagColumns"`
+	FieldFn               interpreter.ResolvedFunction `json:"fieldFn"`
+	TimePrecision         string                       `json:"timePrecision"`
+	BufferSize            int                          `json:"bufferSize"`
+	ErrorMode             string                       `json:"errorMode"`
+	KeepMeasurementColumn bool                         `json:"keepMeasurementColumn"`
+	MaxRetries            int                          `json:"maxRetries"`
+	Mode                  string                       `json:"mode"`
+	MaxTagCardinality     int                          `json:"maxTagCardinality"`
+	PreserveFieldOrder    bool                         `json:"preserveFieldOrder"`
 }
 
 func init() {
 	toSignature := flux.FunctionSignature(
 		map[string]semantic.PolyType{
-			"bucket":            semantic.String,
-			"bucketID":          semantic.String,
-			"org":               semantic.String,
-			"orgID":             semantic.String,
-			"host":              semantic.String,
-			"token":             semantic.String,
-			"timeColumn":        semantic.String,
-			"measurementColumn": semantic.String,
-			"tagColumns":        semantic.Array,
+			"bucket":                semantic.String,
+			"bucketID":              semantic.String,
+			"org":                   semantic.String,
+			"orgID":                 semantic.String,
+			"host":                  semantic.String,
+			"token":                 semantic.String,
+			"timeColumn":            semantic.String,
+			"measurementColumn":     semantic.String,
+			"tagColumns":            semantic.Array,
+			"timePrecision":         semantic.String,
+			"bufferSize":            semantic.Int,
+			"errorMode":             semantic.String,
+			"keepMeasurementColumn": semantic.Bool,
+			"maxRetries":            semantic.Int,
+			"mode":                  semantic.String,
+			"maxTagCardinality":     semantic.Int,
+			"preserveFieldOrder":    semantic.Bool,
 			"fieldFn": semantic.NewFunctionPolyType(semantic.FunctionPolySignature{
 				Parameters: map[string]semantic.PolyType{
 					"r": semantic.Tvar(1),
@@ -140,6 +195,72 @@ func (o *ToOpSpec) ReadArgs(args flux.Arguments) error {
 		}
 	}
 
+	if o.TimePrecision, ok, _ = args.GetString("timePrecision"); !ok {
+		o.TimePrecision = defaultTimePrecision
+	} else if !models.ValidPrecision(o.TimePrecision) {
+		return &flux.Error{
+			Code: codes.Invalid,
+			Msg:  fmt.Sprintf("invalid time precision %q: must be one of ns, us, ms or s", o.TimePrecision),
+		}
+	}
+
+	if bufferSize, ok, _ := args.GetInt("bufferSize"); !ok {
+		o.BufferSize = DefaultBufferSize
+	} else if bufferSize <= 0 {
+		return &flux.Error{
+			Code: codes.Invalid,
+			Msg:  fmt.Sprintf("buffer size must be greater than 0, got %d", bufferSize),
+		}
+	} else {
+		o.BufferSize = int(bufferSize)
+	}
+
+	if o.ErrorMode, ok, _ = args.GetString("errorMode"); !ok {
+		o.ErrorMode = errorModeFail
+	} else if o.ErrorMode != errorModeFail && o.ErrorMode != errorModeContinue {
+		return &flux.Error{
+			Code: codes.Invalid,
+			Msg:  fmt.Sprintf("invalid error mode %q: must be one of %q or %q", o.ErrorMode, errorModeFail, errorModeContinue),
+		}
+	}
+
+	if o.KeepMeasurementColumn, _, err = args.GetBool("keepMeasurementColumn"); err != nil {
+		return err
+	}
+
+	if o.PreserveFieldOrder, _, err = args.GetBool("preserveFieldOrder"); err != nil {
+		return err
+	}
+
+	if maxRetries, ok, _ := args.GetInt("maxRetries"); ok {
+		if maxRetries < 0 {
+			return &flux.Error{
+				Code: codes.Invalid,
+				Msg:  fmt.Sprintf("max retries must not be negative, got %d", maxRetries),
+			}
+		}
+		o.MaxRetries = int(maxRetries)
+	}
+
+	if o.Mode, ok, _ = args.GetString("mode"); !ok {
+		o.Mode = writeModeOverwrite
+	} else if o.Mode != writeModeOverwrite && o.Mode != writeModeSkipExisting {
+		return &flux.Error{
+			Code: codes.Invalid,
+			Msg:  fmt.Sprintf("invalid mode %q: must be one of %q or %q", o.Mode, writeModeOverwrite, writeModeSkipExisting),
+		}
+	}
+
+	if maxTagCardinality, ok, _ := args.GetInt("maxTagCardinality"); ok {
+		if maxTagCardinality <= 0 {
+			return &flux.Error{
+				Code: codes.Invalid,
+				Msg:  fmt.Sprintf("max tag cardinality must be greater than 0, got %d", maxTagCardinality),
+			}
+		}
+		o.MaxTagCardinality = int(maxTagCardinality)
+	}
+
 	return err
 }
 
@@ -223,16 +344,24 @@ func (o *ToProcedureSpec) Copy() plan.ProcedureSpec {
 	s := o.Spec
 	res := &ToProcedureSpec{
 		Spec: &ToOpSpec{
-			Bucket:            s.Bucket,
-			BucketID:          s.BucketID,
-			Org:               s.Org,
-			OrgID:             s.OrgID,
-			Host:              s.Host,
-			Token:             s.Token,
-			TimeColumn:        s.TimeColumn,
-			MeasurementColumn: s.MeasurementColumn,
-			TagColumns:        append([]string(nil), s.TagColumns...),
-			FieldFn:           s.FieldFn.Copy(),
+			Bucket:                s.Bucket,
+			BucketID:              s.BucketID,
+			Org:                   s.Org,
+			OrgID:                 s.OrgID,
+			Host:                  s.Host,
+			Token:                 s.Token,
+			TimeColumn:            s.TimeColumn,
+			MeasurementColumn:     s.MeasurementColumn,
+			TagColumns:            append([]string(nil), s.TagColumns...),
+			FieldFn:               s.FieldFn.Copy(),
+			TimePrecision:         s.TimePrecision,
+			BufferSize:            s.BufferSize,
+			ErrorMode:             s.ErrorMode,
+			KeepMeasurementColumn: s.KeepMeasurementColumn,
+			MaxRetries:            s.MaxRetries,
+			Mode:                  s.Mode,
+			MaxTagCardinality:     s.MaxTagCardinality,
+			PreserveFieldOrder:    s.PreserveFieldOrder,
 		},
 	}
 	return res
@@ -281,6 +410,11 @@ type ToTransformation struct {
 	deps               ToDependencies
 	ideps              dependencies.Interface
 	buf                *storage.BufferedPointsWriter
+	rowErrors          []error
+	stats              map[string]Stats
+	// tagSets holds the distinct tag sets written to each measurement when
+	// the tag cardinality is limited.
+	tagSets map[string]map[string]struct{}
 }
 
 // RetractTable retracts the table for the transformation for the `to` flux function.
@@ -305,7 +439,7 @@ func NewToTransformation(ctx context.Context, d execute.Dataset, cache execute.T
 		if !ok {
 			return nil, &flux.Error{
 				Code: codes.NotFound,
-				Msg:  fmt.Sprintf("failed to look up organization %q", spec.Org),
+				Msg:  fmt.Sprintf("organization %q not found", spec.Org),
 			}
 		}
 		orgID = &oID
@@ -326,10 +460,7 @@ func NewToTransformation(ctx context.Context, d execute.Dataset, cache execute.T
 	if spec.Bucket != "" {
 		bID, ok := deps.BucketLookup.Lookup(ctx, *orgID, spec.Bucket)
 		if !ok {
-			return nil, &flux.Error{
-				Code: codes.NotFound,
-				Msg:  fmt.Sprintf("failed to look up bucket %q in org %q", spec.Bucket, spec.Org),
-			}
+			return nil, bucketNotFoundError(ctx, deps.OrganizationLookup, spec, *orgID)
 		}
 		bucketID = &bID
 	} else if bucketID, err = platform.IDFromString(spec.BucketID); err != nil {
@@ -345,6 +476,16 @@ func NewToTransformation(ctx context.Context, d execute.Dataset, cache execute.T
 			Msg:  "You must specify org and bucket",
 		}
 	}
+	if spec.Mode == writeModeSkipExisting && deps.PointsFinder == nil {
+		return nil, &flux.Error{
+			Code: codes.Unimplemented,
+			Msg:  fmt.Sprintf("the %q mode of the `to` function is not supported", writeModeSkipExisting),
+		}
+	}
+	bufferSize := spec.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
 	return &ToTransformation{
 		Ctx:                ctx,
 		OrgID:              *orgID,
@@ -356,10 +497,30 @@ func NewToTransformation(ctx context.Context, d execute.Dataset, cache execute.T
 		implicitTagColumns: spec.TagColumns == nil,
 		deps:               deps,
 		ideps:              ideps,
-		buf:                storage.NewBufferedPointsWriter(DefaultBufferSize, deps.PointsWriter),
+		buf:                storage.NewBufferedPointsWriter(bufferSize, newRetryPointsWriter(deps.PointsWriter, spec.MaxRetries)),
+		stats:              make(map[string]Stats),
 	}, nil
 }
 
+// bucketNotFoundError returns the error for a bucket name that could not be
+// looked up in the org. When the org was given by ID, or taken from the
+// request, it tells whether the org itself does not exist.
+func bucketNotFoundError(ctx context.Context, orgs OrganizationLookup, spec *ToOpSpec, orgID platform.ID) error {
+	org := spec.Org
+	if org == "" {
+		if org = orgs.LookupName(ctx, orgID); org == "" {
+			return &flux.Error{
+				Code: codes.NotFound,
+				Msg:  fmt.Sprintf("organization with ID %s not found", orgID),
+			}
+		}
+	}
+	return &flux.Error{
+		Code: codes.NotFound,
+		Msg:  fmt.Sprintf("bucket %q not found in organization %q; it may belong to a different organization", spec.Bucket, org),
+	}
+}
+
 // Process does the actual work for the ToTransformation.
 func (t *ToTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
 	if t.implicitTagColumns {
@@ -417,10 +578,17 @@ func newFieldFunctionVisitor(rowParam string, cols []flux.ColMeta) *fieldFunctio
 // mapping field keys to values for each row r of the input. Visit records every column
 // that is referenced in `Function Body`. These columns are either directly or indirectly
 // used as value columns and as such need to be recorded so as not to be used as tag columns.
+// Nested records are not written as fields, so the columns referenced only by them are not
+// recorded.
 func (v *fieldFunctionVisitor) Visit(node semantic.Node) semantic.Visitor {
 	if v.visited[node] {
 		return v
 	}
+	if prop, ok := node.(*semantic.Property); ok {
+		if _, ok := prop.Value.(*semantic.ObjectExpression); ok {
+			return nil
+		}
+	}
 	if member, ok := node.(*semantic.MemberExpression); ok {
 		if obj, ok := member.Object.(*semantic.IdentifierExpression); ok {
 			if obj.Name == v.rowParam && v.columns[member.Property] {
@@ -463,9 +631,132 @@ func (t *ToTransformation) Finish(id execute.DatasetID, err error) {
 	if err == nil {
 		err = t.buf.Flush(t.Ctx)
 	}
+	if err == nil && len(t.rowErrors) > 0 {
+		err = t.skippedRowsError()
+	}
+	if err == nil {
+		t.logStats()
+	}
 	t.d.Finish(err)
 }
 
+// Stats returns the stats of the rows written so far, keyed by measurement.
+func (t *ToTransformation) Stats() map[string]Stats {
+	stats := make(map[string]Stats, len(t.stats))
+	for name, s := range t.stats {
+		stats[name] = s
+	}
+	return stats
+}
+
+// logStats logs the stats of each measurement written, if there is a logger.
+func (t *ToTransformation) logStats() {
+	if t.deps.Logger == nil {
+		return
+	}
+
+	names := make([]string, 0, len(t.stats))
+	for name := range t.stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		s := t.stats[name]
+		t.deps.Logger.Debug("Wrote measurement",
+			zap.String("org_id", t.OrgID.String()),
+			zap.String("bucket_id", t.BucketID.String()),
+			zap.String("measurement", name),
+			zap.Int("rows", s.NRows),
+			zap.Int("fields", s.NFields),
+			zap.Int("tags", s.NTags),
+			zap.Time("earliest", s.Earliest),
+			zap.Time("latest", s.Latest))
+	}
+}
+
+// skippedRowsError returns an error describing the rows skipped when
+// running with errorMode "continue".
+func (t *ToTransformation) skippedRowsError() error {
+	msgs := make([]string, 0, maxReportedRowErrors)
+	for _, err := range t.rowErrors {
+		if len(msgs) == maxReportedRowErrors {
+			msgs = append(msgs, fmt.Sprintf("and %d more", len(t.rowErrors)-maxReportedRowErrors))
+			break
+		}
+		msgs = append(msgs, err.Error())
+	}
+	return &flux.Error{
+		Code: codes.Invalid,
+		Msg:  fmt.Sprintf("skipped %d rows that could not be written: %s", len(t.rowErrors), strings.Join(msgs, "; ")),
+	}
+}
+
+// skipRow records err against the current row if invalid rows are being
+// skipped, and otherwise returns it.
+func (t *ToTransformation) skipRow(err error) error {
+	if t.spec.Spec.ErrorMode != errorModeContinue {
+		return err
+	}
+	t.rowErrors = append(t.rowErrors, err)
+	return nil
+}
+
+// retryPointsWriter retries the writes of a PointsWriter that fail with
+// a retryable error, backing off exponentially between attempts.
+type retryPointsWriter struct {
+	wr         storage.PointsWriter
+	maxRetries int
+}
+
+// newRetryPointsWriter returns wr if no retries are wanted and otherwise
+// wraps it in a retryPointsWriter.
+func newRetryPointsWriter(wr storage.PointsWriter, maxRetries int) storage.PointsWriter {
+	if maxRetries <= 0 {
+		return wr
+	}
+	return &retryPointsWriter{wr: wr, maxRetries: maxRetries}
+}
+
+// WritePoints writes the points to the underlying PointsWriter, retrying
+// up to maxRetries times.
+func (w *retryPointsWriter) WritePoints(ctx context.Context, points []models.Point) error {
+	backoff := retryInitialBackoff
+	for attempt := 0; ; attempt++ {
+		err := w.wr.WritePoints(ctx, points)
+		if err == nil || attempt == w.maxRetries || !isRetryableWriteError(err) {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		if backoff *= 2; backoff > retryMaxBackoff {
+			backoff = retryMaxBackoff
+		}
+	}
+}
+
+// isRetryableWriteError reports whether a failed write may succeed when
+// tried again. Writes rejected as invalid are never retried.
+func isRetryableWriteError(err error) bool {
+	switch err := err.(type) {
+	case tsdb.PartialWriteError, *tsdb.PartialWriteError:
+		return false
+	case *flux.Error:
+		return err.Code != codes.Invalid
+	case *platform.Error:
+		code := platform.ErrorCode(err)
+		return code != platform.EInvalid && code != platform.EUnprocessableEntity
+	}
+	return err != context.Canceled && err != context.DeadlineExceeded
+}
+
 // InjectToDependencies adds the To dependencies to the engine.
 func InjectToDependencies(depsMap execute.Dependencies, deps ToDependencies) error {
 	if err := deps.Validate(); err != nil {
@@ -480,6 +771,21 @@ type ToDependencies struct {
 	BucketLookup       BucketLookup
 	OrganizationLookup OrganizationLookup
 	PointsWriter       storage.PointsWriter
+
+	// PointsFinder is optional. It is required to run the `to`
+	// function with mode "skipExisting".
+	PointsFinder PointsFinder
+
+	// Logger is optional. When set, the stats of each measurement
+	// written by the `to` function are logged to it.
+	Logger *zap.Logger
+}
+
+// PointsFinder finds the points that have already been written.
+type PointsFinder interface {
+	// PointsExist reports, for each of the points, whether a value exists
+	// for the point's series and field at the point's time.
+	PointsExist(ctx context.Context, points []models.Point) ([]bool, error)
 }
 
 // Validate returns an error if any required field is unset.
@@ -496,6 +802,7 @@ func (d ToDependencies) Validate() error {
 	return nil
 }
 
+// Stats describes the rows written to a measurement by the `to` function.
 type Stats struct {
 	NRows    int
 	Latest   time.Time
@@ -504,13 +811,14 @@ type Stats struct {
 	NTags    int
 }
 
-func (s Stats) Update(o Stats) {
+// Update merges the stats o into s.
+func (s *Stats) Update(o Stats) {
 	s.NRows += o.NRows
-	if s.Latest.IsZero() || o.Latest.Unix() > s.Latest.Unix() {
+	if s.Latest.IsZero() || o.Latest.After(s.Latest) {
 		s.Latest = o.Latest
 	}
 
-	if s.Earliest.IsZero() || o.Earliest.Unix() < s.Earliest.Unix() {
+	if s.Earliest.IsZero() || o.Earliest.Before(s.Earliest) {
 		s.Earliest = o.Earliest
 	}
 
@@ -536,6 +844,14 @@ func writeTable(ctx context.Context, t *ToTransformation, tbl flux.Table) (err e
 		tagIdx := sort.SearchStrings(spec.TagColumns, col.Label)
 		isTag[i] = tagIdx < len(spec.TagColumns) && spec.TagColumns[tagIdx] == col.Label
 	}
+	// cache column positions when fields are written in table order
+	var colPos map[string]int
+	if spec.PreserveFieldOrder {
+		colPos = make(map[string]int, len(columns))
+		for i, col := range columns {
+			colPos[col.Label] = i
+		}
+	}
 	// do time
 	timeColLabel := spec.TimeColumn
 	timeColIdx := execute.ColIdx(timeColLabel, columns)
@@ -553,6 +869,12 @@ func writeTable(ctx context.Context, t *ToTransformation, tbl flux.Table) (err e
 		}
 	}
 
+	// points are truncated to the requested precision before being written
+	var precision time.Duration
+	if spec.TimePrecision != "" {
+		precision = time.Duration(models.GetPrecisionMultiplier(spec.TimePrecision))
+	}
+
 	// prepare field function if applicable and record the number of values to write per row
 	if spec.FieldFn.Fn != nil {
 		if err = t.fn.Prepare(columns); err != nil {
@@ -568,7 +890,6 @@ func writeTable(ctx context.Context, t *ToTransformation, tbl flux.Table) (err e
 		}
 	}
 
-	measurementStats := make(map[string]Stats)
 	measurementName := ""
 	return tbl.Do(func(er flux.ColReader) error {
 		var pointTime time.Time
@@ -596,10 +917,16 @@ func writeTable(ctx context.Context, t *ToTransformation, tbl flux.Table) (err e
 			}
 
 			if pointTime.IsZero() {
-				return &flux.Error{
+				if err := t.skipRow(&flux.Error{
 					Code: codes.Invalid,
 					Msg:  "timestamp missing from block",
+				}); err != nil {
+					return err
 				}
+				continue
+			}
+			if precision > time.Nanosecond {
+				pointTime = pointTime.Truncate(precision)
 			}
 
 			if measurementName == "" {
@@ -617,39 +944,11 @@ func writeTable(ctx context.Context, t *ToTransformation, tbl flux.Table) (err e
 				return err
 			}
 
-			fieldValues.Range(func(k string, v values.Value) {
-				if v.IsNull() {
-					fields[k] = nil
-					return
-				}
-				switch v.Type() {
-				case semantic.Float:
-					fields[k] = v.Float()
-				case semantic.Int:
-					fields[k] = v.Int()
-				case semantic.UInt:
-					fields[k] = v.UInt()
-				case semantic.String:
-					fields[k] = v.Str()
-				case semantic.Time:
-					fields[k] = v.Time()
-				case semantic.Bool:
-					fields[k] = v.Bool()
-				}
-			})
+			addFields(fields, fieldValues)
 
-			mstats := Stats{
-				NRows:    1,
-				Latest:   pointTime,
-				Earliest: pointTime,
-				NFields:  len(fields),
-				NTags:    len(tags),
-			}
-			_, ok := measurementStats[measurementName]
-			if !ok {
-				measurementStats[measurementName] = mstats
-			} else {
-				measurementStats[measurementName].Update(mstats)
+			// The measurement is only written as a field when asked for.
+			if spec.KeepMeasurementColumn {
+				fields[spec.MeasurementColumn] = measurementName
 			}
 
 			name := tsdb.EncodeNameString(t.OrgID, t.BucketID)
@@ -658,8 +957,13 @@ func writeTable(ctx context.Context, t *ToTransformation, tbl flux.Table) (err e
 			for k := range fields {
 				fieldNames = append(fieldNames, k)
 			}
-			sort.Strings(fieldNames)
+			if spec.PreserveFieldOrder {
+				sortFieldsByColumn(fieldNames, colPos)
+			} else {
+				sort.Strings(fieldNames)
+			}
 
+			rowPoints := make(models.Points, 0, len(fieldNames))
 			for _, k := range fieldNames {
 				v := fields[k]
 				pointTags := models.Tags{{Key: []byte("\x00"), Value: []byte(measurementName)}}
@@ -668,20 +972,137 @@ func writeTable(ctx context.Context, t *ToTransformation, tbl flux.Table) (err e
 
 				pt, err := models.NewPoint(name, pointTags, models.Fields{k: v}, pointTime)
 				if err != nil {
-					return err
+					rowPoints = nil
+					if err := t.skipRow(err); err != nil {
+						return err
+					}
+					break
 				}
-				points = append(points, pt)
+				rowPoints = append(rowPoints, pt)
 			}
+			if rowPoints == nil {
+				continue
+			}
+			points = append(points, rowPoints...)
+
+			if err := t.checkTagCardinality(measurementName, tags); err != nil {
+				return err
+			}
+
+			mstats := t.stats[measurementName]
+			mstats.Update(Stats{
+				NRows:    1,
+				Latest:   pointTime,
+				Earliest: pointTime,
+				NFields:  len(fields),
+				NTags:    len(tags),
+			})
+			t.stats[measurementName] = mstats
 
 			if err := execute.AppendRecord(i, er, builder); err != nil {
 				return err
 			}
 		}
 
+		if spec.Mode == writeModeSkipExisting {
+			if points, err = t.withoutExistingPoints(ctx, points); err != nil {
+				return err
+			}
+		}
 		return t.buf.WritePoints(ctx, points)
 	})
 }
 
+// checkTagCardinality records the tag set of a row written to the measurement
+// and returns an error once the measurement has more distinct tag sets than
+// allowed by maxTagCardinality.
+func (t *ToTransformation) checkTagCardinality(measurement string, tags models.Tags) error {
+	max := t.spec.Spec.MaxTagCardinality
+	if max <= 0 {
+		return nil
+	}
+	if t.tagSets == nil {
+		t.tagSets = make(map[string]map[string]struct{})
+	}
+	sets := t.tagSets[measurement]
+	if sets == nil {
+		sets = make(map[string]struct{})
+		t.tagSets[measurement] = sets
+	}
+	// The tags are in column order, which may differ between tables.
+	sorted := append(models.Tags(nil), tags...)
+	sort.Sort(sorted)
+	sets[string(sorted.HashKey())] = struct{}{}
+	if len(sets) > max {
+		return &flux.Error{
+			Code: codes.Invalid,
+			Msg:  fmt.Sprintf("measurement %q exceeds the max tag cardinality of %d", measurement, max),
+		}
+	}
+	return nil
+}
+
+// withoutExistingPoints returns the points that have not been written yet.
+func (t *ToTransformation) withoutExistingPoints(ctx context.Context, points models.Points) (models.Points, error) {
+	if len(points) == 0 {
+		return points, nil
+	}
+	exist, err := t.deps.PointsFinder.PointsExist(ctx, points)
+	if err != nil {
+		return nil, err
+	}
+	filtered := points[:0]
+	for i, p := range points {
+		if !exist[i] {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered, nil
+}
+
+// sortFieldsByColumn orders names by the position of the column with the
+// same label, as given by pos. Names without a matching column, such as
+// fields computed by the field function, follow in alphabetical order.
+func sortFieldsByColumn(names []string, pos map[string]int) {
+	sort.Slice(names, func(i, j int) bool {
+		pi, iok := pos[names[i]]
+		pj, jok := pos[names[j]]
+		switch {
+		case iok && jok:
+			return pi < pj
+		case iok != jok:
+			return iok
+		default:
+			return names[i] < names[j]
+		}
+	})
+}
+
+// addFields adds each property of obj with a basic type to fields. Properties
+// of other types, such as nested records, are skipped.
+func addFields(fields models.Fields, obj values.Object) {
+	obj.Range(func(k string, v values.Value) {
+		if v.IsNull() {
+			fields[k] = nil
+			return
+		}
+		switch v.Type() {
+		case semantic.Float:
+			fields[k] = v.Float()
+		case semantic.Int:
+			fields[k] = v.Int()
+		case semantic.UInt:
+			fields[k] = v.UInt()
+		case semantic.String:
+			fields[k] = v.Str()
+		case semantic.Time:
+			fields[k] = v.Time()
+		case semantic.Bool:
+			fields[k] = v.Bool()
+		}
+	})
+}
+
 func defaultFieldMapping(er flux.ColReader, row int) (values.Object, error) {
 	fieldColumnIdx := execute.ColIdx(defaultFieldColLabel, er.Cols())
 	valueColumnIdx := execute.ColIdx(execute.DefaultValueColLabel, er.Cols())
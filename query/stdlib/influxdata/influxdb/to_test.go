@@ -2,18 +2,24 @@ package influxdb_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/codes"
 	"github.com/influxdata/flux/dependencies/dependenciestest"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/execute/executetest"
 	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/querytest"
 	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
 	"github.com/influxdata/flux/values/valuestest"
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/mock"
@@ -22,6 +28,8 @@ import (
 	pquerytest "github.com/influxdata/influxdb/query/querytest"
 	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
 	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestTo_Query(t *testing.T) {
@@ -46,6 +54,10 @@ func TestTo_Query(t *testing.T) {
 							Token:             "auth-token",
 							TimeColumn:        execute.DefaultTimeColLabel,
 							MeasurementColumn: influxdb.DefaultMeasurementColLabel,
+							TimePrecision:     "ns",
+							BufferSize:        influxdb.DefaultBufferSize,
+							ErrorMode:         "fail",
+							Mode:              "overwrite",
 							FieldFn: interpreter.ResolvedFunction{
 								Scope: valuestest.NowScope(),
 								Fn: &semantic.FunctionExpression{
@@ -79,6 +91,158 @@ func TestTo_Query(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "with time precision",
+			Raw:  `from(bucket:"mydb") |> to(bucket:"series1", org:"fred", timePrecision: "s")`,
+			Want: &flux.Spec{
+				Operations: []*flux.Operation{
+					{
+						ID: "influxDBFrom0",
+						Spec: &influxdb.FromOpSpec{
+							Bucket: "mydb",
+						},
+					},
+					{
+						ID: "to1",
+						Spec: &influxdb.ToOpSpec{
+							Bucket:            "series1",
+							Org:               "fred",
+							TimeColumn:        execute.DefaultTimeColLabel,
+							MeasurementColumn: influxdb.DefaultMeasurementColLabel,
+							TimePrecision:     "s",
+							BufferSize:        influxdb.DefaultBufferSize,
+							ErrorMode:         "fail",
+							Mode:              "overwrite",
+						},
+					},
+				},
+				Edges: []flux.Edge{
+					{Parent: "influxDBFrom0", Child: "to1"},
+				},
+			},
+		},
+		{
+			Name: "with buffer size",
+			Raw:  `from(bucket:"mydb") |> to(bucket:"series1", org:"fred", bufferSize: 100)`,
+			Want: &flux.Spec{
+				Operations: []*flux.Operation{
+					{
+						ID: "influxDBFrom0",
+						Spec: &influxdb.FromOpSpec{
+							Bucket: "mydb",
+						},
+					},
+					{
+						ID: "to1",
+						Spec: &influxdb.ToOpSpec{
+							Bucket:            "series1",
+							Org:               "fred",
+							TimeColumn:        execute.DefaultTimeColLabel,
+							MeasurementColumn: influxdb.DefaultMeasurementColLabel,
+							TimePrecision:     "ns",
+							BufferSize:        100,
+							ErrorMode:         "fail",
+							Mode:              "overwrite",
+						},
+					},
+				},
+				Edges: []flux.Edge{
+					{Parent: "influxDBFrom0", Child: "to1"},
+				},
+			},
+		},
+		{
+			Name:    "with invalid buffer size",
+			Raw:     `from(bucket:"mydb") |> to(bucket:"series1", org:"fred", bufferSize: 0)`,
+			WantErr: true,
+		},
+		{
+			Name: "with keep measurement column",
+			Raw:  `from(bucket:"mydb") |> to(bucket:"series1", org:"fred", keepMeasurementColumn: true)`,
+			Want: &flux.Spec{
+				Operations: []*flux.Operation{
+					{
+						ID: "influxDBFrom0",
+						Spec: &influxdb.FromOpSpec{
+							Bucket: "mydb",
+						},
+					},
+					{
+						ID: "to1",
+						Spec: &influxdb.ToOpSpec{
+							Bucket:                "series1",
+							Org:                   "fred",
+							TimeColumn:            execute.DefaultTimeColLabel,
+							MeasurementColumn:     influxdb.DefaultMeasurementColLabel,
+							TimePrecision:         "ns",
+							BufferSize:            influxdb.DefaultBufferSize,
+							ErrorMode:             "fail",
+							Mode:                  "overwrite",
+							KeepMeasurementColumn: true,
+						},
+					},
+				},
+				Edges: []flux.Edge{
+					{Parent: "influxDBFrom0", Child: "to1"},
+				},
+			},
+		},
+		{
+			Name: "with max retries",
+			Raw:  `from(bucket:"mydb") |> to(bucket:"series1", org:"fred", maxRetries: 3)`,
+			Want: &flux.Spec{
+				Operations: []*flux.Operation{
+					{
+						ID: "influxDBFrom0",
+						Spec: &influxdb.FromOpSpec{
+							Bucket: "mydb",
+						},
+					},
+					{
+						ID: "to1",
+						Spec: &influxdb.ToOpSpec{
+							Bucket:            "series1",
+							Org:               "fred",
+							TimeColumn:        execute.DefaultTimeColLabel,
+							MeasurementColumn: influxdb.DefaultMeasurementColLabel,
+							TimePrecision:     "ns",
+							BufferSize:        influxdb.DefaultBufferSize,
+							ErrorMode:         "fail",
+							MaxRetries:        3,
+							Mode:              "overwrite",
+						},
+					},
+				},
+				Edges: []flux.Edge{
+					{Parent: "influxDBFrom0", Child: "to1"},
+				},
+			},
+		},
+		{
+			Name:    "with negative max retries",
+			Raw:     `from(bucket:"mydb") |> to(bucket:"series1", org:"fred", maxRetries: -1)`,
+			WantErr: true,
+		},
+		{
+			Name:    "with invalid mode",
+			Raw:     `from(bucket:"mydb") |> to(bucket:"series1", org:"fred", mode: "append")`,
+			WantErr: true,
+		},
+		{
+			Name:    "with invalid max tag cardinality",
+			Raw:     `from(bucket:"mydb") |> to(bucket:"series1", org:"fred", maxTagCardinality: 0)`,
+			WantErr: true,
+		},
+		{
+			Name:    "with invalid error mode",
+			Raw:     `from(bucket:"mydb") |> to(bucket:"series1", org:"fred", errorMode: "ignore")`,
+			WantErr: true,
+		},
+		{
+			Name:    "with invalid time precision",
+			Raw:     `from(bucket:"mydb") |> to(bucket:"series1", org:"fred", timePrecision: "h")`,
+			WantErr: true,
+		},
 	}
 	for _, tc := range tests {
 		tc := tc
@@ -89,6 +253,21 @@ func TestTo_Query(t *testing.T) {
 	}
 }
 
+func TestToOpSpec_ReadArgs_InvalidTimePrecision(t *testing.T) {
+	args := flux.Arguments{Arguments: interpreter.NewArguments(values.NewObjectWithValues(map[string]values.Value{
+		"bucket":        values.NewString("series1"),
+		"timePrecision": values.NewString("h"),
+	}))}
+
+	err := new(influxdb.ToOpSpec).ReadArgs(args)
+	if err == nil {
+		t.Fatal("expected error: got nil")
+	}
+	if got, want := flux.ErrorCode(err), codes.Invalid; got != want {
+		t.Fatalf("unexpected error code: got %v, want %v", got, want)
+	}
+}
+
 func TestToOpSpec_BucketsAccessed(t *testing.T) {
 	bucketName := "my_bucket"
 	bucketIDString := "ddddccccbbbbaaaa"
@@ -678,6 +857,108 @@ c,tag2=e humidity=65i,temperature=4 41`),
 				}},
 			},
 		},
+		{
+			name: "nested records in field function are skipped",
+			spec: &influxdb.ToProcedureSpec{
+				Spec: &influxdb.ToOpSpec{
+					Org:               "my-org",
+					Bucket:            "my-bucket",
+					TimeColumn:        "_time",
+					MeasurementColumn: "_measurement",
+					FieldFn: interpreter.ResolvedFunction{
+						Scope: valuestest.NowScope(),
+						Fn: &semantic.FunctionExpression{
+							Block: &semantic.FunctionBlock{
+								Parameters: &semantic.FunctionParameters{
+									List: []*semantic.FunctionParameter{
+										{
+											Key: &semantic.Identifier{Name: "r"},
+										},
+									},
+								},
+								Body: &semantic.ObjectExpression{
+									Properties: []*semantic.Property{
+										{
+											Key: &semantic.Identifier{Name: "status"},
+											Value: &semantic.MemberExpression{
+												Object:   &semantic.IdentifierExpression{Name: "r"},
+												Property: "status",
+											},
+										},
+										{
+											Key: &semantic.Identifier{Name: "load"},
+											Value: &semantic.ObjectExpression{
+												Properties: []*semantic.Property{
+													{
+														Key: &semantic.Identifier{Name: "user"},
+														Value: &semantic.MemberExpression{
+															Object:   &semantic.IdentifierExpression{Name: "r"},
+															Property: "user",
+														},
+													},
+													{
+														Key: &semantic.Identifier{Name: "system"},
+														Value: &semantic.MemberExpression{
+															Object:   &semantic.IdentifierExpression{Name: "r"},
+															Property: "system",
+														},
+													},
+													{
+														Key: &semantic.Identifier{Name: "region"},
+														Value: &semantic.MemberExpression{
+															Object:   &semantic.IdentifierExpression{Name: "r"},
+															Property: "region",
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			data: []flux.Table{executetest.MustCopyTable(&executetest.Table{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_measurement", Type: flux.TString},
+					{Label: "host", Type: flux.TString},
+					{Label: "region", Type: flux.TString},
+					{Label: "status", Type: flux.TString},
+					{Label: "user", Type: flux.TFloat},
+					{Label: "system", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{execute.Time(11), "cpu", "a", "east", "ok", 2.0, 1.0},
+					{execute.Time(21), "cpu", "b", "west", "busy", 4.0, 3.0},
+				},
+			})},
+			want: wanted{
+				result: &mock.PointsWriter{
+					// The columns referenced only by the nested record are
+					// not written as fields, so region is still a tag.
+					Points: mockPoints(oid, bid, `cpu,host=a,region=east status="ok" 11
+cpu,host=b,region=west status="busy" 21`),
+				},
+				tables: []*executetest.Table{{
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "host", Type: flux.TString},
+						{Label: "region", Type: flux.TString},
+						{Label: "status", Type: flux.TString},
+						{Label: "user", Type: flux.TFloat},
+						{Label: "system", Type: flux.TFloat},
+					},
+					Data: [][]interface{}{
+						{execute.Time(11), "cpu", "a", "east", "ok", 2.0, 1.0},
+						{execute.Time(21), "cpu", "b", "west", "busy", 4.0, 3.0},
+					},
+				}},
+			},
+		},
 		{
 			name: "multiple _field",
 			spec: &influxdb.ToProcedureSpec{
@@ -732,6 +1013,95 @@ c _hello=4 41`),
 				}},
 			},
 		},
+		{
+			name: "keep measurement column",
+			spec: &influxdb.ToProcedureSpec{
+				Spec: &influxdb.ToOpSpec{
+					Org:                   "my-org",
+					Bucket:                "my-bucket",
+					TimeColumn:            "_time",
+					MeasurementColumn:     "_measurement",
+					KeepMeasurementColumn: true,
+				},
+			},
+			data: []flux.Table{executetest.MustCopyTable(&executetest.Table{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_measurement", Type: flux.TString},
+					{Label: "_field", Type: flux.TString},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{execute.Time(11), "a", "_value", 2.0},
+					{execute.Time(21), "b", "_value", 1.0},
+				},
+			})},
+			want: wanted{
+				result: &mock.PointsWriter{
+					Points: mockPoints(oid, bid, `a _measurement="a" 11
+a _value=2 11
+b _measurement="b" 21
+b _value=1 21`),
+				},
+				tables: []*executetest.Table{{
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "_field", Type: flux.TString},
+						{Label: "_value", Type: flux.TFloat},
+					},
+					Data: [][]interface{}{
+						{execute.Time(11), "a", "_value", 2.0},
+						{execute.Time(21), "b", "_value", 1.0},
+					},
+				}},
+			},
+		},
+		{
+			name: "truncated to time precision",
+			spec: &influxdb.ToProcedureSpec{
+				Spec: &influxdb.ToOpSpec{
+					Org:               "my-org",
+					Bucket:            "my-bucket",
+					TimeColumn:        "_time",
+					MeasurementColumn: "_measurement",
+					TimePrecision:     "s",
+				},
+			},
+			data: []flux.Table{executetest.MustCopyTable(&executetest.Table{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_measurement", Type: flux.TString},
+					{Label: "_field", Type: flux.TString},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1000000000), "a", "_value", 2.0},
+					{execute.Time(1999999999), "a", "_value", 2.0},
+					{execute.Time(2000000001), "b", "_value", 1.0},
+				},
+			})},
+			want: wanted{
+				result: &mock.PointsWriter{
+					Points: mockPoints(oid, bid, `a _value=2 1000000000
+a _value=2 1000000000
+b _value=1 2000000000`),
+				},
+				tables: []*executetest.Table{{
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "_field", Type: flux.TString},
+						{Label: "_value", Type: flux.TFloat},
+					},
+					Data: [][]interface{}{
+						{execute.Time(1000000000), "a", "_value", 2.0},
+						{execute.Time(1999999999), "a", "_value", 2.0},
+						{execute.Time(2000000001), "b", "_value", 1.0},
+					},
+				}},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -766,6 +1136,762 @@ c _hello=4 41`),
 	}
 }
 
+func TestTo_Process_BufferSize(t *testing.T) {
+	deps := mockDependencies()
+	spec := &influxdb.ToProcedureSpec{
+		Spec: &influxdb.ToOpSpec{
+			Org:               "my-org",
+			Bucket:            "my-bucket",
+			TimeColumn:        "_time",
+			MeasurementColumn: "_measurement",
+			BufferSize:        2,
+		},
+	}
+
+	c := execute.NewTableBuilderCache(executetest.UnlimitedAllocator)
+	c.SetTriggerSpec(plan.DefaultTriggerSpec)
+	d := executetest.NewDataset(executetest.RandomDatasetID())
+	tr, err := influxdb.NewToTransformation(context.Background(), d, c, spec, deps, dependenciestest.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each table holds a single point, so a full buffer is flushed while
+	// processing every other table.
+	parentID := executetest.RandomDatasetID()
+	for i := 0; i < 5; i++ {
+		tbl := executetest.MustCopyTable(&executetest.Table{
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_measurement", Type: flux.TString},
+				{Label: "_field", Type: flux.TString},
+				{Label: "_value", Type: flux.TFloat},
+			},
+			KeyCols: []string{"_measurement"},
+			Data: [][]interface{}{
+				{execute.Time(i), fmt.Sprintf("m%d", i), "_value", float64(i)},
+			},
+		})
+		if err := tr.Process(parentID, tbl); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pw := deps.PointsWriter.(*mock.PointsWriter)
+	if got, want := pw.WritePointsCalled(), 2; got != want {
+		t.Fatalf("unexpected number of flushes during process: got %d, want %d", got, want)
+	}
+
+	tr.Finish(parentID, nil)
+	if got, want := pw.WritePointsCalled(), 3; got != want {
+		t.Fatalf("unexpected number of flushes after finish: got %d, want %d", got, want)
+	}
+	if got, want := len(pw.Points), 5; got != want {
+		t.Fatalf("unexpected number of points written: got %d, want %d", got, want)
+	}
+}
+
+func TestTo_Process_PreserveFieldOrder(t *testing.T) {
+	deps := mockDependencies()
+	spec := &influxdb.ToProcedureSpec{
+		Spec: &influxdb.ToOpSpec{
+			Org:                "my-org",
+			Bucket:             "my-bucket",
+			TimeColumn:         "_time",
+			MeasurementColumn:  "_measurement",
+			PreserveFieldOrder: true,
+			FieldFn: interpreter.ResolvedFunction{
+				Scope: valuestest.NowScope(),
+				Fn: &semantic.FunctionExpression{
+					Block: &semantic.FunctionBlock{
+						Parameters: &semantic.FunctionParameters{
+							List: []*semantic.FunctionParameter{
+								{
+									Key: &semantic.Identifier{Name: "r"},
+								},
+							},
+						},
+						Body: &semantic.ObjectExpression{
+							Properties: []*semantic.Property{
+								{
+									Key: &semantic.Identifier{Name: "b"},
+									Value: &semantic.MemberExpression{
+										Object:   &semantic.IdentifierExpression{Name: "r"},
+										Property: "b",
+									},
+								},
+								{
+									Key: &semantic.Identifier{Name: "c"},
+									Value: &semantic.MemberExpression{
+										Object:   &semantic.IdentifierExpression{Name: "r"},
+										Property: "c",
+									},
+								},
+								{
+									Key: &semantic.Identifier{Name: "a"},
+									Value: &semantic.MemberExpression{
+										Object:   &semantic.IdentifierExpression{Name: "r"},
+										Property: "a",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	c := execute.NewTableBuilderCache(executetest.UnlimitedAllocator)
+	c.SetTriggerSpec(plan.DefaultTriggerSpec)
+	d := executetest.NewDataset(executetest.RandomDatasetID())
+	tr, err := influxdb.NewToTransformation(context.Background(), d, c, spec, deps, dependenciestest.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parentID := executetest.RandomDatasetID()
+	tbl := executetest.MustCopyTable(&executetest.Table{
+		ColMeta: []flux.ColMeta{
+			{Label: "_time", Type: flux.TTime},
+			{Label: "_measurement", Type: flux.TString},
+			{Label: "c", Type: flux.TFloat},
+			{Label: "a", Type: flux.TFloat},
+			{Label: "b", Type: flux.TFloat},
+		},
+		KeyCols: []string{"_measurement"},
+		Data: [][]interface{}{
+			{execute.Time(11), "m", 1.0, 2.0, 3.0},
+		},
+	})
+	if err := tr.Process(parentID, tbl); err != nil {
+		t.Fatal(err)
+	}
+	tr.Finish(parentID, nil)
+	if d.FinishedErr != nil {
+		t.Fatalf("unexpected error: %v", d.FinishedErr)
+	}
+
+	var got []string
+	for _, p := range deps.PointsWriter.(*mock.PointsWriter).Points {
+		got = append(got, string(p.Tags().Get([]byte("\xff"))))
+	}
+	if want := []string{"c", "a", "b"}; !cmp.Equal(want, got) {
+		t.Fatalf("unexpected field order -want/+got\n%s", cmp.Diff(want, got))
+	}
+}
+
+// flakyPointsWriter fails its first failures writes with err.
+type flakyPointsWriter struct {
+	failures int
+	err      error
+	calls    int
+	points   []models.Point
+}
+
+func (w *flakyPointsWriter) WritePoints(ctx context.Context, points []models.Point) error {
+	w.calls++
+	if w.calls <= w.failures {
+		return w.err
+	}
+	w.points = append(w.points, points...)
+	return nil
+}
+
+func TestTo_Process_MaxRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxRetries int
+		err        error
+		wantErr    bool
+		wantCalls  int
+	}{
+		{
+			name:       "retried until written",
+			maxRetries: 2,
+			err:        errors.New("engine unavailable"),
+			wantCalls:  3,
+		},
+		{
+			name:       "too few retries",
+			maxRetries: 1,
+			err:        errors.New("engine unavailable"),
+			wantErr:    true,
+			wantCalls:  2,
+		},
+		{
+			name:      "no retries by default",
+			err:       errors.New("engine unavailable"),
+			wantErr:   true,
+			wantCalls: 1,
+		},
+		{
+			name:       "invalid write not retried",
+			maxRetries: 2,
+			err:        tsdb.PartialWriteError{Reason: "field type conflict", Dropped: 1},
+			wantErr:    true,
+			wantCalls:  1,
+		},
+		{
+			name:       "unprocessable write not retried",
+			maxRetries: 2,
+			err:        &platform.Error{Code: platform.EUnprocessableEntity, Msg: "out of range"},
+			wantErr:    true,
+			wantCalls:  1,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pw := &flakyPointsWriter{failures: 2, err: tc.err}
+			deps := mockDependencies()
+			deps.PointsWriter = pw
+			spec := &influxdb.ToProcedureSpec{
+				Spec: &influxdb.ToOpSpec{
+					Org:               "my-org",
+					Bucket:            "my-bucket",
+					TimeColumn:        "_time",
+					MeasurementColumn: "_measurement",
+					MaxRetries:        tc.maxRetries,
+				},
+			}
+
+			c := execute.NewTableBuilderCache(executetest.UnlimitedAllocator)
+			c.SetTriggerSpec(plan.DefaultTriggerSpec)
+			d := executetest.NewDataset(executetest.RandomDatasetID())
+			tr, err := influxdb.NewToTransformation(context.Background(), d, c, spec, deps, dependenciestest.Default())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			parentID := executetest.RandomDatasetID()
+			tbl := executetest.MustCopyTable(&executetest.Table{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_measurement", Type: flux.TString},
+					{Label: "_field", Type: flux.TString},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				KeyCols: []string{"_measurement"},
+				Data: [][]interface{}{
+					{execute.Time(11), "a", "_value", 2.0},
+					{execute.Time(21), "a", "_value", 2.0},
+				},
+			})
+			if err := tr.Process(parentID, tbl); err != nil {
+				t.Fatal(err)
+			}
+			tr.Finish(parentID, nil)
+
+			if got := d.FinishedErr; tc.wantErr && got == nil {
+				t.Fatal("expected the write to fail")
+			} else if !tc.wantErr && got != nil {
+				t.Fatalf("unexpected error: %v", got)
+			}
+			if got, want := pw.calls, tc.wantCalls; got != want {
+				t.Fatalf("unexpected number of writes: got %d, want %d", got, want)
+			}
+			if !tc.wantErr {
+				if got, want := len(pw.points), 2; got != want {
+					t.Fatalf("unexpected number of points written: got %d, want %d", got, want)
+				}
+			}
+		})
+	}
+}
+
+// orgLookup is an OrganizationLookup of the orgs keyed by name.
+type orgLookup map[string]platform.ID
+
+func (l orgLookup) Lookup(_ context.Context, name string) (platform.ID, bool) {
+	id, ok := l[name]
+	return id, ok
+}
+
+func (l orgLookup) LookupName(_ context.Context, id platform.ID) string {
+	for name, orgID := range l {
+		if orgID == id {
+			return name
+		}
+	}
+	return ""
+}
+
+// bucketLookup is a BucketLookup of the buckets keyed by org ID and name.
+type bucketLookup map[platform.ID]map[string]platform.ID
+
+func (l bucketLookup) Lookup(_ context.Context, orgID platform.ID, name string) (platform.ID, bool) {
+	id, ok := l[orgID][name]
+	return id, ok
+}
+
+func (l bucketLookup) LookupName(_ context.Context, orgID platform.ID, id platform.ID) string {
+	for name, bucketID := range l[orgID] {
+		if bucketID == id {
+			return name
+		}
+	}
+	return ""
+}
+
+func TestToTransformation_LookupErrors(t *testing.T) {
+	deps := mockDependencies()
+	deps.OrganizationLookup = orgLookup{"my-org": 2, "other-org": 3}
+	deps.BucketLookup = bucketLookup{
+		2: {"my-bucket": 1},
+		3: {"other-bucket": 4},
+	}
+
+	tests := []struct {
+		name    string
+		spec    influxdb.ToOpSpec
+		wantMsg string
+	}{
+		{
+			name:    "org name not found",
+			spec:    influxdb.ToOpSpec{Org: "no-org", Bucket: "my-bucket"},
+			wantMsg: `organization "no-org" not found`,
+		},
+		{
+			name:    "bucket in another org",
+			spec:    influxdb.ToOpSpec{Org: "my-org", Bucket: "other-bucket"},
+			wantMsg: `bucket "other-bucket" not found in organization "my-org"; it may belong to a different organization`,
+		},
+		{
+			name:    "bucket in another org by org ID",
+			spec:    influxdb.ToOpSpec{OrgID: platform.ID(3).String(), Bucket: "my-bucket"},
+			wantMsg: `bucket "my-bucket" not found in organization "other-org"; it may belong to a different organization`,
+		},
+		{
+			name:    "org ID not found",
+			spec:    influxdb.ToOpSpec{OrgID: platform.ID(9).String(), Bucket: "my-bucket"},
+			wantMsg: `organization with ID 0000000000000009 not found`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := tc.spec
+			d := executetest.NewDataset(executetest.RandomDatasetID())
+			c := execute.NewTableBuilderCache(executetest.UnlimitedAllocator)
+			_, err := influxdb.NewToTransformation(context.Background(), d, c, &influxdb.ToProcedureSpec{Spec: &spec}, deps, dependenciestest.Default())
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if got := flux.ErrorCode(err); got != codes.NotFound {
+				t.Errorf("unexpected error code: got %v, want %v", got, codes.NotFound)
+			}
+			if got := err.Error(); got != tc.wantMsg {
+				t.Errorf("unexpected error: got %q, want %q", got, tc.wantMsg)
+			}
+		})
+	}
+}
+
+// pointsStore is a PointsWriter that can also find the points written to it.
+type pointsStore struct {
+	existing map[string]bool
+	written  []models.Point
+}
+
+func pointsStoreKey(p models.Point) string {
+	return fmt.Sprintf("%s@%d", p.Key(), p.UnixNano())
+}
+
+func (s *pointsStore) WritePoints(ctx context.Context, points []models.Point) error {
+	for _, p := range points {
+		s.existing[pointsStoreKey(p)] = true
+	}
+	s.written = append(s.written, points...)
+	return nil
+}
+
+func (s *pointsStore) PointsExist(ctx context.Context, points []models.Point) ([]bool, error) {
+	exist := make([]bool, len(points))
+	for i, p := range points {
+		exist[i] = s.existing[pointsStoreKey(p)]
+	}
+	return exist, nil
+}
+
+func TestTo_Process_SkipExisting(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      string
+		wantTimes []int64
+	}{
+		{
+			name:      "overwrite",
+			mode:      "overwrite",
+			wantTimes: []int64{11, 21},
+		},
+		{
+			name:      "skip existing",
+			mode:      "skipExisting",
+			wantTimes: []int64{21},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			deps := mockDependencies()
+			store := &pointsStore{existing: make(map[string]bool)}
+			deps.PointsWriter = store
+			deps.PointsFinder = store
+
+			// Write the point at time 11 before the `to` function runs.
+			oid, _ := mock.OrganizationLookup{}.Lookup(context.Background(), "my-org")
+			bid, _ := mock.BucketLookup{}.Lookup(context.Background(), oid, "my-bucket")
+			existing := models.MustNewPoint(
+				tsdb.EncodeNameString(oid, bid),
+				models.NewTags(map[string]string{"\x00": "a", "\xff": "_value"}),
+				models.Fields{"_value": 1.0},
+				time.Unix(0, 11),
+			)
+			if err := store.WritePoints(context.Background(), []models.Point{existing}); err != nil {
+				t.Fatal(err)
+			}
+			store.written = nil
+
+			spec := &influxdb.ToProcedureSpec{
+				Spec: &influxdb.ToOpSpec{
+					Org:               "my-org",
+					Bucket:            "my-bucket",
+					TimeColumn:        "_time",
+					MeasurementColumn: "_measurement",
+					Mode:              tc.mode,
+				},
+			}
+
+			c := execute.NewTableBuilderCache(executetest.UnlimitedAllocator)
+			c.SetTriggerSpec(plan.DefaultTriggerSpec)
+			d := executetest.NewDataset(executetest.RandomDatasetID())
+			tr, err := influxdb.NewToTransformation(context.Background(), d, c, spec, deps, dependenciestest.Default())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			parentID := executetest.RandomDatasetID()
+			tbl := executetest.MustCopyTable(&executetest.Table{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_measurement", Type: flux.TString},
+					{Label: "_field", Type: flux.TString},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				KeyCols: []string{"_measurement"},
+				Data: [][]interface{}{
+					{execute.Time(11), "a", "_value", 2.0},
+					{execute.Time(21), "a", "_value", 2.0},
+				},
+			})
+			if err := tr.Process(parentID, tbl); err != nil {
+				t.Fatal(err)
+			}
+			tr.Finish(parentID, nil)
+			if d.FinishedErr != nil {
+				t.Fatalf("unexpected error: %v", d.FinishedErr)
+			}
+
+			var times []int64
+			for _, p := range store.written {
+				times = append(times, p.UnixNano())
+			}
+			if !cmp.Equal(tc.wantTimes, times) {
+				t.Fatalf("unexpected points written -want/+got\n%s", cmp.Diff(tc.wantTimes, times))
+			}
+		})
+	}
+}
+
+func TestTo_Process_MaxTagCardinality(t *testing.T) {
+	tests := []struct {
+		name    string
+		max     int
+		wantErr string
+	}{
+		{
+			name: "unlimited",
+		},
+		{
+			name: "within the limit",
+			max:  3,
+		},
+		{
+			name:    "over the limit",
+			max:     2,
+			wantErr: `measurement "a" exceeds the max tag cardinality of 2`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := &influxdb.ToProcedureSpec{
+				Spec: &influxdb.ToOpSpec{
+					Org:               "my-org",
+					Bucket:            "my-bucket",
+					TimeColumn:        "_time",
+					MeasurementColumn: "_measurement",
+					TagColumns:        []string{"host", "region"},
+					MaxTagCardinality: tc.max,
+				},
+			}
+
+			c := execute.NewTableBuilderCache(executetest.UnlimitedAllocator)
+			c.SetTriggerSpec(plan.DefaultTriggerSpec)
+			d := executetest.NewDataset(executetest.RandomDatasetID())
+			tr, err := influxdb.NewToTransformation(context.Background(), d, c, spec, mockDependencies(), dependenciestest.Default())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The rows have three distinct tag sets: the repeated host "a"
+			// rows belong to the same series.
+			parentID := executetest.RandomDatasetID()
+			tbl := executetest.MustCopyTable(&executetest.Table{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_measurement", Type: flux.TString},
+					{Label: "region", Type: flux.TString},
+					{Label: "host", Type: flux.TString},
+					{Label: "_field", Type: flux.TString},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				KeyCols: []string{"_measurement"},
+				Data: [][]interface{}{
+					{execute.Time(11), "a", "east", "a", "v", 1.0},
+					{execute.Time(21), "a", "east", "a", "v", 2.0},
+					{execute.Time(31), "a", "east", "b", "v", 3.0},
+					{execute.Time(41), "a", "west", "b", "v", 4.0},
+				},
+			})
+			err = tr.Process(parentID, tbl)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if got := err.Error(); got != tc.wantErr {
+				t.Fatalf("unexpected error: got %q, want %q", got, tc.wantErr)
+			}
+			if code := flux.ErrorCode(err); code != codes.Invalid {
+				t.Fatalf("unexpected error code: got %v, want %v", code, codes.Invalid)
+			}
+		})
+	}
+}
+
+func TestTo_Process_SkipExisting_Unsupported(t *testing.T) {
+	spec := &influxdb.ToProcedureSpec{
+		Spec: &influxdb.ToOpSpec{
+			Org:               "my-org",
+			Bucket:            "my-bucket",
+			TimeColumn:        "_time",
+			MeasurementColumn: "_measurement",
+			Mode:              "skipExisting",
+		},
+	}
+
+	c := execute.NewTableBuilderCache(executetest.UnlimitedAllocator)
+	d := executetest.NewDataset(executetest.RandomDatasetID())
+	if _, err := influxdb.NewToTransformation(context.Background(), d, c, spec, mockDependencies(), dependenciestest.Default()); err == nil {
+		t.Fatal("expected an error without a points finder")
+	}
+}
+
+func TestTo_Process_ErrorMode(t *testing.T) {
+	oid, _ := mock.OrganizationLookup{}.Lookup(context.Background(), "my-org")
+	bid, _ := mock.BucketLookup{}.Lookup(context.Background(), oid, "my-bucket")
+
+	// The second row has a timestamp that cannot be represented by a point,
+	// and the fourth a value that cannot be stored.
+	data := func() []flux.Table {
+		return []flux.Table{executetest.MustCopyTable(&executetest.Table{
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_measurement", Type: flux.TString},
+				{Label: "_field", Type: flux.TString},
+				{Label: "_value", Type: flux.TFloat},
+			},
+			Data: [][]interface{}{
+				{execute.Time(11), "a", "_value", 1.0},
+				{execute.Time(math.MinInt64), "a", "_value", 2.0},
+				{execute.Time(21), "a", "_value", 3.0},
+				{execute.Time(31), "a", "_value", math.NaN()},
+				{execute.Time(41), "a", "_value", 5.0},
+			},
+		})}
+	}
+
+	testCases := []struct {
+		name      string
+		errorMode string
+		wantErr   error
+		want      []models.Point
+	}{
+		{
+			name:      "fail",
+			errorMode: "fail",
+			wantErr:   models.ErrTimeOutOfRange,
+		},
+		{
+			name:      "default",
+			errorMode: "",
+			wantErr:   models.ErrTimeOutOfRange,
+		},
+		{
+			name:      "continue",
+			errorMode: "continue",
+			wantErr: &flux.Error{
+				Code: codes.Invalid,
+				Msg:  "skipped 2 rows that could not be written: " + models.ErrTimeOutOfRange.Error() + "; NAN is an unsupported value for field _value",
+			},
+			want: mockPoints(oid, bid, `a _value=1 11
+a _value=3 21
+a _value=5 41`),
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			deps := mockDependencies()
+			spec := &influxdb.ToProcedureSpec{
+				Spec: &influxdb.ToOpSpec{
+					Org:               "my-org",
+					Bucket:            "my-bucket",
+					TimeColumn:        "_time",
+					MeasurementColumn: "_measurement",
+					ErrorMode:         tc.errorMode,
+				},
+			}
+			executetest.ProcessTestHelper(
+				t,
+				data(),
+				nil,
+				tc.wantErr,
+				func(d execute.Dataset, c execute.TableBuilderCache) execute.Transformation {
+					newT, err := influxdb.NewToTransformation(context.Background(), d, c, spec, deps, dependenciestest.Default())
+					if err != nil {
+						t.Error(err)
+					}
+					return newT
+				},
+			)
+
+			pw := deps.PointsWriter.(*mock.PointsWriter)
+			if got, want := pointsToStr(pw.Points), pointsToStr(tc.want); !cmp.Equal(got, want) {
+				t.Errorf("unexpected points written -want/+got\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestTo_Process_Stats(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	deps := mockDependencies()
+	deps.Logger = zap.New(core)
+	spec := &influxdb.ToProcedureSpec{
+		Spec: &influxdb.ToOpSpec{
+			Org:               "my-org",
+			Bucket:            "my-bucket",
+			TimeColumn:        "_time",
+			MeasurementColumn: "_measurement",
+		},
+	}
+
+	c := execute.NewTableBuilderCache(executetest.UnlimitedAllocator)
+	c.SetTriggerSpec(plan.DefaultTriggerSpec)
+	d := executetest.NewDataset(executetest.RandomDatasetID())
+	tr, err := influxdb.NewToTransformation(context.Background(), d, c, spec, deps, dependenciestest.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parentID := executetest.RandomDatasetID()
+	for _, tbl := range []*executetest.Table{
+		{
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_measurement", Type: flux.TString},
+				{Label: "_field", Type: flux.TString},
+				{Label: "_value", Type: flux.TFloat},
+			},
+			Data: [][]interface{}{
+				{execute.Time(21), "a", "_value", 2.0},
+				{execute.Time(11), "a", "_value", 1.0},
+				{execute.Time(15), "b", "_value", 1.0},
+			},
+		},
+		{
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_measurement", Type: flux.TString},
+				{Label: "host", Type: flux.TString},
+				{Label: "_field", Type: flux.TString},
+				{Label: "_value", Type: flux.TFloat},
+			},
+			KeyCols: []string{"_measurement", "host", "_field"},
+			Data: [][]interface{}{
+				{execute.Time(31), "a", "server01", "_value", 3.0},
+			},
+		},
+	} {
+		if err := tr.Process(parentID, executetest.MustCopyTable(tbl)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tr.Finish(parentID, nil)
+
+	want := map[string]influxdb.Stats{
+		"a": {
+			NRows:    3,
+			Earliest: execute.Time(11).Time(),
+			Latest:   execute.Time(31).Time(),
+			NFields:  1,
+			NTags:    1,
+		},
+		"b": {
+			NRows:    1,
+			Earliest: execute.Time(15).Time(),
+			Latest:   execute.Time(15).Time(),
+			NFields:  1,
+		},
+	}
+	if got := tr.Stats(); !cmp.Equal(want, got) {
+		t.Errorf("unexpected stats -want/+got\n%s", cmp.Diff(want, got))
+	}
+
+	entries := logs.FilterMessage("Wrote measurement").All()
+	if got, want := len(entries), 2; got != want {
+		t.Fatalf("unexpected number of log entries: got %d, want %d", got, want)
+	}
+	if got := entries[0].ContextMap(); got["measurement"] != "a" || got["rows"] != int64(3) {
+		t.Errorf("unexpected log fields: %v", got)
+	}
+}
+
+func TestStats_Update(t *testing.T) {
+	var s influxdb.Stats
+	for _, o := range []influxdb.Stats{
+		{NRows: 1, Earliest: time.Unix(0, 20), Latest: time.Unix(0, 20), NFields: 1, NTags: 2},
+		{NRows: 1, Earliest: time.Unix(0, 10), Latest: time.Unix(0, 10), NFields: 3, NTags: 1},
+		{NRows: 1, Earliest: time.Unix(0, 30), Latest: time.Unix(0, 30), NFields: 2, NTags: 0},
+	} {
+		s.Update(o)
+	}
+
+	want := influxdb.Stats{
+		NRows:    3,
+		Earliest: time.Unix(0, 10),
+		Latest:   time.Unix(0, 30),
+		NFields:  3,
+		NTags:    2,
+	}
+	if !cmp.Equal(want, s) {
+		t.Errorf("unexpected stats -want/+got\n%s", cmp.Diff(want, s))
+	}
+}
+
 func mockDependencies() influxdb.ToDependencies {
 	return influxdb.ToDependencies{
 		BucketLookup:       mock.BucketLookup{},
@@ -87,6 +87,36 @@ func (s *Session) EphemeralAuth(orgID ID) *Authorization {
 	}
 }
 
+// EphemeralAuthForResources generates an Authorization that is not stored
+// and only holds the user's permissions on the given resource types in the org.
+func (s *Session) EphemeralAuthForResources(orgID ID, types ...ResourceType) *Authorization {
+	a := s.EphemeralAuth(orgID)
+	a.Permissions = nil
+	for _, p := range s.Permissions {
+		if !containsResourceType(types, p.Resource.Type) {
+			continue
+		}
+		if p.Resource.OrgID != nil && *p.Resource.OrgID != orgID {
+			continue
+		}
+		if p.Resource.OrgID == nil && p.Resource.ID == nil {
+			// Narrow a global permission down to the org.
+			p.Resource.OrgID = &orgID
+		}
+		a.Permissions = append(a.Permissions, p)
+	}
+	return a
+}
+
+func containsResourceType(types []ResourceType, t ResourceType) bool {
+	for _, typ := range types {
+		if typ == t {
+			return true
+		}
+	}
+	return false
+}
+
 // SessionService represents a service for managing user sessions.
 type SessionService interface {
 	FindSession(ctx context.Context, key string) (*Session, error)
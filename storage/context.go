@@ -0,0 +1,27 @@
+package storage
+
+import (
+	"context"
+)
+
+type contextKey string
+
+const (
+	noWALCtxKey = contextKey("influx/storage/nowal/v1")
+)
+
+// WithNoWAL returns a context that makes writes using it bypass the WAL and go
+// straight to the cache. This is meant for bulk backfills, where the extra
+// write to the WAL is costly and the data can be written again on failure.
+//
+// Points written this way are not durable until the cache is snapshotted to a
+// TSM file: they are lost if the process exits or crashes before then.
+func WithNoWAL(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noWALCtxKey, true)
+}
+
+// noWAL returns true if writes using ctx must bypass the WAL.
+func noWAL(ctx context.Context) bool {
+	v, _ := ctx.Value(noWALCtxKey).(bool)
+	return v
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sort"
 	"sync"
 	"time"
 
@@ -45,6 +46,7 @@ type Engine struct {
 	retentionEnforcer *retentionEnforcer
 
 	defaultMetricLabels prometheus.Labels
+	writeTracker        *writeTracker
 
 	// Tracks all goroutines started by the Engine.
 	wg sync.WaitGroup
@@ -150,6 +152,13 @@ func NewEngine(path string, c Config, options ...Option) *Engine {
 	e.wal.SetDefaultMetricLabels(e.defaultMetricLabels)
 	e.retentionEnforcer.SetDefaultMetricLabels(e.defaultMetricLabels)
 
+	mmu.Lock()
+	if wms == nil {
+		wms = newWriteMetrics(e.defaultMetricLabels)
+	}
+	mmu.Unlock()
+	e.writeTracker = newWriteTracker(wms, e.defaultMetricLabels)
+
 	return e
 }
 
@@ -182,6 +191,7 @@ func (e *Engine) PrometheusCollectors() []prometheus.Collector {
 	metrics = append(metrics, tsm1.PrometheusCollectors()...)
 	metrics = append(metrics, wal.PrometheusCollectors()...)
 	metrics = append(metrics, RetentionPrometheusCollectors()...)
+	metrics = append(metrics, WritePrometheusCollectors()...)
 	return metrics
 }
 
@@ -239,10 +249,14 @@ func (e *Engine) replayWAL() error {
 	// TODO(jeff): we should just do snapshots and wait for them so that we don't hit
 	// OOM situations when reloading huge WALs.
 
-	// Disable the max size during loading
-	limit := e.engine.Cache.MaxSize()
-	defer func() { e.engine.Cache.SetMaxSize(limit) }()
+	// Disable the max size and the high watermark during loading
+	limit, watermark := e.engine.Cache.MaxSize(), e.engine.Cache.HighWatermark()
+	defer func() {
+		e.engine.Cache.SetMaxSize(limit)
+		e.engine.Cache.SetHighWatermark(watermark)
+	}()
 	e.engine.Cache.SetMaxSize(0)
+	e.engine.Cache.SetHighWatermark(0)
 
 	// Execute all the entries in the WAL again
 	reader := wal.NewWALReader(walPaths)
@@ -367,6 +381,113 @@ func (e *Engine) CreateCursorIterator(ctx context.Context) (tsdb.CursorIterator,
 	return e.engine.CreateCursorIterator(ctx)
 }
 
+// PointsExist reports, for each of the points, whether the engine holds a value
+// for the point's series and field at the point's time.
+func (e *Engine) PointsExist(ctx context.Context, points []models.Point) ([]bool, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	itr, err := e.CreateCursorIterator(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	exist := make([]bool, len(points))
+	for i, p := range points {
+		t := p.UnixNano()
+		tags := p.Tags()
+		cur, err := itr.Next(ctx, &tsdb.CursorRequest{
+			Name:      p.Name(),
+			Tags:      tags,
+			Field:     string(tags.Get(models.FieldKeyTagKeyBytes)),
+			Ascending: true,
+			StartTime: t,
+			EndTime:   t + 1, // the end time of an ascending cursor is exclusive
+		})
+		if err != nil {
+			return nil, err
+		} else if cur == nil {
+			continue
+		}
+
+		exist[i] = cursorHasValues(cur)
+		err = cur.Err()
+		cur.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return exist, nil
+}
+
+// cursorHasValues returns true if cur returns any values.
+func cursorHasValues(cur tsdb.Cursor) bool {
+	switch cur := cur.(type) {
+	case tsdb.FloatArrayCursor:
+		return cur.Next().Len() > 0
+	case tsdb.IntegerArrayCursor:
+		return cur.Next().Len() > 0
+	case tsdb.UnsignedArrayCursor:
+		return cur.Next().Len() > 0
+	case tsdb.StringArrayCursor:
+		return cur.Next().Len() > 0
+	case tsdb.BooleanArrayCursor:
+		return cur.Next().Len() > 0
+	default:
+		return false
+	}
+}
+
+// precisionMultipliers maps the write precisions supported by
+// WritePointsWithPrecision to their duration in nanoseconds.
+var precisionMultipliers = map[string]int64{
+	"n":  int64(time.Nanosecond),
+	"u":  int64(time.Microsecond),
+	"ms": int64(time.Millisecond),
+	"s":  int64(time.Second),
+	"m":  int64(time.Minute),
+	"h":  int64(time.Hour),
+}
+
+// WritePointsWithPrecision writes the provided points to the engine, treating
+// their timestamps as being in the provided precision. The timestamps of the
+// points are converted to nanoseconds in place before they are written.
+//
+// The precision must be one of n, u, ms, s, m or h. An error is returned if the
+// precision is unknown or a converted timestamp is out of the representable range.
+func (e *Engine) WritePointsWithPrecision(ctx context.Context, points []models.Point, precision string) error {
+	mult, ok := precisionMultipliers[precision]
+	if !ok {
+		return fmt.Errorf("invalid precision %q: must be one of n, u, ms, s, m or h", precision)
+	}
+
+	if mult != 1 {
+		for _, p := range points {
+			ts := p.UnixNano()
+			if ts > models.MaxNanoTime/mult || ts < models.MinNanoTime/mult {
+				return models.ErrTimeOutOfRange
+			}
+			p.SetTime(time.Unix(0, ts*mult))
+		}
+	}
+
+	return e.WritePoints(ctx, points)
+}
+
+// WriteResult describes the points of a batch rejected by WritePointsDetailed.
+type WriteResult struct {
+	// Rejected holds the rejected points, ordered by their index in the batch.
+	Rejected []RejectedPoint
+}
+
+// RejectedPoint is a point of a batch that was not written.
+type RejectedPoint struct {
+	// Index is the position of the point in the batch.
+	Index int
+	// Reason is one of the RejectReason constants.
+	Reason string
+}
+
 // WritePoints writes the provided points to the engine.
 //
 // The Engine expects all points to have been correctly validated by the caller.
@@ -374,20 +495,31 @@ func (e *Engine) CreateCursorIterator(ctx context.Context) (tsdb.CursorIterator,
 // there are any field type conflicts.
 //
 // Appropriate errors are returned in those cases.
+//
+// The points are not added to the WAL if ctx was created with WithNoWAL.
 func (e *Engine) WritePoints(ctx context.Context, points []models.Point) error {
+	_, err := e.WritePointsDetailed(ctx, points)
+	return err
+}
+
+// WritePointsDetailed is like WritePoints, but also returns which of the points
+// were rejected and why. A tsdb.PartialWriteError is still returned whenever
+// points are rejected.
+func (e *Engine) WritePointsDetailed(ctx context.Context, points []models.Point) (WriteResult, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
+	var result WriteResult
 	collection, j := tsdb.NewSeriesCollection(points), 0
 
 	// dropPoint should be called whenever there is reason to drop a point from
 	// the batch.
-	dropPoint := func(key []byte, reason string) {
-		if collection.Reason == "" {
-			collection.Reason = reason
-		}
+	dropPoint := func(index int, key []byte, rejectReason, format string, args ...interface{}) {
+		collection.AddReasonf(format, args...)
 		collection.Dropped++
 		collection.DroppedKeys = append(collection.DroppedKeys, key)
+		e.writeTracker.AddRejected(rejectReason, 1)
+		result.Rejected = append(result.Rejected, RejectedPoint{Index: index, Reason: rejectReason})
 	}
 
 	for iter := collection.Iterator(); iter.Next(); {
@@ -395,13 +527,13 @@ func (e *Engine) WritePoints(ctx context.Context, points []models.Point) error {
 
 		// Not enough tags present.
 		if tags.Len() < 2 {
-			dropPoint(iter.Key(), fmt.Sprintf("missing required tags: parsed tags: %q", tags))
+			dropPoint(iter.Index(), iter.Key(), RejectReasonInvalidTag, "missing required tags: parsed tags: %q", tags)
 			continue
 		}
 
 		// First tag key is not measurement tag.
 		if !bytes.Equal(tags[0].Key, models.MeasurementTagKeyBytes) {
-			dropPoint(iter.Key(), fmt.Sprintf("missing required measurement tag as first tag, got: %q", tags[0].Key))
+			dropPoint(iter.Index(), iter.Key(), RejectReasonInvalidTag, "missing required measurement tag as first tag, got: %q", tags[0].Key)
 			continue
 		}
 
@@ -409,26 +541,26 @@ func (e *Engine) WritePoints(ctx context.Context, points []models.Point) error {
 
 		// Last tag key is not field tag.
 		if !bytes.Equal(fkey, models.FieldKeyTagKeyBytes) {
-			dropPoint(iter.Key(), fmt.Sprintf("missing required field key tag as last tag, got: %q", tags[0].Key))
+			dropPoint(iter.Index(), iter.Key(), RejectReasonInvalidTag, "missing required field key tag as last tag, got: %q", tags[0].Key)
 			continue
 		}
 
 		// The value representing the underlying field key is invalid if it's "time".
 		if bytes.Equal(fval, timeBytes) {
-			dropPoint(iter.Key(), fmt.Sprintf("invalid field key: input field %q is invalid", timeBytes))
+			dropPoint(iter.Index(), iter.Key(), RejectReasonTimeField, "invalid field key: input field %q is invalid", timeBytes)
 			continue
 		}
 
 		// Filter out any tags with key equal to "time": they are invalid.
 		if tags.Get(timeBytes) != nil {
-			dropPoint(iter.Key(), fmt.Sprintf("invalid tag key: input tag %q on measurement %q is invalid", timeBytes, iter.Name()))
+			dropPoint(iter.Index(), iter.Key(), RejectReasonInvalidTag, "invalid tag key: input tag %q on measurement %q is invalid", timeBytes, iter.Name())
 			continue
 		}
 
 		// Drop any point with invalid unicode characters in any of the tag keys or values.
 		// This will also cover validating the value used to represent the field key.
 		if !models.ValidTagTokens(tags) {
-			dropPoint(iter.Key(), fmt.Sprintf("key contains invalid unicode: %q", iter.Key()))
+			dropPoint(iter.Index(), iter.Key(), RejectReasonInvalidTag, "key contains invalid unicode: %q", iter.Key())
 			continue
 		}
 
@@ -441,21 +573,63 @@ func (e *Engine) WritePoints(ctx context.Context, points []models.Point) error {
 	defer e.mu.RUnlock()
 
 	if e.closing == nil {
-		return ErrEngineClosed
+		return result, ErrEngineClosed
 	}
 
-	// Convert the collection to values for adding to the WAL/Cache.
+	// Convert the collection to values for adding to the WAL/Cache. Points whose
+	// field type conflicts with an earlier point in the batch are dropped.
+	dropped := collection.Dropped
 	values, err := tsm1.CollectionToValues(collection)
 	if err != nil {
-		return err
+		return result, err
+	}
+	if n := collection.Dropped - dropped; n > 0 {
+		e.writeTracker.AddRejected(RejectReasonTypeConflict, n)
 	}
 
-	// Add the write to the WAL to be replayed if there is a crash or shutdown.
-	if _, err := e.wal.WriteMulti(ctx, values); err != nil {
-		return err
+	// Reject the write before it reaches the WAL if the cache has no room for it,
+	// so that it is not replayed after being rejected.
+	if err := e.engine.Cache.CheckWriteMulti(values); err != nil {
+		return result, cacheSizeError(err)
+	}
+
+	// Add the write to the WAL to be replayed if there is a crash or shutdown,
+	// unless the caller has chosen to give up durability for this write.
+	if !noWAL(ctx) {
+		if _, err := e.wal.WriteMulti(ctx, values); err != nil {
+			return result, err
+		}
 	}
 
-	return e.writePointsLocked(ctx, collection, values)
+	err = e.writePointsLocked(ctx, collection, values)
+	if _, ok := err.(tsdb.PartialWriteError); ok {
+		result.Rejected = rejectedTypeConflicts(result.Rejected, points, collection.Points)
+	}
+	return result, err
+}
+
+// rejectedTypeConflicts adds the points missing from written that were not already
+// rejected to rejected. Those points were dropped because of a field type conflict.
+func rejectedTypeConflicts(rejected []RejectedPoint, points, written []models.Point) []RejectedPoint {
+	skip := make(map[int]struct{}, len(rejected))
+	for _, r := range rejected {
+		skip[r.Index] = struct{}{}
+	}
+	ok := make(map[models.Point]struct{}, len(written))
+	for _, p := range written {
+		ok[p] = struct{}{}
+	}
+
+	for i, p := range points {
+		if _, found := skip[i]; found {
+			continue
+		}
+		if _, found := ok[p]; !found {
+			rejected = append(rejected, RejectedPoint{Index: i, Reason: RejectReasonTypeConflict})
+		}
+	}
+	sort.Slice(rejected, func(i, j int) bool { return rejected[i].Index < rejected[j].Index })
+	return rejected
 }
 
 // writePointsLocked does the work of writing points and must be called under some sort of lock.
@@ -467,10 +641,15 @@ func (e *Engine) writePointsLocked(ctx context.Context, collection *tsdb.SeriesC
 	// errors get tracked all the way. Right now, the engine doesn't drop any values
 	// but if it ever did, the errors could end up missing some data.
 
-	// Add new series to the index and series file.
+	// Add new series to the index and series file. Points conflicting with the
+	// type of an existing series are dropped from the collection.
+	dropped := collection.Dropped
 	if err := e.index.CreateSeriesListIfNotExists(collection); err != nil {
 		return err
 	}
+	if n := collection.Dropped - dropped; n > 0 {
+		e.writeTracker.AddRejected(RejectReasonTypeConflict, n)
+	}
 
 	// If there was a PartialWriteError, that means the passed in values may contain
 	// more than the points so we need to recreate them.
@@ -484,12 +663,25 @@ func (e *Engine) writePointsLocked(ctx context.Context, collection *tsdb.SeriesC
 
 	// Write the values to the engine.
 	if err := e.engine.WriteValues(values); err != nil {
-		return err
+		return cacheSizeError(err)
 	}
 
 	return collection.PartialWriteError()
 }
 
+// cacheSizeError marks err as retryable if the cache rejected a write because
+// it is above its high watermark.
+func cacheSizeError(err error) error {
+	if _, ok := err.(tsm1.CacheHighWatermarkExceededError); ok {
+		return &platform.Error{
+			Code: platform.EUnavailable,
+			Msg:  "engine is under memory pressure, retry later",
+			Err:  err,
+		}
+	}
+	return err
+}
+
 // AcquireSegments closes the current WAL segment, gets the set of all the currently closed
 // segments, and calls the callback. It does all of this under the lock on the engine.
 func (e *Engine) AcquireSegments(ctx context.Context, fn func(segs []string) error) error {
@@ -531,6 +723,33 @@ func (e *Engine) DeleteBucket(ctx context.Context, orgID, bucketID platform.ID)
 	return e.DeleteBucketRange(ctx, orgID, bucketID, math.MinInt64, math.MaxInt64)
 }
 
+// DeleteBuckets deletes the provided buckets of an organization from the storage engine.
+// It is more efficient than calling DeleteBucket for each bucket, since compactions are
+// only disabled once for all of the buckets.
+func (e *Engine) DeleteBuckets(ctx context.Context, orgID platform.ID, bucketIDs []platform.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return ErrEngineClosed
+	}
+
+	names := make([][]byte, 0, len(bucketIDs))
+	for _, bucketID := range bucketIDs {
+		// Add the delete to the WAL to be replayed if there is a crash or shutdown.
+		if _, err := e.wal.DeleteBucketRange(orgID, bucketID, math.MinInt64, math.MaxInt64, nil); err != nil {
+			return err
+		}
+
+		encoded := tsdb.EncodeName(orgID, bucketID)
+		names = append(names, models.EscapeMeasurement(encoded[:]))
+	}
+
+	return e.engine.DeletePrefixRanges(ctx, names, math.MinInt64, math.MaxInt64, nil)
+}
+
 // DeleteBucketRange deletes an entire bucket from the storage engine.
 func (e *Engine) DeleteBucketRange(ctx context.Context, orgID, bucketID platform.ID, min, max int64) error {
 	span, ctx := tracing.StartSpanFromContext(ctx)
@@ -587,6 +806,24 @@ func (e *Engine) deleteBucketRangeLocked(ctx context.Context, orgID, bucketID pl
 	return e.engine.DeletePrefixRange(ctx, name, min, max, pred)
 }
 
+// ForceSnapshot writes the data in the cache to a new TSM file, returning once
+// it is durable and the WAL segments holding it have been removed.
+func (e *Engine) ForceSnapshot(ctx context.Context) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	// The snapshot acquires the engine lock to rotate the WAL, so it must not
+	// be held here.
+	e.mu.RLock()
+	closing := e.closing
+	e.mu.RUnlock()
+	if closing == nil {
+		return ErrEngineClosed
+	}
+
+	return e.engine.ForceSnapshot(ctx)
+}
+
 // SeriesCardinality returns the number of series in the engine.
 func (e *Engine) SeriesCardinality() int64 {
 	e.mu.RLock()
@@ -597,6 +834,107 @@ func (e *Engine) SeriesCardinality() int64 {
 	return e.index.SeriesN()
 }
 
+// SeriesCardinalityForBucket returns the number of series in the engine belonging
+// to the provided bucket.
+func (e *Engine) SeriesCardinalityForBucket(ctx context.Context, orgID, bucketID platform.ID) (int64, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return 0, ErrEngineClosed
+	}
+
+	// The TSI index does not store the bucket name in escaped form.
+	encoded := tsdb.EncodeName(orgID, bucketID)
+	itr, err := e.index.MeasurementSeriesIDIterator(encoded[:])
+	if err != nil {
+		return 0, err
+	} else if itr == nil {
+		return 0, nil
+	}
+	defer itr.Close()
+
+	var n int64
+	for {
+		elem, err := itr.Next()
+		if err != nil {
+			return 0, err
+		} else if elem.SeriesID.IsZero() {
+			break
+		}
+		n++
+	}
+	return n, nil
+}
+
+// PointCountEstimate returns the approximate number of points of the bucket in
+// each window of windowNs nanoseconds between min and max. It is cheap enough
+// to plan downsampling with, as it never reads the values of the points.
+func (e *Engine) PointCountEstimate(ctx context.Context, orgID, bucketID platform.ID, min, max, windowNs int64) ([]int64, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return nil, ErrEngineClosed
+	}
+
+	return e.engine.PointCountEstimate(ctx, orgID, bucketID, min, max, windowNs)
+}
+
+// OrgBucket identifies a bucket of an organization.
+type OrgBucket struct {
+	Org, Bucket platform.ID
+}
+
+// Buckets returns the buckets that have series in the engine, ordered by
+// organization and then bucket.
+func (e *Engine) Buckets(ctx context.Context) ([]OrgBucket, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return nil, ErrEngineClosed
+	}
+
+	itr, err := e.index.MeasurementIterator()
+	if err != nil {
+		return nil, err
+	} else if itr == nil {
+		return nil, nil
+	}
+	defer itr.Close()
+
+	var buckets []OrgBucket
+	for {
+		name, err := itr.Next()
+		if err != nil {
+			return nil, err
+		} else if name == nil {
+			break
+		}
+
+		// Every bucket is stored under its encoded org and bucket IDs.
+		if len(name) != len(tsdb.EncodeName(0, 0)) {
+			continue
+		}
+		if ok, err := e.index.MeasurementHasSeries(name); err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+
+		org, bucket := tsdb.DecodeNameSlice(name)
+		buckets = append(buckets, OrgBucket{Org: org, Bucket: bucket})
+	}
+	return buckets, nil
+}
+
 // Path returns the path of the engine's base directory.
 func (e *Engine) Path() string {
 	return e.path
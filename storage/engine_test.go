@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"math"
 	"os"
+	"reflect"
 	"testing"
 	"time"
 
@@ -14,7 +15,9 @@ import (
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/storage"
 	"github.com/influxdata/influxdb/storage/reads/datatypes"
+	"github.com/influxdata/influxdb/storage/wal"
 	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/cursors"
 	"github.com/influxdata/influxdb/tsdb/tsm1"
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -164,6 +167,81 @@ func TestWrite_TimeField(t *testing.T) {
 	}
 }
 
+func TestEngine_WritePointsWithPrecision(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	name := tsdb.EncodeNameString(engine.org, engine.bucket)
+
+	// The same logical time, two hours after the epoch, in every precision.
+	timestamps := map[string]int64{
+		"n":  7200000000000,
+		"u":  7200000000,
+		"ms": 7200000,
+		"s":  7200,
+		"m":  120,
+		"h":  2,
+	}
+	for precision, ts := range timestamps {
+		pt := models.MustNewPoint(
+			name,
+			models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "precision": precision}),
+			map[string]interface{}{"value": 1.0},
+			time.Unix(0, ts),
+		)
+		if err := engine.WritePointsWithPrecision(context.Background(), []models.Point{pt}, precision); err != nil {
+			t.Fatalf("precision %s: %v", precision, err)
+		}
+	}
+
+	itr, err := engine.CreateCursorIterator(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for precision := range timestamps {
+		cur, err := itr.Next(context.Background(), &cursors.CursorRequest{
+			Name:      []byte(name),
+			Tags:      models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "precision": precision}),
+			Field:     "value",
+			Ascending: true,
+			StartTime: math.MinInt64,
+			EndTime:   math.MaxInt64,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cur == nil {
+			t.Fatalf("precision %s: no series found", precision)
+		}
+		a := cur.(cursors.FloatArrayCursor).Next()
+		if got, exp := a.Timestamps, []int64{int64(2 * time.Hour)}; !reflect.DeepEqual(got, exp) {
+			t.Fatalf("precision %s: got timestamps %v, exp %v", precision, got, exp)
+		}
+		cur.Close()
+	}
+}
+
+func TestEngine_WritePointsWithPrecision_Invalid(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	pt := models.MustNewPoint(
+		tsdb.EncodeNameString(engine.org, engine.bucket),
+		models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu"}),
+		map[string]interface{}{"value": 1.0},
+		time.Unix(0, math.MaxInt64/2),
+	)
+
+	if err := engine.WritePointsWithPrecision(context.Background(), []models.Point{pt}, "d"); err == nil {
+		t.Fatal("expected error for unknown precision")
+	}
+	if err := engine.WritePointsWithPrecision(context.Background(), []models.Point{pt}, "s"); err != models.ErrTimeOutOfRange {
+		t.Fatalf("got %v, expected %v", err, models.ErrTimeOutOfRange)
+	}
+}
+
 func TestEngine_WriteAddNewField(t *testing.T) {
 	engine := NewDefaultEngine()
 	defer engine.Close()
@@ -202,6 +280,39 @@ func TestEngine_WriteAddNewField(t *testing.T) {
 	}
 }
 
+func TestEngine_PointsExist(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	name := tsdb.EncodeNameString(engine.org, engine.bucket)
+	point := func(field, host string, ts time.Time) models.Point {
+		return models.MustNewPoint(
+			name,
+			models.NewTags(map[string]string{models.FieldKeyTagKey: field, models.MeasurementTagKey: "cpu", "host": host}),
+			map[string]interface{}{field: 1.0},
+			ts,
+		)
+	}
+
+	if err := engine.Engine.WritePoints(context.TODO(), []models.Point{point("value", "server", time.Unix(1, 2))}); err != nil {
+		t.Fatal(err)
+	}
+
+	exist, err := engine.PointsExist(context.Background(), []models.Point{
+		point("value", "server", time.Unix(1, 2)),
+		point("value", "server", time.Unix(1, 3)),
+		point("value2", "server", time.Unix(1, 2)),
+		point("value", "server2", time.Unix(1, 2)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []bool{true, false, false, false}; !reflect.DeepEqual(exist, exp) {
+		t.Fatalf("got %v, exp %v", exist, exp)
+	}
+}
+
 func TestEngine_DeleteBucket(t *testing.T) {
 	engine := NewDefaultEngine()
 	defer engine.Close()
@@ -254,6 +365,191 @@ func TestEngine_DeleteBucket(t *testing.T) {
 	}
 }
 
+func TestEngine_SeriesCardinalityForBucket(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	orgID, _ := influxdb.IDFromString("3131313131313131")
+	bucketID, _ := influxdb.IDFromString("8888888888888888")
+
+	err := engine.Engine.WritePoints(context.TODO(), []models.Point{models.MustNewPoint(
+		tsdb.EncodeNameString(engine.org, engine.bucket),
+		models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": "server"}),
+		map[string]interface{}{"value": 1.0},
+		time.Unix(1, 2),
+	)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Same org, different bucket.
+	err = engine.Engine.WritePoints(context.TODO(), []models.Point{
+		models.MustNewPoint(
+			tsdb.EncodeNameString(*orgID, *bucketID),
+			models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": "server"}),
+			map[string]interface{}{"value": 1.0},
+			time.Unix(1, 3),
+		),
+		models.MustNewPoint(
+			tsdb.EncodeNameString(*orgID, *bucketID),
+			models.NewTags(map[string]string{models.FieldKeyTagKey: "value2", models.MeasurementTagKey: "cpu", "host": "server"}),
+			map[string]interface{}{"value2": 2.0},
+			time.Unix(1, 3),
+		),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n1, err := engine.SeriesCardinalityForBucket(context.Background(), engine.org, engine.bucket)
+	if err != nil {
+		t.Fatal(err)
+	} else if got, exp := n1, int64(1); got != exp {
+		t.Fatalf("got %d series, exp %d series in bucket", got, exp)
+	}
+
+	n2, err := engine.SeriesCardinalityForBucket(context.Background(), *orgID, *bucketID)
+	if err != nil {
+		t.Fatal(err)
+	} else if got, exp := n2, int64(2); got != exp {
+		t.Fatalf("got %d series, exp %d series in bucket", got, exp)
+	}
+
+	if got, exp := n1+n2, engine.SeriesCardinality(); got != exp {
+		t.Fatalf("got %d series across buckets, exp %d series in index", got, exp)
+	}
+
+	// An unknown bucket has no series.
+	n, err := engine.SeriesCardinalityForBucket(context.Background(), *orgID, influxdb.ID(1))
+	if err != nil {
+		t.Fatal(err)
+	} else if n != 0 {
+		t.Fatalf("got %d series, exp 0 series in bucket", n)
+	}
+}
+
+func TestEngine_Buckets(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	orgID, _ := influxdb.IDFromString("3131313131313131")
+	bucketID, _ := influxdb.IDFromString("8888888888888888")
+
+	err := engine.Engine.WritePoints(context.TODO(), []models.Point{
+		models.MustNewPoint(
+			tsdb.EncodeNameString(engine.org, engine.bucket),
+			models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": "server"}),
+			map[string]interface{}{"value": 1.0},
+			time.Unix(1, 2),
+		),
+		models.MustNewPoint(
+			tsdb.EncodeNameString(*orgID, *bucketID),
+			models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": "server"}),
+			map[string]interface{}{"value": 1.0},
+			time.Unix(1, 3),
+		),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buckets, err := engine.Buckets(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []storage.OrgBucket{
+		{Org: engine.org, Bucket: engine.bucket},
+		{Org: *orgID, Bucket: *bucketID},
+	}
+	if !reflect.DeepEqual(buckets, exp) {
+		t.Fatalf("got buckets %v, exp %v", buckets, exp)
+	}
+
+	// Remove the original bucket.
+	if err := engine.DeleteBucket(context.Background(), engine.org, engine.bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	buckets, err = engine.Buckets(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp = []storage.OrgBucket{{Org: *orgID, Bucket: *bucketID}}
+	if !reflect.DeepEqual(buckets, exp) {
+		t.Fatalf("got buckets %v, exp %v", buckets, exp)
+	}
+}
+
+func TestEngine_DeleteBuckets(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	bucketIDs := make([]influxdb.ID, 3)
+	for i, id := range []string{"1111111111111111", "2222222222222222", "3333333333333333"} {
+		bucketID, _ := influxdb.IDFromString(id)
+		bucketIDs[i] = *bucketID
+
+		err := engine.Engine.WritePoints(context.TODO(), []models.Point{
+			models.MustNewPoint(
+				tsdb.EncodeNameString(engine.org, *bucketID),
+				models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": "server"}),
+				map[string]interface{}{"value": 1.0},
+				time.Unix(1, 2),
+			),
+			models.MustNewPoint(
+				tsdb.EncodeNameString(engine.org, *bucketID),
+				models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "mem", "host": "server"}),
+				map[string]interface{}{"value": 1.0},
+				time.Unix(1, 2),
+			),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got, exp := engine.SeriesCardinality(), int64(6); got != exp {
+		t.Fatalf("got %d series, exp %d series in index", got, exp)
+	}
+
+	// Remove the first two buckets.
+	if err := engine.DeleteBuckets(context.Background(), engine.org, bucketIDs[:2]); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check only the series of the third bucket remain.
+	if got, exp := engine.SeriesCardinality(), int64(2); got != exp {
+		t.Fatalf("got %d series, exp %d series in index", got, exp)
+	}
+
+	// The data of the third bucket survives.
+	itr, err := engine.CreateCursorIterator(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cur, err := itr.Next(context.Background(), &cursors.CursorRequest{
+		Name:      []byte(tsdb.EncodeNameString(engine.org, bucketIDs[2])),
+		Tags:      models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": "server"}),
+		Field:     "value",
+		Ascending: true,
+		StartTime: math.MinInt64,
+		EndTime:   math.MaxInt64,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cur == nil {
+		t.Fatal("expected series of the third bucket")
+	}
+	defer cur.Close()
+	if got, exp := cur.(cursors.FloatArrayCursor).Next().Values, []float64{1.0}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got values %v, exp %v", got, exp)
+	}
+}
+
 func TestEngine_DeleteBucket_Predicate(t *testing.T) {
 	engine := NewDefaultEngine()
 	defer engine.Close()
@@ -351,6 +647,79 @@ func TestEngine_DeleteBucket_Predicate(t *testing.T) {
 
 }
 
+func TestEngine_DeleteBucket_Predicate_SeriesDeletedMetric(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(engine.PrometheusCollectors()...)
+
+	// The metrics are shared by all engines, so only look at the change.
+	deleted := func() float64 {
+		m := promtest.MustFindMetric(t, promtest.MustGather(t, reg), "storage_series_deleted_total", nil)
+		return m.GetCounter().GetValue()
+	}
+	before := deleted()
+
+	p := func(m string, kvs ...string) models.Point {
+		tags := map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: m}
+		for i := 0; i < len(kvs)-1; i += 2 {
+			tags[kvs[i]] = kvs[i+1]
+		}
+		return models.MustNewPoint(
+			tsdb.EncodeNameString(engine.org, engine.bucket),
+			models.NewTags(tags),
+			map[string]interface{}{"value": 1.0},
+			time.Unix(1, 2),
+		)
+	}
+
+	err := engine.Engine.WritePoints(context.TODO(), []models.Point{
+		p("cpu", "region", "east", "host", "a"),
+		p("cpu", "region", "east", "host", "b"),
+		p("cpu", "region", "west", "host", "a"),
+		p("mem", "region", "east", "host", "a"),
+		p("mem", "region", "west", "host", "a"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cardinality := engine.SeriesCardinality()
+
+	// Construct a predicate to remove region=east
+	pred, err := tsm1.NewProtobufPredicate(&datatypes.Predicate{
+		Root: &datatypes.Node{
+			NodeType: datatypes.NodeTypeComparisonExpression,
+			Value:    &datatypes.Node_Comparison_{Comparison: datatypes.ComparisonEqual},
+			Children: []*datatypes.Node{
+				{NodeType: datatypes.NodeTypeTagRef,
+					Value: &datatypes.Node_TagRefValue{TagRefValue: "region"},
+				},
+				{NodeType: datatypes.NodeTypeLiteral,
+					Value: &datatypes.Node_StringValue{StringValue: "east"},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := engine.DeleteBucketRangePredicate(context.Background(), engine.org, engine.bucket,
+		math.MinInt64, math.MaxInt64, pred); err != nil {
+		t.Fatal(err)
+	}
+
+	drop := cardinality - engine.SeriesCardinality()
+	if drop != 3 {
+		t.Fatalf("got a drop of %d series, exp %d", drop, 3)
+	}
+	if got, exp := deleted()-before, float64(drop); got != exp {
+		t.Fatalf("got %v series deleted, exp %v", got, exp)
+	}
+}
+
 func TestEngine_OpenClose(t *testing.T) {
 	engine := NewDefaultEngine()
 	engine.MustOpen()
@@ -395,6 +764,114 @@ func TestEngine_InitializeMetrics(t *testing.T) {
 	}
 }
 
+func TestEngine_ForceSnapshot(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(engine.PrometheusCollectors()...)
+
+	files := func() float64 {
+		var n float64
+		for _, mf := range promtest.MustGather(t, reg) {
+			if mf.GetName() != "storage_tsm_files_total" {
+				continue
+			}
+			for _, m := range mf.GetMetric() {
+				n += m.GetGauge().GetValue()
+			}
+		}
+		return n
+	}
+	if got := files(); got != 0 {
+		t.Fatalf("got %v TSM files before writing, exp 0", got)
+	}
+
+	pt := models.MustNewPoint(
+		tsdb.EncodeNameString(engine.org, engine.bucket),
+		models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu"}),
+		map[string]interface{}{"value": 1.0},
+		time.Unix(1, 2),
+	)
+	if err := engine.Engine.WritePoints(context.Background(), []models.Point{pt}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := engine.ForceSnapshot(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := files(); got != 1 {
+		t.Fatalf("got %v TSM files after snapshot, exp 1", got)
+	}
+
+	// The data is still readable once it has been moved out of the cache.
+	exists, err := engine.PointsExist(context.Background(), []models.Point{pt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exists[0] {
+		t.Fatal("point was not found after snapshot")
+	}
+
+	if err := engine.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.ForceSnapshot(context.Background()); err != storage.ErrEngineClosed {
+		t.Fatalf("got error %v, exp %v", err, storage.ErrEngineClosed)
+	}
+}
+
+func TestEngine_WritesRejectedMetrics(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(engine.PrometheusCollectors()...)
+
+	// The metrics are shared by all engines, so only look at the change.
+	rejected := func(reason string) float64 {
+		m := promtest.FindMetric(promtest.MustGather(t, reg), "storage_writes_rejected_total", prometheus.Labels{"reason": reason})
+		return m.GetCounter().GetValue()
+	}
+	before := map[string]float64{}
+	for _, reason := range []string{"time_field", "invalid_tag", "type_conflict"} {
+		before[reason] = rejected(reason)
+	}
+
+	name := tsdb.EncodeNameString(engine.org, engine.bucket)
+	points := []models.Point{
+		models.MustNewPoint(
+			name,
+			models.NewTags(map[string]string{models.FieldKeyTagKey: "time", models.MeasurementTagKey: "cpu"}),
+			map[string]interface{}{"time": 1.0},
+			time.Unix(1, 2),
+		),
+		models.MustNewPoint(
+			name,
+			models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu"}),
+			map[string]interface{}{"value": 1.0},
+			time.Unix(1, 2),
+		),
+		models.MustNewPoint(
+			name,
+			models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu"}),
+			map[string]interface{}{"value": 2},
+			time.Unix(1, 3),
+		),
+	}
+	if err := engine.Engine.WritePoints(context.TODO(), points); err == nil {
+		t.Fatal("expected error: got nil")
+	}
+
+	for reason, exp := range map[string]float64{"time_field": 1, "invalid_tag": 0, "type_conflict": 1} {
+		if got := rejected(reason) - before[reason]; got != exp {
+			t.Errorf("reason %s: got %v rejected points, expected %v", reason, got, exp)
+		}
+	}
+}
+
 // Ensures that when a shard is closed, it removes any series meta-data
 // from the index.
 func TestEngineClose_RemoveIndex(t *testing.T) {
@@ -455,6 +932,60 @@ func TestEngine_WALDisabled(t *testing.T) {
 	}
 }
 
+func TestEngine_WritePointsNoWAL(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	name := tsdb.EncodeNameString(engine.org, engine.bucket)
+	tags := models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": "server"})
+	pt := models.MustNewPoint(name, tags, map[string]interface{}{"value": 1.0}, time.Unix(1, 2))
+
+	if err := engine.Engine.WritePoints(storage.WithNoWAL(context.Background()), []models.Point{pt}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing should have been written to the WAL.
+	segments, err := wal.SegmentFileNames(storage.NewConfig().GetWALPath(engine.path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, segment := range segments {
+		fi, err := os.Stat(segment)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fi.Size() != 0 {
+			t.Fatalf("got %d bytes in WAL segment %s, exp 0", fi.Size(), segment)
+		}
+	}
+
+	// The point is still readable from the cache.
+	itr, err := engine.CreateCursorIterator(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cur, err := itr.Next(context.Background(), &cursors.CursorRequest{
+		Name:      []byte(name),
+		Tags:      tags,
+		Field:     "value",
+		Ascending: true,
+		StartTime: math.MinInt64,
+		EndTime:   math.MaxInt64,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cur == nil {
+		t.Fatal("no series found")
+	}
+	defer cur.Close()
+	a := cur.(cursors.FloatArrayCursor).Next()
+	if got, exp := a.Values, []float64{1.0}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got values %v, exp %v", got, exp)
+	}
+}
+
 func TestEngine_WriteConflictingBatch(t *testing.T) {
 	engine := NewDefaultEngine()
 	defer engine.Close()
@@ -481,6 +1012,238 @@ func TestEngine_WriteConflictingBatch(t *testing.T) {
 	}
 }
 
+func TestEngine_WriteConflictingBatch_Counts(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	name := tsdb.EncodeNameString(engine.org, engine.bucket)
+
+	const n = 5
+	points := make([]models.Point, 0, n)
+	for i := 0; i < n-1; i++ {
+		points = append(points, models.MustNewPoint(
+			name,
+			models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": fmt.Sprintf("server%d", i)}),
+			map[string]interface{}{"value": float64(i)},
+			time.Unix(1, 2),
+		))
+	}
+	// Two points of the same series conflict with the type of the first point.
+	for i := 0; i < 2; i++ {
+		points = append(points, models.MustNewPoint(
+			name,
+			models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": "server0"}),
+			map[string]interface{}{"value": 2},
+			time.Unix(1, int64(3+i)),
+		))
+	}
+
+	err := engine.Engine.WritePoints(context.TODO(), points)
+	pwe, ok := err.(tsdb.PartialWriteError)
+	if !ok {
+		t.Fatal("expected partial write error. got:", err)
+	}
+	if got, exp := pwe.Written, n-1; got != exp {
+		t.Errorf("got written %d, exp %d", got, exp)
+	}
+	if got, exp := pwe.Dropped, 1; got != exp {
+		t.Errorf("got dropped keys %d, exp %d", got, exp)
+	}
+	if got, exp := pwe.DroppedPoints, 2; got != exp {
+		t.Errorf("got dropped points %d, exp %d", got, exp)
+	}
+	if got, exp := pwe.Reasons, []string{pwe.Reason}; !reflect.DeepEqual(got, exp) {
+		t.Errorf("got reasons %v, exp %v", got, exp)
+	}
+}
+
+func TestEngine_PointCountEstimate(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	name := tsdb.EncodeNameString(engine.org, engine.bucket)
+	point := func(host string, sec int64) models.Point {
+		return models.MustNewPoint(
+			name,
+			models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": host}),
+			map[string]interface{}{"value": 1.0},
+			time.Unix(sec, 0),
+		)
+	}
+
+	// Write points into the first and last of four 10s windows, and snapshot
+	// them so that the estimate is made from TSM files.
+	if err := engine.Engine.WritePoints(context.TODO(), []models.Point{
+		point("server0", 1), point("server0", 2), point("server0", 3),
+		point("server1", 31), point("server1", 32),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.Engine.ForceSnapshot(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+
+	// This point is only in the cache.
+	if err := engine.Engine.WritePoints(context.TODO(), []models.Point{point("server1", 33)}); err != nil {
+		t.Fatal(err)
+	}
+
+	window := int64(10 * time.Second)
+	counts, err := engine.Engine.PointCountEstimate(context.TODO(), engine.org, engine.bucket, 0, 4*window-1, window)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := len(counts), 4; got != exp {
+		t.Fatalf("got %d windows, exp %d", got, exp)
+	}
+	if counts[0] == 0 || counts[3] == 0 {
+		t.Errorf("got counts %v, exp non-zero first and last windows", counts)
+	}
+	if counts[1] != 0 || counts[2] != 0 {
+		t.Errorf("got counts %v, exp empty middle windows", counts)
+	}
+
+	if _, err := engine.Engine.PointCountEstimate(context.TODO(), engine.org, engine.bucket, 0, window, 0); err == nil {
+		t.Error("expected error for a zero window")
+	}
+}
+
+func TestEngine_WritePoints_CacheHighWatermark(t *testing.T) {
+	c := storage.NewConfig()
+	c.Engine.Cache.MaxMemorySize = 4096
+	c.Engine.Cache.HighWatermark = 0.5
+	engine := NewEngine(c)
+	defer engine.Close()
+	engine.MustOpen()
+
+	name := tsdb.EncodeNameString(engine.org, engine.bucket)
+	points := func(start, n int) []models.Point {
+		points := make([]models.Point, 0, n)
+		for i := start; i < start+n; i++ {
+			points = append(points, models.MustNewPoint(
+				name,
+				models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": "server"}),
+				map[string]interface{}{"value": float64(i)},
+				time.Unix(int64(i), 0),
+			))
+		}
+		return points
+	}
+
+	// Write small batches until the cache passes its high watermark.
+	var err error
+	for i := 0; i < 4096 && err == nil; i++ {
+		err = engine.Engine.WritePoints(context.TODO(), points(i, 1))
+	}
+	if got, exp := influxdb.ErrorCode(err), influxdb.EUnavailable; got != exp {
+		t.Fatalf("got error code %q, exp %q: %v", got, exp, err)
+	}
+
+	// A write that would exceed the hard limit still fails with the cache size error.
+	err = engine.Engine.WritePoints(context.TODO(), points(10000, 512))
+	if _, ok := err.(tsm1.CacheMemorySizeLimitExceededError); !ok {
+		t.Fatalf("expected cache size limit error, got %v", err)
+	}
+	if got := influxdb.ErrorCode(err); got == influxdb.EUnavailable {
+		t.Fatalf("expected hard cache limit error to not be retryable, got code %q", got)
+	}
+
+	// A rejected write of a new series is neither indexed nor written to the WAL.
+	cardinality := engine.SeriesCardinality()
+	pt := models.MustNewPoint(
+		name,
+		models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": "rejected"}),
+		map[string]interface{}{"value": 1.0},
+		time.Unix(1, 0),
+	)
+	err = engine.Engine.WritePoints(context.TODO(), []models.Point{pt})
+	if got, exp := influxdb.ErrorCode(err), influxdb.EUnavailable; got != exp {
+		t.Fatalf("got error code %q, exp %q: %v", got, exp, err)
+	}
+	if got := engine.SeriesCardinality(); got != cardinality {
+		t.Fatalf("got series cardinality %d, exp %d", got, cardinality)
+	}
+
+	if err := engine.Engine.Close(); err != nil {
+		t.Fatal(err)
+	}
+	engine.Engine = storage.NewEngine(engine.path, storage.NewConfig())
+	if err := engine.Engine.Open(context.Background()); err != nil {
+		t.Fatalf("unexpected error replaying the WAL: %v", err)
+	}
+	if got := engine.SeriesCardinality(); got != cardinality {
+		t.Fatalf("got series cardinality %d after replaying the WAL, exp %d", got, cardinality)
+	}
+}
+
+func TestEngine_ReplayWAL_CacheHighWatermark(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	name := tsdb.EncodeNameString(engine.org, engine.bucket)
+	tags := models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": "server"})
+	points := make([]models.Point, 0, 128)
+	for i := 0; i < cap(points); i++ {
+		points = append(points, models.MustNewPoint(name, tags, map[string]interface{}{"value": float64(i)}, time.Unix(int64(i), 0)))
+	}
+	if err := engine.Engine.WritePoints(context.TODO(), points); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.Engine.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reopen with a high watermark below the size of the WAL.
+	c := storage.NewConfig()
+	c.Engine.Cache.MaxMemorySize = 8192
+	c.Engine.Cache.HighWatermark = 0.1
+	engine.Engine = storage.NewEngine(engine.path, c)
+	if err := engine.Engine.Open(context.Background()); err != nil {
+		t.Fatalf("unexpected error replaying the WAL: %v", err)
+	}
+
+	// The watermark is in force again once the WAL is replayed.
+	pt := models.MustNewPoint(name, tags, map[string]interface{}{"value": 1.0}, time.Unix(1000, 0))
+	err := engine.Engine.WritePoints(context.TODO(), []models.Point{pt})
+	if got, exp := influxdb.ErrorCode(err), influxdb.EUnavailable; got != exp {
+		t.Fatalf("got error code %q, exp %q: %v", got, exp, err)
+	}
+}
+
+func TestEngine_WritePointsDetailed(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	name := tsdb.EncodeNameString(engine.org, engine.bucket)
+
+	result, err := engine.Engine.WritePointsDetailed(context.TODO(), []models.Point{
+		models.MustNewPoint(
+			name,
+			models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": "server"}),
+			map[string]interface{}{"value": 1.0},
+			time.Unix(1, 2),
+		),
+		models.MustNewPoint(
+			name,
+			models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": "server"}),
+			map[string]interface{}{"value": 2},
+			time.Unix(1, 2),
+		),
+	})
+	if _, ok := err.(tsdb.PartialWriteError); !ok {
+		t.Fatal("expected partial write error. got:", err)
+	}
+
+	exp := []storage.RejectedPoint{{Index: 1, Reason: storage.RejectReasonTypeConflict}}
+	if !reflect.DeepEqual(result.Rejected, exp) {
+		t.Fatalf("got rejected points %+v, exp %+v", result.Rejected, exp)
+	}
+}
+
 func BenchmarkDeleteBucket(b *testing.B) {
 	var engine *Engine
 	setup := func(card int) {
@@ -12,6 +12,7 @@ import (
 // monitored within the same process.
 var (
 	rms *retentionMetrics
+	wms *writeMetrics
 	mmu sync.RWMutex
 )
 
@@ -27,11 +28,25 @@ func RetentionPrometheusCollectors() []prometheus.Collector {
 	return collectors
 }
 
+// WritePrometheusCollectors returns all prometheus metrics for writes.
+func WritePrometheusCollectors() []prometheus.Collector {
+	mmu.RLock()
+	defer mmu.RUnlock()
+
+	var collectors []prometheus.Collector
+	if wms != nil {
+		collectors = append(collectors, wms.PrometheusCollectors()...)
+	}
+	return collectors
+}
+
 // namespace is the leading part of all published metrics for the Storage service.
 const namespace = "storage"
 
 const retentionSubsystem = "retention" // sub-system associated with metrics for writing points.
 
+const writeSubsystem = "writes" // sub-system associated with metrics for points written to the engine.
+
 // retentionMetrics is a set of metrics concerned with tracking data about retention policies.
 type retentionMetrics struct {
 	labels        prometheus.Labels
@@ -88,3 +103,81 @@ func (rm *retentionMetrics) PrometheusCollectors() []prometheus.Collector {
 		rm.CheckDuration,
 	}
 }
+
+// The reasons a point can be rejected by the engine. They label the rejected
+// points metric and are reported by WritePointsDetailed.
+const (
+	RejectReasonTimeField    = "time_field"
+	RejectReasonInvalidTag   = "invalid_tag"
+	RejectReasonTypeConflict = "type_conflict"
+)
+
+// writeMetrics is a set of metrics concerned with tracking data about points
+// written to the engine.
+type writeMetrics struct {
+	labels   prometheus.Labels
+	Rejected *prometheus.CounterVec
+}
+
+func newWriteMetrics(labels prometheus.Labels) *writeMetrics {
+	var names []string
+	for k := range labels {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	rejectedNames := append(append([]string(nil), names...), "reason")
+	sort.Strings(rejectedNames)
+
+	return &writeMetrics{
+		labels: labels,
+		Rejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: writeSubsystem,
+			Name:      "rejected_total",
+			Help:      "Number of points rejected because they failed validation.",
+		}, rejectedNames),
+	}
+}
+
+// Labels returns a copy of labels for use with write metrics.
+func (m *writeMetrics) Labels() prometheus.Labels {
+	l := make(map[string]string, len(m.labels))
+	for k, v := range m.labels {
+		l[k] = v
+	}
+	return l
+}
+
+// PrometheusCollectors satisfies the prom.PrometheusCollector interface.
+func (m *writeMetrics) PrometheusCollectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.Rejected,
+	}
+}
+
+// writeTracker tracks the points written to the engine.
+type writeTracker struct {
+	metrics *writeMetrics
+	labels  prometheus.Labels
+}
+
+func newWriteTracker(metrics *writeMetrics, defaultLabels prometheus.Labels) *writeTracker {
+	return &writeTracker{metrics: metrics, labels: defaultLabels}
+}
+
+// Labels returns a copy of labels for use with write metrics.
+func (t *writeTracker) Labels() prometheus.Labels {
+	l := make(map[string]string, len(t.labels))
+	for k, v := range t.labels {
+		l[k] = v
+	}
+	return l
+}
+
+// AddRejected signals that n points were rejected for the provided reason.
+func (t *writeTracker) AddRejected(reason string, n uint64) {
+	labels := t.Labels()
+	labels["reason"] = reason
+	t.metrics.Rejected.With(labels).Add(float64(n))
+}
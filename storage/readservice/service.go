@@ -51,5 +51,7 @@ func AddControllerConfigDependencies(
 		BucketLookup:       bucketLookupSvc,
 		OrganizationLookup: orgLookupSvc,
 		PointsWriter:       engine,
+		PointsFinder:       engine,
+		Logger:             cc.Logger,
 	})
 }
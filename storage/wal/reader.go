@@ -9,9 +9,19 @@ import (
 
 // WALReader helps one read out the WAL into entries.
 type WALReader struct {
-	files  []string
-	logger *zap.Logger
-	r      *WALSegmentReader
+	files     []string
+	logger    *zap.Logger
+	onCorrupt func(SegmentCorruption)
+	r         *WALSegmentReader
+}
+
+// SegmentCorruption describes the corrupt tail of a segment file that was
+// truncated while reading the WAL.
+type SegmentCorruption struct {
+	Path string // path of the segment file
+	Pos  int64  // position of the first byte that could not be read
+	Size int64  // number of bytes truncated from the segment
+	Err  error  // error encountered reading the entry at Pos
 }
 
 // NewWALReader constructs a WALReader over the given set of files.
@@ -27,6 +37,10 @@ func NewWALReader(files []string) *WALReader {
 // WithLogger sets the logger for the WALReader.
 func (r *WALReader) WithLogger(logger *zap.Logger) { r.logger = logger }
 
+// WithCorruptionHandler sets a function to be called for each segment file
+// whose tail is truncated because of corruption.
+func (r *WALReader) WithCorruptionHandler(fn func(SegmentCorruption)) { r.onCorrupt = fn }
+
 // Read calls the callback with every entry in the WAL files. If, during
 // reading of a segment file, corruption is encountered, that segment file
 // is truncated up to and including the last valid byte, and processing
@@ -74,6 +88,9 @@ func (r *WALReader) readFile(file string, cb func(WALEntry) error) error {
 			if err := f.Truncate(n); err != nil {
 				return err
 			}
+			if r.onCorrupt != nil {
+				r.onCorrupt(SegmentCorruption{Path: file, Pos: n, Size: stat.Size() - n, Err: err})
+			}
 			break
 		}
 
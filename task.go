@@ -18,6 +18,9 @@ const (
 	TaskDefaultPageSize = 100
 	TaskMaxPageSize     = 500
 
+	// RunDefaultPageSize is the number of runs returned when no limit is given.
+	RunDefaultPageSize = 100
+
 	TaskStatusActive   = "active"
 	TaskStatusInactive = "inactive"
 
@@ -26,23 +29,25 @@ const (
 
 // Task is a task. 🎊
 type Task struct {
-	ID              ID             `json:"id"`
-	Type            string         `json:"type,omitempty"`
-	OrganizationID  ID             `json:"orgID"`
-	Organization    string         `json:"org"`
-	AuthorizationID ID             `json:"-"`
-	Authorization   *Authorization `json:"-"`
-	OwnerID         ID             `json:"ownerID"`
-	Name            string         `json:"name"`
-	Description     string         `json:"description,omitempty"`
-	Status          string         `json:"status"`
-	Flux            string         `json:"flux"`
-	Every           string         `json:"every,omitempty"`
-	Cron            string         `json:"cron,omitempty"`
-	Offset          string         `json:"offset,omitempty"`
-	LatestCompleted string         `json:"latestCompleted,omitempty"`
-	CreatedAt       string         `json:"createdAt,omitempty"`
-	UpdatedAt       string         `json:"updatedAt,omitempty"`
+	ID                   ID             `json:"id"`
+	Type                 string         `json:"type,omitempty"`
+	OrganizationID       ID             `json:"orgID"`
+	Organization         string         `json:"org"`
+	AuthorizationID      ID             `json:"-"`
+	Authorization        *Authorization `json:"-"`
+	OwnerID              ID             `json:"ownerID"`
+	Name                 string         `json:"name"`
+	Description          string         `json:"description,omitempty"`
+	Status               string         `json:"status"`
+	Flux                 string         `json:"flux"`
+	Every                string         `json:"every,omitempty"`
+	Cron                 string         `json:"cron,omitempty"`
+	Offset               string         `json:"offset,omitempty"`
+	EffectiveConcurrency int64          `json:"effectiveConcurrency,omitempty"` // EffectiveConcurrency is the number of runs that may execute at once
+	LatestCompleted      string         `json:"latestCompleted,omitempty"`
+	CreatedAt            string         `json:"createdAt,omitempty"`
+	UpdatedAt            string         `json:"updatedAt,omitempty"`
+	InactiveSince        string         `json:"inactiveSince,omitempty"` // InactiveSince is the time the task was last made inactive
 }
 
 // EffectiveCron returns the effective cron string of the options.
@@ -70,6 +75,20 @@ type Run struct {
 	FinishedAt   string `json:"finishedAt,omitempty"`  // FinishedAt is the time the executor finishes running the task
 	RequestedAt  string `json:"requestedAt,omitempty"` // RequestedAt is the time the coordinator told the scheduler to schedule the task
 	Log          []Log  `json:"log,omitempty"`
+	Note         string `json:"note,omitempty"`         // Note is the reason given when the run was forced
+	ErrorMessage string `json:"errorMessage,omitempty"` // ErrorMessage is the reason the run failed
+}
+
+// RunErrorMessage returns the message of the first error level entry of logs,
+// or an empty string if there is none. Later error entries, such as the one
+// the scheduler adds when marking a run failed, only summarize the failure.
+func RunErrorMessage(logs []Log) string {
+	for _, l := range logs {
+		if l.GetLevel() == LogLevelError {
+			return l.Message
+		}
+	}
+	return ""
 }
 
 // ScheduledForTime gives the time.Time that the run is scheduled for.
@@ -82,6 +101,28 @@ func (r *Run) StartedAtTime() (time.Time, error) {
 	return time.Parse(time.RFC3339Nano, r.StartedAt)
 }
 
+// FinishedAtTime gives the time.Time that the run finished.
+func (r *Run) FinishedAtTime() (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, r.FinishedAt)
+}
+
+// Duration returns how long the run took to execute. It returns false if
+// the run has not both started and finished.
+func (r *Run) Duration() (time.Duration, bool) {
+	if r.StartedAt == "" || r.FinishedAt == "" {
+		return 0, false
+	}
+	startedAt, err := r.StartedAtTime()
+	if err != nil {
+		return 0, false
+	}
+	finishedAt, err := r.FinishedAtTime()
+	if err != nil {
+		return 0, false
+	}
+	return finishedAt.Sub(startedAt), true
+}
+
 // RequestedAtTime gives the time.Time that the run was requested.
 func (r *Run) RequestedAtTime() (time.Time, error) {
 	return time.Parse(time.RFC3339, r.RequestedAt)
@@ -89,15 +130,51 @@ func (r *Run) RequestedAtTime() (time.Time, error) {
 
 // Log represents a link to a log resource
 type Log struct {
-	RunID   ID     `json:"runID,omitempty"`
-	Time    string `json:"time"`
-	Message string `json:"message"`
+	RunID   ID       `json:"runID,omitempty"`
+	Time    string   `json:"time"`
+	Level   LogLevel `json:"level,omitempty"`
+	Message string   `json:"message"`
 }
 
 func (l Log) String() string {
 	return l.Time + ": " + l.Message
 }
 
+// GetLevel returns the level of the log. Logs without a level are at info.
+func (l Log) GetLevel() LogLevel {
+	if l.Level == "" {
+		return LogLevelInfo
+	}
+	return l.Level
+}
+
+// LogLevel is the severity of a task run log.
+type LogLevel string
+
+// Log levels, from the least to the most severe.
+const (
+	LogLevelInfo  LogLevel = "info"
+	LogLevelWarn  LogLevel = "warn"
+	LogLevelError LogLevel = "error"
+)
+
+var logLevelSeverity = map[LogLevel]int{
+	LogLevelInfo:  0,
+	LogLevelWarn:  1,
+	LogLevelError: 2,
+}
+
+// Valid returns an error if the log level is unknown.
+func (l LogLevel) Valid() error {
+	if _, ok := logLevelSeverity[l]; !ok {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid log level %q, must be one of info, warn or error", l),
+		}
+	}
+	return nil
+}
+
 // TaskService represents a service for managing one-off and recurring tasks.
 type TaskService interface {
 	// FindTaskByID returns a single task
@@ -107,6 +184,10 @@ type TaskService interface {
 	// of matching tasks.
 	FindTasks(ctx context.Context, filter TaskFilter) ([]*Task, int, error)
 
+	// CountTasks returns the number of tasks that match a filter, regardless of
+	// its limit and paging, which the authorizer associated with ctx may read.
+	CountTasks(ctx context.Context, filter TaskFilter) (int, error)
+
 	// CreateTask creates a new task.
 	// The owner of the task is inferred from the authorizer associated with ctx.
 	CreateTask(ctx context.Context, t TaskCreate) (*Task, error)
@@ -129,12 +210,22 @@ type TaskService interface {
 	// CancelRun cancels a currently running run.
 	CancelRun(ctx context.Context, taskID, runID ID) error
 
+	// CancelTaskRuns cancels every currently running run of a task and returns the IDs of the canceled runs.
+	CancelTaskRuns(ctx context.Context, taskID ID) ([]ID, error)
+
 	// RetryRun creates and returns a new run (which is a retry of another run).
-	RetryRun(ctx context.Context, taskID, runID ID) (*Run, error)
+	// The new run is scheduled for the unix timestamp scheduledFor if it is set,
+	// or the scheduled time of the retried run otherwise.
+	// The new run keeps the note of the retried run, so a retry of a forced run
+	// still records why it was forced.
+	RetryRun(ctx context.Context, taskID, runID ID, scheduledFor *int64) (*Run, error)
 
 	// ForceRun forces a run to occur with unix timestamp scheduledFor, to be executed as soon as possible.
 	// The value of scheduledFor may or may not align with the task's schedule.
 	ForceRun(ctx context.Context, taskID ID, scheduledFor int64) (*Run, error)
+
+	// ForceRunWithNote is ForceRun with a note that records why the run was forced.
+	ForceRunWithNote(ctx context.Context, taskID ID, scheduledFor int64, note string) (*Run, error)
 }
 
 // TaskCreate is the set of values to create a task.
@@ -146,6 +237,11 @@ type TaskCreate struct {
 	OrganizationID ID     `json:"orgID,omitempty"`
 	Organization   string `json:"org,omitempty"`
 	OwnerID        ID     `json:"-"`
+	// Token optionally sets the authorization the task runs with, instead of
+	// the permissions of its owner. It must belong to the task's organization.
+	Token string `json:"token,omitempty"`
+	// Labels are the IDs of labels to attach to the task once it is created.
+	Labels []ID `json:"labels,omitempty"`
 }
 
 func (t TaskCreate) Validate() error {
@@ -160,6 +256,57 @@ func (t TaskCreate) Validate() error {
 	return nil
 }
 
+// TaskExportVersion is the version of the TaskExport document written by this release.
+const TaskExportVersion = 1
+
+// TaskExport is a portable description of a task, used to move a task
+// between organizations or instances. It holds nothing that is specific to
+// the organization the task was exported from. The name and offset are
+// informational; the task options in Flux are used when it is imported.
+type TaskExport struct {
+	Version     int               `json:"version"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Status      string            `json:"status"`
+	Flux        string            `json:"flux"`
+	Offset      string            `json:"offset,omitempty"`
+	Labels      []TaskExportLabel `json:"labels,omitempty"`
+}
+
+// TaskExportLabel is a label of an exported task. Labels are matched by
+// name when the task is imported, and created if they do not exist.
+type TaskExportLabel struct {
+	Name       string            `json:"name"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// NewTaskExport returns the export of t with the given labels.
+func NewTaskExport(t Task, labels []*Label) TaskExport {
+	te := TaskExport{
+		Version:     TaskExportVersion,
+		Name:        t.Name,
+		Description: t.Description,
+		Status:      t.Status,
+		Flux:        t.Flux,
+		Offset:      t.Offset,
+	}
+	for _, l := range labels {
+		te.Labels = append(te.Labels, TaskExportLabel{Name: l.Name, Properties: l.Properties})
+	}
+	return te
+}
+
+// Validate returns an error if the export cannot be imported.
+func (t TaskExport) Validate() error {
+	switch {
+	case t.Version != TaskExportVersion:
+		return fmt.Errorf("unsupported task export version %d", t.Version)
+	case t.Flux == "":
+		return errors.New("missing flux")
+	}
+	return nil
+}
+
 // TaskUpdate represents updates to a task. Options updates override any options set in the Flux field.
 type TaskUpdate struct {
 	Flux        *string `json:"flux,omitempty"`
@@ -295,6 +442,9 @@ func (t *TaskUpdate) UpdateFlux(oldFlux string) error {
 			toDelete["offset"] = struct{}{}
 		}
 	}
+	if t.Options.Concurrency != nil {
+		op["concurrency"] = &ast.IntegerLiteral{Value: *t.Options.Concurrency}
+	}
 	if len(op) > 0 || len(toDelete) > 0 {
 		editFunc := func(opt *ast.OptionStatement) (ast.Expression, error) {
 			a, ok := opt.Assignment.(*ast.VariableAssignment)
@@ -322,6 +472,11 @@ func (t *TaskUpdate) UpdateFlux(oldFlux string) error {
 						delete(op, "offset")
 						p.Value = offset.Copy().(*ast.DurationLiteral)
 					}
+				case "concurrency":
+					if concurrency, ok := op["concurrency"]; ok {
+						delete(op, "concurrency")
+						p.Value = concurrency
+					}
 				case "every":
 					if every, ok := op["every"]; ok && !t.Options.Every.IsZero() {
 						p.Value = every.Copy().(*ast.DurationLiteral)
@@ -373,9 +528,12 @@ type TaskFilter struct {
 	Type           *string
 	Name           *string
 	After          *ID
+	Before         *ID
 	OrganizationID *ID
 	Organization   string
 	User           *ID
+	OwnerID        *ID
+	LabelID        *ID
 	Limit          int
 }
 
@@ -386,6 +544,10 @@ func (f TaskFilter) QueryParams() map[string][]string {
 		qp["after"] = []string{f.After.String()}
 	}
 
+	if f.Before != nil {
+		qp["before"] = []string{f.Before.String()}
+	}
+
 	if f.OrganizationID != nil {
 		qp["orgID"] = []string{f.OrganizationID.String()}
 	}
@@ -398,6 +560,14 @@ func (f TaskFilter) QueryParams() map[string][]string {
 		qp["user"] = []string{f.User.String()}
 	}
 
+	if f.OwnerID != nil {
+		qp["ownerID"] = []string{f.OwnerID.String()}
+	}
+
+	if f.LabelID != nil {
+		qp["labelID"] = []string{f.LabelID.String()}
+	}
+
 	if f.Limit > 0 {
 		qp["limit"] = []string{strconv.Itoa(f.Limit)}
 	}
@@ -423,4 +593,32 @@ type LogFilter struct {
 
 	// The optional Run ID limits logs to a single run.
 	Run *ID
+
+	// The optional MinLevel limits logs to the ones at least as severe.
+	MinLevel *LogLevel
+
+	// The optional AfterTime and BeforeTime limit logs to the ones written
+	// strictly after and strictly before them.
+	AfterTime  *time.Time
+	BeforeTime *time.Time
+}
+
+// Matches returns true if the log is at least at the filter's minimum level
+// and was written within the filter's time range.
+func (f LogFilter) Matches(l Log) bool {
+	if f.MinLevel != nil && logLevelSeverity[l.GetLevel()] < logLevelSeverity[*f.MinLevel] {
+		return false
+	}
+	if f.AfterTime == nil && f.BeforeTime == nil {
+		return true
+	}
+
+	when, err := time.Parse(time.RFC3339Nano, l.Time)
+	if err != nil {
+		return false
+	}
+	if f.AfterTime != nil && !when.After(*f.AfterTime) {
+		return false
+	}
+	return f.BeforeTime == nil || when.Before(*f.BeforeTime)
 }
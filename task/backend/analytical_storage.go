@@ -23,6 +23,8 @@ const (
 	startedAtField    = "startedAt"
 	finishedAtField   = "finishedAt"
 	requestedAtField  = "requestedAt"
+	noteField         = "note"
+	errorMessageField = "errorMessage"
 	logField          = "logs"
 
 	taskIDTag = "taskID"
@@ -82,6 +84,12 @@ func (as *AnalyticalStorage) FinishRun(ctx context.Context, taskID, runID influx
 		if run.RequestedAt != "" {
 			fields[requestedAtField] = run.RequestedAt
 		}
+		if run.Note != "" {
+			fields[noteField] = run.Note
+		}
+		if run.ErrorMessage != "" {
+			fields[errorMessageField] = run.ErrorMessage
+		}
 
 		startedAt, err := run.StartedAtTime()
 		if err != nil {
@@ -120,7 +128,10 @@ func (as *AnalyticalStorage) FindLogs(ctx context.Context, filter influxdb.LogFi
 			return nil, 0, err
 		}
 		for i := 0; i < len(run.Log); i++ {
-			logs = append(logs, &run.Log[i])
+			run.Log[i].Level = run.Log[i].GetLevel()
+			if filter.Matches(run.Log[i]) {
+				logs = append(logs, &run.Log[i])
+			}
 		}
 		return logs, len(logs), nil
 	}
@@ -133,7 +144,10 @@ func (as *AnalyticalStorage) FindLogs(ctx context.Context, filter influxdb.LogFi
 
 	for _, run := range runs {
 		for i := 0; i < len(run.Log); i++ {
-			logs = append(logs, &run.Log[i])
+			run.Log[i].Level = run.Log[i].GetLevel()
+			if filter.Matches(run.Log[i]) {
+				logs = append(logs, &run.Log[i])
+			}
 		}
 	}
 
@@ -144,7 +158,7 @@ func (as *AnalyticalStorage) FindLogs(ctx context.Context, filter influxdb.LogFi
 // First attempt to use the TaskService, then append additional analytical's runs to the list
 func (as *AnalyticalStorage) FindRuns(ctx context.Context, filter influxdb.RunFilter) ([]*influxdb.Run, int, error) {
 	if filter.Limit == 0 {
-		filter.Limit = influxdb.TaskDefaultPageSize
+		filter.Limit = influxdb.RunDefaultPageSize
 	}
 
 	if filter.Limit < 0 || filter.Limit > influxdb.TaskMaxPageSize {
@@ -308,8 +322,8 @@ func (as *AnalyticalStorage) FindRunByID(ctx context.Context, taskID, runID infl
 	return re.runs[0], err
 }
 
-func (as *AnalyticalStorage) RetryRun(ctx context.Context, taskID, runID influxdb.ID) (*influxdb.Run, error) {
-	run, err := as.TaskService.RetryRun(ctx, taskID, runID)
+func (as *AnalyticalStorage) RetryRun(ctx context.Context, taskID, runID influxdb.ID, scheduledFor *int64) (*influxdb.Run, error) {
+	run, err := as.TaskService.RetryRun(ctx, taskID, runID, scheduledFor)
 	if err != nil {
 		if err, ok := err.(*influxdb.Error); !ok || err.Msg != "run not found" {
 			return run, err
@@ -326,12 +340,22 @@ func (as *AnalyticalStorage) RetryRun(ctx context.Context, taskID, runID influxd
 		return run, err
 	}
 
-	sf, err := run.ScheduledForTime()
-	if err != nil {
-		return run, err
+	if scheduledFor == nil {
+		sf, err := run.ScheduledForTime()
+		if err != nil {
+			return run, err
+		}
+		t := sf.Unix()
+		scheduledFor = &t
 	}
 
-	return as.ForceRun(ctx, taskID, sf.Unix())
+	// The retry keeps the note of the retried run.
+	run, err = as.ForceRunWithNote(ctx, taskID, *scheduledFor, run.Note)
+	if queued, ok := err.(influxdb.RunAlreadyQueuedError); ok {
+		// A retry that is already queued has not yet finished.
+		return nil, RequestStillQueuedError{Start: queued.ScheduledFor, End: queued.ScheduledFor}
+	}
+	return run, err
 }
 
 type runReader struct {
@@ -370,6 +394,10 @@ func (re *runReader) readRuns(cr flux.ColReader) error {
 				r.StartedAt = cr.Strings(j).ValueString(i)
 			case requestedAtField:
 				r.RequestedAt = cr.Strings(j).ValueString(i)
+			case noteField:
+				r.Note = cr.Strings(j).ValueString(i)
+			case errorMessageField:
+				r.ErrorMessage = cr.Strings(j).ValueString(i)
 			case scheduledForField:
 				r.ScheduledFor = cr.Strings(j).ValueString(i)
 			case statusTag:
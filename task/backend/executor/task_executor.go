@@ -322,6 +322,9 @@ func (w *worker) finish(p *Promise, rs backend.RunStatus, err error) {
 	defer span.Finish()
 
 	// add to run log
+	if err != nil {
+		w.te.tcs.AddRunLogLevel(p.ctx, p.task.ID, p.run.ID, time.Now(), influxdb.LogLevelError, err.Error())
+	}
 	w.te.tcs.AddRunLog(p.ctx, p.task.ID, p.run.ID, time.Now(), fmt.Sprintf("Completed(%s)", rs.String()))
 	// update run status
 	w.te.tcs.UpdateRunState(ctx, p.task.ID, p.run.ID, time.Now(), rs)
@@ -49,6 +49,34 @@ func (cs *CoordinatingCheckService) CreateCheck(ctx context.Context, c influxdb.
 	return nil
 }
 
+// CloneCheck Clones a check and Publishes the change it can be scheduled.
+// Clones start out inactive, so their task is only published once it is active.
+func (cs *CoordinatingCheckService) CloneCheck(ctx context.Context, id influxdb.ID, newName string) (influxdb.Check, error) {
+	c, err := cs.CheckService.CloneCheck(ctx, id, newName)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := cs.taskService.FindTaskByID(ctx, c.GetTaskID())
+	if err != nil {
+		return nil, err
+	}
+
+	if t.Status != influxdb.TaskStatusActive {
+		return c, nil
+	}
+
+	if err := cs.coordinator.TaskCreated(ctx, t); err != nil {
+		if derr := cs.CheckService.DeleteCheck(ctx, c.GetID()); derr != nil {
+			return nil, fmt.Errorf("schedule task failed: %s\n\tcleanup also failed: %s", err, derr)
+		}
+
+		return nil, err
+	}
+
+	return c, nil
+}
+
 // UpdateCheck Updates a check and publishes the change so the task owner can act on the update
 func (cs *CoordinatingCheckService) UpdateCheck(ctx context.Context, id influxdb.ID, c influxdb.Check) (influxdb.Check, error) {
 	from, err := cs.CheckService.FindCheckByID(ctx, id)
@@ -154,6 +154,37 @@ func TestCheckCreate(t *testing.T) {
 	}
 }
 
+func TestCheckClone(t *testing.T) {
+	mocks, checkService := newCheckSvcStack()
+	ch := mocks.pipingCoordinator.taskCreatedChan()
+
+	mocks.checkSvc.CloneCheckFn = func(_ context.Context, id influxdb.ID, name string) (influxdb.Check, error) {
+		c := &check.Deadman{}
+		c.SetID(5)
+		c.SetTaskID(4)
+		c.SetName(name)
+		c.SetStatus(influxdb.Inactive)
+		return c, nil
+	}
+	mocks.taskSvc.FindTaskByIDFn = func(_ context.Context, id influxdb.ID) (*influxdb.Task, error) {
+		return &influxdb.Task{ID: id, Status: influxdb.TaskStatusInactive}, nil
+	}
+
+	clone, err := checkService.CloneCheck(context.Background(), 1, "clone")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clone.GetTaskID() != 4 {
+		t.Fatalf("unexpected clone: %v", clone)
+	}
+
+	select {
+	case task := <-ch:
+		t.Fatalf("inactive task %s of clone was sent to coordinator", task.ID)
+	default:
+	}
+}
+
 func TestCheckUpdate(t *testing.T) {
 	mocks, checkService := newCheckSvcStack()
 	ch := mocks.pipingCoordinator.taskUpdatedChan()
@@ -107,14 +107,29 @@ func (s *CoordinatingTaskService) CancelRun(ctx context.Context, taskID, runID i
 	return s.coordinator.RunCancelled(ctx, taskID, runID)
 }
 
+// CancelTaskRuns cancels the running runs of the task and publishes each cancelation.
+func (s *CoordinatingTaskService) CancelTaskRuns(ctx context.Context, taskID influxdb.ID) ([]influxdb.ID, error) {
+	ids, err := s.TaskService.CancelTaskRuns(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		if err := s.coordinator.RunCancelled(ctx, taskID, id); err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
+}
+
 // RetryRun calls retry on the task service and publishes the retry.
-func (s *CoordinatingTaskService) RetryRun(ctx context.Context, taskID, runID influxdb.ID) (*influxdb.Run, error) {
+func (s *CoordinatingTaskService) RetryRun(ctx context.Context, taskID, runID influxdb.ID, scheduledFor *int64) (*influxdb.Run, error) {
 	t, err := s.TaskService.FindTaskByID(ctx, taskID)
 	if err != nil {
 		return nil, err
 	}
 
-	r, err := s.TaskService.RetryRun(ctx, taskID, runID)
+	r, err := s.TaskService.RetryRun(ctx, taskID, runID, scheduledFor)
 	if err != nil {
 		return r, err
 	}
@@ -136,3 +151,18 @@ func (s *CoordinatingTaskService) ForceRun(ctx context.Context, taskID influxdb.
 
 	return r, s.coordinator.RunForced(ctx, t, r)
 }
+
+// ForceRunWithNote create the forced run with a note in the task system and publish to the pubSub.
+func (s *CoordinatingTaskService) ForceRunWithNote(ctx context.Context, taskID influxdb.ID, scheduledFor int64, note string) (*influxdb.Run, error) {
+	t, err := s.TaskService.FindTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := s.TaskService.ForceRunWithNote(ctx, taskID, scheduledFor, note)
+	if err != nil {
+		return r, err
+	}
+
+	return r, s.coordinator.RunForced(ctx, t, r)
+}
@@ -694,7 +694,7 @@ func (r *runner) clearRunning(id platform.ID) {
 
 // fail sets r's state to failed, and marks this runner as idle.
 func (r *runner) fail(qr QueuedRun, runLogger *zap.Logger, stage string, reason error) {
-	if err := r.taskControlService.AddRunLog(r.ts.authCtx, r.task.ID, qr.RunID, time.Now(), stage+": "+reason.Error()); err != nil {
+	if err := r.taskControlService.AddRunLogLevel(r.ts.authCtx, r.task.ID, qr.RunID, time.Now(), platform.LogLevelError, stage+": "+reason.Error()); err != nil {
 		runLogger.Info("Failed to update run log", zap.Error(err))
 	}
 
@@ -801,10 +801,10 @@ func (r *runner) updateRunState(qr QueuedRun, s RunStatus, runLogger *zap.Logger
 		r.taskControlService.AddRunLog(r.ts.authCtx, r.task.ID, qr.RunID, time.Now(), "Completed successfully")
 	case RunFail:
 		r.ts.metrics.FinishRun(r.task.ID.String(), false)
-		r.taskControlService.AddRunLog(r.ts.authCtx, r.task.ID, qr.RunID, time.Now(), "Failed")
+		r.taskControlService.AddRunLogLevel(r.ts.authCtx, r.task.ID, qr.RunID, time.Now(), platform.LogLevelError, "Failed")
 	case RunCanceled:
 		r.ts.metrics.FinishRun(r.task.ID.String(), false)
-		r.taskControlService.AddRunLog(r.ts.authCtx, r.task.ID, qr.RunID, time.Now(), "Canceled")
+		r.taskControlService.AddRunLogLevel(r.ts.authCtx, r.task.ID, qr.RunID, time.Now(), platform.LogLevelWarn, "Canceled")
 	default: // We are deliberately not handling RunQueued yet.
 		// There is not really a notion of being queued in this runner architecture.
 		runLogger.Warn("Unhandled run state", zap.Stringer("state", s))
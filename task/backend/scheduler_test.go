@@ -505,6 +505,10 @@ func newLogListener(tcs backend.TaskControlService) *logListener {
 }
 
 func (l *logListener) AddRunLog(ctx context.Context, taskID, runID platform.ID, when time.Time, log string) error {
+	return l.AddRunLogLevel(ctx, taskID, runID, when, platform.LogLevelInfo, log)
+}
+
+func (l *logListener) AddRunLogLevel(ctx context.Context, taskID, runID platform.ID, when time.Time, level platform.LogLevel, log string) error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
@@ -512,7 +516,7 @@ func (l *logListener) AddRunLog(ctx context.Context, taskID, runID platform.ID,
 	logs = append(logs, log)
 	l.logs[taskID.String()+runID.String()] = logs
 
-	return l.TaskControlService.AddRunLog(ctx, taskID, runID, when, log)
+	return l.TaskControlService.AddRunLogLevel(ctx, taskID, runID, when, level, log)
 }
 
 func pollForRunLog(t *testing.T, ll *logListener, taskID, runID platform.ID, exp string) {
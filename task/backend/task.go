@@ -39,8 +39,14 @@ type TaskControlService interface {
 	// UpdateRunState sets the run state at the respective time.
 	UpdateRunState(ctx context.Context, taskID, runID influxdb.ID, when time.Time, state RunStatus) error
 
-	// AddRunLog adds a log line to the run.
+	// AddRunLog adds a log line to the run at the info level.
 	AddRunLog(ctx context.Context, taskID, runID influxdb.ID, when time.Time, log string) error
+
+	// AddRunLogLevel adds a log line to the run at the given level.
+	AddRunLogLevel(ctx context.Context, taskID, runID influxdb.ID, when time.Time, level influxdb.LogLevel, log string) error
+
+	// AddRunLogs adds the log lines to the run in order, in a single write.
+	AddRunLogs(ctx context.Context, taskID, runID influxdb.ID, logs []influxdb.Log) error
 }
 
 type TaskStatus string
@@ -82,6 +88,9 @@ func (r RunStatus) String() string {
 type RequestStillQueuedError struct {
 	// Unix timestamps matching existing request's start and end.
 	Start, End int64
+
+	// RetryAfter is how long the server suggested waiting before trying again, if it did.
+	RetryAfter time.Duration
 }
 
 const fmtRequestStillQueued = "previous retry for start=%s end=%s has not yet finished"
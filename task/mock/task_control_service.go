@@ -308,11 +308,26 @@ func (d *TaskControlService) UpdateRunState(ctx context.Context, taskID, runID i
 		panic("invalid status")
 	}
 	run.Status = state.String()
+	if state == backend.RunFail {
+		run.ErrorMessage = influxdb.RunErrorMessage(run.Log)
+	}
 	return nil
 }
 
 // AddRunLog adds a log line to the run.
 func (d *TaskControlService) AddRunLog(ctx context.Context, taskID, runID influxdb.ID, when time.Time, log string) error {
+	return d.AddRunLogLevel(ctx, taskID, runID, when, influxdb.LogLevelInfo, log)
+}
+
+// AddRunLogLevel adds a log line to the run at the given level.
+func (d *TaskControlService) AddRunLogLevel(ctx context.Context, taskID, runID influxdb.ID, when time.Time, level influxdb.LogLevel, log string) error {
+	return d.AddRunLogs(ctx, taskID, runID, []influxdb.Log{
+		{Time: when.Format(time.RFC3339Nano), Level: level, Message: log},
+	})
+}
+
+// AddRunLogs adds the log lines to the run in order.
+func (d *TaskControlService) AddRunLogs(ctx context.Context, taskID, runID influxdb.ID, logs []influxdb.Log) error {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
@@ -320,7 +335,9 @@ func (d *TaskControlService) AddRunLog(ctx context.Context, taskID, runID influx
 	if run == nil {
 		panic("cannot add a log to a non existent run")
 	}
-	run.Log = append(run.Log, influxdb.Log{RunID: runID, Time: when.Format(time.RFC3339Nano), Message: log})
+	for _, l := range logs {
+		run.Log = append(run.Log, influxdb.Log{RunID: runID, Time: l.Time, Level: l.GetLevel(), Message: l.Message})
+	}
 	return nil
 }
 
@@ -20,6 +20,9 @@ import (
 const maxConcurrency = 100
 const maxRetry = 10
 
+// DefaultConcurrency is the concurrency of a task without a concurrency option.
+const DefaultConcurrency = 1
+
 // Options are the task-related options that can be specified in a Flux script.
 type Options struct {
 	// Name is a non optional name designator for each task.
@@ -211,7 +214,7 @@ func newDeps() dependencies.Dependencies {
 
 // FromScript extracts Options from a Flux script.
 func FromScript(script string) (Options, error) {
-	opt := Options{Retry: pointer.Int64(1), Concurrency: pointer.Int64(1)}
+	opt := Options{Retry: pointer.Int64(1), Concurrency: pointer.Int64(DefaultConcurrency)}
 
 	fluxAST, err := flux.Parse(script)
 	if err != nil {
@@ -405,6 +408,37 @@ func (o *Options) EffectiveCronString() string {
 	return ""
 }
 
+// NextScheduled returns the next count times after latest that the options
+// schedule a run for, computed the way the scheduler does: an every schedule
+// is aligned to a multiple of its duration first. The offset is not applied,
+// as it only delays when a run is due, not the time it is scheduled for.
+func (o *Options) NextScheduled(latest time.Time, count int) ([]time.Time, error) {
+	sch, err := cron.Parse(o.EffectiveCronString())
+	if err != nil {
+		return nil, err
+	}
+
+	if o.Cron == "" {
+		t := time.Unix(latest.Unix(), 0)
+		every, err := o.Every.DurationFrom(t)
+		if err != nil {
+			return nil, err
+		}
+		latest = t.Truncate(every)
+	}
+
+	next := make([]time.Time, 0, count)
+	for len(next) < count {
+		latest = sch.Next(latest)
+		if latest.IsZero() {
+			// The schedule never fires again.
+			break
+		}
+		next = append(next, latest.UTC())
+	}
+	return next, nil
+}
+
 // checkNature returns a clean error of got and expected dont match.
 func checkNature(got, exp semantic.Nature) error {
 	if got != exp {
@@ -61,6 +61,11 @@ func TestTaskService(t *testing.T, fn BackendComponentFactory, testCategory ...s
 					testTaskOptionsUpdateFull(t, sys)
 				})
 
+				t.Run("Task Update Concurrency", func(t *testing.T) {
+					t.Parallel()
+					testTaskConcurrencyUpdate(t, sys)
+				})
+
 				t.Run("Task Runs", func(t *testing.T) {
 					t.Parallel()
 					testTaskRuns(t, sys)
@@ -84,11 +89,26 @@ func TestTaskService(t *testing.T, fn BackendComponentFactory, testCategory ...s
 					testManualRun(t, sys)
 				})
 
+				t.Run("Task Cancel Runs", func(t *testing.T) {
+					t.Parallel()
+					testCancelTaskRuns(t, sys)
+				})
+
 				t.Run("Task Type", func(t *testing.T) {
 					t.Parallel()
 					testTaskType(t, sys)
 				})
 
+				t.Run("Task Create With Token", func(t *testing.T) {
+					t.Parallel()
+					testTaskCreateWithToken(t, sys)
+				})
+
+				t.Run("Task Count", func(t *testing.T) {
+					t.Parallel()
+					testTaskCount(t, sys)
+				})
+
 			})
 		case "analytical":
 			t.Run("AnalyticalTaskService", func(t *testing.T) {
@@ -100,10 +120,18 @@ func TestTaskService(t *testing.T, fn BackendComponentFactory, testCategory ...s
 					t.Parallel()
 					testRetryAcrossStorage(t, sys)
 				})
+				t.Run("Task RetryRun Note", func(t *testing.T) {
+					t.Parallel()
+					testRetryForcedRunNote(t, sys)
+				})
 				t.Run("task Log Storage", func(t *testing.T) {
 					t.Parallel()
 					testLogsAcrossStorage(t, sys)
 				})
+				t.Run("task Batched Log Storage", func(t *testing.T) {
+					t.Parallel()
+					testBatchedLogsAcrossStorage(t, sys)
+				})
 			})
 		}
 	}
@@ -234,19 +262,20 @@ func testTaskCRUD(t *testing.T, sys *System) {
 	found["FindTasks with User filter"] = f
 
 	want := &influxdb.Task{
-		ID:              tsk.ID,
-		CreatedAt:       tsk.CreatedAt,
-		LatestCompleted: tsk.LatestCompleted,
-		OrganizationID:  cr.OrgID,
-		Organization:    cr.Org,
-		AuthorizationID: tsk.AuthorizationID,
-		Authorization:   tsk.Authorization,
-		OwnerID:         tsk.OwnerID,
-		Name:            "task #0",
-		Cron:            "* * * * *",
-		Offset:          "5s",
-		Status:          string(backend.DefaultTaskStatus),
-		Flux:            fmt.Sprintf(scriptFmt, 0),
+		ID:                   tsk.ID,
+		CreatedAt:            tsk.CreatedAt,
+		LatestCompleted:      tsk.LatestCompleted,
+		OrganizationID:       cr.OrgID,
+		Organization:         cr.Org,
+		AuthorizationID:      tsk.AuthorizationID,
+		Authorization:        tsk.Authorization,
+		OwnerID:              tsk.OwnerID,
+		Name:                 "task #0",
+		Cron:                 "* * * * *",
+		Offset:               "5s",
+		EffectiveConcurrency: 100,
+		Status:               string(backend.DefaultTaskStatus),
+		Flux:                 fmt.Sprintf(scriptFmt, 0),
 	}
 	for fn, f := range found {
 		if diff := cmp.Diff(f, want); diff != "" {
@@ -293,6 +322,19 @@ func testTaskCRUD(t *testing.T, sys *System) {
 		}
 	}
 
+	// Check before round-trips with after
+	second := tasks[0]
+	tasks, _, err = sys.TaskService.FindTasks(sys.Ctx, influxdb.TaskFilter{OrganizationID: &cr.OrgID, Before: &second.ID, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 1 {
+		t.Fatalf("expected 1 task before %s: got %d", second.ID, len(tasks))
+	}
+	if tasks[0].ID != first.ID {
+		t.Fatalf("expected the task before %s to be %s: got %s", second.ID, first.ID, tasks[0].ID)
+	}
+
 	// Update task: script only.
 	newFlux := fmt.Sprintf(scriptFmt, 99)
 	origID := f.ID
@@ -323,6 +365,19 @@ func testTaskCRUD(t *testing.T, sys *System) {
 	if f.Status != newStatus {
 		t.Fatalf("expected task status to be inactive, got %q", f.Status)
 	}
+	if _, err := time.Parse(time.RFC3339, f.InactiveSince); err != nil {
+		t.Fatalf("expected inactive task to have an inactive since time, got %q: %v", f.InactiveSince, err)
+	}
+	inactiveSince := f.InactiveSince
+
+	// Updating an inactive task keeps the time it was made inactive.
+	f, err = sys.TaskService.UpdateTask(authorizedCtx, origID, influxdb.TaskUpdate{Status: &newStatus})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.InactiveSince != inactiveSince {
+		t.Fatalf("expected inactive since time to remain %q, got %q", inactiveSince, f.InactiveSince)
+	}
 
 	// Update task: reactivate status and update script.
 	newStatus = string(backend.TaskActive)
@@ -337,6 +392,9 @@ func testTaskCRUD(t *testing.T, sys *System) {
 	if f.Status != newStatus {
 		t.Fatalf("expected task status to be inactive, got %q", f.Status)
 	}
+	if f.InactiveSince != "" {
+		t.Fatalf("expected reactivated task to have no inactive since time, got %q", f.InactiveSince)
+	}
 
 	// Update task: just update an option.
 	newStatus = string(backend.TaskActive)
@@ -492,6 +550,119 @@ from(bucket: "b")
 
 }
 
+// Create a new task with a concurrency option
+// Update the task's concurrency, and then add a concurrency to a task without one
+// Retrieve the task after each update to ensure the flux and effective concurrency agree
+func testTaskConcurrencyUpdate(t *testing.T, sys *System) {
+	script := `option task = {name: "task-Concurrency-Update", every: 1m, concurrency: 100}
+
+from(bucket: "b")
+	|> to(bucket: "two", orgID: "000000000000000")`
+
+	cr := creds(t, sys)
+
+	ct := influxdb.TaskCreate{
+		OrganizationID: cr.OrgID,
+		Flux:           script,
+		OwnerID:        cr.UserID,
+	}
+	authorizedCtx := icontext.SetAuthorizer(sys.Ctx, cr.Authorizer())
+	task, err := sys.TaskService.CreateTask(authorizedCtx, ct)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.EffectiveConcurrency != 100 {
+		t.Fatalf("unexpected effective concurrency of created task: got %d, want %d", task.EffectiveConcurrency, 100)
+	}
+
+	t.Run("update task concurrency", func(t *testing.T) {
+		expectedFlux := `option task = {name: "task-Concurrency-Update", every: 1m, concurrency: 5}
+
+from(bucket: "b")
+	|> to(bucket: "two", orgID: "000000000000000")`
+		concurrency := int64(5)
+		f, err := sys.TaskService.UpdateTask(authorizedCtx, task.ID, influxdb.TaskUpdate{Options: options.Options{Concurrency: &concurrency}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		savedTask, err := sys.TaskService.FindTaskByID(sys.Ctx, f.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if savedTask.Flux != expectedFlux {
+			diff := cmp.Diff(savedTask.Flux, expectedFlux)
+			t.Fatalf("flux unexpected updated: %s", diff)
+		}
+		if savedTask.EffectiveConcurrency != concurrency {
+			t.Fatalf("unexpected effective concurrency: got %d, want %d", savedTask.EffectiveConcurrency, concurrency)
+		}
+	})
+
+	t.Run("remove concurrency from task", func(t *testing.T) {
+		flux := `option task = {name: "task-Concurrency-Update", every: 1m}
+
+from(bucket: "b")
+	|> to(bucket: "two", orgID: "000000000000000")`
+		f, err := sys.TaskService.UpdateTask(authorizedCtx, task.ID, influxdb.TaskUpdate{Flux: &flux})
+		if err != nil {
+			t.Fatal(err)
+		}
+		savedTask, err := sys.TaskService.FindTaskByID(sys.Ctx, f.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if savedTask.EffectiveConcurrency != options.DefaultConcurrency {
+			t.Fatalf("unexpected effective concurrency: got %d, want %d", savedTask.EffectiveConcurrency, options.DefaultConcurrency)
+		}
+	})
+
+	t.Run("add concurrency to task without one", func(t *testing.T) {
+		ct := influxdb.TaskCreate{
+			OrganizationID: cr.OrgID,
+			Flux: `option task = {name: "task-Concurrency-Add", every: 1m}
+
+from(bucket: "b")
+	|> to(bucket: "two", orgID: "000000000000000")`,
+			OwnerID: cr.UserID,
+		}
+		task, err := sys.TaskService.CreateTask(authorizedCtx, ct)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if task.EffectiveConcurrency != 1 {
+			t.Fatalf("unexpected default effective concurrency: got %d, want %d", task.EffectiveConcurrency, 1)
+		}
+
+		expectedFlux := `option task = {name: "task-Concurrency-Add", every: 1m, concurrency: 3}
+
+from(bucket: "b")
+	|> to(bucket: "two", orgID: "000000000000000")`
+		concurrency := int64(3)
+		f, err := sys.TaskService.UpdateTask(authorizedCtx, task.ID, influxdb.TaskUpdate{Options: options.Options{Concurrency: &concurrency}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		savedTask, err := sys.TaskService.FindTaskByID(sys.Ctx, f.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if savedTask.Flux != expectedFlux {
+			diff := cmp.Diff(savedTask.Flux, expectedFlux)
+			t.Fatalf("flux unexpected updated: %s", diff)
+		}
+		if savedTask.EffectiveConcurrency != concurrency {
+			t.Fatalf("unexpected effective concurrency: got %d, want %d", savedTask.EffectiveConcurrency, concurrency)
+		}
+	})
+
+	t.Run("concurrency above the maximum is rejected", func(t *testing.T) {
+		concurrency := int64(101)
+		if _, err := sys.TaskService.UpdateTask(authorizedCtx, task.ID, influxdb.TaskUpdate{Options: options.Options{Concurrency: &concurrency}}); err == nil {
+			t.Fatal("expected an error updating the concurrency above the maximum")
+		}
+	})
+}
+
 func testUpdate(t *testing.T, sys *System) {
 	cr := creds(t, sys)
 
@@ -594,6 +765,51 @@ func testUpdate(t *testing.T, sys *System) {
 	}
 }
 
+func testTaskCreateWithToken(t *testing.T, sys *System) {
+	cr := creds(t, sys)
+	authorizedCtx := icontext.SetAuthorizer(sys.Ctx, cr.Authorizer())
+
+	// A distinct authorization for the task to run with.
+	authz := &influxdb.Authorization{OrgID: cr.OrgID, UserID: cr.UserID, Permissions: influxdb.OperPermissions()}
+	if err := sys.I.CreateAuthorization(sys.Ctx, authz); err != nil {
+		t.Fatal(err)
+	}
+	if authz.ID == cr.AuthorizationID {
+		t.Fatal("expected a distinct authorization")
+	}
+
+	tsk, err := sys.TaskService.CreateTask(authorizedCtx, influxdb.TaskCreate{
+		OrganizationID: cr.OrgID,
+		Flux:           fmt.Sprintf(scriptFmt, 0),
+		OwnerID:        cr.UserID,
+		Token:          authz.Token,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sys.TaskService.FindTaskByID(sys.Ctx, tsk.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// The token must belong to the task's organization.
+	otherOrg := &influxdb.Organization{Name: t.Name() + "-other-org"}
+	if err := sys.I.CreateOrganization(sys.Ctx, otherOrg); err != nil {
+		t.Fatal(err)
+	}
+	otherAuthz := &influxdb.Authorization{OrgID: otherOrg.ID, UserID: cr.UserID, Permissions: influxdb.OperPermissions()}
+	if err := sys.I.CreateAuthorization(sys.Ctx, otherAuthz); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sys.TaskService.CreateTask(authorizedCtx, influxdb.TaskCreate{
+		OrganizationID: cr.OrgID,
+		Flux:           fmt.Sprintf(scriptFmt, 0),
+		OwnerID:        cr.UserID,
+		Token:          otherAuthz.Token,
+	}); err == nil {
+		t.Fatal("expected an error creating a task with a token of another organization")
+	}
+}
+
 func testTaskRuns(t *testing.T, sys *System) {
 	cr := creds(t, sys)
 
@@ -790,7 +1006,7 @@ func testTaskRuns(t *testing.T, sys *System) {
 			t.Fatal(err)
 		}
 
-		expLine1 := &influxdb.Log{RunID: rc1.Created.RunID, Time: log1Time.Format(time.RFC3339Nano), Message: "entry 1"}
+		expLine1 := &influxdb.Log{RunID: rc1.Created.RunID, Time: log1Time.Format(time.RFC3339Nano), Level: influxdb.LogLevelInfo, Message: "entry 1"}
 		exp := []*influxdb.Log{expLine1}
 		if diff := cmp.Diff(logs, exp); diff != "" {
 			t.Fatalf("unexpected log: -got/+want: %s", diff)
@@ -809,11 +1025,74 @@ func testTaskRuns(t *testing.T, sys *System) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		expLine2 := &influxdb.Log{RunID: rc2.Created.RunID, Time: log2Time.Format(time.RFC3339Nano), Message: "entry 2"}
+		expLine2 := &influxdb.Log{RunID: rc2.Created.RunID, Time: log2Time.Format(time.RFC3339Nano), Level: influxdb.LogLevelInfo, Message: "entry 2"}
 		exp = []*influxdb.Log{expLine1, expLine2}
 		if diff := cmp.Diff(logs, exp); diff != "" {
 			t.Fatalf("unexpected log: -got/+want: %s", diff)
 		}
+
+		// Add an error log for the second run.
+		log3Time := time.Now().UTC()
+		if err := sys.TaskControlService.AddRunLogLevel(sys.Ctx, task.ID, rc2.Created.RunID, log3Time, influxdb.LogLevelError, "entry 3"); err != nil {
+			t.Fatal(err)
+		}
+
+		// Ensure only the error is returned when filtering logs by level.
+		minLevel := influxdb.LogLevelError
+		logs, _, err = sys.TaskService.FindLogs(sys.Ctx, influxdb.LogFilter{
+			Task:     task.ID,
+			MinLevel: &minLevel,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expLine3 := &influxdb.Log{RunID: rc2.Created.RunID, Time: log3Time.Format(time.RFC3339Nano), Level: influxdb.LogLevelError, Message: "entry 3"}
+		exp = []*influxdb.Log{expLine3}
+		if diff := cmp.Diff(logs, exp); diff != "" {
+			t.Fatalf("unexpected log: -got/+want: %s", diff)
+		}
+
+		// Add logs a minute apart for the first run.
+		base := log3Time.Add(time.Hour)
+		var spaced []*influxdb.Log
+		for i := 0; i < 3; i++ {
+			when := base.Add(time.Duration(i) * time.Minute)
+			msg := fmt.Sprintf("spaced entry %d", i)
+			if err := sys.TaskControlService.AddRunLog(sys.Ctx, task.ID, rc1.Created.RunID, when, msg); err != nil {
+				t.Fatal(err)
+			}
+			spaced = append(spaced, &influxdb.Log{RunID: rc1.Created.RunID, Time: when.Format(time.RFC3339Nano), Level: influxdb.LogLevelInfo, Message: msg})
+		}
+
+		// Ensure only the logs within the time range are returned.
+		afterTime := base.Add(30 * time.Second)
+		beforeTime := base.Add(90 * time.Second)
+		logs, _, err = sys.TaskService.FindLogs(sys.Ctx, influxdb.LogFilter{
+			Task:       task.ID,
+			Run:        &rc1.Created.RunID,
+			AfterTime:  &afterTime,
+			BeforeTime: &beforeTime,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		exp = []*influxdb.Log{spaced[1]}
+		if diff := cmp.Diff(logs, exp); diff != "" {
+			t.Fatalf("unexpected log: -got/+want: %s", diff)
+		}
+
+		// Ensure an open ended range applies to the logs of every run.
+		logs, _, err = sys.TaskService.FindLogs(sys.Ctx, influxdb.LogFilter{
+			Task:      task.ID,
+			AfterTime: &afterTime,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		exp = []*influxdb.Log{spaced[1], spaced[2]}
+		if diff := cmp.Diff(logs, exp); diff != "" {
+			t.Fatalf("unexpected log: -got/+want: %s", diff)
+		}
 	})
 }
 
@@ -1016,8 +1295,9 @@ func testManualRun(t *testing.T, s *System) {
 		t.Fatal("no task ID set")
 	}
 	scheduledFor := time.Now().UTC()
+	note := "backfill after outage"
 
-	run, err := s.TaskService.ForceRun(authorizedCtx, tsk.ID, scheduledFor.Unix())
+	run, err := s.TaskService.ForceRunWithNote(authorizedCtx, tsk.ID, scheduledFor.Unix(), note)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1025,6 +1305,9 @@ func testManualRun(t *testing.T, s *System) {
 	if run.ScheduledFor != scheduledFor.Format(time.RFC3339) {
 		t.Fatalf("force run returned a different scheduled for time expected: %s, got %s", scheduledFor.Format(time.RFC3339), run.ScheduledFor)
 	}
+	if run.Note != note {
+		t.Fatalf("force run returned a different note expected: %q, got %q", note, run.Note)
+	}
 
 	runs, err := s.TaskControlService.ManualRuns(authorizedCtx, tsk.ID)
 	if err != nil {
@@ -1037,6 +1320,9 @@ func testManualRun(t *testing.T, s *System) {
 		diff := cmp.Diff(runs[0], run)
 		t.Fatalf("manual run missmatch: %s", diff)
 	}
+	if runs[0].Note != note {
+		t.Fatalf("manual run has a different note expected: %q, got %q", note, runs[0].Note)
+	}
 }
 
 func testRunStorage(t *testing.T, sys *System) {
@@ -1095,7 +1381,10 @@ func testRunStorage(t *testing.T, sys *System) {
 		t.Fatal(err)
 	}
 
-	// Mark the second run finished.
+	// Mark the second run failed, logging why as the scheduler would.
+	if err := sys.TaskControlService.AddRunLogLevel(sys.Ctx, task.ID, rc1.Created.RunID, startedAt.Add(time.Second*2), influxdb.LogLevelError, "Run failed to execute: boom"); err != nil {
+		t.Fatal(err)
+	}
 	if err := sys.TaskControlService.UpdateRunState(sys.Ctx, task.ID, rc1.Created.RunID, startedAt.Add(time.Second*2), backend.RunFail); err != nil {
 		t.Fatal(err)
 	}
@@ -1124,6 +1413,9 @@ func testRunStorage(t *testing.T, sys *System) {
 	if runs[0].FinishedAt != "" {
 		t.Fatalf("expected empty FinishedAt, got %q", runs[0].FinishedAt)
 	}
+	if d, ok := runs[0].Duration(); ok {
+		t.Fatalf("expected no duration for an unfinished run, got %s", d)
+	}
 
 	// Create 3rd run and test limiting to 2 runs
 	rc2, err := sys.TaskControlService.CreateNextRun(sys.Ctx, task.ID, requestedAtUnix)
@@ -1186,6 +1478,15 @@ func testRunStorage(t *testing.T, sys *System) {
 	if exp := startedAt.Add(time.Second * 2).Format(time.RFC3339Nano); runs[2].FinishedAt != exp {
 		t.Fatalf("unexpected FinishedAt; want %s, got %s", exp, runs[2].FinishedAt)
 	}
+	if d, ok := runs[2].Duration(); !ok || d != time.Second {
+		t.Fatalf("unexpected duration; want %s, got %s", time.Second, d)
+	}
+	if runs[2].ErrorMessage == "" {
+		t.Fatal("expected an error message for the failed run")
+	}
+	if runs[0].ErrorMessage != "" {
+		t.Fatalf("expected no error message for an unfinished run, got %q", runs[0].ErrorMessage)
+	}
 
 	// Look for a run that doesn't exist.
 	_, err = sys.TaskService.FindRunByID(sys.Ctx, task.ID, influxdb.ID(math.MaxUint64))
@@ -1218,6 +1519,94 @@ func testRunStorage(t *testing.T, sys *System) {
 	}
 }
 
+func testCancelTaskRuns(t *testing.T, sys *System) {
+	cr := creds(t, sys)
+
+	ct := influxdb.TaskCreate{
+		OrganizationID: cr.OrgID,
+		Flux:           fmt.Sprintf(scriptFmt, 0),
+		OwnerID:        cr.UserID,
+	}
+	task, err := sys.TaskService.CreateTask(icontext.SetAuthorizer(sys.Ctx, cr.Authorizer()), ct)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requestedAtUnix := time.Now().Add(5 * time.Minute).UTC().Unix() // This should guarantee we can make three runs.
+
+	// Start two runs and leave a third one scheduled.
+	var runIDs []influxdb.ID
+	for i := 0; i < 3; i++ {
+		rc, err := sys.TaskControlService.CreateNextRun(sys.Ctx, task.ID, requestedAtUnix)
+		if err != nil {
+			t.Fatal(err)
+		}
+		runIDs = append(runIDs, rc.Created.RunID)
+	}
+	startedAt := time.Now().UTC()
+	for _, id := range runIDs[:2] {
+		if err := sys.TaskControlService.UpdateRunState(sys.Ctx, task.ID, id, startedAt, backend.RunStarted); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	canceled, err := sys.TaskService.CancelTaskRuns(sys.Ctx, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(runIDs[:2], canceled); diff != "" {
+		t.Fatalf("unexpected canceled runs -want/+got: %s", diff)
+	}
+
+	for i, id := range runIDs {
+		run, err := sys.TaskService.FindRunByID(sys.Ctx, task.ID, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		exp := backend.RunCanceled.String()
+		if i == 2 {
+			exp = backend.RunScheduled.String()
+		}
+		if run.Status != exp {
+			t.Errorf("expected run %s to be %s, got %s", id, exp, run.Status)
+		}
+	}
+}
+
+func testTaskCount(t *testing.T, sys *System) {
+	cr := creds(t, sys)
+	authorizedCtx := icontext.SetAuthorizer(sys.Ctx, cr.Authorizer())
+
+	for i := 0; i < 3; i++ {
+		ct := influxdb.TaskCreate{
+			OrganizationID: cr.OrgID,
+			Flux:           fmt.Sprintf(scriptFmt, i),
+			OwnerID:        cr.UserID,
+		}
+		if _, err := sys.TaskService.CreateTask(authorizedCtx, ct); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The limit and paging of the filter are ignored.
+	n, err := sys.TaskService.CountTasks(sys.Ctx, influxdb.TaskFilter{OrganizationID: &cr.OrgID, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Fatalf("expected 3 tasks, got %d", n)
+	}
+
+	name := "task #1"
+	n, err = sys.TaskService.CountTasks(sys.Ctx, influxdb.TaskFilter{OrganizationID: &cr.OrgID, Name: &name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 task named %q, got %d", name, n)
+	}
+}
+
 func testRetryAcrossStorage(t *testing.T, sys *System) {
 	cr := creds(t, sys)
 
@@ -1232,7 +1621,7 @@ func testRetryAcrossStorage(t *testing.T, sys *System) {
 		t.Fatal(err)
 	}
 	// Non-existent ID should return the right error.
-	_, err = sys.TaskService.RetryRun(sys.Ctx, task.ID, influxdb.ID(math.MaxUint64))
+	_, err = sys.TaskService.RetryRun(sys.Ctx, task.ID, influxdb.ID(math.MaxUint64), nil)
 	if !strings.Contains(err.Error(), "run not found") {
 		t.Errorf("expected retrying run that doesn't exist to return %v, got %v", influxdb.ErrRunNotFound, err)
 	}
@@ -1261,7 +1650,7 @@ func testRetryAcrossStorage(t *testing.T, sys *System) {
 	}
 
 	// Now retry the run.
-	m, err := sys.TaskService.RetryRun(sys.Ctx, task.ID, rc.Created.RunID)
+	m, err := sys.TaskService.RetryRun(sys.Ctx, task.ID, rc.Created.RunID, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1281,10 +1670,161 @@ func testRetryAcrossStorage(t *testing.T, sys *System) {
 
 	exp := backend.RequestStillQueuedError{Start: rc.Created.Now, End: rc.Created.Now}
 
-	// Retrying a run which has been queued but not started, should be rejected.
-	if _, err = sys.TaskService.RetryRun(sys.Ctx, task.ID, rc.Created.RunID); err != exp && err.Error() != "<conflict> run already queued" {
+	// Retrying a run which has been queued but not started, should be rejected
+	// with the scheduled window of the queued run, so callers can back off.
+	_, err = sys.TaskService.RetryRun(sys.Ctx, task.ID, rc.Created.RunID, nil)
+	queued, ok := err.(backend.RequestStillQueuedError)
+	if !ok {
 		t.Fatalf("subsequent retry should have been rejected with %v; got %v", exp, err)
 	}
+	if queued.Start != exp.Start || queued.End != exp.End {
+		t.Fatalf("expected rejected retry to carry the queued window %d-%d, got %d-%d", exp.Start, exp.End, queued.Start, queued.End)
+	}
+
+	// Retrying with an overridden scheduled time queues the run at that time instead.
+	scheduledFor := rc.Created.Now + 60*60
+	m, err = sys.TaskService.RetryRun(sys.Ctx, task.ID, rc.Created.RunID, &scheduledFor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	overrideTime, err := time.Parse(time.RFC3339, m.ScheduledFor)
+	if err != nil {
+		t.Fatalf("expected scheduledFor to be a parsable time in RFC3339, but got %s", m.ScheduledFor)
+	}
+	if overrideTime.Unix() != scheduledFor {
+		t.Fatalf("wrong scheduledFor on overridden retry: got %s, want %s", m.ScheduledFor, time.Unix(scheduledFor, 0).UTC().Format(time.RFC3339))
+	}
+}
+
+func testRetryForcedRunNote(t *testing.T, sys *System) {
+	cr := creds(t, sys)
+
+	ct := influxdb.TaskCreate{
+		OrganizationID: cr.OrgID,
+		Flux:           fmt.Sprintf(scriptFmt, 0),
+		OwnerID:        cr.UserID,
+	}
+	authorizedCtx := icontext.SetAuthorizer(sys.Ctx, cr.Authorizer())
+	task, err := sys.TaskService.CreateTask(authorizedCtx, ct)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scheduledFor := time.Now().UTC()
+	const note = "backfill after outage"
+	run, err := sys.TaskService.ForceRunWithNote(authorizedCtx, task.ID, scheduledFor.Unix(), note)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Run the forced run to failure; normally the scheduler would do this.
+	if _, err := sys.TaskControlService.StartManualRun(sys.Ctx, task.ID, run.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := sys.TaskControlService.UpdateRunState(sys.Ctx, task.ID, run.ID, scheduledFor, backend.RunFail); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sys.TaskControlService.FinishRun(sys.Ctx, task.ID, run.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// A retry of the forced run keeps its note.
+	m, err := sys.TaskService.RetryRun(authorizedCtx, task.ID, run.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Note != note {
+		t.Fatalf("retried run has a different note expected: %q, got %q", note, m.Note)
+	}
+}
+
+func testBatchedLogsAcrossStorage(t *testing.T, sys *System) {
+	cr := creds(t, sys)
+
+	ct := influxdb.TaskCreate{
+		OrganizationID: cr.OrgID,
+		Flux:           fmt.Sprintf(scriptFmt, 0),
+		OwnerID:        cr.UserID,
+	}
+	task, err := sys.TaskService.CreateTask(icontext.SetAuthorizer(sys.Ctx, cr.Authorizer()), ct)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requestedAtUnix := time.Now().Add(5 * time.Minute).UTC().Unix()
+
+	startedAt := time.Now().UTC()
+	var runIDs []influxdb.ID
+	for i := 0; i < 2; i++ {
+		rc, err := sys.TaskControlService.CreateNextRun(sys.Ctx, task.ID, requestedAtUnix)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sys.TaskControlService.UpdateRunState(sys.Ctx, task.ID, rc.Created.RunID, startedAt, backend.RunStarted); err != nil {
+			t.Fatal(err)
+		}
+		runIDs = append(runIDs, rc.Created.RunID)
+	}
+
+	// Write a batch to each run, then finish the second run so its logs are
+	// read from the analytical storage while the first run's are not.
+	batch := func(run int, n int) []influxdb.Log {
+		logs := make([]influxdb.Log, 0, n)
+		for i := 0; i < n; i++ {
+			logs = append(logs, influxdb.Log{
+				Time:    startedAt.Add(time.Duration(i) * time.Millisecond).Format(time.RFC3339Nano),
+				Message: fmt.Sprintf("%d-%d", run, i),
+			})
+		}
+		logs[n-1].Level = influxdb.LogLevelError
+		return logs
+	}
+	if err := sys.TaskControlService.AddRunLogs(sys.Ctx, task.ID, runIDs[0], batch(0, 3)); err != nil {
+		t.Fatal(err)
+	}
+	if err := sys.TaskControlService.AddRunLogs(sys.Ctx, task.ID, runIDs[1], batch(1, 4)); err != nil {
+		t.Fatal(err)
+	}
+	if err := sys.TaskControlService.UpdateRunState(sys.Ctx, task.ID, runIDs[1], startedAt.Add(time.Second), backend.RunSuccess); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sys.TaskControlService.FinishRun(sys.Ctx, task.ID, runIDs[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	smash := func(logs []*influxdb.Log) string {
+		smashed := ""
+		for _, log := range logs {
+			smashed = smashed + log.Message
+		}
+		return smashed
+	}
+
+	for _, tc := range []struct {
+		run  *influxdb.ID
+		want string
+	}{
+		{run: nil, want: "0-00-10-21-01-11-21-3"},
+		{run: &runIDs[0], want: "0-00-10-2"},
+		{run: &runIDs[1], want: "1-01-11-21-3"},
+	} {
+		logs, _, err := sys.TaskService.FindLogs(sys.Ctx, influxdb.LogFilter{Task: task.ID, Run: tc.run})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := smash(logs); got != tc.want {
+			t.Fatalf("log contents not acceptable, expected: %q, got: %q", tc.want, got)
+		}
+	}
+
+	minLevel := influxdb.LogLevelError
+	logs, _, err := sys.TaskService.FindLogs(sys.Ctx, influxdb.LogFilter{Task: task.ID, MinLevel: &minLevel})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := smash(logs); got != "0-21-3" {
+		t.Fatalf("log levels were not preserved, expected: %q, got: %q", "0-21-3", got)
+	}
 }
 
 func testLogsAcrossStorage(t *testing.T, sys *System) {
@@ -1341,11 +1881,11 @@ func testLogsAcrossStorage(t *testing.T, sys *System) {
 	// We can then finalize rc1 and ensure that both the transactional (currently running logs) can be found with analytical (completed) logs.
 	sys.TaskControlService.AddRunLog(sys.Ctx, task.ID, rc0.Created.RunID, time.Now(), "0-0")
 	sys.TaskControlService.AddRunLog(sys.Ctx, task.ID, rc0.Created.RunID, time.Now(), "0-1")
-	sys.TaskControlService.AddRunLog(sys.Ctx, task.ID, rc0.Created.RunID, time.Now(), "0-2")
+	sys.TaskControlService.AddRunLogLevel(sys.Ctx, task.ID, rc0.Created.RunID, time.Now(), influxdb.LogLevelWarn, "0-2")
 	sys.TaskControlService.AddRunLog(sys.Ctx, task.ID, rc1.Created.RunID, time.Now(), "1-0")
 	sys.TaskControlService.AddRunLog(sys.Ctx, task.ID, rc1.Created.RunID, time.Now(), "1-1")
 	sys.TaskControlService.AddRunLog(sys.Ctx, task.ID, rc1.Created.RunID, time.Now(), "1-2")
-	sys.TaskControlService.AddRunLog(sys.Ctx, task.ID, rc1.Created.RunID, time.Now(), "1-3")
+	sys.TaskControlService.AddRunLogLevel(sys.Ctx, task.ID, rc1.Created.RunID, time.Now(), influxdb.LogLevelError, "1-3")
 	if _, err := sys.TaskControlService.FinishRun(sys.Ctx, task.ID, rc1.Created.RunID); err != nil {
 		t.Fatal(err)
 	}
@@ -1395,6 +1935,25 @@ func testLogsAcrossStorage(t *testing.T, sys *System) {
 		t.Fatalf("log contents not acceptable, expected: %q, got: %q", "0-00-10-2", smash(logs))
 	}
 
+	// Ensure filtering by level works across the transactional and analytical logs.
+	minLevel := influxdb.LogLevelWarn
+	logs, _, err = sys.TaskService.FindLogs(sys.Ctx, influxdb.LogFilter{Task: task.ID, MinLevel: &minLevel})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if smash(logs) != "0-21-3" {
+		t.Fatalf("log contents not acceptable, expected: %q, got: %q", "0-21-3", smash(logs))
+	}
+
+	minLevel = influxdb.LogLevelError
+	logs, _, err = sys.TaskService.FindLogs(sys.Ctx, influxdb.LogFilter{Task: task.ID, Run: &rc1.Created.RunID, MinLevel: &minLevel})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if smash(logs) != "1-3" {
+		t.Fatalf("log contents not acceptable, expected: %q, got: %q", "1-3", smash(logs))
+	}
+
 }
 
 func creds(t *testing.T, s *System) TestCreds {
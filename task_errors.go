@@ -63,11 +63,21 @@ var (
 		Code: EInvalid,
 	}
 
+	// ErrAfterAndBeforeTaskFilter is returned when a task filter pages in both directions at once.
+	ErrAfterAndBeforeTaskFilter = &Error{
+		Msg:  "cannot use both after and before to page tasks",
+		Code: EInvalid,
+	}
+
 	ErrOrgNotFound = &Error{
 		Msg:  "organization not found",
 		Code: ENotFound,
 	}
 
+	// ErrTaskRunAlreadyQueued is returned when forcing a run for a time that already has a queued run.
+	//
+	// Deprecated: ForceRun returns a RunAlreadyQueuedError instead, which carries the
+	// scheduled time of the queued run and the suggested wait before trying again.
 	ErrTaskRunAlreadyQueued = &Error{
 		Msg:  "run already queued",
 		Code: EConflict,
@@ -84,6 +94,13 @@ var (
 		Code: EInvalid,
 		Msg:  "cannot create task with invalid ownerID",
 	}
+
+	// ErrInvalidTaskToken is called when trying to create a task with a token that does not
+	// belong to an authorization in the task's organization
+	ErrInvalidTaskToken = &Error{
+		Code: EInvalid,
+		Msg:  "cannot create task with token outside of the task's organization",
+	}
 )
 
 func ErrInternalTaskServiceError(err error) *Error {
@@ -131,3 +148,35 @@ func ErrRunNotDueYet(dueAt int64) *Error {
 		Msg:  fmt.Sprintf("run not due until: %v", time.Unix(dueAt, 0).UTC().Format(time.RFC3339)),
 	}
 }
+
+// RunAlreadyQueuedError is returned by ForceRun when a run is already queued for the requested time.
+type RunAlreadyQueuedError struct {
+	// ScheduledFor is the unix timestamp of the queued run.
+	ScheduledFor int64
+
+	// RetryAfter is how long the server suggested waiting before trying again, if it did.
+	RetryAfter time.Duration
+}
+
+const fmtRunAlreadyQueued = "run already queued for %s"
+
+func (e RunAlreadyQueuedError) Error() string {
+	return fmt.Sprintf(fmtRunAlreadyQueued, time.Unix(e.ScheduledFor, 0).UTC().Format(time.RFC3339))
+}
+
+// ParseRunAlreadyQueuedError attempts to parse a RunAlreadyQueuedError from msg.
+// If msg is formatted correctly, the resultant error is returned; otherwise it returns nil.
+func ParseRunAlreadyQueuedError(msg string) *RunAlreadyQueuedError {
+	var sf string
+	n, err := fmt.Sscanf(msg, fmtRunAlreadyQueued, &sf)
+	if err != nil || n != 1 {
+		return nil
+	}
+
+	t, err := time.Parse(time.RFC3339, sf)
+	if err != nil {
+		return nil
+	}
+
+	return &RunAlreadyQueuedError{ScheduledFor: t.Unix()}
+}
@@ -195,6 +195,10 @@ func CheckService(
 			name: "DeleteCheck",
 			fn:   DeleteCheck,
 		},
+		{
+			name: "CloneCheck",
+			fn:   CloneCheck,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -583,6 +587,42 @@ func CreateCheck(
 				},
 			},
 		},
+		{
+			name: "create check with unknown status message template variable",
+			fields: CheckFields{
+				IDGenerator:   mock.NewIDGenerator(checkOneID, t),
+				TimeGenerator: mock.TimeGenerator{FakeValue: time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC)},
+				Checks:        []influxdb.Check{},
+				Organizations: []*influxdb.Organization{
+					{
+						Name: "theorg",
+						ID:   MustIDBase16(orgOneID),
+					},
+				},
+			},
+			args: args{
+				userID: MustIDBase16(twoID),
+				check: &check.Deadman{
+					Base: check.Base{
+						Name:                  "name1",
+						OrgID:                 MustIDBase16(orgOneID),
+						Every:                 mustDuration("1m"),
+						Query:                 influxdb.DashboardQuery{Text: script},
+						Status:                influxdb.Active,
+						StatusMessageTemplate: "{check.nonsense}",
+					},
+					TimeSince: 21,
+					Level:     notification.Critical,
+				},
+			},
+			wants: wants{
+				checks: []influxdb.Check{},
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  `status message template references unknown variable "check.nonsense"`,
+				},
+			},
+		},
 		{
 			name: "create check with orgID not exist",
 			fields: CheckFields{
@@ -754,9 +794,11 @@ func FindChecks(
 ) {
 	type args struct {
 		ID           influxdb.ID
+		IDs          []influxdb.ID
 		name         string
 		organization string
 		OrgID        influxdb.ID
+		typ          string
 		findOptions  influxdb.FindOptions
 	}
 
@@ -925,6 +967,56 @@ func FindChecks(
 				},
 			},
 		},
+		{
+			name: "find checks by ids",
+			fields: CheckFields{
+				Organizations: []*influxdb.Organization{
+					{
+						Name: "theorg",
+						ID:   MustIDBase16(orgOneID),
+					},
+					{
+						Name: "otherorg",
+						ID:   MustIDBase16(orgTwoID),
+					},
+				},
+				Checks: []influxdb.Check{
+					deadman1,
+					threshold1,
+				},
+			},
+			args: args{
+				IDs: []influxdb.ID{MustIDBase16(checkTwoID), MustIDBase16(threeID)},
+			},
+			wants: wants{
+				checks: []influxdb.Check{
+					threshold1,
+				},
+			},
+		},
+		{
+			name: "find checks by type",
+			fields: CheckFields{
+				Organizations: []*influxdb.Organization{
+					{
+						Name: "theorg",
+						ID:   MustIDBase16(orgOneID),
+					},
+				},
+				Checks: []influxdb.Check{
+					deadman1,
+					threshold1,
+				},
+			},
+			args: args{
+				typ: "deadman",
+			},
+			wants: wants{
+				checks: []influxdb.Check{
+					deadman1,
+				},
+			},
+		},
 		{
 			name: "missing check returns no checks",
 			fields: CheckFields{
@@ -962,6 +1054,10 @@ func FindChecks(
 			if tt.args.name != "" {
 				filter.Name = &tt.args.name
 			}
+			if tt.args.typ != "" {
+				filter.Type = &tt.args.typ
+			}
+			filter.IDs = tt.args.IDs
 
 			checks, _, err := s.FindChecks(ctx, filter, tt.args.findOptions)
 			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
@@ -1576,3 +1672,196 @@ func PatchCheck(
 		})
 	}
 }
+
+// CloneCheck testing
+func CloneCheck(
+	init func(CheckFields, *testing.T) (influxdb.CheckService, string, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id   influxdb.ID
+		name string
+	}
+	type wants struct {
+		err   error
+		check influxdb.Check
+	}
+
+	tests := []struct {
+		name   string
+		fields CheckFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "clone threshold check",
+			fields: CheckFields{
+				IDGenerator:   mock.NewIDGenerator(threeID, t),
+				TimeGenerator: mock.TimeGenerator{FakeValue: time.Date(2007, 5, 4, 1, 2, 3, 0, time.UTC)},
+				Organizations: []*influxdb.Organization{
+					{
+						Name: "theorg",
+						ID:   MustIDBase16(orgTwoID),
+					},
+				},
+				Checks: []influxdb.Check{
+					threshold1,
+				},
+			},
+			args: args{
+				id:   MustIDBase16(checkTwoID),
+				name: "name2 clone",
+			},
+			wants: wants{
+				check: &check.Threshold{
+					Base: check.Base{
+						Name:                  "name2 clone",
+						ID:                    MustIDBase16(threeID),
+						OrgID:                 MustIDBase16(orgTwoID),
+						OwnerID:               MustIDBase16(sixID),
+						Description:           "desc2",
+						Status:                influxdb.Inactive,
+						StatusMessageTemplate: "msg2",
+						Every:                 mustDuration("1m"),
+						Query: influxdb.DashboardQuery{
+							Text: script,
+							BuilderConfig: influxdb.BuilderConfig{
+								Tags: []struct {
+									Key    string   `json:"key"`
+									Values []string `json:"values"`
+								}{
+									{
+										Key:    "_field",
+										Values: []string{"usage_user"},
+									},
+								},
+							},
+						},
+						Tags: []notification.Tag{
+							{Key: "k11", Value: "v11"},
+						},
+						CRUDLog: influxdb.CRUDLog{
+							CreatedAt: time.Date(2007, 5, 4, 1, 2, 3, 0, time.UTC),
+							UpdatedAt: time.Date(2007, 5, 4, 1, 2, 3, 0, time.UTC),
+						},
+					},
+					Thresholds: []check.ThresholdConfig{
+						&check.Lesser{
+							ThresholdConfigBase: check.ThresholdConfigBase{
+								Level: notification.Ok,
+							},
+							Value: 1000,
+						},
+						&check.Greater{
+							ThresholdConfigBase: check.ThresholdConfigBase{
+								Level: notification.Warn,
+							},
+							Value: 2000,
+						},
+						&check.Range{
+							ThresholdConfigBase: check.ThresholdConfigBase{
+								Level: notification.Info,
+							},
+							Min:    1500,
+							Max:    1900,
+							Within: true,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "clone name must be unique",
+			fields: CheckFields{
+				IDGenerator:   mock.NewIDGenerator(threeID, t),
+				TimeGenerator: mock.TimeGenerator{FakeValue: time.Date(2007, 5, 4, 1, 2, 3, 0, time.UTC)},
+				Organizations: []*influxdb.Organization{
+					{
+						Name: "theorg",
+						ID:   MustIDBase16(orgTwoID),
+					},
+				},
+				Checks: []influxdb.Check{
+					threshold1,
+				},
+			},
+			args: args{
+				id:   MustIDBase16(checkTwoID),
+				name: "name2",
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EConflict,
+					Msg:  "check with name name2 already exists",
+				},
+			},
+		},
+		{
+			name: "clone a check that does not exist",
+			fields: CheckFields{
+				IDGenerator:   mock.NewIDGenerator(threeID, t),
+				TimeGenerator: mock.TimeGenerator{FakeValue: time.Date(2007, 5, 4, 1, 2, 3, 0, time.UTC)},
+				Checks: []influxdb.Check{
+					threshold1,
+				},
+			},
+			args: args{
+				id:   MustIDBase16(checkOneID),
+				name: "clone",
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  "check not found",
+					Op:   influxdb.OpCloneCheck,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, opPrefix, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			clone, err := s.CloneCheck(ctx, tt.args.id, tt.args.name)
+			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
+
+			if diff := cmp.Diff(clone, tt.wants.check, checkCmpOptions...); diff != "" {
+				t.Errorf("check is different -got/+want\ndiff %s", diff)
+			}
+
+			if tt.wants.err != nil {
+				return
+			}
+
+			// The stored clone keeps its thresholds and the original is left untouched.
+			chk, err := s.FindCheckByID(ctx, clone.GetID())
+			if err != nil {
+				t.Fatalf("failed to retrieve clone: %v", err)
+			}
+			if diff := cmp.Diff(chk, tt.wants.check, checkCmpOptions...); diff != "" {
+				t.Errorf("stored clone is different -got/+want\ndiff %s", diff)
+			}
+			orig, err := s.FindCheckByID(ctx, tt.args.id)
+			if err != nil {
+				t.Fatalf("failed to retrieve original: %v", err)
+			}
+			if orig.GetName() != threshold1.GetName() || orig.GetStatus() != influxdb.Active {
+				t.Errorf("original check was modified: %v", orig)
+			}
+
+			// The task of the clone must not be scheduled until the clone is activated.
+			if ts, ok := s.(influxdb.TaskService); ok {
+				task, err := ts.FindTaskByID(ctx, clone.GetTaskID())
+				if err != nil {
+					t.Fatalf("failed to retrieve task of clone: %v", err)
+				}
+				if task.Status != influxdb.TaskStatusInactive {
+					t.Errorf("task of clone has status %q, want %q", task.Status, influxdb.TaskStatusInactive)
+				}
+			}
+		})
+	}
+}
@@ -79,6 +79,10 @@ func LabelService(
 			name: "DeleteLabelMapping",
 			fn:   DeleteLabelMapping,
 		},
+		{
+			name: "FindResourceLabelsForResources",
+			fn:   FindResourceLabelsForResources,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -742,6 +746,101 @@ func CreateLabelMapping(
 	}
 }
 
+func FindResourceLabelsForResources(
+	init func(LabelFields, *testing.T) (influxdb.LabelService, string, func()),
+	t *testing.T,
+) {
+	type args struct {
+		resourceIDs []influxdb.ID
+	}
+	type wants struct {
+		err    error
+		labels map[influxdb.ID][]*influxdb.Label
+	}
+
+	tests := []struct {
+		name   string
+		fields LabelFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "find labels of several resources",
+			fields: LabelFields{
+				Labels: []*influxdb.Label{
+					{
+						ID:   MustIDBase16(labelOneID),
+						Name: "Tag1",
+					},
+					{
+						ID:   MustIDBase16(labelTwoID),
+						Name: "Tag2",
+					},
+				},
+				Mappings: []*influxdb.LabelMapping{
+					{
+						LabelID:      MustIDBase16(labelOneID),
+						ResourceID:   MustIDBase16(bucketOneID),
+						ResourceType: influxdb.BucketsResourceType,
+					},
+					{
+						LabelID:      MustIDBase16(labelTwoID),
+						ResourceID:   MustIDBase16(bucketOneID),
+						ResourceType: influxdb.BucketsResourceType,
+					},
+					{
+						LabelID:      MustIDBase16(labelTwoID),
+						ResourceID:   MustIDBase16(bucketTwoID),
+						ResourceType: influxdb.BucketsResourceType,
+					},
+				},
+			},
+			args: args{
+				resourceIDs: []influxdb.ID{
+					MustIDBase16(bucketOneID),
+					MustIDBase16(bucketTwoID),
+					MustIDBase16(bucketThreeID),
+				},
+			},
+			wants: wants{
+				labels: map[influxdb.ID][]*influxdb.Label{
+					MustIDBase16(bucketOneID): {
+						{
+							ID:   MustIDBase16(labelOneID),
+							Name: "Tag1",
+						},
+						{
+							ID:   MustIDBase16(labelTwoID),
+							Name: "Tag2",
+						},
+					},
+					MustIDBase16(bucketTwoID): {
+						{
+							ID:   MustIDBase16(labelTwoID),
+							Name: "Tag2",
+						},
+					},
+					MustIDBase16(bucketThreeID): {},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, opPrefix, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+			labels, err := s.FindResourceLabelsForResources(ctx, influxdb.BucketsResourceType, tt.args.resourceIDs)
+			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
+
+			if diff := cmp.Diff(labels, tt.wants.labels, labelCmpOptions...); diff != "" {
+				t.Errorf("labels are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
 func DeleteLabelMapping(
 	init func(LabelFields, *testing.T) (influxdb.LabelService, string, func()),
 	t *testing.T,
@@ -594,7 +594,8 @@ func FindNotificationRules(
 	t *testing.T,
 ) {
 	type args struct {
-		filter influxdb.NotificationRuleFilter
+		filter      influxdb.NotificationRuleFilter
+		findOptions influxdb.FindOptions
 	}
 
 	type wants struct {
@@ -731,6 +732,190 @@ func FindNotificationRules(
 				},
 			},
 		},
+		{
+			name: "find all notification rules by offset and limit",
+			fields: NotificationRuleFields{
+				UserResourceMappings: []*influxdb.UserResourceMapping{
+					{
+						ResourceID:   MustIDBase16(oneID),
+						UserID:       MustIDBase16(sixID),
+						UserType:     influxdb.Owner,
+						ResourceType: influxdb.NotificationRuleResourceType,
+					},
+					{
+						ResourceID:   MustIDBase16(twoID),
+						UserID:       MustIDBase16(sixID),
+						UserType:     influxdb.Member,
+						ResourceType: influxdb.NotificationRuleResourceType,
+					},
+				},
+				NotificationRules: []influxdb.NotificationRule{
+					&rule.Slack{
+						Base: rule.Base{
+							ID:          MustIDBase16(oneID),
+							Name:        "name1",
+							OwnerID:     MustIDBase16(sixID),
+							OrgID:       MustIDBase16(fourID),
+							EndpointID:  MustIDBase16(twoID),
+							Status:      influxdb.Active,
+							RunbookLink: "runbooklink1",
+							SleepUntil:  &time3,
+							Every:       mustDuration("1h"),
+							CRUDLog: influxdb.CRUDLog{
+								CreatedAt: timeGen1.Now(),
+								UpdatedAt: timeGen2.Now(),
+							},
+						},
+						Channel:         "channel1",
+						MessageTemplate: "msg1",
+					},
+					&rule.Slack{
+						Base: rule.Base{
+							ID:          MustIDBase16(twoID),
+							Name:        "name2",
+							OwnerID:     MustIDBase16(sixID),
+							OrgID:       MustIDBase16(fourID),
+							EndpointID:  MustIDBase16(twoID),
+							Status:      influxdb.Active,
+							RunbookLink: "runbooklink2",
+							SleepUntil:  &time3,
+							Every:       mustDuration("1h"),
+							CRUDLog: influxdb.CRUDLog{
+								CreatedAt: timeGen1.Now(),
+								UpdatedAt: timeGen2.Now(),
+							},
+						},
+						MessageTemplate: "msg",
+					},
+				},
+			},
+			args: args{
+				filter: influxdb.NotificationRuleFilter{
+					UserResourceMappingFilter: influxdb.UserResourceMappingFilter{
+						UserID:       MustIDBase16(sixID),
+						ResourceType: influxdb.NotificationRuleResourceType,
+					},
+				},
+				findOptions: influxdb.FindOptions{
+					Offset: 1,
+					Limit:  1,
+				},
+			},
+			wants: wants{
+				notificationRules: []influxdb.NotificationRule{
+					&rule.Slack{
+						Base: rule.Base{
+							ID:          MustIDBase16(twoID),
+							Name:        "name2",
+							OwnerID:     MustIDBase16(sixID),
+							OrgID:       MustIDBase16(fourID),
+							EndpointID:  MustIDBase16(twoID),
+							Status:      influxdb.Active,
+							RunbookLink: "runbooklink2",
+							SleepUntil:  &time3,
+							Every:       mustDuration("1h"),
+							CRUDLog: influxdb.CRUDLog{
+								CreatedAt: timeGen1.Now(),
+								UpdatedAt: timeGen2.Now(),
+							},
+						},
+						MessageTemplate: "msg",
+					},
+				},
+			},
+		},
+		{
+			name: "find all notification rules by descending",
+			fields: NotificationRuleFields{
+				UserResourceMappings: []*influxdb.UserResourceMapping{
+					{
+						ResourceID:   MustIDBase16(oneID),
+						UserID:       MustIDBase16(sixID),
+						UserType:     influxdb.Owner,
+						ResourceType: influxdb.NotificationRuleResourceType,
+					},
+					{
+						ResourceID:   MustIDBase16(twoID),
+						UserID:       MustIDBase16(sixID),
+						UserType:     influxdb.Member,
+						ResourceType: influxdb.NotificationRuleResourceType,
+					},
+				},
+				NotificationRules: []influxdb.NotificationRule{
+					&rule.Slack{
+						Base: rule.Base{
+							ID:          MustIDBase16(oneID),
+							Name:        "name1",
+							OwnerID:     MustIDBase16(sixID),
+							OrgID:       MustIDBase16(fourID),
+							EndpointID:  MustIDBase16(twoID),
+							Status:      influxdb.Active,
+							RunbookLink: "runbooklink1",
+							SleepUntil:  &time3,
+							Every:       mustDuration("1h"),
+							CRUDLog: influxdb.CRUDLog{
+								CreatedAt: timeGen1.Now(),
+								UpdatedAt: timeGen2.Now(),
+							},
+						},
+						Channel:         "channel1",
+						MessageTemplate: "msg1",
+					},
+					&rule.Slack{
+						Base: rule.Base{
+							ID:          MustIDBase16(twoID),
+							Name:        "name2",
+							OwnerID:     MustIDBase16(sixID),
+							OrgID:       MustIDBase16(fourID),
+							EndpointID:  MustIDBase16(twoID),
+							Status:      influxdb.Active,
+							RunbookLink: "runbooklink2",
+							SleepUntil:  &time3,
+							Every:       mustDuration("1h"),
+							CRUDLog: influxdb.CRUDLog{
+								CreatedAt: timeGen1.Now(),
+								UpdatedAt: timeGen2.Now(),
+							},
+						},
+						MessageTemplate: "msg",
+					},
+				},
+			},
+			args: args{
+				filter: influxdb.NotificationRuleFilter{
+					UserResourceMappingFilter: influxdb.UserResourceMappingFilter{
+						UserID:       MustIDBase16(sixID),
+						ResourceType: influxdb.NotificationRuleResourceType,
+					},
+				},
+				findOptions: influxdb.FindOptions{
+					Limit:      1,
+					Descending: true,
+				},
+			},
+			wants: wants{
+				notificationRules: []influxdb.NotificationRule{
+					&rule.Slack{
+						Base: rule.Base{
+							ID:          MustIDBase16(twoID),
+							Name:        "name2",
+							OwnerID:     MustIDBase16(sixID),
+							OrgID:       MustIDBase16(fourID),
+							EndpointID:  MustIDBase16(twoID),
+							Status:      influxdb.Active,
+							RunbookLink: "runbooklink2",
+							SleepUntil:  &time3,
+							Every:       mustDuration("1h"),
+							CRUDLog: influxdb.CRUDLog{
+								CreatedAt: timeGen1.Now(),
+								UpdatedAt: timeGen2.Now(),
+							},
+						},
+						MessageTemplate: "msg",
+					},
+				},
+			},
+		},
 		{
 			name: "find owners only",
 			fields: NotificationRuleFields{
@@ -912,6 +1097,98 @@ func FindNotificationRules(
 				},
 			},
 		},
+		{
+			name: "filter by organization id and name",
+			fields: NotificationRuleFields{
+				Orgs: []*influxdb.Organization{
+					{
+						ID:   MustIDBase16(oneID),
+						Name: "org1",
+					},
+					{
+						ID:   MustIDBase16(fourID),
+						Name: "org4",
+					},
+				},
+				UserResourceMappings: []*influxdb.UserResourceMapping{
+					{
+						ResourceID:   MustIDBase16(oneID),
+						ResourceType: influxdb.NotificationRuleResourceType,
+						UserID:       MustIDBase16(sixID),
+						UserType:     influxdb.Owner,
+					},
+					{
+						ResourceID:   MustIDBase16(twoID),
+						ResourceType: influxdb.NotificationRuleResourceType,
+						UserID:       MustIDBase16(sixID),
+						UserType:     influxdb.Member,
+					},
+					{
+						ResourceID:   MustIDBase16(fourID),
+						ResourceType: influxdb.NotificationRuleResourceType,
+						UserID:       MustIDBase16(sixID),
+						UserType:     influxdb.Owner,
+					},
+				},
+				NotificationRules: []influxdb.NotificationRule{
+					&rule.Slack{
+						Base: rule.Base{
+							ID:         MustIDBase16(oneID),
+							OrgID:      MustIDBase16(fourID),
+							EndpointID: 1,
+							OwnerID:    MustIDBase16(sixID),
+							Status:     influxdb.Active,
+							Name:       "nr1",
+						},
+						Channel:         "ch1",
+						MessageTemplate: "msg1",
+					},
+					&rule.Slack{
+						Base: rule.Base{
+							ID:         MustIDBase16(twoID),
+							OrgID:      MustIDBase16(fourID),
+							EndpointID: 1,
+							OwnerID:    MustIDBase16(sixID),
+							Status:     influxdb.Active,
+							Name:       "nr2",
+						},
+						MessageTemplate: "body2",
+					},
+					&rule.Slack{
+						Base: rule.Base{
+							ID:         MustIDBase16(fourID),
+							OrgID:      MustIDBase16(oneID),
+							EndpointID: 1,
+							OwnerID:    MustIDBase16(sixID),
+							Status:     influxdb.Active,
+							Name:       "nr2",
+						},
+						MessageTemplate: "msg",
+					},
+				},
+			},
+			args: args{
+				filter: influxdb.NotificationRuleFilter{
+					OrgID: idPtr(MustIDBase16(fourID)),
+					Name:  strPtr("nr2"),
+				},
+			},
+			wants: wants{
+				notificationRules: []influxdb.NotificationRule{
+					&rule.Slack{
+						Base: rule.Base{
+							ID:         MustIDBase16(twoID),
+							OrgID:      MustIDBase16(fourID),
+							OwnerID:    MustIDBase16(sixID),
+							EndpointID: 1,
+							Status:     influxdb.Active,
+							Name:       "nr2",
+						},
+						MessageTemplate: "body2",
+					},
+				},
+			},
+		},
 		{
 			name: "filter by organization name only",
 			fields: NotificationRuleFields{
@@ -1278,7 +1555,7 @@ func FindNotificationRules(
 			defer done()
 			ctx := context.Background()
 
-			nrs, n, err := s.FindNotificationRules(ctx, tt.args.filter)
+			nrs, n, err := s.FindNotificationRules(ctx, tt.args.filter, tt.args.findOptions)
 			ErrorsEqual(t, err, tt.wants.err)
 			if n != len(tt.wants.notificationRules) {
 				t.Fatalf("notification rules length is different got %d, want %d", n, len(tt.wants.notificationRules))
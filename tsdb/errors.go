@@ -13,12 +13,25 @@ var (
 	ErrUnknownFieldType = errors.New("unknown field type")
 )
 
+// MaxPartialWriteReasons is the maximum number of distinct reasons kept as a
+// sample in a PartialWriteError.
+const MaxPartialWriteReasons = 10
+
 // PartialWriteError indicates a write request could only write a portion of the
 // requested values.
 type PartialWriteError struct {
-	Reason  string
+	Reason string
+
+	// Dropped is the number of distinct series keys that were dropped.
 	Dropped int
 
+	// Written and DroppedPoints are the number of points that were written and dropped.
+	Written       int
+	DroppedPoints int
+
+	// A sample of up to MaxPartialWriteReasons distinct reasons points were dropped.
+	Reasons []string
+
 	// A sorted slice of series keys that were dropped.
 	DroppedKeys [][]byte
 }
@@ -1,6 +1,7 @@
 package tsdb
 
 import (
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"unsafe"
@@ -24,6 +25,7 @@ type SeriesCollection struct {
 	Dropped     uint64
 	DroppedKeys [][]byte
 	Reason      string
+	Reasons     []string
 
 	// Used by the concurrent iterators to stage drops. Inefficient, but should be
 	// very infrequently used.
@@ -32,9 +34,9 @@ type SeriesCollection struct {
 
 // seriesCollectionState keeps track of concurrent iterator state.
 type seriesCollectionState struct {
-	mu     sync.Mutex
-	reason string
-	index  map[int]struct{}
+	mu      sync.Mutex
+	reasons []string
+	index   map[int]struct{}
 }
 
 // NewSeriesCollection builds a SeriesCollection from a slice of points. It does some filtering
@@ -200,9 +202,7 @@ func (s *SeriesCollection) Advance(length int) {
 
 // InvalidateAll causes all of the entries to become invalid.
 func (s *SeriesCollection) InvalidateAll(reason string) {
-	if s.Reason == "" {
-		s.Reason = reason
-	}
+	s.AddReason(reason)
 	s.Dropped += uint64(len(s.Keys))
 	s.DroppedKeys = append(s.DroppedKeys, s.Keys...)
 	s.Truncate(0)
@@ -233,8 +233,8 @@ func (s *SeriesCollection) ApplyConcurrentDrops() {
 	}
 	s.Truncate(j)
 
-	if s.Reason == "" {
-		s.Reason = state.reason
+	for _, reason := range state.reasons {
+		s.AddReason(reason)
 	}
 
 	// clear concurrent state
@@ -269,9 +269,7 @@ func (s *SeriesCollection) invalidIndex(index int, reason string) {
 		state.index = make(map[int]struct{})
 	}
 	state.index[index] = struct{}{}
-	if state.reason == "" {
-		state.reason = reason
-	}
+	state.reasons = appendReason(state.reasons, reason)
 	state.mu.Unlock()
 }
 
@@ -283,10 +281,46 @@ func (s *SeriesCollection) PartialWriteError() error {
 	}
 	droppedKeys := bytesutil.SortDedup(s.DroppedKeys)
 	return PartialWriteError{
-		Reason:      s.Reason,
-		Dropped:     len(droppedKeys),
-		DroppedKeys: droppedKeys,
+		Reason:        s.Reason,
+		Dropped:       len(droppedKeys),
+		Written:       s.Length(),
+		DroppedPoints: int(s.Dropped),
+		Reasons:       s.Reasons,
+		DroppedKeys:   droppedKeys,
+	}
+}
+
+// AddReason records reason as a reason entries have been dropped. The first reason
+// becomes the Reason of the collection, and up to MaxPartialWriteReasons distinct
+// reasons are kept as a sample.
+func (s *SeriesCollection) AddReason(reason string) {
+	if s.Reason == "" {
+		s.Reason = reason
+	}
+	s.Reasons = appendReason(s.Reasons, reason)
+}
+
+// AddReasonf is like AddReason, but only formats the reason if the collection
+// still has room to record it.
+func (s *SeriesCollection) AddReasonf(format string, args ...interface{}) {
+	if s.Reason != "" && len(s.Reasons) >= MaxPartialWriteReasons {
+		return
+	}
+	s.AddReason(fmt.Sprintf(format, args...))
+}
+
+// appendReason appends reason to reasons if it is not already present and there
+// is room left in the sample.
+func appendReason(reasons []string, reason string) []string {
+	if len(reasons) >= MaxPartialWriteReasons {
+		return reasons
+	}
+	for _, r := range reasons {
+		if r == reason {
+			return reasons
+		}
 	}
+	return append(reasons, reason)
 }
 
 // Iterator returns a new iterator over the entries in the collection. Multiple iterators
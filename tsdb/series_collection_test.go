@@ -118,9 +118,11 @@ func TestSeriesCollection(t *testing.T) {
 		collection.InvalidateAll("test reason")
 		assertEqual(t, "length", collection.Length(), 0)
 		assertEqual(t, "error", collection.PartialWriteError(), PartialWriteError{
-			Reason:      "test reason",
-			Dropped:     3,
-			DroppedKeys: bs("ka", "kb", "kc"),
+			Reason:        "test reason",
+			Dropped:       3,
+			DroppedPoints: 3,
+			Reasons:       []string{"test reason"},
+			DroppedKeys:   bs("ka", "kb", "kc"),
 		})
 	})
 
@@ -141,9 +143,34 @@ func TestSeriesCollection(t *testing.T) {
 		collection.ApplyConcurrentDrops()
 		assertEqual(t, "length", collection.Length(), 1)
 		assertEqual(t, "error", collection.PartialWriteError(), PartialWriteError{
-			Reason:      "test reason",
-			Dropped:     2,
-			DroppedKeys: bs("ka", "kc"),
+			Reason:        "test reason",
+			Dropped:       2,
+			Written:       1,
+			DroppedPoints: 2,
+			Reasons:       []string{"test reason"},
+			DroppedKeys:   bs("ka", "kc"),
 		})
 	})
+
+	t.Run("AddReasonf", func(t *testing.T) {
+		collection := &SeriesCollection{}
+		for i := 0; i < MaxPartialWriteReasons+1; i++ {
+			collection.AddReasonf("reason %d", i)
+		}
+		assertEqual(t, "reason", collection.Reason, "reason 0")
+		assertEqual(t, "reasons", len(collection.Reasons), MaxPartialWriteReasons)
+
+		// Once the sample is full, reasons are no longer formatted.
+		var formatted int
+		collection.AddReasonf("%v", formatCounter{&formatted})
+		assertEqual(t, "formatted", formatted, 0)
+	})
+}
+
+// formatCounter counts the number of times it is formatted.
+type formatCounter struct{ n *int }
+
+func (c formatCounter) String() string {
+	*c.n++
+	return "formatted"
 }
@@ -19,6 +19,7 @@ import (
 	"github.com/cespare/xxhash"
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/fs"
 	"github.com/influxdata/influxdb/pkg/lifecycle"
 	"github.com/influxdata/influxdb/pkg/slices"
 	"github.com/influxdata/influxdb/query"
@@ -339,6 +340,20 @@ func (i *Index) Wait() {
 	}
 }
 
+// Sync flushes the buffered data of the log files of all partitions and fsyncs
+// the files of the index, even if syncing has been disabled with DisableFsync.
+func (i *Index) Sync() error {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	for _, p := range i.partitions {
+		if err := p.Sync(); err != nil {
+			return err
+		}
+	}
+	return fs.SyncDir(i.path)
+}
+
 // Close closes the index.
 func (i *Index) Close() error {
 	// Lock index and close partitions.
@@ -244,6 +244,18 @@ func (f *LogFile) FlushAndSync() error {
 	return f.file.Sync()
 }
 
+// Flush flushes buffered data to the underlying file without syncing it. Unlike
+// FlushAndSync, it flushes even if flushing and syncing have been disabled.
+func (f *LogFile) Flush() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.w == nil {
+		return nil
+	}
+	return f.w.Flush()
+}
+
 // ID returns the file sequence identifier.
 func (f *LogFile) ID() int { return f.id }
 
@@ -407,6 +407,44 @@ func (p *Partition) close() error {
 	return err
 }
 
+// Sync flushes the buffered data of the active log file and fsyncs the files
+// of the partition along with its manifest.
+func (p *Partition) Sync() error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.activeLogFile != nil {
+		if err := p.activeLogFile.Flush(); err != nil {
+			return err
+		}
+	}
+
+	paths := []string{p.manifestPath()}
+	if p.fileSet != nil {
+		for _, f := range p.fileSet.Files() {
+			paths = append(paths, f.Path())
+		}
+	}
+	for _, path := range paths {
+		if err := syncFile(path); err != nil {
+			return err
+		}
+	}
+	return fs.SyncDir(p.path)
+}
+
+// syncFile fsyncs the file at path.
+func syncFile(path string) error {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer f.Close()
+	return f.Sync()
+}
+
 // Path returns the path to the partition.
 func (p *Partition) Path() string { return p.path }
 
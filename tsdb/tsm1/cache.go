@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -40,32 +41,114 @@ func ErrCacheMemorySizeLimitExceeded(n, limit uint64) error {
 	return CacheMemorySizeLimitExceededError{Size: n, Limit: limit}
 }
 
+// CacheHighWatermarkExceededError is the type of error returned from the cache when
+// a write would place it over its high watermark. Unlike exceeding the size limit,
+// it is expected to be transient: the write can be retried once snapshots and
+// compactions have freed up room in the cache.
+type CacheHighWatermarkExceededError struct {
+	Size      uint64
+	Watermark uint64
+}
+
+func (c CacheHighWatermarkExceededError) Error() string {
+	return fmt.Sprintf("cache high watermark exceeded, retry later: (%d/%d)", c.Size, c.Watermark)
+}
+
+// ErrCacheHighWatermarkExceeded returns an error indicating an operation could
+// not be completed because the cache is above its high watermark.
+func ErrCacheHighWatermarkExceeded(n, watermark uint64) error {
+	return CacheHighWatermarkExceededError{Size: n, Watermark: watermark}
+}
+
+// CachePrefixMemorySizeLimitExceededError is the type of error returned from the cache
+// when a write would place the keys sharing a prefix over the prefix's size limit.
+type CachePrefixMemorySizeLimitExceededError struct {
+	CacheMemorySizeLimitExceededError
+	Prefix string
+}
+
+func (c CachePrefixMemorySizeLimitExceededError) Error() string {
+	return fmt.Sprintf("prefix %q: %s", c.Prefix, c.CacheMemorySizeLimitExceededError.Error())
+}
+
+// ErrCachePrefixMemorySizeLimitExceeded returns an error indicating an operation
+// could not be completed due to exceeding the size limit of a key prefix.
+func ErrCachePrefixMemorySizeLimitExceeded(prefix string, n, limit uint64) error {
+	return CachePrefixMemorySizeLimitExceededError{
+		CacheMemorySizeLimitExceededError: CacheMemorySizeLimitExceededError{Size: n, Limit: limit},
+		Prefix:                            prefix,
+	}
+}
+
 // Cache maintains an in-memory store of Values for a set of keys.
 type Cache struct {
 	mu      sync.RWMutex
 	store   *ring
 	maxSize uint64
 
+	// prefixMaxSizes are soft limits on the bytes used by the keys starting with
+	// each prefix. maxSize remains the hard limit of the whole cache.
+	// prefixSizes and snapshotPrefixSizes track the bytes used by each limited
+	// prefix in the live store and in the snapshot. prefixLimited is 1 while
+	// prefixMaxSizes is not empty, so that writes skip prefixMu when it is.
+	prefixLimited       uint32
+	prefixMu            sync.RWMutex
+	prefixMaxSizes      map[string]uint64
+	prefixSizes         map[string]uint64
+	snapshotPrefixSizes map[string]uint64
+
 	// snapshots are the cache objects that are currently being written to tsm files
 	// they're kept in memory while flushing so they can be queried along with the cache.
 	// they are read only and should never be modified
 	snapshot     *Cache
 	snapshotting bool
 
+	// snapshotSizeThreshold is the number of bytes the cache may accumulate
+	// before ShouldSnapshot reports that it should be snapshotted.
+	snapshotSizeThreshold uint64
+
+	// highWatermark is the number of bytes above which writes are rejected
+	// with a CacheHighWatermarkExceededError, before maxSize is reached.
+	highWatermark uint64
+
 	tracker       *cacheTracker
 	lastSnapshot  time.Time
 	lastWriteTime time.Time
 }
 
+// CacheOption is a functional option for configuring a Cache.
+type CacheOption func(c *Cache)
+
+// WithSnapshotSizeThreshold sets the number of bytes the cache may accumulate
+// before ShouldSnapshot returns true. A threshold of 0 disables it.
+func WithSnapshotSizeThreshold(size uint64) CacheOption {
+	return func(c *Cache) {
+		c.snapshotSizeThreshold = size
+	}
+}
+
+// WithHighWatermark sets the number of bytes above which writes are rejected
+// with a retryable CacheHighWatermarkExceededError. A watermark of 0 disables it.
+func WithHighWatermark(size uint64) CacheOption {
+	return func(c *Cache) {
+		c.highWatermark = size
+	}
+}
+
 // NewCache returns an instance of a cache which will use a maximum of maxSize bytes of memory.
 // Only used for engine caches, never for snapshots.
-func NewCache(maxSize uint64) *Cache {
-	return &Cache{
+func NewCache(maxSize uint64, options ...CacheOption) *Cache {
+	c := &Cache{
 		maxSize:      maxSize,
 		store:        newRing(),
 		lastSnapshot: time.Now(),
 		tracker:      newCacheTracker(newCacheMetrics(nil), nil),
 	}
+
+	for _, option := range options {
+		option(c)
+	}
+	return c
 }
 
 // Write writes the set of values for the key to the cache. This function is goroutine-safe.
@@ -73,24 +156,26 @@ func NewCache(maxSize uint64) *Cache {
 func (c *Cache) Write(key []byte, values []Value) error {
 	addedSize := uint64(Values(values).Size())
 
-	// Enough room in the cache?
-	limit := c.maxSize
-	n := c.Size() + addedSize
+	if err := c.checkSize(addedSize); err != nil {
+		return err
+	}
 
-	if limit > 0 && n > limit {
+	if err := c.reservePrefixSize(key, addedSize); err != nil {
 		c.tracker.IncWritesErr()
 		c.tracker.AddWrittenBytesDrop(uint64(addedSize))
-		return ErrCacheMemorySizeLimitExceeded(n, limit)
+		return err
 	}
 
 	newKey, err := c.store.write(key, values)
 	if err != nil {
+		c.releasePrefixSize(key, addedSize)
 		c.tracker.IncWritesErr()
 		c.tracker.AddWrittenBytesErr(uint64(addedSize))
 		return err
 	}
 
 	if newKey {
+		c.addPrefixSize(key, uint64(len(key)))
 		addedSize += uint64(len(key))
 	}
 	// Update the cache size and the memory size stat.
@@ -108,11 +193,31 @@ func (c *Cache) Write(key []byte, values []Value) error {
 // values as possible.  If one key fails, the others can still succeed and an
 // error will be returned.
 func (c *Cache) WriteMulti(values map[string][]Value) error {
+	return c.writeMulti(values, true)
+}
+
+// WriteMultiUnsafe is like WriteMulti but does not check the values for field
+// type conflicts, while still enforcing the cache's memory limits. It is only
+// safe for trusted input where all the values of a key are of the same type as
+// each other and as the values already in the cache for that key.
+func (c *Cache) WriteMultiUnsafe(values map[string][]Value) error {
+	return c.writeMulti(values, false)
+}
+
+// CheckWriteMulti returns the error WriteMulti would fail with because the cache
+// has no room for values, without writing them. It allows callers to reject a
+// write before making it durable elsewhere, such as in the WAL.
+func (c *Cache) CheckWriteMulti(values map[string][]Value) error {
 	var addedSize uint64
 	for _, v := range values {
 		addedSize += uint64(Values(v).Size())
 	}
+	return c.checkSize(addedSize)
+}
 
+// checkSize returns an error if adding addedSize bytes would take the cache over
+// its max size or high watermark.
+func (c *Cache) checkSize(addedSize uint64) error {
 	// Enough room in the cache?
 	limit := c.maxSize // maxSize is safe for reading without a lock.
 	n := c.Size() + addedSize
@@ -121,25 +226,62 @@ func (c *Cache) WriteMulti(values map[string][]Value) error {
 		c.tracker.AddWrittenBytesDrop(uint64(addedSize))
 		return ErrCacheMemorySizeLimitExceeded(n, limit)
 	}
+	if c.highWatermark > 0 && n > c.highWatermark {
+		c.tracker.IncWritesErr()
+		c.tracker.AddWrittenBytesDrop(uint64(addedSize))
+		return ErrCacheHighWatermarkExceeded(n, c.highWatermark)
+	}
+	return nil
+}
+
+func (c *Cache) writeMulti(values map[string][]Value, check bool) error {
+	var addedSize uint64
+	for _, v := range values {
+		addedSize += uint64(Values(v).Size())
+	}
+
+	if err := c.checkSize(addedSize); err != nil {
+		return err
+	}
 
 	var werr error
 	c.mu.RLock()
 	store := c.store
 	c.mu.RUnlock()
 
-	var bytesWrittenErr uint64
+	var bytesWrittenErr, bytesDropped uint64
 
 	// We'll optimistically set size here, and then decrement it for write errors.
 	for k, v := range values {
-		newKey, err := store.write([]byte(k), v)
+		key := []byte(k)
+		size := uint64(Values(v).Size())
+
+		// Keys whose prefix is over its limit are dropped without affecting
+		// the other keys of the batch.
+		if err := c.reservePrefixSize(key, size); err != nil {
+			werr = err
+			addedSize -= size
+			bytesDropped += size
+			continue
+		}
+
+		var newKey bool
+		var err error
+		if check {
+			newKey, err = store.write(key, v)
+		} else {
+			newKey = store.writeUnchecked(key, v)
+		}
 		if err != nil {
 			// The write failed, hold onto the error and adjust the size delta.
+			c.releasePrefixSize(key, size)
 			werr = err
-			addedSize -= uint64(Values(v).Size())
-			bytesWrittenErr += uint64(Values(v).Size())
+			addedSize -= size
+			bytesWrittenErr += size
 		}
 
 		if newKey {
+			c.addPrefixSize(key, uint64(len(k)))
 			addedSize += uint64(len(k))
 		}
 	}
@@ -150,6 +292,7 @@ func (c *Cache) WriteMulti(values map[string][]Value) error {
 		c.tracker.IncWritesErr()
 		c.tracker.IncWritesDrop()
 		c.tracker.AddWrittenBytesErr(bytesWrittenErr)
+		c.tracker.AddWrittenBytesDrop(bytesDropped)
 	}
 
 	// Update the memory size stat
@@ -198,6 +341,11 @@ func (c *Cache) Snapshot() (*Cache, error) {
 	c.snapshot.tracker.SetSnapshotSize(snapshotSize) // Save the size of the snapshot on the snapshot cache
 	c.tracker.SetSnapshotSize(snapshotSize)          // Save the size of the snapshot on the live cache
 
+	// The prefix sizes of the live store now belong to the snapshot.
+	c.prefixMu.Lock()
+	c.snapshotPrefixSizes, c.prefixSizes = c.prefixSizes, nil
+	c.prefixMu.Unlock()
+
 	// Reset the cache's store.
 	c.store.reset()
 	c.tracker.SetCacheSize(0)
@@ -253,6 +401,10 @@ func (c *Cache) ClearSnapshot(success bool) {
 		c.tracker.SetSnapshotSize(0)
 		c.tracker.SetDiskBytes(0)
 		c.tracker.SetSnapshotsActive(0)
+
+		c.prefixMu.Lock()
+		c.snapshotPrefixSizes = nil
+		c.prefixMu.Unlock()
 	}
 }
 
@@ -261,6 +413,35 @@ func (c *Cache) Size() uint64 {
 	return c.tracker.CacheSize() + c.tracker.SnapshotSize()
 }
 
+// SnapshotSize returns the number of bytes a call to Snapshot would move from
+// the live store into the snapshot, without taking the snapshot.
+func (c *Cache) SnapshotSize() uint64 {
+	return c.tracker.CacheSize()
+}
+
+// TotalBytes returns the number of bytes the cache is responsible for: the
+// bytes of the live store plus those of a snapshot still being written.
+// Unlike Size, it is read under the cache lock, so a concurrent Snapshot
+// cannot cause the bytes moving to the snapshot to be counted twice.
+func (c *Cache) TotalBytes() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.tracker.CacheSize() + c.tracker.SnapshotSize()
+}
+
+// ShouldSnapshot returns true if the cache has accumulated more bytes than its
+// snapshot size threshold since the last snapshot. Bytes already handed to a
+// snapshot are not counted.
+func (c *Cache) ShouldSnapshot() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.snapshotSizeThreshold == 0 {
+		return false
+	}
+	return c.tracker.CacheSize() > c.snapshotSizeThreshold
+}
+
 // MaxSize returns the maximum number of bytes the cache may consume.
 func (c *Cache) MaxSize() uint64 {
 	return c.maxSize
@@ -281,6 +462,37 @@ func (c *Cache) Keys() [][]byte {
 	return store.keys(true)
 }
 
+// ForEachKey calls fn with each key in the cache, in no particular order, until
+// fn returns false. Unlike Keys, it does not build a slice holding every key.
+// fn may call other methods of the cache.
+func (c *Cache) ForEachKey(fn func(key []byte) bool) {
+	c.mu.RLock()
+	store := c.store
+	c.mu.RUnlock()
+	store.forEachKey(fn)
+}
+
+// KeyStats returns the number of values buffered for each key, including
+// the values of any snapshot being written.
+func (c *Cache) KeyStats() map[string]int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	stats := make(map[string]int, c.store.count())
+	count := func(k []byte, e *entry) error {
+		stats[string(k)] += e.count()
+		return nil
+	}
+	// applySerial only errors if the closure returns an error.
+	_ = c.store.applySerial(count)
+	if c.snapshot != nil {
+		_ = c.snapshot.store.applySerial(count)
+	}
+	return stats
+}
+
+// Split partitions the keys of the cache across n caches. Each returned cache
+// reports the size of the keys it holds.
 func (c *Cache) Split(n int) []*Cache {
 	if n == 1 {
 		return []*Cache{c}
@@ -289,13 +501,58 @@ func (c *Cache) Split(n int) []*Cache {
 	caches := make([]*Cache, n)
 	storers := c.store.split(n)
 	for i := 0; i < n; i++ {
-		caches[i] = &Cache{
-			store: storers[i],
-		}
+		caches[i] = c.newSplit(storers[i])
+	}
+	return caches
+}
+
+// SplitByPrefix partitions the keys of the cache by prefix, such as the
+// org and bucket prefix of a series key, rather than by hash. It returns a
+// cache for each of prefixes followed by a cache holding the keys that match
+// none of them. A key is assigned to the first prefix it matches.
+func (c *Cache) SplitByPrefix(prefixes [][]byte) []*Cache {
+	c.mu.RLock()
+	storers := c.store.splitByPrefix(prefixes)
+	c.mu.RUnlock()
+
+	caches := make([]*Cache, len(storers))
+	for i, store := range storers {
+		caches[i] = c.newSplit(store)
 	}
 	return caches
 }
 
+// newSplit returns a cache over store, a part of the store of c, with a
+// tracker of its own reporting the size of store. The tracker does not report
+// to the metrics of c, so that writes to the split do not move the gauges of
+// the cache it was split from.
+func (c *Cache) newSplit(store *ring) *Cache {
+	s := &Cache{
+		store:   store,
+		tracker: newCacheTracker(newCacheMetrics(nil), nil),
+	}
+	s.tracker.SetCacheSize(store.size())
+	return s
+}
+
+// Merge writes the values of all the keys in other into the cache. It is the
+// inverse of Split. The values are written with WriteMulti, so the memory limits
+// of the cache are enforced and values conflicting with the type of the values
+// already in the cache are dropped. The first such error is returned, but the
+// keys that did not fail are still merged.
+func (c *Cache) Merge(other *Cache) error {
+	keys := other.Keys()
+	if len(keys) == 0 {
+		return nil
+	}
+
+	values := make(map[string][]Value, len(keys))
+	for _, k := range keys {
+		values[string(k)] = other.Values(k)
+	}
+	return c.WriteMulti(values)
+}
+
 // Type returns the series type for a key.
 func (c *Cache) Type(key []byte) (models.FieldType, error) {
 	c.mu.RLock()
@@ -330,6 +587,13 @@ func (c *Cache) Type(key []byte) (models.FieldType, error) {
 
 // Values returns a copy of all values, deduped and sorted, for the given key.
 func (c *Cache) Values(key []byte) Values {
+	return c.ValuesBetween(key, math.MinInt64, math.MaxInt64)
+}
+
+// ValuesBetween returns a copy of the values, deduped and sorted, for the given key
+// with timestamps between min and max inclusive. Only the values within the range
+// are copied.
+func (c *Cache) ValuesBetween(key []byte, min, max int64) Values {
 	var snapshotEntries *entry
 
 	c.mu.RLock()
@@ -339,59 +603,83 @@ func (c *Cache) Values(key []byte) Values {
 	}
 	c.mu.RUnlock()
 
-	if e == nil {
-		if snapshotEntries == nil {
-			// No values in hot cache or snapshots.
-			return nil
-		}
-	} else {
-		e.deduplicate()
+	if e == nil && snapshotEntries == nil {
+		// No values in hot cache or snapshots.
+		return nil
 	}
 
-	// Build the sequence of entries that will be returned, in the correct order.
-	// Calculate the required size of the destination buffer.
-	var entries []*entry
-	sz := 0
-
+	// Copy the snapshot and then the hot values within the range. Individual
+	// entries are sorted while they are copied, so now the code has to check
+	// if the resultant buffer will be sorted from start to finish.
+	var values Values
 	if snapshotEntries != nil {
-		snapshotEntries.deduplicate() // guarantee we are deduplicated
-		entries = append(entries, snapshotEntries)
-		sz += snapshotEntries.count()
+		values = snapshotEntries.appendBetween(values, min, max)
 	}
-
 	if e != nil {
-		entries = append(entries, e)
-		sz += e.count()
+		values = e.appendBetween(values, min, max)
 	}
-
-	// Any entries? If not, return.
-	if sz == 0 {
+	if len(values) == 0 {
 		return nil
 	}
-
-	// Create the buffer, and copy all hot values and snapshots. Individual
-	// entries are sorted at this point, so now the code has to check if the
-	// resultant buffer will be sorted from start to finish.
-	values := make(Values, sz)
-	n := 0
-	for _, e := range entries {
-		e.mu.RLock()
-		n += copy(values[n:], e.values)
-		e.mu.RUnlock()
-	}
-	values = values[:n]
 	values = values.Deduplicate()
 
 	return values
 }
 
+// rangeBounds returns the positions of the sorted values a such that a[lo:hi]
+// holds the values between min and max inclusive.
+func rangeBounds(a Values, min, max int64) (lo, hi int) {
+	lo, hi = a.FindRange(min, max)
+	if lo == -1 && hi == -1 {
+		return 0, 0
+	}
+	if hi < len(a) && a[hi].UnixNano() == max {
+		hi++
+	}
+	if hi < lo {
+		hi = lo
+	}
+	return lo, hi
+}
+
+// DeleteBucketRangeOptions configures a DeleteBucketRangeWithOptions call.
+type DeleteBucketRangeOptions struct {
+	// ExclusiveMax excludes values with a timestamp equal to max from the deletion,
+	// so that the range [min, max) is deleted.
+	ExclusiveMax bool
+}
+
 // DeleteBucketRange removes values for all keys containing points
 // with timestamps between min and max contained in the bucket identified
 // by name from the cache.
 func (c *Cache) DeleteBucketRange(ctx context.Context, name []byte, min, max int64, pred Predicate) {
+	c.DeleteBucketRangeWithOptions(ctx, name, min, max, pred, DeleteBucketRangeOptions{})
+}
+
+// DeleteMatching removes values for all keys matching pred containing points
+// with timestamps between min and max from the cache, regardless of the bucket
+// the keys belong to. A nil pred matches every key.
+func (c *Cache) DeleteMatching(ctx context.Context, min, max int64, pred Predicate) {
+	c.DeleteBucketRangeWithOptions(ctx, nil, min, max, pred, DeleteBucketRangeOptions{})
+}
+
+// DeleteBucketRangeWithOptions removes values for all keys containing points
+// with timestamps between min and max contained in the bucket identified
+// by name from the cache. The max is inclusive unless opts.ExclusiveMax is set.
+func (c *Cache) DeleteBucketRangeWithOptions(ctx context.Context, name []byte, min, max int64, pred Predicate, opts DeleteBucketRangeOptions) {
 	span, _ := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
+	if opts.ExclusiveMax {
+		if max == math.MinInt64 {
+			return
+		}
+		max--
+	}
+	if min > max {
+		return
+	}
+
 	// TODO(edd/jeff): find a way to optimize lock usage
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -408,10 +696,12 @@ func (c *Cache) DeleteBucketRange(ctx context.Context, name []byte, min, max int
 			return nil
 		}
 
-		total += uint64(e.size())
+		sz := uint64(e.size())
+		total += sz
 
 		// if everything is being deleted, just stage it to be deleted and move on.
 		if min == math.MinInt64 && max == math.MaxInt64 {
+			c.releasePrefixSize(k, sz)
 			toDelete = append(toDelete, k)
 			return nil
 		}
@@ -419,6 +709,7 @@ func (c *Cache) DeleteBucketRange(ctx context.Context, name []byte, min, max int
 		// filter the values and subtract out the remaining bytes from the reduction.
 		e.filter(min, max)
 		total -= uint64(e.size())
+		c.releasePrefixSize(k, sz-uint64(e.size()))
 
 		// if it has no entries left, flag it to be deleted.
 		if e.count() == 0 {
@@ -430,6 +721,7 @@ func (c *Cache) DeleteBucketRange(ctx context.Context, name []byte, min, max int
 
 	for _, k := range toDelete {
 		total += uint64(len(k))
+		c.releasePrefixSize(k, uint64(len(k)))
 		c.store.remove(k)
 	}
 
@@ -437,6 +729,26 @@ func (c *Cache) DeleteBucketRange(ctx context.Context, name []byte, min, max int
 	c.tracker.SetMemBytes(uint64(c.Size()))
 }
 
+// RemoveKey removes all values for the exact key from the cache. It returns
+// true if the key existed. Values of the key held by a snapshot are not removed.
+func (c *Cache) RemoveKey(key []byte) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := c.store.entry(key)
+	if e == nil {
+		return false
+	}
+
+	sz := uint64(e.size() + len(key))
+	c.releasePrefixSize(key, sz)
+	c.store.remove(key)
+
+	c.tracker.DecCacheSize(sz)
+	c.tracker.SetMemBytes(uint64(c.Size()))
+	return true
+}
+
 // SetMaxSize updates the memory limit of the cache.
 func (c *Cache) SetMaxSize(size uint64) {
 	c.mu.Lock()
@@ -444,6 +756,127 @@ func (c *Cache) SetMaxSize(size uint64) {
 	c.mu.Unlock()
 }
 
+// HighWatermark returns the number of bytes above which writes are rejected
+// with a CacheHighWatermarkExceededError.
+func (c *Cache) HighWatermark() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.highWatermark
+}
+
+// SetHighWatermark updates the number of bytes above which writes are rejected
+// with a CacheHighWatermarkExceededError. A watermark of 0 disables it.
+func (c *Cache) SetHighWatermark(size uint64) {
+	c.mu.Lock()
+	c.highWatermark = size
+	c.mu.Unlock()
+}
+
+// SetPrefixMaxSizes sets soft memory limits for the keys starting with each
+// prefix. Writes that would exceed the limit of a prefix fail with a
+// CachePrefixMemorySizeLimitExceededError, while the keys of other prefixes are
+// unaffected. Usage of a prefix is accounted from the time its limit is set.
+// A nil or empty map removes all prefix limits.
+func (c *Cache) SetPrefixMaxSizes(limits map[string]uint64) {
+	c.prefixMu.Lock()
+	defer c.prefixMu.Unlock()
+
+	c.prefixMaxSizes = make(map[string]uint64, len(limits))
+	for p, limit := range limits {
+		c.prefixMaxSizes[p] = limit
+	}
+	c.prefixSizes = nil
+	c.snapshotPrefixSizes = nil
+
+	var limited uint32
+	if len(c.prefixMaxSizes) > 0 {
+		limited = 1
+	}
+	atomic.StoreUint32(&c.prefixLimited, limited)
+}
+
+// hasPrefixLimit returns true if key starts with a prefix that has a limit.
+func (c *Cache) hasPrefixLimit(key []byte) bool {
+	if atomic.LoadUint32(&c.prefixLimited) == 0 {
+		return false
+	}
+
+	c.prefixMu.RLock()
+	defer c.prefixMu.RUnlock()
+	for p := range c.prefixMaxSizes {
+		if bytes.HasPrefix(key, []byte(p)) {
+			return true
+		}
+	}
+	return false
+}
+
+// reservePrefixSize adds n bytes to the usage of every limited prefix of key,
+// unless doing so would exceed the limit of any of them.
+func (c *Cache) reservePrefixSize(key []byte, n uint64) error {
+	if !c.hasPrefixLimit(key) {
+		return nil
+	}
+
+	c.prefixMu.Lock()
+	defer c.prefixMu.Unlock()
+
+	for p, limit := range c.prefixMaxSizes {
+		if !bytes.HasPrefix(key, []byte(p)) {
+			continue
+		}
+		if sz := c.prefixSizes[p] + c.snapshotPrefixSizes[p] + n; sz > limit {
+			return ErrCachePrefixMemorySizeLimitExceeded(p, sz, limit)
+		}
+	}
+	c.addPrefixSizeLocked(key, n)
+	return nil
+}
+
+// addPrefixSize adds n bytes to the usage of every limited prefix of key.
+func (c *Cache) addPrefixSize(key []byte, n uint64) {
+	if !c.hasPrefixLimit(key) {
+		return
+	}
+
+	c.prefixMu.Lock()
+	c.addPrefixSizeLocked(key, n)
+	c.prefixMu.Unlock()
+}
+
+func (c *Cache) addPrefixSizeLocked(key []byte, n uint64) {
+	for p := range c.prefixMaxSizes {
+		if !bytes.HasPrefix(key, []byte(p)) {
+			continue
+		}
+		if c.prefixSizes == nil {
+			c.prefixSizes = make(map[string]uint64, len(c.prefixMaxSizes))
+		}
+		c.prefixSizes[p] += n
+	}
+}
+
+// releasePrefixSize removes n bytes from the live usage of every limited prefix of key.
+func (c *Cache) releasePrefixSize(key []byte, n uint64) {
+	if !c.hasPrefixLimit(key) {
+		return
+	}
+
+	c.prefixMu.Lock()
+	defer c.prefixMu.Unlock()
+
+	for p := range c.prefixMaxSizes {
+		if !bytes.HasPrefix(key, []byte(p)) {
+			continue
+		}
+		if sz := c.prefixSizes[p]; sz > n {
+			c.prefixSizes[p] = sz - n
+		} else {
+			delete(c.prefixSizes, p)
+		}
+	}
+}
+
 // values returns the values for the key. It assumes the data is already sorted.
 // It doesn't lock the cache but it does read-lock the entry if there is one for the key.
 // values should only be used in compact.go in the CacheKeyIterator.
@@ -471,44 +904,116 @@ func (c *Cache) ApplyEntryFn(f func(key []byte, entry *entry) error) error {
 // CacheLoader processes a set of WAL segment files, and loads a cache with the data
 // contained within those files.
 type CacheLoader struct {
-	reader *wal.WALReader
+	reader    *wal.WALReader
+	keyPrefix []byte
+	onError   func(CacheLoaderError)
+}
+
+// CacheLoaderOption is a functional option for configuring a CacheLoader.
+type CacheLoaderOption func(cl *CacheLoader)
+
+// WithCacheLoaderKeyPrefix restricts a CacheLoader to loading the values of
+// the keys starting with prefix. An empty prefix loads all keys.
+func WithCacheLoaderKeyPrefix(prefix []byte) CacheLoaderOption {
+	return func(cl *CacheLoader) {
+		cl.keyPrefix = prefix
+	}
+}
+
+// CacheLoaderError describes WAL data that could not be loaded into the cache.
+type CacheLoaderError struct {
+	Path  string // path of the segment file
+	Pos   int64  // position of the entry in the segment file
+	Bytes int64  // number of bytes skipped
+	Err   error
+}
+
+// CacheLoaderStats summarizes the data loaded into the cache by a CacheLoader.
+type CacheLoaderStats struct {
+	EntriesLoaded int   // number of WAL entries applied to the cache
+	BytesSkipped  int64 // number of bytes of corrupt WAL data skipped
 }
 
 // NewCacheLoader returns a new instance of a CacheLoader.
-func NewCacheLoader(files []string) *CacheLoader {
-	return &CacheLoader{
+func NewCacheLoader(files []string, options ...CacheLoaderOption) *CacheLoader {
+	cl := &CacheLoader{
 		reader: wal.NewWALReader(files),
 	}
+
+	for _, option := range options {
+		option(cl)
+	}
+	return cl
 }
 
-// Load returns a cache loaded with the data contained within the segment files.
-func (cl *CacheLoader) Load(cache *Cache) error {
-	return cl.reader.Read(func(entry wal.WALEntry) error {
-		switch en := entry.(type) {
-		case *wal.WriteWALEntry:
-			return cache.WriteMulti(en.Values)
+// Load loads the cache with the data contained within the segment files. A
+// corrupt segment is truncated and loading continues with the next segment.
+// Load returns a summary of the data loaded and skipped.
+func (cl *CacheLoader) Load(cache *Cache) (CacheLoaderStats, error) {
+	var stats CacheLoaderStats
+	cl.reader.WithCorruptionHandler(func(c wal.SegmentCorruption) {
+		stats.BytesSkipped += c.Size
+		if cl.onError != nil {
+			cl.onError(CacheLoaderError{Path: c.Path, Pos: c.Pos, Bytes: c.Size, Err: c.Err})
+		}
+	})
+	defer cl.reader.WithCorruptionHandler(nil)
 
-		case *wal.DeleteBucketRangeWALEntry:
-			var pred Predicate
-			if len(en.Predicate) > 0 {
-				var err error
-				pred, err = UnmarshalPredicate(en.Predicate)
-				if err != nil {
-					return err
-				}
-			}
+	err := cl.reader.Read(func(entry wal.WALEntry) error {
+		if err := cl.apply(cache, entry); err != nil {
+			return err
+		}
+		stats.EntriesLoaded++
+		return nil
+	})
+	return stats, err
+}
 
-			// TODO(edd): we need to clean up how we're encoding the prefix so that we
-			// don't have to remember to get it right everywhere we need to touch TSM data.
-			encoded := tsdb.EncodeName(en.OrgID, en.BucketID)
-			name := models.EscapeMeasurement(encoded[:])
+func (cl *CacheLoader) apply(cache *Cache, entry wal.WALEntry) error {
+	switch en := entry.(type) {
+	case *wal.WriteWALEntry:
+		if len(cl.keyPrefix) == 0 {
+			return cache.WriteMulti(en.Values)
+		}
 
-			cache.DeleteBucketRange(context.Background(), name, en.Min, en.Max, pred)
+		prefix := string(cl.keyPrefix)
+		values := make(map[string][]Value)
+		for k, v := range en.Values {
+			if strings.HasPrefix(k, prefix) {
+				values[k] = v
+			}
+		}
+		if len(values) == 0 {
 			return nil
 		}
+		return cache.WriteMulti(values)
+
+	case *wal.DeleteBucketRangeWALEntry:
+		var pred Predicate
+		if len(en.Predicate) > 0 {
+			var err error
+			pred, err = UnmarshalPredicate(en.Predicate)
+			if err != nil {
+				return err
+			}
+		}
+
+		// TODO(edd): we need to clean up how we're encoding the prefix so that we
+		// don't have to remember to get it right everywhere we need to touch TSM data.
+		encoded := tsdb.EncodeName(en.OrgID, en.BucketID)
+		name := models.EscapeMeasurement(encoded[:])
 
+		cache.DeleteBucketRange(context.Background(), name, en.Min, en.Max, pred)
 		return nil
-	})
+	}
+
+	return nil
+}
+
+// WithErrorHandler sets a function to be called for each WAL entry that is
+// skipped because it could not be read.
+func (cl *CacheLoader) WithErrorHandler(fn func(CacheLoaderError)) {
+	cl.onError = fn
 }
 
 // WithLogger sets the logger on the CacheLoader.
@@ -19,13 +19,9 @@ type entry struct {
 // newEntryValues returns a new instance of entry with the given values.  If the
 // values are not valid, an error is returned.
 func newEntryValues(values []Value) (*entry, error) {
-	e := &entry{}
-	e.values = make(Values, 0, len(values))
-	e.values = append(e.values, values...)
-
 	// No values, don't check types and ordering
 	if len(values) == 0 {
-		return e, nil
+		return newEntryValuesUnchecked(values), nil
 	}
 
 	et := valueType(values[0])
@@ -36,10 +32,22 @@ func newEntryValues(values []Value) (*entry, error) {
 		}
 	}
 
+	return newEntryValuesUnchecked(values), nil
+}
+
+// newEntryValuesUnchecked returns a new instance of entry with the given values
+// without checking that they are all of the same type.
+func newEntryValuesUnchecked(values []Value) *entry {
+	e := &entry{}
+	e.values = make(Values, 0, len(values))
+	e.values = append(e.values, values...)
+
 	// Set the type of values stored.
-	e.vtype = et
+	if len(values) > 0 {
+		e.vtype = valueType(values[0])
+	}
 
-	return e, nil
+	return e
 }
 
 // add adds the given values to the entry.
@@ -57,19 +65,28 @@ func (e *entry) add(values []Value) error {
 		}
 	}
 
+	e.addUnchecked(values)
+	return nil
+}
+
+// addUnchecked adds the given values to the entry without checking their type.
+func (e *entry) addUnchecked(values []Value) {
+	if len(values) == 0 {
+		return // Nothing to do.
+	}
+
 	// entry currently has no values, so add the new ones and we're done.
 	e.mu.Lock()
 	if len(e.values) == 0 {
 		e.values = values
 		e.vtype = valueType(values[0])
 		e.mu.Unlock()
-		return nil
+		return
 	}
 
 	// Append the new values to the existing ones...
 	e.values = append(e.values, values...)
 	e.mu.Unlock()
-	return nil
 }
 
 // deduplicate sorts and orders the entry's values. If values are already deduped and sorted,
@@ -92,6 +109,20 @@ func (e *entry) count() int {
 	return n
 }
 
+// appendBetween appends to dst the values of the entry with timestamps between
+// min and max inclusive. The entry is deduplicated first, under the same lock,
+// so that values written concurrently cannot leave it unsorted.
+func (e *entry) appendBetween(dst Values, min, max int64) Values {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.values) > 1 {
+		e.values = e.values.Deduplicate()
+	}
+	lo, hi := rangeBounds(e.values, min, max)
+	return append(dst, e.values[lo:hi]...)
+}
+
 // filter removes all values with timestamps between min and max inclusive.
 func (e *entry) filter(min, max int64) {
 	e.mu.Lock()
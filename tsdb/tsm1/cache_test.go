@@ -15,6 +15,7 @@ import (
 	"sync/atomic"
 	"testing"
 
+	"github.com/influxdata/influxdb/pkg/bytesutil"
 	"github.com/influxdata/influxdb/storage/wal"
 
 	"github.com/golang/snappy"
@@ -157,6 +158,79 @@ func TestCache_CacheWriteMulti_TypeConflict(t *testing.T) {
 	}
 }
 
+func TestCache_CacheWriteMultiUnsafe(t *testing.T) {
+	v0 := NewValue(1, 1.0)
+	v1 := NewValue(2, 2.0)
+	v2 := NewValue(3, 3.0)
+	values := Values{v0, v1, v2}
+	valuesSize := uint64(v0.Size() + v1.Size() + v2.Size())
+
+	c := NewCache(2*valuesSize + 6)
+
+	if err := c.WriteMultiUnsafe(map[string][]Value{"foo": values, "bar": values}); err != nil {
+		t.Fatalf("failed to write keys to cache: %s", err.Error())
+	}
+	if n := c.Size(); n != 2*valuesSize+6 {
+		t.Fatalf("cache size incorrect after 2 writes, exp %d, got %d", 2*valuesSize+6, n)
+	}
+	if exp, keys := [][]byte{[]byte("bar"), []byte("foo")}, c.Keys(); !reflect.DeepEqual(keys, exp) {
+		t.Fatalf("cache keys incorrect after 2 writes, exp %v, got %v", exp, keys)
+	}
+	if exp, got := values, c.Values([]byte("foo")); !reflect.DeepEqual(exp, got) {
+		t.Fatalf("cache values incorrect, exp %v, got %v", exp, got)
+	}
+
+	// The memory limit is still enforced.
+	err := c.WriteMultiUnsafe(map[string][]Value{"foo": {NewValue(4, 4.0)}})
+	if _, ok := err.(CacheMemorySizeLimitExceededError); !ok {
+		t.Fatalf("expected a cache memory size limit error, got %v", err)
+	}
+	if n := c.Size(); n != 2*valuesSize+6 {
+		t.Fatalf("cache size changed after a rejected write, exp %d, got %d", 2*valuesSize+6, n)
+	}
+	if exp, got := values, c.Values([]byte("foo")); !reflect.DeepEqual(exp, got) {
+		t.Fatalf("cache values changed after a rejected write, exp %v, got %v", exp, got)
+	}
+}
+
+func TestCache_RemoveKey(t *testing.T) {
+	v0 := NewValue(1, 1.0)
+	v1 := NewValue(2, 2.0)
+	values := Values{v0, v1}
+	valuesSize := uint64(v0.Size() + v1.Size())
+
+	c := NewCache(0)
+
+	for _, key := range []string{"foo", "bar", "baz"} {
+		if err := c.Write([]byte(key), values); err != nil {
+			t.Fatalf("failed to write key %s to cache: %s", key, err.Error())
+		}
+	}
+	if n := c.Size(); n != 3*valuesSize+9 {
+		t.Fatalf("cache size incorrect after 3 writes, exp %d, got %d", 3*valuesSize+9, n)
+	}
+
+	if !c.RemoveKey([]byte("bar")) {
+		t.Fatalf("expected key bar to exist")
+	}
+	if exp, keys := [][]byte{[]byte("baz"), []byte("foo")}, c.Keys(); !reflect.DeepEqual(keys, exp) {
+		t.Fatalf("cache keys incorrect after remove, exp %v, got %v", exp, keys)
+	}
+	if n := c.Size(); n != 2*valuesSize+6 {
+		t.Fatalf("cache size incorrect after remove, exp %d, got %d", 2*valuesSize+6, n)
+	}
+	if got := c.Values([]byte("bar")); len(got) != 0 {
+		t.Fatalf("expected no values for removed key, got %v", got)
+	}
+
+	if c.RemoveKey([]byte("bar")) {
+		t.Fatalf("expected key bar to no longer exist")
+	}
+	if n := c.Size(); n != 2*valuesSize+6 {
+		t.Fatalf("cache size changed after removing a missing key, exp %d, got %d", 2*valuesSize+6, n)
+	}
+}
+
 func TestCache_Cache_DeleteBucketRange(t *testing.T) {
 	v0 := NewValue(1, 1.0)
 	v1 := NewValue(2, 2.0)
@@ -266,6 +340,34 @@ func TestCache_DeleteBucketRange_NotSorted(t *testing.T) {
 	}
 }
 
+func TestCache_DeleteBucketRange_ExclusiveMax(t *testing.T) {
+	v0 := NewValue(2, 2.0)
+	v1 := NewValue(3, 3.0)
+
+	c := NewCache(0)
+	if err := c.WriteMulti(map[string][]Value{"foo": {v0, v1}}); err != nil {
+		t.Fatalf("failed to write key foo to cache: %s", err.Error())
+	}
+
+	c.DeleteBucketRangeWithOptions(context.Background(), []byte("foo"), 2, 3, nil, DeleteBucketRangeOptions{ExclusiveMax: true})
+
+	if exp, keys := [][]byte{[]byte("foo")}, c.Keys(); !reflect.DeepEqual(keys, exp) {
+		t.Fatalf("cache keys incorrect after delete, exp %v, got %v", exp, keys)
+	}
+	if got, exp := c.Size(), uint64(v1.Size())+3; exp != got {
+		t.Fatalf("cache size incorrect after delete, exp %d, got %d", exp, got)
+	}
+	if got, exp := c.Values([]byte("foo")), (Values{v1}); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("cache values mismatch: got %v, exp %v", got, exp)
+	}
+
+	// The inclusive default removes both values.
+	c.DeleteBucketRange(context.Background(), []byte("foo"), 2, 3, nil)
+	if got := c.Values([]byte("foo")); len(got) != 0 {
+		t.Fatalf("cache values mismatch: got %v, exp none", got)
+	}
+}
+
 func TestCache_DeleteBucketRange_NonExistent(t *testing.T) {
 	c := NewCache(1024)
 
@@ -320,6 +422,57 @@ func TestCache_Cache_DeleteBucketRange_WithPredicate(t *testing.T) {
 	}
 }
 
+// stringsPredicate matches any of the keys it contains.
+type stringsPredicate []string
+
+func (s stringsPredicate) Matches(k []byte) bool {
+	for _, p := range s {
+		if stringPredicate(p).Matches(k) {
+			return true
+		}
+	}
+	return false
+}
+func (s stringsPredicate) Marshal() ([]byte, error) { return nil, errors.New("unused") }
+
+func TestCache_DeleteMatching(t *testing.T) {
+	v0 := NewValue(1, 1.0)
+	v1 := NewValue(2, 2.0)
+	v2 := NewValue(3, 3.0)
+	values := Values{v0, v1, v2}
+	valuesSize := uint64(v0.Size() + v1.Size() + v2.Size())
+
+	c := NewCache(30 * valuesSize)
+
+	if err := c.WriteMulti(map[string][]Value{"bar": values, "baz": values, "foo": values}); err != nil {
+		t.Fatalf("failed to write keys to cache: %s", err.Error())
+	}
+
+	// The matching keys do not share a prefix.
+	c.DeleteMatching(context.Background(), 2, math.MaxInt64, stringsPredicate{"bar", "foo"})
+
+	if exp, keys := [][]byte{[]byte("bar"), []byte("baz"), []byte("foo")}, c.Keys(); !reflect.DeepEqual(keys, exp) {
+		t.Fatalf("cache keys incorrect after delete, exp %v, got %v", exp, keys)
+	}
+
+	if got, exp := c.Size(), valuesSize+2*uint64(v0.Size())+9; exp != got {
+		t.Fatalf("cache size incorrect after delete, exp %d, got %d", exp, got)
+	}
+
+	for _, tc := range []struct {
+		key string
+		exp int
+	}{
+		{key: "bar", exp: 1},
+		{key: "baz", exp: 3},
+		{key: "foo", exp: 1},
+	} {
+		if got := len(c.Values([]byte(tc.key))); got != tc.exp {
+			t.Fatalf("cache values mismatch for %s: got %v, exp %v", tc.key, got, tc.exp)
+		}
+	}
+}
+
 // This tests writing two batches to the same series.  The first batch
 // is sorted.  The second batch is also sorted but contains duplicates.
 func TestCache_CacheWriteMulti_Duplicates(t *testing.T) {
@@ -380,6 +533,115 @@ func TestCache_CacheValues(t *testing.T) {
 	}
 }
 
+func TestCache_ValuesBetween(t *testing.T) {
+	v0 := NewValue(1, 1.0)
+	v1 := NewValue(2, 2.0)
+	v2 := NewValue(3, 3.0)
+	v3 := NewValue(4, 4.0)
+	v4 := NewValue(5, 5.0)
+
+	c := NewCache(0)
+	if err := c.Write([]byte("foo"), Values{v0, v1, v2}); err != nil {
+		t.Fatalf("failed to write key foo to cache: %s", err.Error())
+	}
+
+	// Move some of the values into the snapshot.
+	if _, err := c.Snapshot(); err != nil {
+		t.Fatalf("failed to snapshot cache: %v", err)
+	}
+	if err := c.Write([]byte("foo"), Values{v3, v4}); err != nil {
+		t.Fatalf("failed to write key foo to cache: %s", err.Error())
+	}
+
+	tests := []struct {
+		name     string
+		min, max int64
+		exp      Values
+	}{
+		{name: "all", min: math.MinInt64, max: math.MaxInt64, exp: Values{v0, v1, v2, v3, v4}},
+		{name: "exact boundaries", min: 2, max: 4, exp: Values{v1, v2, v3}},
+		{name: "single", min: 3, max: 3, exp: Values{v2}},
+		{name: "snapshot only", min: 0, max: 2, exp: Values{v0, v1}},
+		{name: "hot only", min: 5, max: 10, exp: Values{v4}},
+		{name: "before", min: -10, max: 0, exp: nil},
+		{name: "after", min: 6, max: 10, exp: nil},
+		{name: "empty range", min: 4, max: 2, exp: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.ValuesBetween([]byte("foo"), tt.min, tt.max); !reflect.DeepEqual(got, tt.exp) {
+				t.Fatalf("values mismatch: got %v, exp %v", got, tt.exp)
+			}
+		})
+	}
+
+	if got := c.ValuesBetween([]byte("bar"), math.MinInt64, math.MaxInt64); got != nil {
+		t.Fatalf("values for missing key: got %v, exp nil", got)
+	}
+}
+
+func TestCache_ValuesBetween_NotSorted(t *testing.T) {
+	v0 := NewValue(1, 1.0)
+	v1 := NewValue(3, 3.0)
+	v2 := NewValue(2, 2.0)
+	v3 := NewValue(2, 4.0)
+
+	c := NewCache(0)
+	if err := c.WriteMulti(map[string][]Value{"foo": {v0, v1, v2, v3}}); err != nil {
+		t.Fatalf("failed to write key foo to cache: %s", err.Error())
+	}
+
+	if got, exp := c.ValuesBetween([]byte("foo"), 2, 3), (Values{v3, v1}); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("values mismatch: got %v, exp %v", got, exp)
+	}
+}
+
+func TestCache_ValuesBetween_OutOfOrderWrites(t *testing.T) {
+	c := NewCache(0)
+	if err := c.Write([]byte("foo"), Values{NewValue(10, 10.0), NewValue(20, 20.0)}); err != nil {
+		t.Fatalf("failed to write key foo to cache: %s", err.Error())
+	}
+	if got, exp := c.ValuesBetween([]byte("foo"), 10, 20), (Values{NewValue(10, 10.0), NewValue(20, 20.0)}); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("values mismatch: got %v, exp %v", got, exp)
+	}
+
+	// Write values older than, and overwriting, those already in the entry.
+	if err := c.Write([]byte("foo"), Values{NewValue(15, 15.0), NewValue(5, 5.0), NewValue(20, 21.0)}); err != nil {
+		t.Fatalf("failed to write key foo to cache: %s", err.Error())
+	}
+	if got, exp := c.ValuesBetween([]byte("foo"), 5, 15), (Values{NewValue(5, 5.0), NewValue(10, 10.0), NewValue(15, 15.0)}); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("values mismatch: got %v, exp %v", got, exp)
+	}
+	if got, exp := c.ValuesBetween([]byte("foo"), 16, 30), (Values{NewValue(20, 21.0)}); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("values mismatch: got %v, exp %v", got, exp)
+	}
+
+	// Read while values are written out of order.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := int64(0); i < 1000; i++ {
+			if err := c.Write([]byte("foo"), Values{NewValue(1000-i, float64(i))}); err != nil {
+				t.Errorf("failed to write key foo to cache: %s", err.Error())
+				return
+			}
+		}
+	}()
+	for i := int64(0); i < 1000; i++ {
+		values := c.ValuesBetween([]byte("foo"), 100, 900)
+		for j, v := range values {
+			if v.UnixNano() < 100 || v.UnixNano() > 900 {
+				t.Fatalf("value out of range: %v", v)
+			}
+			if j > 0 && values[j-1].UnixNano() >= v.UnixNano() {
+				t.Fatalf("values not sorted: %v", values)
+			}
+		}
+	}
+	wg.Wait()
+}
+
 func TestCache_CacheSnapshot(t *testing.T) {
 	v0 := NewValue(2, 0.0)
 	v1 := NewValue(3, 2.0)
@@ -498,6 +760,174 @@ func TestCache_Snapshot_Stats(t *testing.T) {
 	}
 }
 
+func TestCache_ForEachKey(t *testing.T) {
+	c := NewCache(0)
+	values := map[string][]Value{}
+	for i := 0; i < 100; i++ {
+		values[fmt.Sprintf("key%03d", i)] = []Value{NewValue(1, float64(i))}
+	}
+	if err := c.WriteMulti(values); err != nil {
+		t.Fatal(err)
+	}
+
+	var keys [][]byte
+	c.ForEachKey(func(key []byte) bool {
+		keys = append(keys, key)
+		return true
+	})
+	bytesutil.Sort(keys)
+	if exp := c.Keys(); !reflect.DeepEqual(keys, exp) {
+		t.Fatalf("keys mismatch: got %q, exp %q", keys, exp)
+	}
+
+	var n int
+	c.ForEachKey(func(key []byte) bool {
+		n++
+		return n < 10
+	})
+	if n != 10 {
+		t.Fatalf("expected iteration to stop after 10 keys, visited %d", n)
+	}
+}
+
+func TestCache_SnapshotSize(t *testing.T) {
+	c := NewCache(0)
+	if err := c.WriteMulti(map[string][]Value{"foo": {NewValue(1, 1.0)}}); err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := c.SnapshotSize(), c.Size(); got != exp {
+		t.Fatalf("snapshot size mismatch before snapshot: got %v, exp %v", got, exp)
+	}
+
+	if _, err := c.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.SnapshotSize(); got != 0 {
+		t.Fatalf("snapshot size mismatch after snapshot: got %v, exp %v", got, 0)
+	}
+}
+
+func TestCache_TotalBytes(t *testing.T) {
+	c := NewCache(0)
+	if err := c.WriteMulti(map[string][]Value{"foo": {NewValue(1, 1.0)}}); err != nil {
+		t.Fatal(err)
+	}
+	total := c.TotalBytes()
+	if got, exp := total, uint64(16)+3; got != exp {
+		t.Fatalf("total bytes mismatch after write: got %v, exp %v", got, exp)
+	}
+
+	if _, err := c.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The bytes moved to the snapshot are still the cache's responsibility,
+	// while the live store is empty.
+	if got := c.TotalBytes(); got != total {
+		t.Fatalf("total bytes mismatch after snapshot: got %v, exp %v", got, total)
+	}
+	if got := c.tracker.CacheSize(); got != 0 {
+		t.Fatalf("live bytes mismatch after snapshot: got %v, exp %v", got, 0)
+	}
+
+	// Writes during the snapshot add to the total.
+	if err := c.WriteMulti(map[string][]Value{"bar": {NewValue(2, 2.0)}}); err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := c.TotalBytes(), total+uint64(16)+3; got != exp {
+		t.Fatalf("total bytes mismatch after write during snapshot: got %v, exp %v", got, exp)
+	}
+
+	// A failed snapshot stays in flight.
+	c.ClearSnapshot(false)
+	if got, exp := c.TotalBytes(), total+uint64(16)+3; got != exp {
+		t.Fatalf("total bytes mismatch after failed snapshot: got %v, exp %v", got, exp)
+	}
+
+	if _, err := c.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+	c.ClearSnapshot(true)
+	if got, exp := c.TotalBytes(), uint64(16)+3; got != exp {
+		t.Fatalf("total bytes mismatch after cleared snapshot: got %v, exp %v", got, exp)
+	}
+}
+
+func TestCache_KeyStats(t *testing.T) {
+	v0 := NewValue(1, 1.0)
+	v1 := NewValue(2, 2.0)
+	v2 := NewValue(3, 3.0)
+
+	c := NewCache(0)
+	if err := c.WriteMulti(map[string][]Value{"foo": {v0, v1}, "bar": {v2}}); err != nil {
+		t.Fatalf("failed to write to cache: %s", err.Error())
+	}
+	if got, exp := c.KeyStats(), map[string]int{"foo": 2, "bar": 1}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("key stats mismatch after write: got %v, exp %v", got, exp)
+	}
+
+	// Values in the snapshot are still counted.
+	if _, err := c.Snapshot(); err != nil {
+		t.Fatalf("failed to snapshot cache: %v", err)
+	}
+	if err := c.WriteMulti(map[string][]Value{"foo": {v2}, "baz": {v0}}); err != nil {
+		t.Fatalf("failed to write to cache: %s", err.Error())
+	}
+	if got, exp := c.KeyStats(), map[string]int{"foo": 3, "bar": 1, "baz": 1}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("key stats mismatch after snapshot: got %v, exp %v", got, exp)
+	}
+
+	c.ClearSnapshot(true)
+	if got, exp := c.KeyStats(), map[string]int{"foo": 1, "baz": 1}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("key stats mismatch after clearing snapshot: got %v, exp %v", got, exp)
+	}
+}
+
+func TestCache_ShouldSnapshot(t *testing.T) {
+	v0 := NewValue(1, 1.0)
+
+	c := NewCache(0, WithSnapshotSizeThreshold(uint64(3*v0.Size())))
+	if c.ShouldSnapshot() {
+		t.Fatal("empty cache should not need a snapshot")
+	}
+
+	// Write until the threshold is crossed.
+	var i int
+	for ; !c.ShouldSnapshot(); i++ {
+		if i > 10 {
+			t.Fatal("cache never reached its snapshot threshold")
+		}
+		if err := c.Write([]byte(fmt.Sprintf("foo%d", i)), Values{v0}); err != nil {
+			t.Fatalf("failed to write to cache: %s", err.Error())
+		}
+	}
+	if c.Size() <= uint64(3*v0.Size()) {
+		t.Fatalf("cache size %d is not over the threshold %d", c.Size(), 3*v0.Size())
+	}
+
+	if _, err := c.Snapshot(); err != nil {
+		t.Fatalf("failed to snapshot cache: %v", err)
+	}
+	if c.ShouldSnapshot() {
+		t.Fatal("cache should not need a snapshot after snapshotting")
+	}
+
+	c.ClearSnapshot(true)
+	if c.ShouldSnapshot() {
+		t.Fatal("cache should not need a snapshot after clearing the snapshot")
+	}
+}
+
+func TestCache_ShouldSnapshot_NoThreshold(t *testing.T) {
+	c := NewCache(0)
+	if err := c.Write([]byte("foo"), Values{NewValue(1, 1.0)}); err != nil {
+		t.Fatalf("failed to write to cache: %s", err.Error())
+	}
+	if c.ShouldSnapshot() {
+		t.Fatal("cache without a threshold should never need a snapshot")
+	}
+}
+
 func TestCache_CacheEmptySnapshot(t *testing.T) {
 	c := NewCache(512)
 
@@ -558,6 +988,70 @@ func TestCache_CacheWriteMemoryExceeded(t *testing.T) {
 	}
 }
 
+func TestCache_CacheWritePrefixMemoryExceeded(t *testing.T) {
+	v0 := NewValue(1, 1.0)
+	v1 := NewValue(2, 2.0)
+
+	c := NewCache(0)
+	c.SetPrefixMaxSizes(map[string]uint64{"foo": uint64(v0.Size() + len("foo,a"))})
+
+	if err := c.Write([]byte("foo,a"), Values{v0}); err != nil {
+		t.Fatalf("failed to write key foo,a to cache: %s", err.Error())
+	}
+	err := c.Write([]byte("foo,b"), Values{v1})
+	if _, ok := err.(CachePrefixMemorySizeLimitExceededError); !ok || !strings.Contains(err.Error(), "cache-max-memory-size") {
+		t.Fatalf("wrong error writing key foo,b to cache: %v", err)
+	}
+
+	// Keys of other prefixes are not limited.
+	if err := c.Write([]byte("bar"), Values{v1}); err != nil {
+		t.Fatalf("failed to write key bar to cache: %s", err.Error())
+	}
+
+	// Only the keys of the limited prefix are dropped from a batch.
+	err = c.WriteMulti(map[string][]Value{"foo,b": {v1}, "baz": {v1}})
+	if _, ok := err.(CachePrefixMemorySizeLimitExceededError); !ok {
+		t.Fatalf("wrong error writing batch to cache: %v", err)
+	}
+	if exp, keys := [][]byte{[]byte("bar"), []byte("baz"), []byte("foo,a")}, c.Keys(); !reflect.DeepEqual(keys, exp) {
+		t.Fatalf("cache keys incorrect after writes, exp %v, got %v", exp, keys)
+	}
+
+	// Grab snapshot, write should still fail since we're still using the memory.
+	if _, err := c.Snapshot(); err != nil {
+		t.Fatalf("failed to snapshot cache: %v", err)
+	}
+	if err := c.Write([]byte("foo,b"), Values{v1}); err == nil {
+		t.Fatal("expected error writing key foo,b to cache")
+	}
+
+	// Clear the snapshot and the write should now succeed.
+	c.ClearSnapshot(true)
+	if err := c.Write([]byte("foo,b"), Values{v1}); err != nil {
+		t.Fatalf("failed to write key foo,b to cache: %s", err.Error())
+	}
+
+	// Deleting the data frees the prefix again.
+	c.DeleteBucketRange(context.Background(), []byte("foo"), math.MinInt64, math.MaxInt64, nil)
+	if err := c.Write([]byte("foo,a"), Values{v0}); err != nil {
+		t.Fatalf("failed to write key foo,a to cache: %s", err.Error())
+	}
+
+	// Keys of other prefixes are not accounted.
+	if _, ok := c.prefixSizes["bar"]; ok || len(c.prefixSizes) != 1 {
+		t.Fatalf("unexpected prefix sizes: %v", c.prefixSizes)
+	}
+
+	// Once the limits are removed, writes are no longer accounted or limited.
+	c.SetPrefixMaxSizes(nil)
+	if err := c.Write([]byte("foo,c"), Values{v0, v1}); err != nil {
+		t.Fatalf("failed to write key foo,c to cache: %s", err.Error())
+	}
+	if c.prefixSizes != nil {
+		t.Fatalf("unexpected prefix sizes without limits: %v", c.prefixSizes)
+	}
+}
+
 func TestCache_Deduplicate_Concurrent(t *testing.T) {
 	if testing.Short() || os.Getenv("GORACE") != "" || os.Getenv("APPVEYOR") != "" {
 		t.Skip("Skipping test in short, race, appveyor mode.")
@@ -626,7 +1120,7 @@ func TestCacheLoader_LoadSingle(t *testing.T) {
 	// Load the cache using the segment.
 	cache := NewCache(1024)
 	loader := NewCacheLoader([]string{f.Name()})
-	if err := loader.Load(cache); err != nil {
+	if _, err := loader.Load(cache); err != nil {
 		t.Fatalf("failed to load cache: %s", err.Error())
 	}
 
@@ -649,7 +1143,7 @@ func TestCacheLoader_LoadSingle(t *testing.T) {
 	// Reload the cache using the segment.
 	cache = NewCache(1024)
 	loader = NewCacheLoader([]string{f.Name()})
-	if err := loader.Load(cache); err != nil {
+	if _, err := loader.Load(cache); err != nil {
 		t.Fatalf("failed to load cache: %s", err.Error())
 	}
 
@@ -711,7 +1205,7 @@ func TestCacheLoader_LoadDouble(t *testing.T) {
 	// Load the cache using the segments.
 	cache := NewCache(1024)
 	loader := NewCacheLoader([]string{f1.Name(), f2.Name()})
-	if err := loader.Load(cache); err != nil {
+	if _, err := loader.Load(cache); err != nil {
 		t.Fatalf("failed to load cache: %s", err.Error())
 	}
 
@@ -730,6 +1224,123 @@ func TestCacheLoader_LoadDouble(t *testing.T) {
 	}
 }
 
+// Ensure the CacheLoader reports the corrupt tail of a segment while loading
+// the entries before it.
+func TestCacheLoader_LoadCorruptStats(t *testing.T) {
+	dir := mustTempDir()
+	defer os.RemoveAll(dir)
+	f := mustTempFile(dir)
+	w := wal.NewWALSegmentWriter(f)
+
+	p1 := NewValue(1, 1.1)
+	p2 := NewValue(1, int64(1))
+	p3 := NewValue(1, true)
+
+	var offsets []int64
+	for _, values := range []map[string][]Value{{"foo": {p1}}, {"bar": {p2}}, {"baz": {p3}}} {
+		if err := w.Write(mustMarshalEntry(&wal.WriteWALEntry{Values: values})); err != nil {
+			t.Fatal("write points", err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatalf("flush error: %v", err)
+		}
+		stat, err := f.Stat()
+		if err != nil {
+			t.Fatal(err)
+		}
+		offsets = append(offsets, stat.Size())
+	}
+
+	// Corrupt the type of the second entry.
+	if _, err := f.WriteAt([]byte{0xff}, offsets[0]); err != nil {
+		t.Fatalf("corrupt WAL segment: %s", err.Error())
+	}
+
+	var errs []CacheLoaderError
+	cache := NewCache(1024)
+	loader := NewCacheLoader([]string{f.Name()})
+	loader.WithErrorHandler(func(err CacheLoaderError) {
+		errs = append(errs, err)
+	})
+	stats, err := loader.Load(cache)
+	if err != nil {
+		t.Fatalf("failed to load cache: %s", err.Error())
+	}
+
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 load error, got %d", len(errs))
+	}
+	if got, exp := errs[0].Path, f.Name(); got != exp {
+		t.Fatalf("unexpected path, got %s, exp %s", got, exp)
+	}
+	if got, exp := errs[0].Pos, offsets[0]; got != exp {
+		t.Fatalf("unexpected position, got %d, exp %d", got, exp)
+	}
+	if got, exp := errs[0].Bytes, offsets[2]-offsets[0]; got != exp {
+		t.Fatalf("unexpected bytes skipped, got %d, exp %d", got, exp)
+	}
+	if errs[0].Err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if got, exp := stats, (CacheLoaderStats{EntriesLoaded: 1, BytesSkipped: offsets[2] - offsets[0]}); got != exp {
+		t.Fatalf("unexpected stats, got %+v, exp %+v", got, exp)
+	}
+
+	// Check the cache.
+	if values := cache.Values([]byte("foo")); !reflect.DeepEqual(values, Values{p1}) {
+		t.Fatalf("cache key foo not as expected, got %v, exp %v", values, Values{p1})
+	}
+	if values := cache.Values([]byte("bar")); len(values) != 0 {
+		t.Fatalf("cache key bar not as expected, got %v, exp none", values)
+	}
+	if values := cache.Values([]byte("baz")); len(values) != 0 {
+		t.Fatalf("cache key baz not as expected, got %v, exp none", values)
+	}
+}
+
+// Ensure the CacheLoader only loads the keys matching its key prefix.
+func TestCacheLoader_LoadKeyPrefix(t *testing.T) {
+	dir := mustTempDir()
+	defer os.RemoveAll(dir)
+	f := mustTempFile(dir)
+	w := wal.NewWALSegmentWriter(f)
+
+	p1 := NewValue(1, 1.1)
+	p2 := NewValue(1, int64(1))
+	p3 := NewValue(1, true)
+
+	entry := &wal.WriteWALEntry{
+		Values: map[string][]Value{
+			"foo": {p1},
+			"bar": {p2},
+			"baz": {p3},
+		},
+	}
+	if err := w.Write(mustMarshalEntry(entry)); err != nil {
+		t.Fatal("write points", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("flush error: %v", err)
+	}
+
+	cache := NewCache(1024)
+	loader := NewCacheLoader([]string{f.Name()}, WithCacheLoaderKeyPrefix([]byte("ba")))
+	if _, err := loader.Load(cache); err != nil {
+		t.Fatalf("failed to load cache: %s", err.Error())
+	}
+
+	if exp, keys := [][]byte{[]byte("bar"), []byte("baz")}, cache.Keys(); !reflect.DeepEqual(keys, exp) {
+		t.Fatalf("cache keys incorrect, exp %v, got %v", exp, keys)
+	}
+	if values := cache.Values([]byte("bar")); !reflect.DeepEqual(values, Values{p2}) {
+		t.Fatalf("cache key bar not as expected, got %v, exp %v", values, Values{p2})
+	}
+	if values := cache.Values([]byte("baz")); !reflect.DeepEqual(values, Values{p3}) {
+		t.Fatalf("cache key baz not as expected, got %v, exp %v", values, Values{p3})
+	}
+}
+
 func TestCache_Split(t *testing.T) {
 	v0 := NewValue(1, 1.0)
 	v1 := NewValue(2, 2.0)
@@ -767,6 +1378,108 @@ func TestCache_Split(t *testing.T) {
 			t.Fatalf("missing key, exp %s, got %v", key, nil)
 		}
 	}
+
+	var size uint64
+	for _, s := range splits {
+		if exp := uint64(s.Count()) * (valuesSize + 3); s.Size() != exp {
+			t.Fatalf("split cache size incorrect, exp %d, got %d", exp, s.Size())
+		}
+		size += s.Size()
+	}
+	if size != c.Size() {
+		t.Fatalf("split cache sizes incorrect, exp total %d, got %d", c.Size(), size)
+	}
+}
+
+func TestCache_SplitByPrefix(t *testing.T) {
+	values := Values{NewValue(1, 1.0), NewValue(2, 2.0)}
+
+	c := NewCache(0)
+	keys := []string{"aaaa,cpu", "aaaa,mem", "bbbb,cpu", "bbbb,disk", "bbbb,mem", "cccc,cpu"}
+	for _, key := range keys {
+		if err := c.Write([]byte(key), values); err != nil {
+			t.Fatalf("failed to write key %s to cache: %s", key, err.Error())
+		}
+	}
+
+	splits := c.SplitByPrefix([][]byte{[]byte("aaaa"), []byte("bbbb")})
+	if got, exp := len(splits), 3; got != exp {
+		t.Fatalf("unexpected number of splits, exp %d, got %d", exp, got)
+	}
+
+	exp := [][]string{
+		{"aaaa,cpu", "aaaa,mem"},
+		{"bbbb,cpu", "bbbb,disk", "bbbb,mem"},
+		{"cccc,cpu"},
+	}
+	var size uint64
+	for i, s := range splits {
+		var got []string
+		for _, k := range s.Keys() {
+			got = append(got, string(k))
+		}
+		if !reflect.DeepEqual(got, exp[i]) {
+			t.Fatalf("unexpected keys in split %d, exp %v, got %v", i, exp[i], got)
+		}
+		if s.Count() != len(exp[i]) {
+			t.Fatalf("unexpected key count in split %d, exp %d, got %d", i, len(exp[i]), s.Count())
+		}
+		size += s.Size()
+	}
+	if size != c.Size() {
+		t.Fatalf("split cache sizes incorrect, exp total %d, got %d", c.Size(), size)
+	}
+}
+
+func TestCache_Merge(t *testing.T) {
+	c := NewCache(0)
+	for i, key := range []string{"foo", "bar", "baz", "qux"} {
+		values := Values{NewValue(1, float64(i)), NewValue(2, float64(i+1))}
+		if err := c.Write([]byte(key), values); err != nil {
+			t.Fatalf("failed to write key %s to cache: %s", key, err.Error())
+		}
+	}
+
+	merged := NewCache(0)
+	for _, s := range c.Split(3) {
+		if err := merged.Merge(s); err != nil {
+			t.Fatalf("failed to merge cache: %s", err.Error())
+		}
+	}
+
+	if exp, got := c.Keys(), merged.Keys(); !reflect.DeepEqual(exp, got) {
+		t.Fatalf("cache keys incorrect after merge, exp %v, got %v", exp, got)
+	}
+	for _, k := range c.Keys() {
+		if exp, got := c.Values(k), merged.Values(k); !reflect.DeepEqual(exp, got) {
+			t.Fatalf("cache values incorrect for key %s after merge, exp %v, got %v", k, exp, got)
+		}
+	}
+	if exp, got := c.Size(), merged.Size(); exp != got {
+		t.Fatalf("cache size incorrect after merge, exp %d, got %d", exp, got)
+	}
+}
+
+func TestCache_Merge_Errors(t *testing.T) {
+	other := NewCache(0)
+	if err := other.Write([]byte("foo"), Values{NewValue(1, int64(1))}); err != nil {
+		t.Fatalf("failed to write key foo to cache: %s", err.Error())
+	}
+
+	// The values of other conflict with the type of the values in the cache.
+	c := NewCache(0)
+	if err := c.Write([]byte("foo"), Values{NewValue(2, 1.0)}); err != nil {
+		t.Fatalf("failed to write key foo to cache: %s", err.Error())
+	}
+	if err := c.Merge(other); err == nil {
+		t.Fatal("expected a field type conflict merging caches")
+	}
+
+	// The values of other do not fit in the cache.
+	c = NewCache(1)
+	if _, ok := c.Merge(other).(CacheMemorySizeLimitExceededError); !ok {
+		t.Fatal("expected a cache size error merging caches")
+	}
 }
 
 func mustTempDir() string {
@@ -843,6 +1556,30 @@ func BenchmarkCacheParallelFloatEntries(b *testing.B) {
 	})
 }
 
+func BenchmarkCacheParallelFloatEntriesUnsafe(b *testing.B) {
+	c := b.N * runtime.GOMAXPROCS(0)
+	cache := NewCache(uint64(c)*fvSize*10 + 20*5)
+	vals := make([]map[string][]Value, c)
+	for i := 0; i < c; i++ {
+		v := make([]Value, 10)
+		for j := 0; j < 10; j++ {
+			v[j] = NewValue(1, float64(i+j))
+		}
+		vals[i] = map[string][]Value{fmt.Sprintf("cpu%v", rand.Intn(20)): v}
+	}
+	i := int32(-1)
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			j := atomic.AddInt32(&i, 1)
+			if err := cache.WriteMultiUnsafe(vals[j]); err != nil {
+				b.Fatal("err:", err, "j:", j, "N:", b.N)
+			}
+		}
+	})
+}
+
 func BenchmarkEntry_add(b *testing.B) {
 	b.RunParallel(func(pb *testing.PB) {
 		for pb.Next() {
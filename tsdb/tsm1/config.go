@@ -113,6 +113,20 @@ type CacheConfig struct {
 	//
 	// SnapshotWriteColdDuration should not be larger than SnapshotAgeDuration
 	SnapshotWriteColdDuration toml.Duration `toml:"snapshot-write-cold-duration"`
+
+	// HighWatermark is the fraction of MaxMemorySize above which the cache
+	// rejects writes with a retryable error, giving clients a chance to slow
+	// down before writes fail at MaxMemorySize. A value of 0 disables it.
+	HighWatermark float64 `toml:"high-watermark"`
+}
+
+// HighWatermarkSize returns the number of bytes above which the cache rejects
+// writes with a retryable error, or 0 if there is no such watermark.
+func (c CacheConfig) HighWatermarkSize() uint64 {
+	if c.HighWatermark <= 0 || c.HighWatermark >= 1 {
+		return 0
+	}
+	return uint64(float64(c.MaxMemorySize) * c.HighWatermark)
 }
 
 // NewCacheConfig initialises a new CacheConfig with default values.
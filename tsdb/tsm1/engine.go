@@ -156,6 +156,7 @@ type Engine struct {
 
 	compactionTracker   *compactionTracker // Used to track state of compactions.
 	readTracker         *readTracker       // Used to track number of reads.
+	deleteTracker       *deleteTracker     // Used to track number of deleted series.
 	defaultMetricLabels prometheus.Labels  // N.B this must not be mutated after Open is called.
 
 	// Limiter for concurrent compactions.
@@ -171,7 +172,9 @@ func NewEngine(path string, idx *tsi1.Index, config Config, options ...EngineOpt
 	fs.openLimiter = limiter.NewFixed(config.MaxConcurrentOpens)
 	fs.tsmMMAPWillNeed = config.MADVWillNeed
 
-	cache := NewCache(uint64(config.Cache.MaxMemorySize))
+	cache := NewCache(uint64(config.Cache.MaxMemorySize),
+		WithSnapshotSizeThreshold(uint64(config.Cache.SnapshotMemorySize)),
+		WithHighWatermark(config.Cache.HighWatermarkSize()))
 
 	c := NewCompactor()
 	c.Dir = path
@@ -496,6 +499,7 @@ func (e *Engine) initTrackers() {
 	e.FileStore.tracker = newFileTracker(bms.fileMetrics, e.defaultMetricLabels)
 	e.Cache.tracker = newCacheTracker(bms.cacheMetrics, e.defaultMetricLabels)
 	e.readTracker = newReadTracker(bms.readMetrics, e.defaultMetricLabels)
+	e.deleteTracker = newDeleteTracker(bms.deleteMetrics, e.defaultMetricLabels)
 
 	e.scheduler.setCompactionTracker(e.compactionTracker)
 }
@@ -868,6 +872,24 @@ func (e *Engine) writeSnapshotAndCommit(ctx context.Context, log *zap.Logger, sn
 	})
 }
 
+// ForceSnapshot snapshots the cache and writes it to a new TSM file, returning
+// once the file is part of the file store. If a snapshot is already in progress,
+// it waits for it to finish and then snapshots the writes made since.
+func (e *Engine) ForceSnapshot(ctx context.Context) error {
+	for {
+		err := e.WriteSnapshot(ctx, CacheStatusForced)
+		if err != ErrSnapshotInProgress {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
 // compactCache checks once per second if the in-memory cache should be
 // snapshotted to a TSM file.
 func (e *Engine) compactCache() {
@@ -914,6 +936,7 @@ const (
 	CacheStatusColdNoWrites                      // The cache has not been written to for long enough that it should be snapshotted.
 	CacheStatusRetention                         // The cache was snapshotted before running retention.
 	CacheStatusFullCompaction                    // The cache was snapshotted as part of a full compaction.
+	CacheStatusForced                            // The cache snapshot was requested with ForceSnapshot.
 )
 
 // ShouldCompactCache returns a status indicating if the Cache should be
@@ -1436,3 +1459,32 @@ func (t *readTracker) AddSeeks(n uint64) {
 	atomic.AddUint64(&t.seeks, n)
 	t.metrics.Seeks.With(t.labels).Add(float64(n))
 }
+
+// deleteTracker tracks deletes from the engine.
+type deleteTracker struct {
+	metrics       *deleteMetrics
+	labels        prometheus.Labels
+	seriesDeleted uint64
+}
+
+func newDeleteTracker(metrics *deleteMetrics, defaultLabels prometheus.Labels) *deleteTracker {
+	t := &deleteTracker{metrics: metrics, labels: defaultLabels}
+	t.AddSeriesDeleted(0)
+	return t
+}
+
+// Labels returns a copy of the default labels used by the tracker's metrics.
+// The returned map is safe for modification.
+func (t *deleteTracker) Labels() prometheus.Labels {
+	labels := make(prometheus.Labels, len(t.labels))
+	for k, v := range t.labels {
+		labels[k] = v
+	}
+	return labels
+}
+
+// AddSeriesDeleted increases the number of series removed from the index.
+func (t *deleteTracker) AddSeriesDeleted(n uint64) {
+	atomic.AddUint64(&t.seriesDeleted, n)
+	t.metrics.SeriesDeleted.With(t.labels).Add(float64(n))
+}
@@ -11,12 +11,70 @@ import (
 	"github.com/influxdata/influxdb/pkg/bytesutil"
 	"github.com/influxdata/influxdb/tsdb"
 	"github.com/influxdata/influxql"
+	"github.com/pkg/errors"
 )
 
+// DeletePrefixRangeProgress describes how far a DeletePrefixRange call has got.
+type DeletePrefixRangeProgress struct {
+	// Name is the prefix being deleted.
+	Name []byte
+	// FilesProcessed is the number of TSM files the prefix has been deleted from.
+	FilesProcessed int
+	// FilesTotal is the number of TSM files the prefix is being deleted from.
+	FilesTotal int
+	// CacheProcessed is true once the prefix has been deleted from the cache.
+	CacheProcessed bool
+	// SeriesTombstoned is the number of distinct series keys deleted so far.
+	SeriesTombstoned int
+}
+
+// DeletePrefixRangeOption configures a DeletePrefixRange call.
+type DeletePrefixRangeOption func(*deletePrefixRangeOptions)
+
+type deletePrefixRangeOptions struct {
+	progress func(DeletePrefixRangeProgress)
+}
+
+// WithDeletePrefixRangeProgress sets a callback invoked after each TSM file and
+// after the cache have been processed. The callback is never called concurrently.
+func WithDeletePrefixRangeProgress(fn func(DeletePrefixRangeProgress)) DeletePrefixRangeOption {
+	return func(o *deletePrefixRangeOptions) {
+		o.progress = fn
+	}
+}
+
+// deletePrefixRangeTracker reports the progress of a delete to a callback.
+type deletePrefixRangeTracker struct {
+	mu       sync.Mutex
+	fn       func(DeletePrefixRangeProgress)
+	progress DeletePrefixRangeProgress
+}
+
+// update applies fn to the progress and reports the result to the callback.
+func (t *deletePrefixRangeTracker) update(fn func(p *DeletePrefixRangeProgress)) {
+	if t.fn == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fn(&t.progress)
+	if t.progress.FilesProcessed > t.progress.FilesTotal {
+		// Files can be added by snapshots while the delete is running.
+		t.progress.FilesTotal = t.progress.FilesProcessed
+	}
+	t.fn(t.progress)
+}
+
 // DeletePrefixRange removes all TSM data belonging to a bucket, and removes all index
 // and series file data associated with the bucket. The provided time range ensures
 // that only bucket data for that range is removed.
-func (e *Engine) DeletePrefixRange(rootCtx context.Context, name []byte, min, max int64, pred Predicate) error {
+//
+// The context is checked between TSM files and while filtering the cache. If it is
+// canceled the delete stops removing data, leaving any tombstones already written
+// in place, and the context's error is returned wrapped. Series whose data has
+// already been removed are still dropped from the index and series file.
+func (e *Engine) DeletePrefixRange(rootCtx context.Context, name []byte, min, max int64, pred Predicate, opts ...DeletePrefixRangeOption) error {
 	span, ctx := tracing.StartSpanFromContext(rootCtx)
 	defer span.Finish()
 	// TODO(jeff): we need to block writes to this prefix while deletes are in progress
@@ -28,11 +86,44 @@ func (e *Engine) DeletePrefixRange(rootCtx context.Context, name []byte, min, ma
 	// now we know that the series file or index won't be closed out from underneath
 	// of us.
 
+	defer e.disableDeleteCompactions(rootCtx)()
+
+	var o deletePrefixRangeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return e.deletePrefixRange(rootCtx, ctx, name, min, max, pred, o)
+}
+
+// DeletePrefixRanges is like DeletePrefixRange for each of the provided names,
+// but only disables and re-enables compactions once for all of them.
+func (e *Engine) DeletePrefixRanges(rootCtx context.Context, names [][]byte, min, max int64, pred Predicate, opts ...DeletePrefixRangeOption) error {
+	span, ctx := tracing.StartSpanFromContext(rootCtx)
+	defer span.Finish()
+
+	defer e.disableDeleteCompactions(rootCtx)()
+
+	var o deletePrefixRangeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	for _, name := range names {
+		if err := e.deletePrefixRange(rootCtx, ctx, name, min, max, pred, o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// disableDeleteCompactions disables the compactions that could interfere with
+// deleting data and returns a function re-enabling them.
+func (e *Engine) disableDeleteCompactions(rootCtx context.Context) func() {
 	// Ensure that the index does not compact away the measurement or series we're
 	// going to delete before we're done with them.
-	span, _ = tracing.StartSpanFromContextWithOperationName(rootCtx, "disable index compactions")
+	span, _ := tracing.StartSpanFromContextWithOperationName(rootCtx, "disable index compactions")
 	e.index.DisableCompactions()
-	defer e.index.EnableCompactions()
 	e.index.Wait()
 	span.Finish()
 
@@ -44,14 +135,22 @@ func (e *Engine) DeletePrefixRange(rootCtx context.Context, name []byte, min, ma
 	// filling up.
 	span, _ = tracing.StartSpanFromContextWithOperationName(rootCtx, "disable tsm compactions")
 	e.disableLevelCompactions(true)
-	defer e.enableLevelCompactions(true)
 	span.Finish()
 
 	span, _ = tracing.StartSpanFromContextWithOperationName(rootCtx, "disable series file compactions")
 	e.sfile.DisableCompactions()
-	defer e.sfile.EnableCompactions()
 	span.Finish()
 
+	return func() {
+		e.sfile.EnableCompactions()
+		e.enableLevelCompactions(true)
+		e.index.EnableCompactions()
+	}
+}
+
+// deletePrefixRange does the work of DeletePrefixRange and must be called with
+// compactions disabled.
+func (e *Engine) deletePrefixRange(rootCtx, ctx context.Context, name []byte, min, max int64, pred Predicate, opts deletePrefixRangeOptions) error {
 	// TODO(jeff): are the query language values still a thing?
 	// Min and max time in the engine are slightly different from the query language values.
 	if min == influxql.MinTime {
@@ -73,53 +172,94 @@ func (e *Engine) DeletePrefixRange(rootCtx context.Context, name []byte, min, ma
 	}
 	possiblyDead.keys = make(map[string]struct{})
 
+	tracker := &deletePrefixRangeTracker{
+		fn:       opts.progress,
+		progress: DeletePrefixRangeProgress{Name: name, FilesTotal: e.FileStore.Count()},
+	}
+
+	// canceled is set once the context is done. Data removed up to that point
+	// still has to be cleaned up from the index and series file.
+	var canceled error
+
 	if err := e.FileStore.Apply(func(r TSMFile) error {
 		// TODO(edd): tracing this deep down is currently speculative, so I have
 		// not added the tracing into the TSMReader API.
 		span, _ := tracing.StartSpanFromContextWithOperationName(rootCtx, "TSMFile delete prefix")
 		defer span.Finish()
 
-		return r.DeletePrefix(name, min, max, pred, func(key []byte) {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err := r.DeletePrefix(name, min, max, pred, func(key []byte) {
 			possiblyDead.Lock()
 			possiblyDead.keys[string(key)] = struct{}{}
 			possiblyDead.Unlock()
+		}); err != nil {
+			return err
+		}
+
+		tracker.update(func(p *DeletePrefixRangeProgress) {
+			possiblyDead.RLock()
+			p.FilesProcessed++
+			p.SeriesTombstoned = len(possiblyDead.keys)
+			possiblyDead.RUnlock()
 		})
+		return nil
 	}); err != nil {
 		return err
 	}
+	canceled = deletePrefixRangeCanceled(ctx)
 
 	var deleteKeys [][]byte
 
-	// ApplySerialEntryFn cannot return an error in this invocation.
-	_ = e.Cache.ApplyEntryFn(func(k []byte, _ *entry) error {
-		// TODO(edd): tracing this deep down is currently speculative, so I have
-		// not added the tracing into the Cache API.
-		span, _ := tracing.StartSpanFromContextWithOperationName(rootCtx, "Cache find delete keys")
-		defer span.Finish()
+	if canceled == nil {
+		// ApplyEntryFn only returns an error if the context is canceled.
+		if err := e.Cache.ApplyEntryFn(func(k []byte, _ *entry) error {
+			// TODO(edd): tracing this deep down is currently speculative, so I have
+			// not added the tracing into the Cache API.
+			span, _ := tracing.StartSpanFromContextWithOperationName(rootCtx, "Cache find delete keys")
+			defer span.Finish()
 
-		if !bytes.HasPrefix(k, name) {
-			return nil
-		}
-		if pred != nil && !pred.Matches(k) {
-			return nil
-		}
+			if err := deletePrefixRangeCanceled(ctx); err != nil {
+				return err
+			}
 
-		deleteKeys = append(deleteKeys, k)
+			if !bytes.HasPrefix(k, name) {
+				return nil
+			}
+			if pred != nil && !pred.Matches(k) {
+				return nil
+			}
 
-		// we have to double check every key in the cache because maybe
-		// it exists in the index but not yet on disk.
-		possiblyDead.keys[string(k)] = struct{}{}
+			deleteKeys = append(deleteKeys, k)
 
-		return nil
-	})
+			// we have to double check every key in the cache because maybe
+			// it exists in the index but not yet on disk.
+			possiblyDead.keys[string(k)] = struct{}{}
 
-	// Sort the series keys because ApplyEntryFn iterates over the keys randomly.
-	sortSpan, _ := tracing.StartSpanFromContextWithOperationName(rootCtx, "Cache sort keys")
-	bytesutil.Sort(deleteKeys)
-	sortSpan.Finish()
+			return nil
+		}); err != nil {
+			canceled = err
+		} else {
+			// Sort the series keys because ApplyEntryFn iterates over the keys randomly.
+			sortSpan, _ := tracing.StartSpanFromContextWithOperationName(rootCtx, "Cache sort keys")
+			bytesutil.Sort(deleteKeys)
+			sortSpan.Finish()
+
+			// Delete from the cache.
+			e.Cache.DeleteBucketRange(ctx, name, min, max, pred)
+
+			tracker.update(func(p *DeletePrefixRangeProgress) {
+				p.CacheProcessed = true
+				p.SeriesTombstoned = len(possiblyDead.keys)
+			})
+		}
+	}
 
-	// Delete from the cache.
-	e.Cache.DeleteBucketRange(ctx, name, min, max, pred)
+	if canceled != nil && len(possiblyDead.keys) == 0 {
+		return canceled
+	}
 
 	// Now that all of the data is purged, we need to find if some keys are fully deleted
 	// and if so, remove them from the index.
@@ -190,7 +330,8 @@ func (e *Engine) DeletePrefixRange(rootCtx context.Context, name []byte, min, ma
 		// the deletes of the data in the tsm files.
 
 		// In this case the entire measurement (bucket) can be removed from the index.
-		if min == math.MinInt64 && max == math.MaxInt64 && pred == nil {
+		// A canceled delete may have left data behind, so only its series are dropped.
+		if canceled == nil && min == math.MinInt64 && max == math.MaxInt64 && pred == nil {
 			// The TSI index and Series File do not store series data in escaped form.
 			name = models.UnescapeMeasurement(name)
 
@@ -217,7 +358,7 @@ func (e *Engine) DeletePrefixRange(rootCtx context.Context, name []byte, min, ma
 			}
 
 			// Remove the measurement from the index before the series file.
-			span, _ = tracing.StartSpanFromContextWithOperationName(rootCtx, "TSI drop measurement")
+			span, _ := tracing.StartSpanFromContextWithOperationName(rootCtx, "TSI drop measurement")
 			if err := e.index.DropMeasurement(name); err != nil {
 				return err
 			}
@@ -232,11 +373,17 @@ func (e *Engine) DeletePrefixRange(rootCtx context.Context, name []byte, min, ma
 				}
 			})
 			span.Finish()
+			e.deleteTracker.AddSeriesDeleted(set.Cardinality())
 			return err
 		}
 
 		// This is the slow path, when not dropping the entire bucket (measurement)
-		span, _ = tracing.StartSpanFromContextWithOperationName(rootCtx, "TSI/SFile Delete keys")
+		// Keys of the same series with different fields share a series id, so
+		// the deleted series are tracked to only count each of them once.
+		deleted := tsdb.NewSeriesIDSet()
+		defer func() { e.deleteTracker.AddSeriesDeleted(deleted.Cardinality()) }()
+
+		span, _ := tracing.StartSpanFromContextWithOperationName(rootCtx, "TSI/SFile Delete keys")
 		for key := range possiblyDead.keys {
 			// TODO(jeff): ugh reduce copies here
 			keyb := []byte(key)
@@ -255,9 +402,18 @@ func (e *Engine) DeletePrefixRange(rootCtx context.Context, name []byte, min, ma
 			if err := e.sfile.DeleteSeriesID(sid); err != nil {
 				return err
 			}
+			deleted.AddNoLock(sid)
 		}
 		span.Finish()
 	}
 
+	return canceled
+}
+
+// deletePrefixRangeCanceled returns the wrapped context error if ctx is done.
+func deletePrefixRangeCanceled(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return errors.Wrap(err, "delete prefix range canceled")
+	}
 	return nil
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"github.com/pkg/errors"
 )
 
 func TestEngine_DeletePrefix(t *testing.T) {
@@ -119,3 +120,141 @@ func TestEngine_DeletePrefix(t *testing.T) {
 		}
 	}
 }
+
+func TestEngine_DeletePrefix_Progress(t *testing.T) {
+	e, err := NewEngine()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	// Write two TSM files and leave some data in the cache.
+	for _, points := range [][]models.Point{
+		{MustParsePointString("cpu,host=A value=1.1 1", "mm0"), MustParsePointString("mem,host=A value=1.1 1", "mm1")},
+		{MustParsePointString("cpu,host=B value=1.2 2", "mm0"), MustParsePointString("cpu,host=A value=1.2 2", "mm0")},
+	} {
+		if err := e.writePoints(points...); err != nil {
+			t.Fatalf("failed to write points: %s", err.Error())
+		}
+		if err := e.WriteSnapshot(context.Background(), tsm1.CacheStatusColdNoWrites); err != nil {
+			t.Fatalf("failed to snapshot: %s", err.Error())
+		}
+	}
+	if err := e.writePoints(MustParsePointString("cpu,host=C value=1.3 3", "mm0")); err != nil {
+		t.Fatalf("failed to write points: %s", err.Error())
+	}
+
+	if exp, got := 2, e.FileStore.Count(); exp != got {
+		t.Fatalf("file count mismatch: exp %v, got %v", exp, got)
+	}
+
+	var got []tsm1.DeletePrefixRangeProgress
+	progress := tsm1.WithDeletePrefixRangeProgress(func(p tsm1.DeletePrefixRangeProgress) {
+		got = append(got, p)
+	})
+	if err := e.DeletePrefixRange(context.Background(), []byte("mm0"), 0, 9, nil, progress); err != nil {
+		t.Fatalf("failed to delete series: %v", err)
+	}
+
+	if exp, got := 3, len(got); exp != got {
+		t.Fatalf("callback count mismatch: exp %v, got %v", exp, got)
+	}
+	for i, p := range got[:2] {
+		if !bytes.Equal(p.Name, []byte("mm0")) {
+			t.Fatalf("unexpected name: %q", p.Name)
+		}
+		if p.FilesProcessed != i+1 || p.FilesTotal != 2 || p.CacheProcessed {
+			t.Fatalf("unexpected progress after file %d: %+v", i+1, p)
+		}
+	}
+
+	// Both TSM files hold host=A, so it should only be counted once.
+	exp := tsm1.DeletePrefixRangeProgress{
+		Name:             []byte("mm0"),
+		FilesProcessed:   2,
+		FilesTotal:       2,
+		CacheProcessed:   true,
+		SeriesTombstoned: 3,
+	}
+	if !reflect.DeepEqual(got[2], exp) {
+		t.Fatalf("unexpected final progress: got %+v, exp %+v", got[2], exp)
+	}
+}
+
+func TestEngine_DeletePrefix_Canceled(t *testing.T) {
+	e, err := NewEngine()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	// Write two TSM files and leave some data in the cache.
+	for _, points := range [][]models.Point{
+		{MustParsePointString("cpu,host=A value=1.1 1", "mm0")},
+		{MustParsePointString("cpu,host=B value=1.2 2", "mm0")},
+	} {
+		if err := e.writePoints(points...); err != nil {
+			t.Fatalf("failed to write points: %s", err.Error())
+		}
+		if err := e.WriteSnapshot(context.Background(), tsm1.CacheStatusColdNoWrites); err != nil {
+			t.Fatalf("failed to snapshot: %s", err.Error())
+		}
+	}
+	if err := e.writePoints(MustParsePointString("cpu,host=C value=1.3 3", "mm0")); err != nil {
+		t.Fatalf("failed to write points: %s", err.Error())
+	}
+
+	// Cancel the delete once the first TSM file has been processed.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	progress := tsm1.WithDeletePrefixRangeProgress(func(p tsm1.DeletePrefixRangeProgress) {
+		cancel()
+	})
+
+	err = e.DeletePrefixRange(ctx, []byte("mm0"), 0, 9, nil, progress)
+	if got, exp := errors.Cause(err), context.Canceled; got != exp {
+		t.Fatalf("unexpected error: got %v, exp %v", err, exp)
+	}
+
+	// The delete stopped before reaching the cache.
+	if exp, got := 1, len(e.Cache.Keys()); exp != got {
+		t.Fatalf("cache key count mismatch: exp %v, got %v", exp, got)
+	}
+
+	// Only the series whose data was removed were dropped from the index.
+	iter, err := e.index.MeasurementSeriesIDIterator([]byte("mm0"))
+	if err != nil {
+		t.Fatalf("iterator error: %v", err)
+	}
+	var n int
+	for {
+		elem, err := iter.Next()
+		if err != nil {
+			t.Fatal(err)
+		} else if elem.SeriesID.IsZero() {
+			break
+		}
+		n++
+	}
+	iter.Close()
+	if exp, got := len(e.FileStore.Keys())+len(e.Cache.Keys()), n; exp != got {
+		t.Fatalf("index series count mismatch: exp %v, got %v", exp, got)
+	}
+	if n == 3 {
+		t.Fatal("no series were dropped from the index")
+	}
+
+	// The engine is still usable once canceled.
+	if err := e.DeletePrefixRange(context.Background(), []byte("mm0"), 0, 9, nil); err != nil {
+		t.Fatalf("failed to delete series: %v", err)
+	}
+	if exp, got := 0, len(e.Cache.Keys()); exp != got {
+		t.Fatalf("cache key count mismatch: exp %v, got %v", exp, got)
+	}
+}
@@ -0,0 +1,109 @@
+package tsm1
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+// ErrInvalidEstimateWindow is returned by PointCountEstimate when the window
+// is not positive or the time range is empty.
+var ErrInvalidEstimateWindow = errors.New("point count estimate window must be positive and max must not be less than min")
+
+// PointCountEstimate returns the approximate number of points of the bucket in
+// each window of window nanoseconds, starting at min, up to and including max.
+//
+// The estimate is built from the TSM index and the timestamp encoding of each
+// block without decoding any values. The points of a block are assumed to be
+// spread evenly between its min and max time, and tombstones are ignored, so
+// the counts are only exact for data that is still in the cache.
+func (e *Engine) PointCountEstimate(ctx context.Context, orgID, bucketID influxdb.ID, min, max, window int64) ([]int64, error) {
+	if window <= 0 || max < min {
+		return nil, ErrInvalidEstimateWindow
+	}
+
+	n := (max-min)/window + 1
+	estimates := make([]float64, n)
+
+	// add spreads the count points of a block evenly over the windows
+	// overlapping [bmin, bmax].
+	add := func(bmin, bmax int64, count int) {
+		span := float64(bmax-bmin) + 1
+		if bmin < min {
+			bmin = min
+		}
+		if bmax > max {
+			bmax = max
+		}
+		for i := (bmin - min) / window; i < n; i++ {
+			wmin := min + i*window
+			if wmin > bmax {
+				break
+			}
+			wmax := bmax
+			if bmax-wmin >= window {
+				wmax = wmin + window - 1
+			}
+			if wmin < bmin {
+				wmin = bmin
+			}
+			estimates[i] += float64(count) * (float64(wmax-wmin) + 1) / span
+		}
+	}
+
+	encoded := tsdb.EncodeName(orgID, bucketID)
+	prefix := models.EscapeMeasurement(encoded[:])
+
+	var err error
+	e.FileStore.ForEachFile(func(f TSMFile) bool {
+		// Check the context before accessing each tsm file
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			return false
+		default:
+		}
+		if !f.OverlapsTimeRange(min, max) || !f.OverlapsKeyPrefixRange(prefix, prefix) {
+			return true
+		}
+
+		iter := f.TimeRangeIterator(prefix, min, max)
+		for iter.Next() {
+			if !bytes.HasPrefix(iter.Key(), prefix) {
+				// end of org+bucket
+				break
+			}
+			if !iter.ForEachBlockCount(add) {
+				break
+			}
+		}
+		err = iter.Err()
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_ = e.Cache.ApplyEntryFn(func(sfkey []byte, entry *entry) error {
+		if !bytes.HasPrefix(sfkey, prefix) {
+			return nil
+		}
+		for _, v := range entry.values {
+			if ts := v.UnixNano(); ts >= min && ts <= max {
+				estimates[(ts-min)/window]++
+			}
+		}
+		return nil
+	})
+
+	counts := make([]int64, n)
+	for i, v := range estimates {
+		counts[i] = int64(math.Round(v))
+	}
+	return counts, nil
+}
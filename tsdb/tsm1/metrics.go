@@ -26,6 +26,7 @@ func PrometheusCollectors() []prometheus.Collector {
 		collectors = append(collectors, bms.fileMetrics.PrometheusCollectors()...)
 		collectors = append(collectors, bms.cacheMetrics.PrometheusCollectors()...)
 		collectors = append(collectors, bms.readMetrics.PrometheusCollectors()...)
+		collectors = append(collectors, bms.deleteMetrics.PrometheusCollectors()...)
 	}
 	return collectors
 }
@@ -45,6 +46,7 @@ type blockMetrics struct {
 	*fileMetrics
 	*cacheMetrics
 	*readMetrics
+	*deleteMetrics
 }
 
 // newBlockMetrics initialises the prometheus metrics for the block subsystem.
@@ -55,6 +57,7 @@ func newBlockMetrics(labels prometheus.Labels) *blockMetrics {
 		fileMetrics:       newFileMetrics(labels),
 		cacheMetrics:      newCacheMetrics(labels),
 		readMetrics:       newReadMetrics(labels),
+		deleteMetrics:     newDeleteMetrics(labels),
 	}
 }
 
@@ -65,6 +68,7 @@ func (m *blockMetrics) PrometheusCollectors() []prometheus.Collector {
 	metrics = append(metrics, m.fileMetrics.PrometheusCollectors()...)
 	metrics = append(metrics, m.cacheMetrics.PrometheusCollectors()...)
 	metrics = append(metrics, m.readMetrics.PrometheusCollectors()...)
+	metrics = append(metrics, m.deleteMetrics.PrometheusCollectors()...)
 	return metrics
 }
 
@@ -288,3 +292,32 @@ func (m *readMetrics) PrometheusCollectors() []prometheus.Collector {
 		m.Seeks,
 	}
 }
+
+// deleteMetrics are a set of metrics concerned with tracking data about deletes.
+type deleteMetrics struct {
+	SeriesDeleted *prometheus.CounterVec
+}
+
+// newDeleteMetrics initialises the prometheus metrics for tracking deletes.
+func newDeleteMetrics(labels prometheus.Labels) *deleteMetrics {
+	var names []string
+	for k := range labels {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	return &deleteMetrics{
+		SeriesDeleted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "series_deleted_total",
+			Help:      "Number of series removed from the index by deletes.",
+		}, names),
+	}
+}
+
+// PrometheusCollectors satisfies the prom.PrometheusCollector interface.
+func (m *deleteMetrics) PrometheusCollectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.SeriesDeleted,
+	}
+}
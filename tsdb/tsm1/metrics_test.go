@@ -147,6 +147,41 @@ func TestMetrics_Cache(t *testing.T) {
 	}
 }
 
+func TestMetrics_CacheSplit(t *testing.T) {
+	labels := prometheus.Labels{"engine_id": "0", "node_id": "0"}
+	metrics := newCacheMetrics(labels)
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(metrics.PrometheusCollectors()...)
+
+	c := NewCache(0)
+	c.tracker = newCacheTracker(metrics, labels)
+	values := Values{NewValue(1, 1.0), NewValue(2, 2.0)}
+	for _, key := range []string{"foo", "bar", "baz"} {
+		if err := c.Write([]byte(key), values); err != nil {
+			t.Fatalf("failed to write key %s to cache: %s", key, err.Error())
+		}
+	}
+
+	memSize := func() float64 {
+		mfs, err := reg.Gather()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return promtest.MustFindMetric(t, mfs, namespace+"_"+cacheSubsystem+"_inuse_bytes", labels).GetGauge().GetValue()
+	}
+	exp := memSize()
+
+	// Writing to the splits must not move the gauges of the cache.
+	for _, s := range c.Split(3) {
+		if err := s.Write([]byte("qux"), values); err != nil {
+			t.Fatalf("failed to write key qux to split: %s", err.Error())
+		}
+	}
+	if got := memSize(); got != exp {
+		t.Fatalf("cache memory size changed by writes to splits, exp %v, got %v", exp, got)
+	}
+}
+
 func TestMetrics_Compactions(t *testing.T) {
 	// metrics to be shared by multiple file stores.
 	metrics := newCompactionMetrics(prometheus.Labels{"engine_id": "", "node_id": ""})
@@ -106,6 +106,26 @@ func (b *TimeRangeIterator) HasData() bool {
 	return false
 }
 
+// ForEachBlockCount calls fn with the time range and number of points of each
+// block of the current key that overlaps the time range. Only the timestamps of
+// each block are counted, the values are never decoded, and tombstones are
+// ignored. ForEachBlockCount returns false if a block could not be read.
+func (b *TimeRangeIterator) ForEachBlockCount(fn func(min, max int64, n int)) bool {
+	if b.Err() != nil {
+		return false
+	}
+
+	e := excludeEntries(b.iter.Entries(), b.tr)
+	for i := range e {
+		_, b.buf, b.err = b.r.ReadBytes(&e[i], b.buf)
+		if b.err != nil {
+			return false
+		}
+		fn(e[i].MinTime, e[i].MaxTime, BlockCount(b.buf))
+	}
+	return true
+}
+
 // readBlock reads the block identified by IndexEntry e and accumulates
 // statistics. readBlock returns true on success.
 func (b *TimeRangeIterator) readBlock(e *IndexEntry) bool {
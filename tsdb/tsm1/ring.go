@@ -1,6 +1,7 @@
 package tsm1
 
 import (
+	"strings"
 	"sync"
 	"sync/atomic"
 
@@ -78,7 +79,13 @@ func (r *ring) entry(key []byte) *entry {
 // If no entry exists for the key then one will be created.
 // write is safe for use by multiple goroutines.
 func (r *ring) write(key []byte, values Values) (bool, error) {
-	return r.getPartition(key).write(key, values)
+	return r.getPartition(key).write(key, values, true)
+}
+
+// writeUnchecked is like write but does not check the values for type conflicts.
+func (r *ring) writeUnchecked(key []byte, values Values) bool {
+	newKey, _ := r.getPartition(key).write(key, values, false)
+	return newKey
 }
 
 // add adds an entry to the ring.
@@ -110,6 +117,19 @@ func (r *ring) keys(sorted bool) [][]byte {
 	return keys
 }
 
+// forEachKey calls fn with each key in the ring until fn returns false. Only
+// the keys of one partition are copied at a time, and fn is called without
+// holding the partition's lock.
+func (r *ring) forEachKey(fn func(key []byte) bool) {
+	for _, p := range r.partitions {
+		for _, k := range p.keys() {
+			if !fn(k) {
+				return
+			}
+		}
+	}
+}
+
 func (r *ring) count() int {
 	var n int
 	for _, p := range r.partitions {
@@ -183,7 +203,6 @@ func (r *ring) applySerial(f func([]byte, *entry) error) error {
 }
 
 func (r *ring) split(n int) []*ring {
-	var keys int
 	storers := make([]*ring, n)
 	for i := 0; i < n; i++ {
 		storers[i] = newRing()
@@ -191,11 +210,52 @@ func (r *ring) split(n int) []*ring {
 
 	for i, p := range r.partitions {
 		storers[i%n].partitions[i] = p
-		keys += len(p.store)
+		storers[i%n].keysHint += int64(len(p.store))
+	}
+	return storers
+}
+
+// splitByPrefix returns a ring for each of prefixes, holding the entries whose
+// keys start with that prefix, followed by a ring holding the entries whose keys
+// match none of them. A key is assigned to the first prefix it matches. The
+// entries are shared with r, not copied.
+func (r *ring) splitByPrefix(prefixes [][]byte) []*ring {
+	storers := make([]*ring, len(prefixes)+1)
+	for i := range storers {
+		storers[i] = newRing()
+	}
+
+	for _, p := range r.partitions {
+		p.mu.RLock()
+		for k, e := range p.store {
+			i := len(prefixes)
+			for j, prefix := range prefixes {
+				if strings.HasPrefix(k, string(prefix)) {
+					i = j
+					break
+				}
+			}
+			storers[i].add([]byte(k), e)
+		}
+		p.mu.RUnlock()
 	}
 	return storers
 }
 
+// size returns the number of bytes used by the keys and values in the ring,
+// as accounted for by the cache.
+func (r *ring) size() uint64 {
+	var n uint64
+	for _, p := range r.partitions {
+		p.mu.RLock()
+		for k, e := range p.store {
+			n += uint64(len(k) + e.size())
+		}
+		p.mu.RUnlock()
+	}
+	return n
+}
+
 // partition provides safe access to a map of series keys to entries.
 type partition struct {
 	mu    sync.RWMutex
@@ -212,15 +272,15 @@ func (p *partition) entry(key []byte) *entry {
 }
 
 // write writes the values to the entry in the partition, creating the entry
-// if it does not exist.
+// if it does not exist. The values are checked for type conflicts if check is set.
 // write is safe for use by multiple goroutines.
-func (p *partition) write(key []byte, values Values) (bool, error) {
+func (p *partition) write(key []byte, values Values, check bool) (bool, error) {
 	p.mu.RLock()
 	e := p.store[string(key)]
 	p.mu.RUnlock()
 	if e != nil {
 		// Hot path.
-		return false, e.add(values)
+		return false, addEntryValues(e, values, check)
 	}
 
 	p.mu.Lock()
@@ -228,10 +288,15 @@ func (p *partition) write(key []byte, values Values) (bool, error) {
 
 	// Check again.
 	if e = p.store[string(key)]; e != nil {
-		return false, e.add(values)
+		return false, addEntryValues(e, values, check)
 	}
 
 	// Create a new entry using a preallocated size if we have a hint available.
+	if !check {
+		p.store[string(key)] = newEntryValuesUnchecked(values)
+		return true, nil
+	}
+
 	e, err := newEntryValues(values)
 	if err != nil {
 		return false, err
@@ -241,6 +306,15 @@ func (p *partition) write(key []byte, values Values) (bool, error) {
 	return true, nil
 }
 
+// addEntryValues adds the values to the entry, checking them for type conflicts if check is set.
+func addEntryValues(e *entry, values Values, check bool) error {
+	if !check {
+		e.addUnchecked(values)
+		return nil
+	}
+	return e.add(values)
+}
+
 // add adds a new entry for key to the partition.
 func (p *partition) add(key []byte, entry *entry) {
 	p.mu.Lock()
@@ -58,6 +58,10 @@ func CollectionToValues(collection *tsdb.SeriesCollection) (map[string][]Value,
 	var (
 		keyBuf  []byte
 		baseLen int
+
+		// The keys a type conflict has been recorded for, so that the reason of
+		// each conflicting key is formatted at most once.
+		conflicts map[string]struct{}
 	)
 
 	j := 0
@@ -108,8 +112,12 @@ func CollectionToValues(collection *tsdb.SeriesCollection) (map[string][]Value,
 
 			vs, ok := values[string(keyBuf)]
 			if ok && len(vs) > 0 && valueType(vs[0]) != valueType(v) {
-				if collection.Reason == "" {
-					collection.Reason = fmt.Sprintf(
+				if _, ok := conflicts[string(keyBuf)]; !ok {
+					if conflicts == nil {
+						conflicts = make(map[string]struct{})
+					}
+					conflicts[string(keyBuf)] = struct{}{}
+					collection.AddReasonf(
 						"conflicting field type: %s has field type %T but expected %T",
 						citer.Key(), v.Value(), vs[0].Value())
 				}